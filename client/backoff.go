@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Backoff describes how an operation is retried: the wait before each retry grows exponentially from
+// InitialInterval by Multiplier up to MaxInterval, and is randomised by up to Jitter (a fraction of the
+// wait) in either direction. No retry is started once MaxElapsedTime has passed since the first attempt.
+type Backoff struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+	MaxElapsedTime  time.Duration
+}
+
+// DefaultDeletionBackoff is used to wait for a deleted secret, which may take a few seconds to show up as
+// deleted.
+var DefaultDeletionBackoff = Backoff{
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     10 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+	MaxElapsedTime:  60 * time.Second,
+}
+
+// DefaultRecoveryBackoff is used to wait for a recovered secret, which remains in "recovering" state for
+// a few seconds after it was recovered.
+var DefaultRecoveryBackoff = Backoff{
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     10 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+	MaxElapsedTime:  60 * time.Second,
+}
+
+// Interval returns the wait before the given retry, counting from 1, without jitter.
+func (b Backoff) Interval(retry int) time.Duration {
+	interval := float64(b.InitialInterval)
+	for i := 1; i < retry && interval < float64(b.MaxInterval); i++ {
+		interval *= b.Multiplier
+	}
+	if interval > float64(b.MaxInterval) {
+		return b.MaxInterval
+	}
+	return time.Duration(interval)
+}
+
+// jittered randomises interval by up to b.Jitter of it in either direction.
+func (b Backoff) jittered(interval time.Duration) time.Duration {
+	if b.Jitter <= 0 {
+		return interval
+	}
+	delta := b.Jitter * float64(interval)
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
+
+// permanentError is returned by an operation passed to Backoff.Retry that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// permanent marks err as an error that Backoff.Retry returns right away.
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Retry calls operation until it succeeds, and returns the last error once MaxElapsedTime has passed.
+// It stops waiting as soon as ctx is done, returning the error of ctx wrapped together with the last error.
+// Errors marked with permanent are returned right away.
+func (b Backoff) Retry(ctx context.Context, description string, operation func() error) error {
+	start := time.Now()
+
+	for retry := 1; ; retry++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		var permanentErr *permanentError
+		if errors.As(err, &permanentErr) {
+			return permanentErr.err
+		}
+
+		wait := b.jittered(b.Interval(retry))
+		if time.Since(start)+wait > b.MaxElapsedTime {
+			return err
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Failed to %s, retrying", description), map[string]any{
+			"retry": retry,
+			"wait":  wait.String(),
+			"error": err.Error(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w while waiting to retry, last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// ErrorClass classifies the error responses of the vault that call for a remedy of their own.
+type ErrorClass int
+
+const (
+	// ErrorClassOther is any error that is not classified otherwise, including errors without a response.
+	ErrorClassOther ErrorClass = iota
+
+	// ErrorClassNotFound is a response that the secret does not exist.
+	ErrorClassNotFound
+
+	// ErrorClassForbiddenByRBAC is a response of a vault that uses Azure RBAC, and has no role assignment that
+	// allows the caller to perform the action.
+	ErrorClassForbiddenByRBAC
+
+	// ErrorClassForbiddenByAccessPolicy is a response of a vault that uses access policies, and has no access
+	// policy that grants the caller the permission.
+	ErrorClassForbiddenByAccessPolicy
+
+	// ErrorClassForbiddenByFirewall is a response of the firewall of a vault, which denies requests from network
+	// addresses that are not allowed.
+	ErrorClassForbiddenByFirewall
+
+	// ErrorClassForbidden is any other response that the request is forbidden, e.g. because the secret is disabled.
+	ErrorClassForbidden
+
+	// ErrorClassBeingDeleted is a response that the secret is still being deleted.
+	ErrorClassBeingDeleted
+
+	// ErrorClassDeletedButRecoverable is a response that the name of the secret cannot be reused, as a deleted
+	// secret with that name is kept by the soft-delete of the vault.
+	ErrorClassDeletedButRecoverable
+
+	// ErrorClassConflict is any other response that the request conflicts with the state of the secret.
+	ErrorClassConflict
+)
+
+// ErrorClassification is the class of an error, together with the details of its response that a remedy
+// refers to.
+type ErrorClassification struct {
+	Class ErrorClass
+
+	// ClientAddress is the network address of the caller that the firewall of the vault denied, when the
+	// response names it.
+	ClientAddress string
+
+	// Action is the action that Azure RBAC denied the caller, e.g.
+	// Microsoft.KeyVault/vaults/secrets/getSecret/action, when the response names it.
+	Action string
+}
+
+// vaultErrorBody is the body of an error response of the vault.
+type vaultErrorBody struct {
+	Error struct {
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+		InnerError *struct {
+			Code string `json:"code"`
+		} `json:"innererror"`
+	} `json:"error"`
+}
+
+// ClassifyError classifies err by the status, error codes and message of the error response of the vault that
+// it wraps.
+func ClassifyError(err error) ErrorClassification {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return ErrorClassification{Class: ErrorClassOther}
+	}
+
+	var body vaultErrorBody
+	if responseErr.RawResponse != nil {
+		if payload, err := runtime.Payload(responseErr.RawResponse); err == nil {
+			_ = json.Unmarshal(payload, &body)
+		}
+	}
+	innerCode := ""
+	if body.Error.InnerError != nil {
+		innerCode = strings.ToLower(body.Error.InnerError.Code)
+	}
+	message := strings.ToLower(body.Error.Message)
+
+	switch responseErr.StatusCode {
+	case http.StatusNotFound:
+		return ErrorClassification{Class: ErrorClassNotFound}
+	case http.StatusForbidden:
+		switch {
+		case innerCode == "forbiddenbyfirewall" || strings.Contains(message, "client address is not authorized"):
+			return ErrorClassification{
+				Class:         ErrorClassForbiddenByFirewall,
+				ClientAddress: messageField(body.Error.Message, "Client address"),
+			}
+		case innerCode == "forbiddenbyrbac" || strings.Contains(message, "caller is not authorized to perform action"):
+			return ErrorClassification{
+				Class:  ErrorClassForbiddenByRBAC,
+				Action: strings.Trim(messageField(body.Error.Message, "Action"), "'"),
+			}
+		case innerCode == "accessdenied" || innerCode == "forbiddenbypolicy" || strings.Contains(message, "does not have secrets"):
+			return ErrorClassification{Class: ErrorClassForbiddenByAccessPolicy}
+		}
+		return ErrorClassification{Class: ErrorClassForbidden}
+	case http.StatusConflict:
+		switch {
+		case innerCode == "objectisbeingdeleted" || strings.Contains(message, "being deleted"):
+			return ErrorClassification{Class: ErrorClassBeingDeleted}
+		case innerCode == "objectisdeletedbutrecoverable" || strings.Contains(message, "deleted but recoverable"):
+			return ErrorClassification{Class: ErrorClassDeletedButRecoverable}
+		}
+		return ErrorClassification{Class: ErrorClassConflict}
+	}
+	return ErrorClassification{Class: ErrorClassOther}
+}
+
+// messageField returns the value of the line "<name>: <value>" of the message of an error response, which
+// the vault uses to list the caller, the client address and the denied action.
+func messageField(message string, name string) string {
+	for _, line := range strings.FieldsFunc(message, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), name+":"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -4,30 +4,85 @@ package azrandom
 
 import (
 	"context"
-	"strconv"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
-	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-func CreateClient(
-	vaultUrl string,
-	disabledCredentials azidentity.DisabledCredentials,
+// ClientOptions configures the client of the vault.
+type ClientOptions struct {
+	// Cloud holds the endpoints of the environment that hosts the vault. It defaults to the public cloud.
+	Cloud cloud.Configuration
 
-) (*azsecrets.Client, error) {
+	// Retry configures how requests that fail transiently, e.g. on a network error, a throttled or a 5xx
+	// response, are retried by the client. Zero values keep the defaults of azcore, and nil StatusCodes retry
+	// retryStatusCodes.
+	Retry policy.RetryOptions
 
-	credentialOptions := azidentity.DefaultAzureCredentialOptions{}
+	// Transport sends the requests of the client, e.g. through a proxy given by ProxyTransport. The default
+	// transport of azcore is used when it is nil.
+	Transport policy.Transporter
 
-	// Create a new DefaultAzureCredential
-	credential, err := azidentity.NewCustomDefaultAzureCredential(&credentialOptions, disabledCredentials)
-	if err != nil {
-		return nil, err
+	// UserAgent identifies the provider and the partner in the User-Agent of the requests of the client.
+	UserAgent UserAgent
+
+	// OperationTimeout bounds each call of the client, including the retries of its requests, returning an
+	// *OperationTimeoutError when it is exceeded. Calls are not bounded when it is zero.
+	OperationTimeout time.Duration
+
+	// Limiter bounds the number of requests in flight, and is shared between the clients of all vaults so that
+	// the bound holds across them. Requests are not bounded when it is nil. Waiting for a slot counts towards
+	// OperationTimeout.
+	Limiter *ConcurrencyLimiter
+
+	// DisableChallengeResourceVerification accepts authentication challenges of the vault for any resource,
+	// rather than only for the DNS suffix of the vault, e.g. for a custom endpoint serving an emulator.
+	DisableChallengeResourceVerification bool
+}
+
+// SecretsClientOptions returns the options that CreateClient creates the client of the vault with.
+func SecretsClientOptions(options ClientOptions) *azsecrets.ClientOptions {
+	clientOptions := azcore.ClientOptions{
+		Cloud:     options.Cloud,
+		Retry:     options.Retry,
+		Transport: options.Transport,
+	}
+	if clientOptions.Retry.StatusCodes == nil {
+		clientOptions.Retry.StatusCodes = retryStatusCodes
+	}
+	options.UserAgent.apply(&clientOptions)
+	if options.OperationTimeout > 0 {
+		clientOptions.PerCallPolicies = append(clientOptions.PerCallPolicies, operationTimeoutPolicy{timeout: options.OperationTimeout})
+	}
+	if options.Limiter != nil {
+		clientOptions.PerRetryPolicies = append(clientOptions.PerRetryPolicies, concurrencyLimitPolicy{limiter: options.Limiter})
+	}
+	return &azsecrets.ClientOptions{
+		ClientOptions:                        clientOptions,
+		DisableChallengeResourceVerification: options.DisableChallengeResourceVerification,
+	}
+}
+
+// CreateClient returns a client of the vault at vaultUrl, authenticating with credential. It returns an
+// error wrapping ErrManagedHSM for the URL of a Managed HSM pool, which has no secrets API.
+func CreateClient(vaultUrl string, credential azcore.TokenCredential, options ClientOptions) (*azsecrets.Client, error) {
+	if IsManagedHSM(vaultUrl) {
+		return nil, fmt.Errorf("%s is a Managed HSM pool: %w", vaultUrl, ErrManagedHSM)
 	}
 
 	// Create a new KeyClient
-	client, err := azsecrets.NewClient(vaultUrl, credential, nil)
+	client, err := azsecrets.NewClient(vaultUrl, credential, SecretsClientOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -35,86 +90,555 @@ func CreateClient(
 	return client, nil
 }
 
+// credentialValidationTimeout bounds ValidateCredential, as some credentials in the chain probe
+// endpoints that may not respond outside of Azure.
+const credentialValidationTimeout = 60 * time.Second
+
+// ValidateCredential acquires a token for the vault at vaultUrl, so that authentication failures
+// surface before any secret is read or written. It does not check that the identity may access the vault.
+func ValidateCredential(ctx context.Context, credential azcore.TokenCredential, vaultUrl string) error {
+	ctx, cancel := context.WithTimeout(ctx, credentialValidationTimeout)
+	defer cancel()
+
+	_, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{VaultScope(vaultUrl)}})
+	return err
+}
+
+// accessCheckSecretName is the secret that ValidateAccess gets, which does not need to exist.
+const accessCheckSecretName = "azrandom-access-check"
+
+// ValidateAccess gets a secret from the vault of client, so that a vault that cannot be reached, e.g. because
+// of its firewall, or an identity that may not get its secrets surfaces before any secret is read or written.
+// The secret does not need to exist, so that an empty vault passes.
+func ValidateAccess(ctx context.Context, client *azsecrets.Client) error {
+	_, err := SecretExists(ctx, client, accessCheckSecretName)
+	return err
+}
+
+// SecretProperties holds the metadata stored alongside a secret's value. An empty ContentType leaves
+// the content type of the secret unset, a nil Enabled leaves the secret enabled, and a nil Expires or
+// NotBefore leaves its validity unbounded. Created and Updated are set by the vault, and are only
+// returned when reading a secret.
+type SecretProperties struct {
+	Tags        map[string]string
+	ContentType string
+	Enabled     *bool
+	Expires     *time.Time
+	NotBefore   *time.Time
+	Created     *time.Time
+	Updated     *time.Time
+}
+
+// SecretExists returns whether the secret name exists. A soft-deleted secret does not exist. Only a
+// "not found" response means that the secret does not exist: any other error, such as an authorization,
+// throttling or network failure, is returned.
 func SecretExists(ctx context.Context, client *azsecrets.Client, name string) (bool, error) {
 
-	// TODO this is not entirely reliable. If secret is in a "deleting" or "recovering" state this will probably throw an error that we'll need to differentiate
 	_, err := client.GetSecret(ctx, name, "", nil)
-	if err == nil {
+	if err == nil || IsDisabled(err) {
 		return true, nil
 	}
-	return false, err
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return false, wrapResponseError(err)
+
+}
+
+// IsNotFound returns whether err is a response of the vault that the requested item does not exist.
+func IsNotFound(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// IsDisabled returns whether err is a response of the vault refusing to get a secret, because the secret
+// is disabled.
+func IsDisabled(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden &&
+		strings.Contains(strings.ToLower(err.Error()), "disabled")
+}
+
+// GetSecret returns the version and the properties of the given version of a secret, or of its latest version
+// when version is empty. The properties of a disabled secret, which the vault refuses to get, are looked up in
+// the list of its versions.
+func GetSecret(ctx context.Context, client *azsecrets.Client, name string, version string) (string, SecretProperties, error) {
+
+	version, properties, _, err := GetSecretWithValue(ctx, client, name, version)
+	return version, properties, err
 
 }
 
-func GetSecret(ctx context.Context, client *azsecrets.Client, name string) (string, error) {
+// GetSecretWithValue is GetSecret, also returning the value of the secret, which the vault returns along with its
+// properties. The value is nil when the secret is disabled, as the vault refuses to get it.
+func GetSecretWithValue(ctx context.Context, client *azsecrets.Client, name string, version string) (string, SecretProperties, *string, error) {
 
-	secret, err := client.GetSecret(ctx, name, "", nil)
+	secret, err := client.GetSecret(ctx, name, version, nil)
+	if IsDisabled(err) && version != "" {
+		version, properties, err := listedSecretVersion(ctx, client, name, version)
+		return version, properties, nil, err
+	}
+	if IsDisabled(err) {
+		version, properties, err := latestSecretVersion(ctx, client, name)
+		return version, properties, nil, err
+	}
 	if err != nil {
-		return "", err
+		return "", SecretProperties{}, nil, wrapResponseError(err)
 	}
-	return secret.ID.Version(), nil
+
+	value := ""
+	if secret.Value != nil {
+		value = *secret.Value
+	}
+	return secret.ID.Version(), fromSecret(secret.Tags, secret.ContentType, secret.Attributes), &value, nil
 
 }
 
-func CreateSecret(ctx context.Context, client *azsecrets.Client, name string, value string) (string, error) {
+// listSecretVersions returns the properties of every version of a secret, paging through all of them.
+func listSecretVersions(ctx context.Context, client *azsecrets.Client, name string) ([]*azsecrets.SecretProperties, error) {
 
-	// If deleted secret exists, recover it first
-	foundDeletedSecret := false
-	_, err := client.GetDeletedSecret(ctx, name, nil)
-	if err == nil {
-		foundDeletedSecret = true
-		_, err := client.RecoverDeletedSecret(ctx, name, nil)
+	var versions []*azsecrets.SecretProperties
+	pager := client.NewListSecretPropertiesVersionsPager(name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, wrapResponseError(err)
+		}
+
+		for _, version := range page.Value {
+			if version != nil && version.ID != nil {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// latestSecretVersion returns the version and the properties of the most recently created version of
+// a secret, listing all of its versions.
+func latestSecretVersion(ctx context.Context, client *azsecrets.Client, name string) (string, SecretProperties, error) {
+
+	versions, err := listSecretVersions(ctx, client, name)
+	if err != nil {
+		return "", SecretProperties{}, err
+	}
+
+	var latest *azsecrets.SecretProperties
+	for _, version := range versions {
+		if version.Attributes == nil || version.Attributes.Created == nil {
+			continue
+		}
+		if latest == nil || version.Attributes.Created.After(*latest.Attributes.Created) {
+			latest = version
+		}
+	}
+	if latest == nil {
+		return "", SecretProperties{}, fmt.Errorf("no versions of secret %s were found", name)
+	}
+
+	return latest.ID.Version(), fromSecret(latest.Tags, latest.ContentType, latest.Attributes), nil
+}
+
+// listedSecretVersion returns the properties of the given version of a secret, listing all of its versions.
+func listedSecretVersion(ctx context.Context, client *azsecrets.Client, name string, version string) (string, SecretProperties, error) {
+
+	versions, err := listSecretVersions(ctx, client, name)
+	if err != nil {
+		return "", SecretProperties{}, err
+	}
+
+	for _, properties := range versions {
+		if properties.ID.Version() == version {
+			return version, fromSecret(properties.Tags, properties.ContentType, properties.Attributes), nil
+		}
+	}
+	return "", SecretProperties{}, fmt.Errorf("version %s of secret %s was not found", version, name)
+}
+
+// DisablePreviousVersions disables every enabled version of a secret other than version, so that values
+// replaced by a rotation can no longer be read, and returns the versions that were disabled.
+func DisablePreviousVersions(ctx context.Context, client *azsecrets.Client, name string, version string) ([]string, error) {
+
+	versions, err := listSecretVersions(ctx, client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []string
+	for _, properties := range versions {
+		if properties.ID.Version() != version && isVersionEnabled(properties) {
+			previous = append(previous, properties.ID.Version())
+		}
+	}
+	return disableSecretVersions(ctx, client, name, previous)
+}
+
+// ErrListForbidden is returned by PruneSecretVersions when the caller lacks permission to list the versions
+// of a secret.
+var ErrListForbidden = errors.New("not allowed to list the versions of the secret")
+
+// PruneSecretVersions disables the enabled versions of a secret beyond the keep most recently created ones,
+// oldest first, and returns the versions that were disabled. Key Vault cannot delete individual versions, so
+// disabling them is as close to pruning as it gets. version is never disabled, even when it is not among
+// the most recent ones. It returns an error wrapping ErrListForbidden when the versions cannot be listed.
+func PruneSecretVersions(ctx context.Context, client *azsecrets.Client, name string, version string, keep int) ([]string, error) {
+
+	versions, err := listSecretVersions(ctx, client, name)
+	if err != nil {
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("%w: %w", ErrListForbidden, err)
+		}
+		return nil, err
+	}
+
+	// Most recently created first, versions without a creation time counting as the oldest
+	created := func(properties *azsecrets.SecretProperties) time.Time {
+		if properties.Attributes == nil || properties.Attributes.Created == nil {
+			return time.Time{}
+		}
+		return *properties.Attributes.Created
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return created(versions[i]).After(created(versions[j]))
+	})
+
+	var pruned []string
+	for i := len(versions) - 1; i >= keep; i-- {
+		if versions[i].ID.Version() != version && isVersionEnabled(versions[i]) {
+			pruned = append(pruned, versions[i].ID.Version())
+		}
+	}
+	return disableSecretVersions(ctx, client, name, pruned)
+}
+
+// isVersionEnabled returns whether a version of a secret is enabled.
+func isVersionEnabled(properties *azsecrets.SecretProperties) bool {
+	return properties.Attributes != nil && properties.Attributes.Enabled != nil && *properties.Attributes.Enabled
+}
+
+// disableSecretVersions disables the given versions of a secret in order, and returns the versions that were
+// disabled before an error occurred.
+func disableSecretVersions(ctx context.Context, client *azsecrets.Client, name string, versions []string) ([]string, error) {
+
+	disabled := false
+	var result []string
+	for _, version := range versions {
+		_, err := client.UpdateSecretProperties(ctx, name, version, azsecrets.UpdateSecretPropertiesParameters{
+			SecretAttributes: &azsecrets.SecretAttributes{Enabled: &disabled},
+		}, nil)
 		if err != nil {
-			return "", err
+			return result, fmt.Errorf("could not disable version %s of secret %s: %w", version, name, wrapResponseError(err))
 		}
+		result = append(result, version)
+	}
+	return result, nil
+}
+
+// GetSecretValue returns the value stored in the given version of a secret. An empty version returns
+// the value of the latest version.
+func GetSecretValue(ctx context.Context, client *azsecrets.Client, name string, version string) (string, error) {
+
+	secret, err := client.GetSecret(ctx, name, version, nil)
+	if err != nil {
+		return "", wrapResponseError(err)
+	}
+	if secret.Value == nil {
+		return "", nil
+	}
+	return *secret.Value, nil
+
+}
+
+// HashSecretValue returns the hex encoded SHA-256 of value, which identifies a value without revealing it.
+func HashSecretValue(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateSecret stores value as a new secret, and returns the version and the properties of the stored secret.
+// A soft-deleted secret with the same name is recovered, waiting for it with DefaultRecoveryBackoff.
+func CreateSecret(ctx context.Context, client *azsecrets.Client, name string, value string, properties SecretProperties) (string, SecretProperties, error) {
+	return CreateSecretWithBackoff(ctx, client, name, value, properties, DefaultRecoveryBackoff)
+}
+
+// CreateSecretWithBackoff is CreateSecret, waiting for a recovered secret according to backoff. A secret whose
+// name is free takes a single request. Only when the vault answers with a conflict is a soft-deleted secret with
+// the same name looked up and recovered, after which setting the secret is retried: four requests or more.
+func CreateSecretWithBackoff(ctx context.Context, client *azsecrets.Client, name string, value string, properties SecretProperties, backoff Backoff) (string, SecretProperties, error) {
+
+	var secret azsecrets.SetSecretResponse
+	setSecret := func() (err error) {
+		secret, err = client.SetSecret(ctx, name, setSecretParameters(value, properties), nil)
+		return wrapResponseError(err)
 	}
 
 	// Attempt to create secret
-	secret, err := client.SetSecret(ctx, name, azsecrets.SetSecretParameters{Value: &value}, nil)
-
-	// If creation fails, keep trying until succeeds (deleted secret remains in "recovering" state for a few seconds)
-	if err != nil && foundDeletedSecret {
-	out:
-		for attempt := 2; attempt <= 8; attempt++ {
-			secret, err = client.SetSecret(ctx, name, azsecrets.SetSecretParameters{Value: &value}, nil)
-			if err == nil {
-				// No error, return
-				break out
+	err := setSecret()
+
+	// If a deleted secret holds the name, recover it first
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusConflict {
+		_, getErr := client.GetDeletedSecret(ctx, name, nil)
+		if getErr != nil && !IsNotFound(getErr) {
+			return "", SecretProperties{}, wrapResponseError(getErr)
+		}
+		if getErr == nil {
+			if _, err := client.RecoverDeletedSecret(ctx, name, nil); err != nil {
+				return "", SecretProperties{}, wrapResponseError(err)
 			}
 
-			tflog.Debug(ctx, "Failed to set new secret after recovery. Now waiting 10 seconds before retrying. Attemp "+strconv.Itoa(attempt))
-
-			// Sleep 5 seconds before retrying
-			time.Sleep(5 * time.Second)
+			// Keep trying until creation succeeds (deleted secret remains in "recovering" state for a few seconds)
+			err = backoff.Retry(ctx, "set new secret after recovery", setSecret)
 		}
 	}
 
 	if err != nil {
-		return "", err
+		return "", SecretProperties{}, err
 	}
 
-	return secret.ID.Version(), nil
+	return secret.ID.Version(), fromSecret(secret.Tags, secret.ContentType, secret.Attributes), nil
+
+}
 
+// RecoverDeletedSecret recovers a soft-deleted secret, and waits with DefaultRecoveryBackoff until the recovered
+// secret can be read. It returns false when there is no deleted secret with the given name, and any other error of
+// the vault, such as a missing permission to get deleted secrets, as it is.
+func RecoverDeletedSecret(ctx context.Context, client *azsecrets.Client, name string) (bool, error) {
+	return RecoverDeletedSecretWithBackoff(ctx, client, name, DefaultRecoveryBackoff)
 }
 
-func UpdateSecret(ctx context.Context, client *azsecrets.Client, name string, value string) (string, error) {
+// RecoverDeletedSecretWithBackoff is RecoverDeletedSecret, waiting for the recovered secret according to backoff.
+func RecoverDeletedSecretWithBackoff(ctx context.Context, client *azsecrets.Client, name string, backoff Backoff) (bool, error) {
 
-	secret, err := client.SetSecret(ctx, name, azsecrets.SetSecretParameters{Value: &value}, nil)
+	_, err := client.GetDeletedSecret(ctx, name, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
-		return "", err
+		return false, wrapResponseError(err)
 	}
 
-	return secret.ID.Version(), nil
+	_, err = client.RecoverDeletedSecret(ctx, name, nil)
+	if err != nil {
+		return false, wrapResponseError(err)
+	}
 
+	// The recovered secret remains in "recovering" state for a few seconds
+	err = backoff.Retry(ctx, "read secret after recovery", func() error {
+		_, err := client.GetSecret(ctx, name, "", nil)
+		return err
+	})
+
+	return true, wrapResponseError(err)
 }
 
-func DeleteSecret(ctx context.Context, client *azsecrets.Client, name string) error {
+// UpdateSecret stores value as a new version of an existing secret, and returns the version and the
+// properties of the stored secret.
+func UpdateSecret(ctx context.Context, client *azsecrets.Client, name string, value string, properties SecretProperties) (string, SecretProperties, error) {
 
-	_, err := client.DeleteSecret(ctx, name, nil)
+	secret, err := client.SetSecret(ctx, name, setSecretParameters(value, properties), nil)
+	if err != nil {
+		return "", SecretProperties{}, wrapResponseError(err)
+	}
+
+	return secret.ID.Version(), fromSecret(secret.Tags, secret.ContentType, secret.Attributes), nil
 
+}
+
+// UpdateSecretProperties updates the metadata of an existing secret version without creating a new version,
+// and returns the updated properties. A nil Expires or NotBefore removes the bound from the secret.
+func UpdateSecretProperties(ctx context.Context, client *azsecrets.Client, name string, version string, properties SecretProperties) (SecretProperties, error) {
+
+	secret, err := client.UpdateSecretProperties(ctx, name, version, azsecrets.UpdateSecretPropertiesParameters{
+		Tags:        toTags(properties.Tags),
+		ContentType: toContentType(properties.ContentType),
+		SecretAttributes: &azsecrets.SecretAttributes{
+			Enabled:   properties.Enabled,
+			Expires:   toClearableTime(properties.Expires),
+			NotBefore: toClearableTime(properties.NotBefore),
+		},
+	}, nil)
+	if err != nil {
+		return SecretProperties{}, wrapResponseError(err)
+	}
+
+	return fromSecret(secret.Tags, secret.ContentType, secret.Attributes), nil
+}
+
+// WriteVerificationError is returned by VerifySecretValue when the stored value differs from the value
+// that was sent. Only hashes are kept, so that the error never contains either value.
+type WriteVerificationError struct {
+	Name       string
+	Version    string
+	SentHash   string
+	StoredHash string
+}
+
+func (e *WriteVerificationError) Error() string {
+	return fmt.Sprintf("version %s of secret %s does not hold the value that was sent: expected a value with SHA-256 %s, "+
+		"the vault returned a value with SHA-256 %s", e.Version, e.Name, e.SentHash, e.StoredHash)
+}
+
+// VerifySecretValue reads back the given version of a secret, and returns a *WriteVerificationError
+// when its value differs from value.
+func VerifySecretValue(ctx context.Context, client *azsecrets.Client, name string, version string, value string) error {
+
+	stored, err := GetSecretValue(ctx, client, name, version)
 	if err != nil {
 		return err
 	}
 
+	if sentHash, storedHash := HashSecretValue(value), HashSecretValue(stored); sentHash != storedHash {
+		return &WriteVerificationError{
+			Name:       name,
+			Version:    version,
+			SentHash:   sentHash,
+			StoredHash: storedHash,
+		}
+	}
+
 	return nil
 }
+
+// DeleteSecret deletes the secret name, and returns whether it was deleted. A secret that was already
+// deleted, e.g. manually outside of Terraform, is not an error: a warning is logged, and false is returned.
+func DeleteSecret(ctx context.Context, client *azsecrets.Client, name string) (bool, error) {
+
+	_, err := client.DeleteSecret(ctx, name, nil)
+	if IsNotFound(err) || isBeingDeleted(err) {
+		tflog.Warn(ctx, "Secret was already deleted", map[string]any{"name": name, "error": err.Error()})
+		return false, nil
+	}
+	if err != nil {
+		return false, wrapResponseError(err)
+	}
+
+	return true, nil
+}
+
+// isBeingDeleted returns whether err is a response of the vault that the secret cannot be deleted
+// because it is already being deleted.
+func isBeingDeleted(err error) bool {
+	return ClassifyError(err).Class == ErrorClassBeingDeleted
+}
+
+// WaitForDeletion waits with DefaultDeletionBackoff until the deletion of the secret name has completed,
+// which is when it shows up as a deleted secret. Until then, a secret with the same name cannot be created.
+func WaitForDeletion(ctx context.Context, client *azsecrets.Client, name string) error {
+	return WaitForDeletionWithBackoff(ctx, client, name, DefaultDeletionBackoff)
+}
+
+// WaitForDeletionWithBackoff is WaitForDeletion, waiting according to backoff.
+func WaitForDeletionWithBackoff(ctx context.Context, client *azsecrets.Client, name string, backoff Backoff) error {
+
+	// The deleted secret is not found until its deletion completes
+	err := backoff.Retry(ctx, "read deleted secret", func() error {
+		_, err := client.GetDeletedSecret(ctx, name, nil)
+		if err != nil && !IsNotFound(err) {
+			return permanent(wrapResponseError(err))
+		}
+		return wrapResponseError(err)
+	})
+	if IsNotFound(err) {
+		return fmt.Errorf("the deletion of secret %s did not complete within %s: %w", name, backoff.MaxElapsedTime, err)
+	}
+	return err
+}
+
+// ErrPurgeProtected is returned by PurgeSecret when the vault has purge protection enabled, so that
+// deleted secrets are only purged once their retention period has passed.
+var ErrPurgeProtected = errors.New("the vault has purge protection enabled, so the deleted secret is kept until its retention period has passed")
+
+// PurgeSecret permanently removes the deleted secret name, waiting for its deletion to complete with
+// DefaultDeletionBackoff. It returns an error wrapping ErrPurgeProtected when the vault has purge protection enabled.
+func PurgeSecret(ctx context.Context, client *azsecrets.Client, name string) error {
+	return PurgeSecretWithBackoff(ctx, client, name, DefaultDeletionBackoff)
+}
+
+// PurgeSecretWithBackoff is PurgeSecret, waiting for the deletion to complete according to backoff.
+func PurgeSecretWithBackoff(ctx context.Context, client *azsecrets.Client, name string, backoff Backoff) error {
+
+	err := WaitForDeletionWithBackoff(ctx, client, name, backoff)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PurgeDeletedSecret(ctx, name, nil)
+	if isPurgeProtected(err) {
+		return fmt.Errorf("%w: %w", ErrPurgeProtected, wrapResponseError(err))
+	}
+	return wrapResponseError(err)
+}
+
+// isPurgeProtected returns whether err is a response of a vault that refuses to purge, because purge
+// protection is enabled.
+func isPurgeProtected(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden &&
+		strings.Contains(strings.ToLower(err.Error()), "purge protection")
+}
+
+func setSecretParameters(value string, properties SecretProperties) azsecrets.SetSecretParameters {
+	return azsecrets.SetSecretParameters{
+		Value:       &value,
+		Tags:        toTags(properties.Tags),
+		ContentType: toContentType(properties.ContentType),
+		SecretAttributes: &azsecrets.SecretAttributes{
+			Enabled:   properties.Enabled,
+			Expires:   properties.Expires,
+			NotBefore: properties.NotBefore,
+		},
+	}
+}
+
+// toClearableTime returns t, or an explicit null that removes the time from a secret when t is nil.
+func toClearableTime(t *time.Time) *time.Time {
+	if t == nil {
+		return azcore.NullValue[*time.Time]()
+	}
+	return t
+}
+
+func toContentType(contentType string) *string {
+	if contentType == "" {
+		return nil
+	}
+	return &contentType
+}
+
+func toTags(tags map[string]string) map[string]*string {
+	result := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		v := v
+		result[k] = &v
+	}
+	return result
+}
+
+func fromSecret(tags map[string]*string, contentType *string, attributes *azsecrets.SecretAttributes) SecretProperties {
+	properties := SecretProperties{
+		Tags: fromTags(tags),
+	}
+	if contentType != nil {
+		properties.ContentType = *contentType
+	}
+	if attributes != nil {
+		properties.Enabled = attributes.Enabled
+		properties.Expires = attributes.Expires
+		properties.NotBefore = attributes.NotBefore
+		properties.Created = attributes.Created
+		properties.Updated = attributes.Updated
+	}
+	return properties
+}
+
+func fromTags(tags map[string]*string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if v != nil {
+			result[k] = *v
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// ConcurrencyLimiter bounds the number of requests in flight to the vaults of the clients it is shared by,
+// however many operations Terraform runs in parallel, so that they stay below the transaction limits of Key
+// Vault. Requests waiting for a slot are served in the order they started waiting.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter allowing up to maxConcurrentRequests requests in flight, which must
+// be positive.
+func NewConcurrencyLimiter(maxConcurrentRequests int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{slots: make(chan struct{}, maxConcurrentRequests)}
+}
+
+// acquire waits for a free slot, or returns the error of ctx when it is done first.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a request that acquired one.
+func (l *ConcurrencyLimiter) release() {
+	<-l.slots
+}
+
+// concurrencyLimitPolicy holds a slot of limiter while each try of a request is in flight. Retries give up their
+// slot while they wait, so that the other requests are not held up by the backoff of a failing one.
+type concurrencyLimitPolicy struct {
+	limiter *ConcurrencyLimiter
+}
+
+func (p concurrencyLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if err := p.limiter.acquire(req.Raw().Context()); err != nil {
+		return nil, err
+	}
+	defer p.limiter.release()
+
+	// The body of the response has been read by the pipeline by the time it returns
+	return req.Next()
+}
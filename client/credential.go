@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	// Enables the persistent token cache of azidentity
+	_ "github.com/Azure/azure-sdk-for-go/sdk/azidentity/cache"
+)
+
+// ErrIncompleteServicePrincipal is returned by CreateCredential when a service principal is given without
+// its tenant ID, client ID and exactly one of a client secret and a client certificate.
+var ErrIncompleteServicePrincipal = errors.New("the tenant ID, client ID and exactly one of a client secret and a client " +
+	"certificate of the service principal must be set")
+
+// ErrConflictingCredentials is returned by CreateCredential when an explicit service principal is combined
+// with disabled credentials of the DefaultAzureCredential chain, which is not used in that case.
+var ErrConflictingCredentials = errors.New("an explicit service principal cannot be combined with disabled credentials of the DefaultAzureCredential chain")
+
+// ErrConflictingManagedIdentity is returned by CreateCredential when a user-assigned managed identity is
+// selected by both its client ID and its resource ID.
+var ErrConflictingManagedIdentity = errors.New("a user-assigned managed identity can be selected by either its client ID or its resource ID, not both")
+
+// ErrConflictingAuthMethod is returned by CreateCredential when an auth method is combined with disabled
+// credentials of the DefaultAzureCredential chain or with a service principal.
+var ErrConflictingAuthMethod = errors.New("an auth method cannot be combined with disabled credentials or a service principal")
+
+// ErrTokenCacheUnavailable is returned by CheckTokenCache when tokens cannot be persisted on this platform,
+// e.g. because no keyring is available to encrypt them.
+var ErrTokenCacheUnavailable = errors.New("the persistent token cache is unavailable")
+
+// AuthMethod pins the credential used to authenticate with the vault.
+type AuthMethod string
+
+const (
+	AuthMethodDefaultChain     AuthMethod = "default_chain"
+	AuthMethodCLI              AuthMethod = "cli"
+	AuthMethodManagedIdentity  AuthMethod = "managed_identity"
+	AuthMethodWorkloadIdentity AuthMethod = "workload_identity"
+	AuthMethodEnvironment      AuthMethod = "environment"
+	AuthMethodDeveloperCLI     AuthMethod = "developer_cli"
+)
+
+func (m AuthMethod) String() string {
+	return string(m)
+}
+
+// AuthMethods returns the names of the supported auth methods.
+func AuthMethods() []string {
+	return []string{
+		AuthMethodDefaultChain.String(),
+		AuthMethodCLI.String(),
+		AuthMethodManagedIdentity.String(),
+		AuthMethodWorkloadIdentity.String(),
+		AuthMethodEnvironment.String(),
+		AuthMethodDeveloperCLI.String(),
+	}
+}
+
+// InteractiveAuth selects how a user signs in interactively when no other credential can authenticate.
+type InteractiveAuth string
+
+const (
+	InteractiveAuthBrowser    InteractiveAuth = "browser"
+	InteractiveAuthDeviceCode InteractiveAuth = "device_code"
+)
+
+// ServicePrincipal holds the credentials of a service principal, which authenticates with either a client
+// secret or a client certificate.
+type ServicePrincipal struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// Certificates and PrivateKey are the client certificate, as returned by LoadClientCertificate.
+	Certificates []*x509.Certificate
+	PrivateKey   crypto.PrivateKey
+}
+
+// isSet returns whether any of the credentials of the service principal are given.
+func (s ServicePrincipal) isSet() bool {
+	return s.TenantID != "" || s.ClientID != "" || s.ClientSecret != "" || len(s.Certificates) > 0
+}
+
+// isComplete returns whether the service principal has all it needs to authenticate.
+func (s ServicePrincipal) isComplete() bool {
+	return s.TenantID != "" && s.ClientID != "" && (s.ClientSecret != "") != (len(s.Certificates) > 0)
+}
+
+// CheckTokenCache returns ErrTokenCacheUnavailable when the persistent token cache with the given name cannot
+// be opened on this platform.
+func CheckTokenCache(name string) error {
+	if err := azidentity.CheckTokenCachePersistence(tokenCachePersistenceOptions(name)); err != nil {
+		return fmt.Errorf("%w: %w", ErrTokenCacheUnavailable, err)
+	}
+	return nil
+}
+
+// tokenCachePersistenceOptions returns the options of the persistent token cache with the given name, or nil
+// when name is empty, which keeps tokens in memory only.
+func tokenCachePersistenceOptions(name string) *azidentity.TokenCachePersistenceOptions {
+	if name == "" {
+		return nil
+	}
+	return &azidentity.TokenCachePersistenceOptions{Name: name}
+}
+
+// LoadClientCertificate reads the certificates and the private key of a client certificate from the PEM
+// or PFX file at path. password decrypts a PFX file, and must be empty for a PEM file.
+func LoadClientCertificate(path string, password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read client certificate: %w", err)
+	}
+
+	var passwordBytes []byte
+	if password != "" {
+		passwordBytes = []byte(password)
+	}
+	certificates, key, err := azidentity.ParseCertificates(data, passwordBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse client certificate %s: %w", path, err)
+	}
+	return certificates, key, nil
+}
+
+// CredentialOptions selects the credential used to authenticate with the vault.
+type CredentialOptions struct {
+	// AuthMethod builds only the given credential instead of the DefaultAzureCredential chain, unless it is
+	// AuthMethodDefaultChain or empty.
+	AuthMethod AuthMethod
+	// ServicePrincipal authenticates as a service principal instead of the DefaultAzureCredential chain.
+	ServicePrincipal ServicePrincipal
+	// DisabledCredentials are left out of the DefaultAzureCredential chain.
+	DisabledCredentials azidentity.DisabledCredentials
+	// ManagedIdentityClientID or ManagedIdentityResourceID select the user-assigned identity of the managed
+	// identity credential in the DefaultAzureCredential chain.
+	ManagedIdentityClientID   string
+	ManagedIdentityResourceID string
+	// Cloud holds the authority host that tokens are requested from. It defaults to the public cloud.
+	Cloud cloud.Configuration
+	// AdditionallyAllowedTenants are the tenants besides the home tenant of the identity that tokens may be
+	// requested for, "*" allowing any tenant.
+	AdditionallyAllowedTenants []string
+	// DisableInstanceDiscovery skips requesting the metadata of the authority, which is needed for
+	// authorities that do not serve it, such as ADFS and private clouds.
+	DisableInstanceDiscovery bool
+	// InteractiveAuth appends a credential signing in a user interactively, which is tried when the
+	// selected credential cannot authenticate.
+	InteractiveAuth InteractiveAuth
+	// DeviceCodePrompt shows the user where to enter the device code, and must not block. It is required
+	// for InteractiveAuthDeviceCode.
+	DeviceCodePrompt func(ctx context.Context, message string)
+	// TokenCacheName persists the tokens of the service principal and interactive credentials in the
+	// persistent token cache with this name, so that they are reused across runs. The other credentials
+	// rely on the cache of their own tooling. Tokens are kept in memory only when it is empty, and callers
+	// should check that the cache is available with CheckTokenCache first.
+	TokenCacheName string
+	// Transport sends the requests of the credentials, e.g. through a proxy given by ProxyTransport. The
+	// default transport of azcore is used when it is nil.
+	Transport policy.Transporter
+	// UserAgent identifies the provider and the partner in the User-Agent of the token requests.
+	UserAgent UserAgent
+	// UseFakeCredential returns a FakeCredential instead of any other credential, for emulators of Key Vault.
+	UseFakeCredential bool
+}
+
+// ManagedIdentityID returns the user-assigned identity selected by the options, or nil when neither its
+// client ID nor its resource ID is given.
+func (o CredentialOptions) ManagedIdentityID() azidentity.ManagedIDKind {
+	switch {
+	case o.ManagedIdentityClientID != "":
+		return azidentity.ClientID(o.ManagedIdentityClientID)
+	case o.ManagedIdentityResourceID != "":
+		return azidentity.ResourceID(o.ManagedIdentityResourceID)
+	default:
+		return nil
+	}
+}
+
+// anyDisabled returns whether any credential of the DefaultAzureCredential chain is disabled.
+func (o CredentialOptions) anyDisabled() bool {
+	d := o.DisabledCredentials
+	return d.ManagedIdentityCredential || d.WorkloadIdentityCredential || d.AzureCLICredential ||
+		d.AzureDeveloperCLICredential || d.EnvironmentCredential
+}
+
+// CreateCredential returns the single credential of the auth method when one is given, a ClientSecretCredential
+// or a ClientCertificateCredential when a service principal is given, and the DefaultAzureCredential chain
+// without the disabled credentials otherwise. It returns ErrConflictingAuthMethod, ErrIncompleteServicePrincipal,
+// ErrConflictingCredentials or ErrConflictingManagedIdentity when the options are inconsistent.
+//
+// An interactive credential is tried after the selected credential when InteractiveAuth is set.
+func CreateCredential(options CredentialOptions) (azcore.TokenCredential, error) {
+	if options.UseFakeCredential {
+		return FakeCredential{}, nil
+	}
+
+	clientOptions := azcore.ClientOptions{Cloud: options.Cloud, Transport: options.Transport}
+	options.UserAgent.apply(&clientOptions)
+
+	credential, err := createCredential(options, clientOptions)
+	if err != nil || options.InteractiveAuth == "" {
+		return credential, err
+	}
+
+	interactive, err := createInteractiveCredential(options, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	return azidentity.NewChainedTokenCredential([]azcore.TokenCredential{credential, interactive}, nil)
+}
+
+// createInteractiveCredential returns the credential signing in a user interactively.
+func createInteractiveCredential(options CredentialOptions, clientOptions azcore.ClientOptions) (azcore.TokenCredential, error) {
+	switch options.InteractiveAuth {
+	case InteractiveAuthBrowser:
+		return azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
+			ClientOptions:                clientOptions,
+			AdditionallyAllowedTenants:   options.AdditionallyAllowedTenants,
+			DisableInstanceDiscovery:     options.DisableInstanceDiscovery,
+			TokenCachePersistenceOptions: tokenCachePersistenceOptions(options.TokenCacheName),
+		})
+	case InteractiveAuthDeviceCode:
+		if options.DeviceCodePrompt == nil {
+			return nil, errors.New("a device code prompt is required to sign in with a device code")
+		}
+		return azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+			ClientOptions:                clientOptions,
+			AdditionallyAllowedTenants:   options.AdditionallyAllowedTenants,
+			DisableInstanceDiscovery:     options.DisableInstanceDiscovery,
+			TokenCachePersistenceOptions: tokenCachePersistenceOptions(options.TokenCacheName),
+			UserPrompt: func(ctx context.Context, message azidentity.DeviceCodeMessage) error {
+				options.DeviceCodePrompt(ctx, message.Message)
+				return nil
+			},
+		})
+	default:
+		return nil, fmt.Errorf("unsupported interactive auth %q", options.InteractiveAuth)
+	}
+}
+
+// createCredential returns the credential selected by options, without the interactive credential.
+func createCredential(options CredentialOptions, clientOptions azcore.ClientOptions) (azcore.TokenCredential, error) {
+
+	servicePrincipal := options.ServicePrincipal
+	if options.AuthMethod != "" && (servicePrincipal.isSet() || options.anyDisabled()) {
+		return nil, ErrConflictingAuthMethod
+	}
+	if options.ManagedIdentityClientID != "" && options.ManagedIdentityResourceID != "" {
+		return nil, ErrConflictingManagedIdentity
+	}
+
+	switch options.AuthMethod {
+	case "", AuthMethodDefaultChain:
+	case AuthMethodCLI:
+		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{
+			AdditionallyAllowedTenants: options.AdditionallyAllowedTenants,
+		})
+	case AuthMethodDeveloperCLI:
+		return azidentity.NewAzureDeveloperCLICredential(&azidentity.AzureDeveloperCLICredentialOptions{
+			AdditionallyAllowedTenants: options.AdditionallyAllowedTenants,
+		})
+	case AuthMethodManagedIdentity:
+		return azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ID:            options.ManagedIdentityID(),
+		})
+	case AuthMethodWorkloadIdentity:
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions:              clientOptions,
+			AdditionallyAllowedTenants: options.AdditionallyAllowedTenants,
+			DisableInstanceDiscovery:   options.DisableInstanceDiscovery,
+		})
+	case AuthMethodEnvironment:
+		return azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{
+			ClientOptions:            clientOptions,
+			DisableInstanceDiscovery: options.DisableInstanceDiscovery,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported auth method %q, expected one of %s", options.AuthMethod, strings.Join(AuthMethods(), ", "))
+	}
+
+	if servicePrincipal.isSet() {
+		if !servicePrincipal.isComplete() {
+			return nil, ErrIncompleteServicePrincipal
+		}
+		if options.anyDisabled() {
+			return nil, ErrConflictingCredentials
+		}
+
+		if len(servicePrincipal.Certificates) > 0 {
+			return azidentity.NewClientCertificateCredential(
+				servicePrincipal.TenantID,
+				servicePrincipal.ClientID,
+				servicePrincipal.Certificates,
+				servicePrincipal.PrivateKey,
+				&azidentity.ClientCertificateCredentialOptions{
+					ClientOptions:                clientOptions,
+					AdditionallyAllowedTenants:   options.AdditionallyAllowedTenants,
+					DisableInstanceDiscovery:     options.DisableInstanceDiscovery,
+					TokenCachePersistenceOptions: tokenCachePersistenceOptions(options.TokenCacheName),
+				},
+			)
+		}
+		return azidentity.NewClientSecretCredential(
+			servicePrincipal.TenantID,
+			servicePrincipal.ClientID,
+			servicePrincipal.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{
+				ClientOptions:                clientOptions,
+				AdditionallyAllowedTenants:   options.AdditionallyAllowedTenants,
+				DisableInstanceDiscovery:     options.DisableInstanceDiscovery,
+				TokenCachePersistenceOptions: tokenCachePersistenceOptions(options.TokenCacheName),
+			},
+		)
+	}
+
+	credentialOptions := azidentity.DefaultAzureCredentialOptions{
+		ClientOptions:              clientOptions,
+		AdditionallyAllowedTenants: options.AdditionallyAllowedTenants,
+		DisableInstanceDiscovery:   options.DisableInstanceDiscovery,
+		ManagedIdentityID:          options.ManagedIdentityID(),
+	}
+
+	// Create a new DefaultAzureCredential
+	credential, err := azidentity.NewCustomDefaultAzureCredential(&credentialOptions, options.DisabledCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
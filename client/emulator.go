@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// fakeToken is the token of FakeCredential.
+const fakeToken = "azrandom-fake-token"
+
+// FakeCredential returns the same static token for every scope without requesting it from Microsoft Entra ID.
+// Emulators of Key Vault, such as Lowkey Vault, accept it, whereas a vault in Azure rejects it.
+type FakeCredential struct{}
+
+func (FakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: fakeToken, ExpiresOn: time.Now().Add(24 * time.Hour)}, nil
+}
+
+// InsecureTransport returns transport, or the default transport of azcore when it is nil, without verification
+// of the TLS certificates of the servers it connects to, e.g. the self-signed certificate of an emulator.
+// Transports other than those of ProxyTransport are not supported.
+func InsecureTransport(transport policy.Transporter) (policy.Transporter, error) {
+	var base *http.Transport
+	switch t := transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Client:
+		httpTransport, ok := t.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("cannot skip the verification of TLS certificates for a client with a %T", t.Transport)
+		}
+		base = httpTransport.Clone()
+	default:
+		return nil, fmt.Errorf("cannot skip the verification of TLS certificates for a %T", transport)
+	}
+
+	if base.TLSClientConfig == nil {
+		base.TLSClientConfig = &tls.Config{}
+	}
+	base.TLSClientConfig.InsecureSkipVerify = true
+	return &http.Client{Transport: base}, nil
+}
@@ -56,6 +56,14 @@ var (
 // TokenCachePersistenceOptions contains options for persistent token caching
 type TokenCachePersistenceOptions = internal.TokenCachePersistenceOptions
 
+// CheckTokenCachePersistence opens the persistent token cache described by options, returning an error when
+// persistent caching is unavailable on this platform. Credentials only open their cache on the first token
+// request, so this allows callers to detect an unavailable cache before authenticating.
+func CheckTokenCachePersistence(options *TokenCachePersistenceOptions) error {
+	_, err := internal.NewCache(options, false)
+	return err
+}
+
 // setAuthorityHost initializes the authority host for credentials. Precedence is:
 //  1. cloud.Configuration.ActiveDirectoryAuthorityHost value set by user
 //  2. value of AZURE_AUTHORITY_HOST
@@ -34,6 +34,9 @@ type DefaultAzureCredentialOptions struct {
 	DisableInstanceDiscovery bool
 	// TenantID sets the default tenant for authentication via the Azure CLI and workload identity.
 	TenantID string
+	// ManagedIdentityID selects the user-assigned identity of the managed identity credential. It defaults to
+	// the client ID in the environment variable AZURE_CLIENT_ID, or the system-assigned identity when unset.
+	ManagedIdentityID ManagedIDKind
 }
 
 type DisabledCredentials struct {
@@ -64,8 +67,6 @@ type DefaultAzureCredential struct {
 	chain *ChainedTokenCredential
 }
 
-
-
 // NewDefaultAzureCredential creates a DefaultAzureCredential. Pass nil for options to accept defaults.
 func NewCustomDefaultAzureCredential(options *DefaultAzureCredentialOptions, disabledCredentials DisabledCredentials) (*DefaultAzureCredential, error) {
 	var creds []azcore.TokenCredential
@@ -112,7 +113,9 @@ func NewCustomDefaultAzureCredential(options *DefaultAzureCredentialOptions, dis
 
 	if !disabledCredentials.ManagedIdentityCredential {
 		o := &ManagedIdentityCredentialOptions{ClientOptions: options.ClientOptions, dac: true}
-		if ID, ok := os.LookupEnv(azureClientID); ok {
+		if options.ManagedIdentityID != nil {
+			o.ID = options.ManagedIdentityID
+		} else if ID, ok := os.LookupEnv(azureClientID); ok {
 			o.ID = ClientID(ID)
 		}
 		miCred, err := NewManagedIdentityCredential(o)
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// ProxyTransport returns a transport that sends requests through the HTTP, HTTPS or SOCKS5 proxy at proxyUrl,
+// authenticating with username and password when username is set. They take precedence over the user info
+// of proxyUrl. Requests to loopback and link-local hosts, such as the managed identity endpoints, are not
+// proxied, as the proxy cannot reach them.
+func ProxyTransport(proxyUrl string, username string, password string) (policy.Transporter, error) {
+	u, err := url.Parse(proxyUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("the proxy URL has no host")
+	}
+	if username != "" {
+		u.User = url.UserPassword(username, password)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if isLocalHost(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return u, nil
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// isLocalHost returns whether host is localhost, or a loopback or link-local address.
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast())
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// requestIDHeader holds the ID that the vault assigns to each request, which Azure support asks for to trace it.
+const requestIDHeader = "x-ms-request-id"
+
+// ResponseError is returned by the operations on secrets when the vault answered with an error response. It
+// carries the request ID, HTTP status code and error code of that response, so that they can be reported
+// along with the error. It wraps the error of the operation, which wraps the *azcore.ResponseError.
+type ResponseError struct {
+	RequestID  string
+	StatusCode int
+	ErrorCode  string
+	Err        error
+}
+
+func (e *ResponseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// AsResponseError returns the *ResponseError that err wraps, if any.
+func AsResponseError(err error) (*ResponseError, bool) {
+	var wrapped *ResponseError
+	if errors.As(err, &wrapped) {
+		return wrapped, true
+	}
+	return nil, false
+}
+
+// wrapResponseError returns err as a *ResponseError when it wraps an error response of the vault and is not
+// wrapped already, wrapping a throttled response that the retry policy gave up on in a *ThrottledError first.
+// Other errors, including nil, are returned as they are.
+func wrapResponseError(err error) error {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return err
+	}
+	if _, ok := AsResponseError(err); ok {
+		return err
+	}
+
+	var throttledErr *ThrottledError
+	if IsThrottled(err) && !errors.As(err, &throttledErr) {
+		err = &ThrottledError{Err: err}
+	}
+
+	wrapped := &ResponseError{
+		StatusCode: responseErr.StatusCode,
+		ErrorCode:  responseErr.ErrorCode,
+		Err:        err,
+	}
+	if responseErr.RawResponse != nil {
+		wrapped.RequestID = responseErr.RawResponse.Header.Get(requestIDHeader)
+	}
+	return wrapped
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"regexp"
+	"sync/atomic"
+
+	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// SDKLogLevel selects the events of the Azure SDK that are forwarded to the log of Terraform.
+type SDKLogLevel string
+
+const (
+	// SDKLogLevelOff forwards no events.
+	SDKLogLevelOff SDKLogLevel = "off"
+	// SDKLogLevelDebug forwards the token requests of the credentials, the retries of failed requests and
+	// their errors, at the DEBUG level.
+	SDKLogLevelDebug SDKLogLevel = "debug"
+	// SDKLogLevelTrace forwards every request to the vaults and its response as well, at the TRACE level.
+	SDKLogLevelTrace SDKLogLevel = "trace"
+)
+
+func (l SDKLogLevel) String() string {
+	return string(l)
+}
+
+// SDKLogLevels returns the supported SDK log levels.
+func SDKLogLevels() []string {
+	return []string{SDKLogLevelOff.String(), SDKLogLevelDebug.String(), SDKLogLevelTrace.String()}
+}
+
+// sdkLogEvents are the events that are forwarded, by the least verbose level that forwards them. Other events,
+// such as those of long-running operations, which the secrets API does not have, are dropped.
+var sdkLogEvents = map[azlog.Event]SDKLogLevel{
+	azidentity.EventAuthentication: SDKLogLevelDebug,
+	azlog.EventRetryPolicy:         SDKLogLevelDebug,
+	azlog.EventResponseError:       SDKLogLevelDebug,
+	azlog.EventRequest:             SDKLogLevelTrace,
+	azlog.EventResponse:            SDKLogLevelTrace,
+}
+
+// sdkLog is where the events of the Azure SDK are forwarded to.
+type sdkLog struct {
+	ctx   context.Context
+	level SDKLogLevel
+}
+
+// currentSDKLog is set by SetSDKLog. The events of the Azure SDK are dropped until it is set.
+var currentSDKLog atomic.Pointer[sdkLog]
+
+// The listener of the Azure SDK cannot be set safely while its clients are in use, so it is set once, and
+// forwards the events to where SetSDKLog last pointed it.
+func init() {
+	events := make([]azlog.Event, 0, len(sdkLogEvents))
+	for event := range sdkLogEvents {
+		events = append(events, event)
+	}
+	azlog.SetEvents(events...)
+	azlog.SetListener(forwardSDKLog)
+}
+
+// SetSDKLog forwards the events of the Azure SDK selected by level to the logger of ctx, redacted by
+// RedactSDKLogMessage. The events of all clients are forwarded to the logger of the last call, as the
+// listener of the Azure SDK is global to the process.
+func SetSDKLog(ctx context.Context, level SDKLogLevel) {
+	currentSDKLog.Store(&sdkLog{ctx: ctx, level: level})
+}
+
+// forwardSDKLog is the listener of the Azure SDK.
+func forwardSDKLog(event azlog.Event, message string) {
+	log := currentSDKLog.Load()
+	if log == nil {
+		return
+	}
+	eventLevel, ok := sdkLogEvents[event]
+	if !ok {
+		return
+	}
+
+	fields := map[string]any{"azure_sdk_event": string(event)}
+	message = RedactSDKLogMessage(message)
+	switch {
+	case eventLevel == SDKLogLevelDebug && log.level != SDKLogLevelOff:
+		tflog.Debug(log.ctx, message, fields)
+	case eventLevel == SDKLogLevelTrace && log.level == SDKLogLevelTrace:
+		tflog.Trace(log.ctx, message, fields)
+	}
+}
+
+var (
+	// sdkLogAuthorizationHeader matches the Authorization header of a logged request, which the Azure SDK
+	// already redacts.
+	sdkLogAuthorizationHeader = regexp.MustCompile(`(?im)^(\s*(?:proxy-)?authorization:\s*).*$`)
+	// sdkLogBearerToken matches a bearer token, but not the parameters of a WWW-Authenticate challenge.
+	sdkLogBearerToken = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-_.~+/]{16,}=*`)
+	// sdkLogJSONSecret matches the value of a secret, or a token, in a JSON body.
+	sdkLogJSONSecret = regexp.MustCompile(`("(?:value|access_token|refresh_token|id_token|client_secret)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// sdkLogFormSecret matches a secret in a URL-encoded body or query.
+	sdkLogFormSecret = regexp.MustCompile(`((?:^|[?&\s])(?:client_secret|client_assertion|password|refresh_token|access_token)=)[^&\s]*`)
+)
+
+// RedactSDKLogMessage replaces the Authorization headers, bearer tokens, secret values and client secrets in
+// message, an event of the Azure SDK, by REDACTED.
+func RedactSDKLogMessage(message string) string {
+	message = sdkLogAuthorizationHeader.ReplaceAllString(message, "${1}REDACTED")
+	message = sdkLogBearerToken.ReplaceAllString(message, "${1}REDACTED")
+	message = sdkLogJSONSecret.ReplaceAllString(message, `${1}"REDACTED"`)
+	message = sdkLogFormSecret.ReplaceAllString(message, "${1}REDACTED")
+	return message
+}
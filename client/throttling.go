@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// retryStatusCodes are the status codes of the responses that the retry policy of the client retries, unless
+// ClientOptions.Retry sets its own. They are the defaults of azcore, which include HTTP 429 so that throttled
+// requests are retried as long as their Retry-After header asks to, up to the MaxRetryDelay of the policy.
+var retryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ThrottledError is returned when the vault still throttled a request after the retries of the retry policy.
+type ThrottledError struct {
+	Err error
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("vault throttled: the request was still rejected with HTTP 429 Too Many Requests after it was "+
+		"retried. Key Vault limits the number of requests per vault, so reduce the number of concurrent operations "+
+		"(e.g. with `terraform apply -parallelism=n`), raise max_retries of the retry block, or try again later: %s", e.Err)
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// IsThrottled returns whether err is a response of the vault that rejected the request with HTTP 429.
+func IsThrottled(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// OperationTimeoutError is returned when an operation on a secret did not complete within the operation
+// timeout of the client, including the retries of its requests.
+type OperationTimeoutError struct {
+	Operation string
+	Name      string
+	Timeout   time.Duration
+	Err       error
+}
+
+func (e *OperationTimeoutError) Error() string {
+	return fmt.Sprintf("%s %s timed out after %s. The vault did not respond in time, e.g. because a firewall of the vault "+
+		"or of the network drops the requests of this host: check that the vault can be reached, or raise "+
+		"operation_timeout: %s", e.Operation, e.Name, e.Timeout, e.Err)
+}
+
+func (e *OperationTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsOperationTimeout returns whether err is an *OperationTimeoutError.
+func IsOperationTimeout(err error) bool {
+	var timeoutErr *OperationTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// operationTimeoutPolicy bounds each call of the client of the vault, including the retries of its
+// requests, by timeout. Calls whose context is done for another reason, e.g. because Terraform was
+// interrupted, are not reported as timed out.
+type operationTimeoutPolicy struct {
+	timeout time.Duration
+}
+
+func (p operationTimeoutPolicy) Do(req *policy.Request) (*http.Response, error) {
+	parent := req.Raw().Context()
+	ctx, cancel := context.WithTimeout(parent, p.timeout)
+	defer cancel()
+
+	// The body of the response has been read by the pipeline by the time it returns
+	resp, err := req.WithContext(ctx).Next()
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+		operation, name := vaultOperation(req.Raw())
+		return resp, &OperationTimeoutError{Operation: operation, Name: name, Timeout: p.timeout, Err: err}
+	}
+	return resp, err
+}
+
+// vaultOperation returns the operation of the vault that req performs, and the name of the secret it
+// performs it on.
+func vaultOperation(req *http.Request) (string, string) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	name := ""
+	if len(segments) > 1 {
+		name = segments[1]
+	}
+
+	switch {
+	case segments[0] == "secrets" && len(segments) > 2 && segments[2] == "versions":
+		return "listing the versions of secret", name
+	case segments[0] == "secrets" && req.Method == http.MethodGet:
+		return "getting secret", name
+	case segments[0] == "secrets" && req.Method == http.MethodPut:
+		return "setting secret", name
+	case segments[0] == "secrets" && req.Method == http.MethodPatch:
+		return "updating the properties of secret", name
+	case segments[0] == "secrets" && req.Method == http.MethodDelete:
+		return "deleting secret", name
+	case segments[0] == "deletedsecrets" && req.Method == http.MethodGet:
+		return "getting deleted secret", name
+	case segments[0] == "deletedsecrets" && req.Method == http.MethodPost:
+		return "recovering deleted secret", name
+	case segments[0] == "deletedsecrets" && req.Method == http.MethodDelete:
+		return "purging deleted secret", name
+	}
+	return req.Method + " " + req.URL.Path, name
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// TerraformPartnerID is the partner ID of Terraform, which attributes requests to Terraform when no other
+// partner ID is given.
+const TerraformPartnerID = "222c6c49-1b0a-5959-a213-6608f9eb8820"
+
+// UserAgent identifies the provider and the partner that requests are made for in their User-Agent.
+type UserAgent struct {
+	// ProviderVersion is the version of the provider, which is sent as azrandom/<version>. Nothing is sent
+	// when it is empty.
+	ProviderVersion string
+	// PartnerID is the GUID that attributes requests to a partner, which is sent as pid-<guid>. Nothing is
+	// sent when it is empty.
+	PartnerID string
+}
+
+// apply adds the provider and the partner to the User-Agent of the requests sent with options. azcore cuts
+// the application ID of its telemetry to 24 characters, which the partner ID would not fit in, so it is
+// appended by a policy instead.
+func (u UserAgent) apply(options *azcore.ClientOptions) {
+	if u.ProviderVersion != "" {
+		options.Telemetry.ApplicationID = "azrandom/" + u.ProviderVersion
+	}
+	if u.PartnerID != "" {
+		options.PerCallPolicies = append(options.PerCallPolicies, userAgentPolicy{suffix: "pid-" + u.PartnerID})
+	}
+}
+
+// userAgentPolicy appends suffix to the User-Agent set by the telemetry policy of azcore.
+type userAgentPolicy struct {
+	suffix string
+}
+
+func (p userAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
+	header := req.Raw().Header
+	if userAgent := header.Get("User-Agent"); userAgent != "" {
+		header.Set("User-Agent", userAgent+" "+p.suffix)
+	} else {
+		header.Set("User-Agent", p.suffix)
+	}
+	return req.Next()
+}
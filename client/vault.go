@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+
+package azrandom
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Environment identifies the Azure cloud that hosts the vault.
+type Environment string
+
+const (
+	EnvironmentPublic       Environment = "public"
+	EnvironmentUSGovernment Environment = "usgovernment"
+	EnvironmentChina        Environment = "china"
+)
+
+func (e Environment) String() string {
+	return string(e)
+}
+
+// keyVaultDNSSuffixes holds the DNS suffix of the vaults in each environment.
+var keyVaultDNSSuffixes = map[Environment]string{
+	EnvironmentPublic:       "vault.azure.net",
+	EnvironmentUSGovernment: "vault.usgovcloudapi.net",
+	EnvironmentChina:        "vault.azure.cn",
+}
+
+// managedHSMDNSSuffixes holds the DNS suffix of the Managed HSM pools in each environment.
+var managedHSMDNSSuffixes = map[Environment]string{
+	EnvironmentPublic:       "managedhsm.azure.net",
+	EnvironmentUSGovernment: "managedhsm.usgovcloudapi.net",
+	EnvironmentChina:        "managedhsm.azure.cn",
+}
+
+// ErrManagedHSM is returned for the URL of a Managed HSM pool, which only stores keys: it has no secrets API
+// that the generated values could be stored in.
+var ErrManagedHSM = errors.New("secrets are not supported by Managed HSM pools, which only store keys, so the values " +
+	"generated by this provider cannot be stored in them: use a Key Vault instead")
+
+// cloudConfigurations holds the authority host and service endpoints of each environment.
+var cloudConfigurations = map[Environment]cloud.Configuration{
+	EnvironmentPublic:       cloud.AzurePublic,
+	EnvironmentUSGovernment: cloud.AzureGovernment,
+	EnvironmentChina:        cloud.AzureChina,
+}
+
+// Cloud returns the cloud configuration of the environment, authenticating with authorityHost instead of
+// the authority host of the environment when it is not empty.
+func (e Environment) Cloud(authorityHost string) cloud.Configuration {
+	configuration, ok := cloudConfigurations[e]
+	if !ok {
+		configuration = cloud.AzurePublic
+	}
+	if authorityHost != "" {
+		configuration.ActiveDirectoryAuthorityHost = authorityHost
+	}
+	return configuration
+}
+
+// Environments returns the names of the supported environments.
+func Environments() []string {
+	return []string{
+		EnvironmentPublic.String(),
+		EnvironmentUSGovernment.String(),
+		EnvironmentChina.String(),
+	}
+}
+
+// vaultNamePattern matches the names Azure accepts for a vault: 3 to 24 letters, digits and hyphens,
+// starting with a letter and ending with a letter or digit.
+var vaultNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{1,22}[a-zA-Z0-9]$`)
+
+// ParseVaultName returns the name of a vault given either its name or its ARM resource ID, such as
+// /subscriptions/<subscription>/resourceGroups/<group>/providers/Microsoft.KeyVault/vaults/<name>.
+func ParseVaultName(value string) (string, error) {
+	name := value
+	if strings.HasPrefix(value, "/") {
+		id, err := arm.ParseResourceID(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a valid ARM resource ID: %w", value, err)
+		}
+		if !strings.EqualFold(id.ResourceType.String(), "Microsoft.KeyVault/vaults") {
+			return "", fmt.Errorf("%q is the ID of a %s resource, expected a Microsoft.KeyVault/vaults resource", value, id.ResourceType.String())
+		}
+		name = id.Name
+	}
+
+	if !vaultNamePattern.MatchString(name) || strings.Contains(name, "--") {
+		return "", fmt.Errorf("%q is not a valid vault name, which must be 3 to 24 letters, digits and non-consecutive hyphens, "+
+			"starting with a letter and ending with a letter or digit", name)
+	}
+
+	return name, nil
+}
+
+// VaultURL returns the URL of the vault with the given name or ARM resource ID in environment.
+func VaultURL(vaultName string, environment Environment) (string, error) {
+	suffix, ok := keyVaultDNSSuffixes[environment]
+	if !ok {
+		return "", fmt.Errorf("unsupported environment %q, expected one of %s", environment, strings.Join(Environments(), ", "))
+	}
+
+	return VaultURLWithDNSSuffix(vaultName, suffix)
+}
+
+// dnsSuffixPattern matches DNS suffixes of at least two labels of letters, digits and hyphens, such as
+// vault.azure.net.
+var dnsSuffixPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)+$`)
+
+// ValidateDNSSuffix returns an error when dnsSuffix is not a DNS suffix such as vault.azure.net.
+func ValidateDNSSuffix(dnsSuffix string) error {
+	if !dnsSuffixPattern.MatchString(dnsSuffix) {
+		return fmt.Errorf("%q is not a valid DNS suffix, which must be dot-separated labels of letters, digits and "+
+			"hyphens such as vault.azure.net", dnsSuffix)
+	}
+	return nil
+}
+
+// VaultURLWithDNSSuffix returns the URL of the vault with the given name or ARM resource ID under the Key
+// Vault DNS suffix dnsSuffix, e.g. of a private cloud.
+func VaultURLWithDNSSuffix(vaultName string, dnsSuffix string) (string, error) {
+	name, err := ParseVaultName(vaultName)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateDNSSuffix(dnsSuffix); err != nil {
+		return "", err
+	}
+
+	return "https://" + strings.ToLower(name) + "." + strings.ToLower(dnsSuffix) + "/", nil
+}
+
+// VaultEnvironment returns the environment whose Key Vault DNS suffix ends the host of vaultUrl, and false
+// when the vault is outside of the known environments.
+func VaultEnvironment(vaultUrl string) (Environment, bool) {
+	host := vaultUrl
+	if u, err := url.Parse(vaultUrl); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	host = strings.ToLower(host)
+
+	for _, environment := range Environments() {
+		if strings.HasSuffix(host, "."+keyVaultDNSSuffixes[Environment(environment)]) {
+			return Environment(environment), true
+		}
+	}
+	return "", false
+}
+
+// ValidateVaultURL returns an error when vaultUrl is not the URL of a vault, which has a host ending with the
+// Key Vault DNS suffix of a known environment and no path, query or fragment. Its scheme is left to the caller.
+// The error wraps ErrManagedHSM for the URL of a Managed HSM pool.
+func ValidateVaultURL(vaultUrl string) error {
+	u, err := parseVaultURL(vaultUrl)
+	if err != nil {
+		return err
+	}
+	if IsManagedHSM(vaultUrl) {
+		return fmt.Errorf("%s is a Managed HSM pool: %w", u.Hostname(), ErrManagedHSM)
+	}
+	if _, known := VaultEnvironment(vaultUrl); !known {
+		suffixes := make([]string, 0, len(keyVaultDNSSuffixes))
+		for _, environment := range Environments() {
+			suffixes = append(suffixes, keyVaultDNSSuffixes[Environment(environment)])
+		}
+		return fmt.Errorf("the host %q does not end with the Key Vault DNS suffix of a known environment, one of %s",
+			u.Hostname(), strings.Join(suffixes, ", "))
+	}
+	return nil
+}
+
+// ValidateCustomEndpoint returns an error when endpoint is not the URL of a service implementing the secrets API
+// of Key Vault, such as an emulator. Unlike ValidateVaultURL, its host may have any DNS suffix and a port.
+func ValidateCustomEndpoint(endpoint string) error {
+	_, err := parseVaultURL(endpoint)
+	return err
+}
+
+// parseVaultURL parses vaultUrl, which must consist of a scheme and a host only.
+func parseVaultURL(vaultUrl string) (*url.URL, error) {
+	u, err := url.Parse(vaultUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("the URL has no host")
+	}
+	if strings.Trim(u.Path, "/") != "" {
+		return nil, fmt.Errorf("the URL has the path %q, but the URL of a vault ends with its host, such as "+
+			"https://my-vault.vault.azure.net/", u.Path)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return nil, errors.New("the URL of a vault cannot have user info, a query or a fragment")
+	}
+	return u, nil
+}
+
+// NormalizeVaultURL returns vaultUrl in lowercase and without trailing slashes, so that the URLs of a vault
+// with and without a trailing slash are the same.
+func NormalizeVaultURL(vaultUrl string) string {
+	return strings.ToLower(strings.TrimRight(vaultUrl, "/"))
+}
+
+// IsManagedHSM returns whether the host of vaultUrl ends with the Managed HSM DNS suffix of a known environment.
+func IsManagedHSM(vaultUrl string) bool {
+	host := vaultUrl
+	if u, err := url.Parse(vaultUrl); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	host = strings.ToLower(host)
+
+	for _, suffix := range managedHSMDNSSuffixes {
+		if strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// VaultScope returns the OAuth scope of the vault at vaultUrl, which depends on the environment that
+// hosts it. Vaults outside of the known environments are assumed to accept public cloud tokens.
+func VaultScope(vaultUrl string) string {
+	environment, ok := VaultEnvironment(vaultUrl)
+	if !ok {
+		environment = EnvironmentPublic
+	}
+	return "https://" + keyVaultDNSSuffixes[environment] + "/.default"
+}
@@ -4,13 +4,41 @@
 package diagnostics
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 )
 
 const RetryMsg = "Retry the Terraform operation. If the error still occurs or happens regularly, please contact the provider developer with hardware and operating system information.\n\n"
 
+// ErrorDetail returns the message of err, followed by the request ID, HTTP status and error code of the
+// response of the vault when err wraps an *azrandom.ResponseError, so that they can be copied as they are
+// into a support request.
+func ErrorDetail(err error) string {
+	responseErr, ok := azrandom.AsResponseError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	b.WriteString(err.Error())
+	b.WriteString("\n\nAzure Response:\n")
+	if responseErr.RequestID != "" {
+		fmt.Fprintf(&b, "  x-ms-request-id: %s\n", responseErr.RequestID)
+	}
+	fmt.Fprintf(&b, "  HTTP status:     %d %s\n", responseErr.StatusCode, http.StatusText(responseErr.StatusCode))
+	if responseErr.ErrorCode != "" {
+		fmt.Fprintf(&b, "  Error code:      %s\n", responseErr.ErrorCode)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 func RandomReadError(errMsg string) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -49,3 +77,110 @@ func RandomnessGenerationError(errMsg string) diag.Diagnostics {
 
 	return diags
 }
+
+// CredentialError classifies a failure to acquire a token for the vault, so that common causes are
+// reported with a specific remedy rather than the raw error of the credential chain.
+func CredentialError(errMsg string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	const skipMsg = "If the credential cannot be validated while configuring the provider, for example when planning offline, " +
+		"set skip_credential_validation to true.\n\n"
+
+	summary := "Authentication Failed"
+	var hint string
+	switch {
+	case strings.Contains(errMsg, "AADSTS700016"):
+		summary = "Application Not Found in Tenant"
+		hint = "The application (client) ID was not found in the tenant that the token was requested from (AADSTS700016). " +
+			"This usually means the tenant ID is wrong, for example AZURE_TENANT_ID refers to a different tenant than the " +
+			"one the application is registered in, or the application has not been consented to in this tenant.\n\n"
+	case strings.Contains(errMsg, "AADSTS700082"), strings.Contains(errMsg, "AADSTS50173"), strings.Contains(errMsg, "az login"):
+		summary = "Azure CLI Login Expired"
+		hint = "The Azure CLI login is missing or has expired. Run `az login` (optionally with --tenant) and retry.\n\n"
+	}
+
+	detail := "The provider could not acquire a token for the vault, so no secret could be read or written. " +
+		"This is an authentication failure: it happens before the vault checks whether the identity may access it.\n\n" + hint
+
+	if attempts := credentialChainAttempts(errMsg); len(attempts) > 0 {
+		if hint == "" {
+			summary = "No Credential in the Chain Could Authenticate"
+		}
+		detail += "Every credential in the DefaultAzureCredential chain was tried, and each failed:\n"
+		for _, attempt := range attempts {
+			detail += "  - " + attempt + "\n"
+		}
+		detail += "\nConfigure one of these credentials, or disable the ones that do not apply with the disable_*_credential attributes.\n\n"
+	}
+
+	diags.AddError(summary, detail+skipMsg+fmt.Sprintf("Original Error: %s", errMsg))
+
+	return diags
+}
+
+// credentialChainAttempts returns the failure of each credential listed in the error of a chained
+// credential, which puts each attempt on its own tab-indented line after "Attempted credentials:".
+func credentialChainAttempts(errMsg string) []string {
+	_, list, ok := strings.Cut(errMsg, "Attempted credentials:")
+	if !ok {
+		return nil
+	}
+
+	var attempts []string
+	for _, line := range strings.Split(list, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			attempts = append(attempts, strings.TrimSpace(line))
+			continue
+		}
+		// Continuation of a multi-line error of the previous credential
+		if line = strings.TrimSpace(line); line != "" && len(attempts) > 0 {
+			attempts[len(attempts)-1] += " " + line
+		}
+	}
+	return attempts
+}
+
+// AccessError classifies a failure to get a secret from the vault at vaultUrl while configuring the
+// provider, so that a vault that cannot be reached or refuses the identity is reported once with a specific
+// remedy, rather than by every resource.
+func AccessError(vaultUrl string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	const skipMsg = "To skip this check, e.g. when the vault is only reachable while applying, set validate_access to false.\n\n"
+
+	summary := "Vault Access Check Failed"
+	detail := "The provider could not get a secret from the vault " + vaultUrl + ".\n\n"
+
+	classification := azrandom.ClassifyError(err)
+	var responseErr *azcore.ResponseError
+	switch {
+	case errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusUnauthorized:
+		summary = "Vault Rejected the Token"
+		detail = "The vault " + vaultUrl + " rejected the token of the identity of the provider. This usually means the " +
+			"token was issued by another tenant than the one of the vault, e.g. because tenant_id or AZURE_TENANT_ID refers " +
+			"to another tenant, or for another cloud than the one of the vault.\n\n"
+	case classification.Class == azrandom.ErrorClassForbiddenByFirewall:
+		summary = "Vault Firewall Denied Access"
+		detail += firewallHint(classification.ClientAddress)
+	case classification.Class == azrandom.ErrorClassForbiddenByRBAC:
+		summary = "Vault Access Denied"
+		detail += rbacHint(classification.Action)
+	case classification.Class == azrandom.ErrorClassForbiddenByAccessPolicy:
+		summary = "Vault Access Denied"
+		detail += accessPolicyHint
+	case errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden:
+		summary = "Vault Access Denied"
+		detail = "The identity of the provider is not allowed to get secrets from the vault " + vaultUrl + ". Grant it the " +
+			"Key Vault Secrets Officer role on the vault when it uses Azure RBAC, or an access policy with the get, list, " +
+			"set, delete, recover and purge secret permissions when it uses access policies. Role assignments may take a " +
+			"few minutes to apply.\n\n"
+	case azrandom.IsOperationTimeout(err), !errors.As(err, &responseErr):
+		summary = "Vault Unreachable"
+		detail = "The provider could not reach the vault " + vaultUrl + ". Check that its URL is correct, that its DNS name " +
+			"resolves from this host, and that no firewall or proxy drops the requests to it.\n\n"
+	}
+
+	diags.AddError(summary, detail+skipMsg+"Original Error: "+ErrorDetail(err))
+
+	return diags
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Operation is the Terraform operation on a resource during which the vault returned an error.
+type Operation string
+
+const (
+	OperationCreate Operation = "Create"
+	OperationRead   Operation = "Read"
+	OperationUpdate Operation = "Update"
+	OperationDelete Operation = "Delete"
+	OperationImport Operation = "Import"
+)
+
+// CreateError reports that the secret secretName of a resource of type resourceType could not be created in
+// the vault at vaultUrl.
+func CreateError(resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	return VaultError(OperationCreate, "create the secret", resourceType, secretName, vaultUrl, err)
+}
+
+// ReadError reports that the secret secretName of a resource of type resourceType could not be read from the
+// vault at vaultUrl.
+func ReadError(resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	return VaultError(OperationRead, "read the secret", resourceType, secretName, vaultUrl, err)
+}
+
+// UpdateError reports that a new version of the secret secretName of a resource of type resourceType could
+// not be stored in the vault at vaultUrl.
+func UpdateError(resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	return VaultError(OperationUpdate, "update the secret", resourceType, secretName, vaultUrl, err)
+}
+
+// DeleteError reports that the secret secretName of a resource of type resourceType could not be deleted from
+// the vault at vaultUrl.
+func DeleteError(resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	return VaultError(OperationDelete, "delete the secret", resourceType, secretName, vaultUrl, err)
+}
+
+// ImportError reports that the secret secretName could not be read from the vault at vaultUrl to import it
+// as a resource of type resourceType.
+func ImportError(resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	return VaultError(OperationImport, "read the secret", resourceType, secretName, vaultUrl, err)
+}
+
+// VaultError reports err, returned by the vault at vaultUrl when the provider tried to perform action, e.g.
+// "update the properties of the secret", on the secret secretName during operation on a resource of type
+// resourceType. Responses that the secret does not exist, that access to it is denied or that it conflicts
+// with the state of the secret get a summary and a hint of their own. An empty vaultUrl is left out.
+func VaultError(operation Operation, action string, resourceType string, secretName string, vaultUrl string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	summary, hint := classifyVaultError(err)
+	if summary == "" {
+		summary = fmt.Sprintf("%s %s error", operation, resourceType)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Could not %s for %s.\n\n", action, resourceType)
+	fmt.Fprintf(&b, "Secret: %s\n", secretName)
+	if vaultUrl != "" {
+		fmt.Fprintf(&b, "Vault:  %s\n", vaultUrl)
+	}
+	b.WriteString("\n")
+	b.WriteString(hint)
+	b.WriteString("Original Error: " + ErrorDetail(err))
+
+	diags.AddError(summary, b.String())
+
+	return diags
+}
+
+// classifyVaultError returns the summary and hint of the error responses of the vault that have one of their
+// own, or empty strings for other errors.
+func classifyVaultError(err error) (string, string) {
+	classification := azrandom.ClassifyError(err)
+
+	switch classification.Class {
+	case azrandom.ErrorClassNotFound:
+		return "Secret Not Found", "The secret does not exist in the vault. It may have been deleted outside of " +
+			"Terraform, or the vault of the resource is not the vault that holds the secret.\n\n"
+	case azrandom.ErrorClassForbiddenByRBAC:
+		return "Vault Access Denied", rbacHint(classification.Action)
+	case azrandom.ErrorClassForbiddenByAccessPolicy:
+		return "Vault Access Denied", accessPolicyHint
+	case azrandom.ErrorClassForbiddenByFirewall:
+		return "Vault Firewall Denied Access", firewallHint(classification.ClientAddress)
+	case azrandom.ErrorClassForbidden:
+		return "Vault Access Denied", "The vault denied the identity of the provider access to the secret. Check " +
+			"that the identity has the permissions this operation needs, and that the secret is enabled.\n\n"
+	case azrandom.ErrorClassBeingDeleted:
+		return "Secret Being Deleted", "The secret is still being deleted, e.g. because a resource with the same " +
+			"name was destroyed moments ago, and its name cannot be reused until the deletion has completed. Set " +
+			"wait_for_deletion to true on the destroyed resource so that destroy waits for the deletion, and " +
+			"purge_on_destroy to true so that the name can be reused right away, or retry once the deletion has " +
+			"completed.\n\n"
+	case azrandom.ErrorClassDeletedButRecoverable:
+		return "Deleted Secret With the Same Name", "A deleted secret with this name is kept by the soft-delete of " +
+			"the vault, so its name cannot be reused until it is recovered or purged. Purge it, e.g. with " +
+			"`az keyvault secret purge`, or set purge_on_destroy to true so that destroyed secrets are purged.\n\n"
+	case azrandom.ErrorClassConflict:
+		return "Secret Conflict", "The vault refused the request because it conflicts with the current state of " +
+			"the secret, e.g. because the secret is being recovered. Retry once that has completed.\n\n"
+	}
+	return "", ""
+}
+
+// accessPolicyHint is the remedy for a vault whose access policies do not grant the identity of the provider
+// the permission it needs.
+const accessPolicyHint = "The vault uses access policies, and none grants the identity of the provider the " +
+	"permission this operation needs. Add an access policy for the identity with the get, list, set, delete, " +
+	"recover and purge secret permissions, e.g. with `az keyvault set-policy --secret-permissions get list set " +
+	"delete recover purge`.\n\n"
+
+// rbacHint returns the remedy for a vault whose role assignments do not allow the identity of the provider to
+// perform action, which is left out when it is empty.
+func rbacHint(action string) string {
+	denied := "the action this operation needs"
+	if action != "" {
+		denied = action
+	}
+	return "The vault uses Azure RBAC, and no role assignment allows the identity of the provider to perform " +
+		denied + ". Assign the identity the Key Vault Secrets Officer role on the vault, e.g. with `az role " +
+		"assignment create --role \"Key Vault Secrets Officer\" --assignee <object ID> --scope <vault ID>`. Role " +
+		"assignments may take a few minutes to apply.\n\n"
+}
+
+// firewallHint returns the remedy for a vault whose firewall denied the network address clientAddress, which
+// is left out when it is empty.
+func firewallHint(clientAddress string) string {
+	address := "the network address of this host"
+	rule := "<address>"
+	if clientAddress != "" {
+		address = "the network address " + clientAddress
+		rule = clientAddress
+	}
+	return "The firewall of the vault denied the request, as " + address + " is not allowed to access the vault. " +
+		"Add the address to the networking rules of the vault, e.g. with `az keyvault network-rule add " +
+		"--ip-address " + rule + "`, or run Terraform from a network that can reach the vault, e.g. through a " +
+		"private endpoint.\n\n"
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+)
+
+// ConflictPolicy represents how Create proceeds when the secret already exists in the vault.
+type ConflictPolicy string
+
+const (
+	ConflictPolicyFail      ConflictPolicy = "fail"
+	ConflictPolicyOverwrite ConflictPolicy = "overwrite"
+	ConflictPolicyAdopt     ConflictPolicy = "adopt"
+)
+
+func (o ConflictPolicy) String() string {
+	return string(o)
+}
+
+// conflictPolicyAttribute returns the schema of the conflict_policy attribute. It is only used by Create, so
+// changing it never generates a new value.
+func conflictPolicyAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("What to do when creating the resource while a secret with the same name already "+
+			"exists in the vault, for example because the Terraform state was lost. `%s` fails, `%s` stores a newly "+
+			"generated value as a new version of the secret, and `%s` takes the current version of the secret into "+
+			"state without generating a value, like `terraform import`. A soft-deleted secret is recovered first. "+
+			"Default value is `%s`.",
+			ConflictPolicyFail, ConflictPolicyOverwrite, ConflictPolicyAdopt, ConflictPolicyFail),
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(ConflictPolicyFail.String()),
+		Validators: []validator.String{
+			stringvalidator.OneOf(
+				ConflictPolicyFail.String(), ConflictPolicyOverwrite.String(), ConflictPolicyAdopt.String(),
+			),
+		},
+	}
+}
+
+// adoptExistingSecret returns the current version of the secret name, recovering it first when it
+// was soft-deleted, waiting for the recovered secret according to backoff. found is false when there is no
+// such secret.
+func adoptExistingSecret(ctx context.Context, client *azsecrets.Client, name string, backoff azrandom.Backoff) (version string, found bool, err error) {
+	if _, err := azrandom.RecoverDeletedSecretWithBackoff(ctx, client, name, backoff); err != nil {
+		return "", false, err
+	}
+
+	version, _, err = azrandom.GetSecret(ctx, client, name, "")
+	if azrandom.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return version, true, nil
+}
+
+// secretExistsBeforeCreate returns whether the secret name already exists before a resource creates it. With
+// skip_existence_check set on the provider, the vault is not asked and the secret is assumed not to exist, so
+// that CreateSecret overwrites an existing secret with a new version.
+func secretExistsBeforeCreate(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, name string) (bool, error) {
+	if data != nil && data.skipExistenceCheck {
+		tflog.Debug(ctx, "Skipping existence check of secret", map[string]any{"name": name})
+		return false, nil
+	}
+	return azrandom.SecretExists(ctx, client, name)
+}
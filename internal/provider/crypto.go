@@ -20,16 +20,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/crypto/ssh"
 )
 
-// keyGenerator extracts data from the given *schema.ResourceData,
+// keyGenerator extracts the algorithm specific settings from the given model,
 // and generates a new public/private key-pair according to the
 // selected algorithm.
-type keyGenerator func(prvKeyConf *cryptographicKeyModelV0) (crypto.PrivateKey, error)
+type keyGenerator func(ctx context.Context, prvKeyConf *cryptographicKeyModelV1) (crypto.PrivateKey, error)
 
 // keyParser parses a private key from the given []byte,
 // according to the selected algorithm.
@@ -55,20 +60,59 @@ func (k HMACSHA256Key) Bytes() []byte {
 	return []byte(k)
 }
 
+const (
+	defaultRSABits          int64            = 2048
+	defaultECDSACurve       ECDSACurve       = P224
+	defaultHMACHashFunction HMACHashFunction = SHA256
+	defaultHMACKeyLength    int64            = 32 // 32 bytes for HMAC-SHA256 (256 bits)
+)
+
+// defaultKeySettings returns the settings of the nested attribute attributeName as generated when it is
+// omitted from configuration.
+func defaultKeySettings(attributeName string) types.Object {
+	switch attributeName {
+	case "rsa":
+		return types.ObjectValueMust(cryptographicKeyRSAAttrTypes, map[string]attr.Value{
+			"bits": types.Int64Value(defaultRSABits),
+		})
+	case "ecdsa":
+		return types.ObjectValueMust(cryptographicKeyECDSAAttrTypes, map[string]attr.Value{
+			"curve": types.StringValue(defaultECDSACurve.String()),
+		})
+	default:
+		return types.ObjectValueMust(cryptographicKeyHMACAttrTypes, map[string]attr.Value{
+			"hash_function": types.StringValue(defaultHMACHashFunction.String()),
+			"key_length":    types.Int64Value(defaultHMACKeyLength),
+		})
+	}
+}
+
 var keyGenerators = map[Algorithm]keyGenerator{
-	RSA: func(prvKeyConf *cryptographicKeyModelV0) (crypto.PrivateKey, error) {
-		if prvKeyConf.RSABits.IsUnknown() || prvKeyConf.RSABits.IsNull() {
-			return nil, fmt.Errorf("RSA bits curve not provided")
+	RSA: func(ctx context.Context, prvKeyConf *cryptographicKeyModelV1) (crypto.PrivateKey, error) {
+		settings := cryptographicKeyRSAModel{
+			Bits: types.Int64Value(defaultRSABits),
+		}
+		if err := objectAs(ctx, prvKeyConf.RSA, &settings); err != nil {
+			return nil, err
+		}
+		if settings.Bits.IsUnknown() || settings.Bits.IsNull() {
+			return nil, fmt.Errorf("RSA bits not provided")
 		}
 
-		return rsa.GenerateKey(rand.Reader, int(prvKeyConf.RSABits.ValueInt64()))
+		return rsa.GenerateKey(rand.Reader, int(settings.Bits.ValueInt64()))
 	},
-	ECDSA: func(prvKeyConf *cryptographicKeyModelV0) (crypto.PrivateKey, error) {
-		if prvKeyConf.ECDSACurve.IsUnknown() || prvKeyConf.ECDSACurve.IsNull() {
+	ECDSA: func(ctx context.Context, prvKeyConf *cryptographicKeyModelV1) (crypto.PrivateKey, error) {
+		settings := cryptographicKeyECDSAModel{
+			Curve: types.StringValue(defaultECDSACurve.String()),
+		}
+		if err := objectAs(ctx, prvKeyConf.ECDSA, &settings); err != nil {
+			return nil, err
+		}
+		if settings.Curve.IsUnknown() || settings.Curve.IsNull() {
 			return nil, fmt.Errorf("ECDSA curve not provided")
 		}
 
-		curve := ECDSACurve(prvKeyConf.ECDSACurve.ValueString())
+		curve := ECDSACurve(settings.Curve.ValueString())
 		switch curve {
 		case P224:
 			return ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
@@ -82,23 +126,33 @@ var keyGenerators = map[Algorithm]keyGenerator{
 			return nil, fmt.Errorf("invalid ECDSA curve; supported values are: %v", supportedECDSACurves())
 		}
 	},
-	ED25519: func(_ *cryptographicKeyModelV0) (crypto.PrivateKey, error) {
+	ED25519: func(_ context.Context, _ *cryptographicKeyModelV1) (crypto.PrivateKey, error) {
 		_, key, err := ed25519.GenerateKey(rand.Reader)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate ED25519 key: %s", err)
 		}
 		return key, err
 	},
-	HMAC: func(prvKeyConf *cryptographicKeyModelV0) (crypto.PrivateKey, error) {
-		if prvKeyConf.HMACHashFunction.IsUnknown() || prvKeyConf.HMACHashFunction.IsNull() {
+	HMAC: func(ctx context.Context, prvKeyConf *cryptographicKeyModelV1) (crypto.PrivateKey, error) {
+		settings := cryptographicKeyHMACModel{
+			HashFunction: types.StringValue(defaultHMACHashFunction.String()),
+			KeyLength:    types.Int64Value(defaultHMACKeyLength),
+		}
+		if err := objectAs(ctx, prvKeyConf.HMAC, &settings); err != nil {
+			return nil, err
+		}
+		if settings.HashFunction.IsUnknown() || settings.HashFunction.IsNull() {
 			return nil, fmt.Errorf("HMAC hash function not provided")
 		}
+		if settings.KeyLength.IsUnknown() || settings.KeyLength.IsNull() {
+			return nil, fmt.Errorf("HMAC key length not provided")
+		}
 
-		hash_function := HMACHashFunction(prvKeyConf.HMACHashFunction.ValueString())
+		hash_function := HMACHashFunction(settings.HashFunction.ValueString())
 		switch hash_function {
 		case SHA256:
 			{
-				key := make([]byte, 32) // 32 bytes for HMAC-SHA256 (256 bits)
+				key := make([]byte, settings.KeyLength.ValueInt64())
 				_, err := rand.Read(key)
 				if err != nil {
 					return nil, fmt.Errorf("Error generating random key: %s", err)
@@ -107,11 +161,104 @@ var keyGenerators = map[Algorithm]keyGenerator{
 			}
 
 		default:
-			return nil, fmt.Errorf("invalid ECDSA curve; supported values are: %v", supportedECDSACurves())
+			return nil, fmt.Errorf("invalid HMAC hash function; supported values are: %v", supportedHMACHashFunctions())
 		}
 	},
 }
 
+// keyParsers parses the private keys stored by this provider, by the preamble of their PEM block.
+var keyParsers = map[PEMPreamble]keyParser{
+	PreamblePrivateKeyRSA: func(der []byte) (crypto.PrivateKey, error) {
+		return x509.ParsePKCS1PrivateKey(der)
+	},
+	PreamblePrivateKeyEC: func(der []byte) (crypto.PrivateKey, error) {
+		return x509.ParseECPrivateKey(der)
+	},
+	PreamblePrivateKeyPKCS8: func(der []byte) (crypto.PrivateKey, error) {
+		return x509.ParsePKCS8PrivateKey(der)
+	},
+	PreamblePrivateKeyHMAC: func(der []byte) (crypto.PrivateKey, error) {
+		return HMACSHA256Key(der), nil
+	},
+}
+
+// parsePrivateKeyPEM parses a private key in the PEM format written by createKey.
+func parsePrivateKeyPEM(value string) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("value is not PEM encoded")
+	}
+
+	preamble, err := pemBlockToPEMPreamble(block)
+	if err != nil {
+		return nil, err
+	}
+
+	parse, ok := keyParsers[preamble]
+	if !ok {
+		return nil, fmt.Errorf("PEM block of type %q does not hold a private key", preamble)
+	}
+	return parse(block.Bytes)
+}
+
+// keySettings sets the algorithm of model, and the settings of that algorithm, to those the given private key
+// was generated with. It is the inverse of keyGenerators, used where only the stored key is known.
+func keySettings(prvKey crypto.PrivateKey, model *cryptographicKeyModelV1) error {
+	model.RSA = types.ObjectNull(cryptographicKeyRSAAttrTypes)
+	model.ECDSA = types.ObjectNull(cryptographicKeyECDSAAttrTypes)
+	model.HMAC = types.ObjectNull(cryptographicKeyHMACAttrTypes)
+
+	var diags diag.Diagnostics
+	switch k := prvKey.(type) {
+	case *rsa.PrivateKey:
+		model.Algorithm = types.StringValue(RSA.String())
+		model.RSA, diags = types.ObjectValue(cryptographicKeyRSAAttrTypes, map[string]attr.Value{
+			"bits": types.Int64Value(int64(k.N.BitLen())),
+		})
+	case *ecdsa.PrivateKey:
+		// The curves are named P-224 etc. by crypto/elliptic
+		curve := ECDSACurve(strings.ReplaceAll(k.Curve.Params().Name, "-", ""))
+		if !slices.Contains(supportedECDSACurves(), curve) {
+			return fmt.Errorf("unsupported ECDSA curve %q", k.Curve.Params().Name)
+		}
+		model.Algorithm = types.StringValue(ECDSA.String())
+		model.ECDSA, diags = types.ObjectValue(cryptographicKeyECDSAAttrTypes, map[string]attr.Value{
+			"curve": types.StringValue(curve.String()),
+		})
+	case ed25519.PrivateKey:
+		model.Algorithm = types.StringValue(ED25519.String())
+	case HMACSHA256Key:
+		model.Algorithm = types.StringValue(HMAC.String())
+		model.HMAC, diags = types.ObjectValue(cryptographicKeyHMACAttrTypes, map[string]attr.Value{
+			"hash_function": types.StringValue(SHA256.String()),
+			"key_length":    types.Int64Value(int64(len(k))),
+		})
+	default:
+		return fmt.Errorf("unsupported private key type: %T", prvKey)
+	}
+	if diags.HasError() {
+		return fmt.Errorf("unable to set algorithm settings: %v", diags)
+	}
+	return nil
+}
+
+// objectAs decodes the given nested attribute into target. A null object leaves target untouched,
+// so that target can be pre-populated with the defaults of the nested attribute.
+func objectAs(ctx context.Context, object types.Object, target interface{}) error {
+	if object.IsNull() {
+		return nil
+	}
+	if object.IsUnknown() {
+		return errors.New("algorithm settings are not known")
+	}
+
+	diags := object.As(ctx, target, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return fmt.Errorf("unable to read algorithm settings: %v", diags)
+	}
+	return nil
+}
+
 // privateKeyToPublicKey takes a crypto.PrivateKey and extracts the corresponding crypto.PublicKey,
 // after having figured out its type.
 func privateKeyToPublicKey(prvKey crypto.PrivateKey) (crypto.PublicKey, error) {
@@ -385,7 +532,7 @@ func supportedURLSchemesStr() []string {
 	return supportedStr
 }
 
-func createKey(ctx context.Context, plan cryptographicKeyModelV0) (crypto.PrivateKey, *pem.Block, error) {
+func createKey(ctx context.Context, plan cryptographicKeyModelV1) (crypto.PrivateKey, *pem.Block, error) {
 	keyAlgoName := Algorithm(plan.Algorithm.ValueString())
 
 	var emptyKey crypto.PrivateKey
@@ -401,7 +548,7 @@ func createKey(ctx context.Context, plan cryptographicKeyModelV0) (crypto.Privat
 	tflog.Debug(ctx, "Generating private key for algorithm", map[string]interface{}{
 		"algorithm": keyAlgoName,
 	})
-	prvKey, err := keyGen(&plan)
+	prvKey, err := keyGen(ctx, &plan)
 	if err != nil {
 		return emptyKey, emptyBlock, errors.New("Unable to generate Key from configuration" + err.Error())
 	}
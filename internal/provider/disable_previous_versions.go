@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// disablePreviousVersionsAttribute returns the schema of the disable_previous_versions attribute. It is
+// only used after a new value was written, so changing it never generates a new value.
+func disablePreviousVersionsAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Disable all other versions of the secret after a new value was generated, so that clients holding " +
+			"the URI of a previous version can no longer read the replaced value. Requires permission to list secrets " +
+			"and to set their properties. Default value is `false`.",
+		Optional: true,
+	}
+}
+
+// disablePreviousVersions disables all versions of the secret name other than version when
+// disable_previous_versions is enabled.
+func disablePreviousVersions(ctx context.Context, client *azsecrets.Client, enabled types.Bool, name string, version string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !enabled.ValueBool() {
+		return diags
+	}
+
+	disabled, err := azrandom.DisablePreviousVersions(ctx, client, name, version)
+	if len(disabled) > 0 {
+		tflog.Debug(ctx, "Disabled previous secret versions", map[string]any{"name": name, "versions": disabled})
+	}
+	if err != nil {
+		diags.AddError(
+			"Disable previous versions failed",
+			"A new version of "+name+" was written, but its previous versions could not all be disabled, "+
+				"unexpected error: "+diagnostics.ErrorDetail(err),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// secretIdentityModel is the identity of resources that store a single secret: the vault that the secret is
+// stored in and its name. It never changes, since moving the secret to another vault or renaming it replaces
+// the resource.
+type secretIdentityModel struct {
+	VaultUrl types.String `tfsdk:"vault_url"`
+	Name     types.String `tfsdk:"name"`
+}
+
+// secretIdentitySchema returns the identity schema of resources that store a single secret.
+func secretIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"vault_url": identityschema.StringAttribute{
+				Description: "URL of the Azure Key Vault that the secret is stored in. Defaults to the vault of the " +
+					"provider when importing.",
+				OptionalForImport: true,
+			},
+			"name": identityschema.StringAttribute{
+				Description:       "Name of the secret.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// setSecretIdentity sets identity to the secret name in the vault of a resource with the given vault_url, which
+// is the vault of the provider when null.
+func setSecretIdentity(ctx context.Context, data *azrandomProviderData, identity *tfsdk.ResourceIdentity, vaultUrl types.String, name types.String) diag.Diagnostics {
+	return identity.Set(ctx, secretIdentityModel{
+		VaultUrl: types.StringValue(vaultKey(resourceVaultUrl(data, vaultUrl))),
+		Name:     name,
+	})
+}
+
+// secretImportID returns the import ID of a resource that stores a single secret, which is given either as
+// the ID of the import or by the identity attribute of an import block. An identity is turned into the ID
+// <vault_url>/secrets/<name>, or <name> when it has no vault_url.
+func secretImportID(ctx context.Context, req resource.ImportStateRequest) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if req.ID != "" || req.Identity == nil || req.Identity.Raw.IsNull() {
+		return req.ID, diags
+	}
+
+	var identity secretIdentityModel
+	diags.Append(req.Identity.Get(ctx, &identity)...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	name := identity.Name.ValueString()
+	if name == "" {
+		diags.AddAttributeError(path.Root("name"), "Invalid Import Identity", "The name of the secret to import is empty.")
+		return "", diags
+	}
+	vaultUrl := identity.VaultUrl.ValueString()
+	if vaultUrl == "" {
+		return name, diags
+	}
+	if !strings.HasPrefix(strings.ToLower(vaultUrl), "https://") {
+		diags.AddAttributeError(
+			path.Root("vault_url"),
+			"Invalid Import Identity",
+			"Expected the vault_url of the secret to import to be an https URL, got: "+vaultUrl,
+		)
+		return "", diags
+	}
+	return strings.TrimRight(vaultUrl, "/") + "/secrets/" + url.PathEscape(name), diags
+}
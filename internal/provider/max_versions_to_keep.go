@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// maxVersionsToKeepAttribute returns the schema of the max_versions_to_keep attribute. It is only used
+// after a new value was written, so changing it never generates a new value.
+func maxVersionsToKeepAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Description: "Number of most recent versions of the secret to keep enabled after a new value was generated. " +
+			"Older versions are disabled, oldest first, since Key Vault cannot delete individual versions. The current " +
+			"version is never disabled. Requires permission to list secrets and to set their properties; without " +
+			"permission to list secrets a warning is shown instead. By default no versions are disabled.",
+		Optional: true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+}
+
+// pruneSecretVersions disables the versions of the secret name beyond the most recent maxVersions when
+// max_versions_to_keep is set. Lacking permission to list versions is reported as a warning rather than
+// an error, since the new value was written regardless.
+func pruneSecretVersions(ctx context.Context, client *azsecrets.Client, maxVersions types.Int64, name string, version string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if maxVersions.IsNull() || maxVersions.IsUnknown() {
+		return diags
+	}
+
+	pruned, err := azrandom.PruneSecretVersions(ctx, client, name, version, int(maxVersions.ValueInt64()))
+	if len(pruned) > 0 {
+		tflog.Debug(ctx, "Disabled old secret versions", map[string]any{"name": name, "versions": pruned})
+	}
+	if errors.Is(err, azrandom.ErrListForbidden) {
+		diags.AddWarning(
+			"Old versions not pruned",
+			"A new version of "+name+" was written, but its old versions were not pruned since the versions of the "+
+				"secret could not be listed. Grant permission to list secrets, or unset max_versions_to_keep to "+
+				"silence this warning.",
+		)
+	} else if err != nil {
+		diags.AddError(
+			"Prune old versions failed",
+			"A new version of "+name+" was written, but its old versions could not all be disabled, "+
+				"unexpected error: "+diagnostics.ErrorDetail(err),
+		)
+	}
+	return diags
+}
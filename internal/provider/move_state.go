@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// The providers whose resources can be moved into azrandom resources with a moved block.
+const (
+	randomProviderSource = "hashicorp/random"
+	tlsProviderSource    = "hashicorp/tls"
+)
+
+// movedFrom returns whether req moves the state of a resource of type typeName of the provider with the given
+// source address, e.g. hashicorp/random. The hostname of the registry is ignored, so that the provider may be
+// installed from a mirror.
+func movedFrom(req resource.MoveStateRequest, source string, typeName string) bool {
+	return req.SourceTypeName == typeName && req.SourceRawState != nil &&
+		(req.SourceProviderAddress == source || strings.HasSuffix(req.SourceProviderAddress, "/"+source))
+}
+
+// decodeMovedState decodes the state of the resource that req moves into target.
+func decodeMovedState(req resource.MoveStateRequest, target any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := json.Unmarshal(req.SourceRawState.JSON, target); err != nil {
+		diags.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf("The state of the %s to move could not be read: %s", req.SourceTypeName, err),
+		)
+	}
+	return diags
+}
+
+// movedKeepers returns the keepers of a moved resource as the value of the keepers attribute.
+func movedKeepers(ctx context.Context, keepers map[string]*string) (types.Map, diag.Diagnostics) {
+	if keepers == nil {
+		return types.MapNull(types.StringType), nil
+	}
+	return types.MapValueFrom(ctx, types.StringType, keepers)
+}
+
+// storeMovedValue records value, the value of a resource that was moved from another provider, until the first
+// apply after the move stores it in the secret of the resource. The value was held in the state of the moved
+// resource already.
+func storeMovedValue(ctx context.Context, private privateState, value string) diag.Diagnostics {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the moved value in private state", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, privateMovedKey, raw)
+}
+
+// movedValue returns the value that a resource was moved from another provider with, and whether it is yet to
+// be stored in the secret of the resource. Until then, the resource has no secret to read or delete.
+func movedValue(ctx context.Context, private privateState) (string, bool, diag.Diagnostics) {
+	raw, diags := private.GetKey(ctx, privateMovedKey)
+	if diags.HasError() || raw == nil {
+		return "", false, diags
+	}
+
+	var value *string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		diags.AddError("Unable to read the moved value from private state", err.Error())
+		return "", false, diags
+	}
+	if value == nil {
+		return "", false, diags
+	}
+	return *value, true, diags
+}
+
+// clearMovedValue records that the moved value of a resource was stored in its secret.
+func clearMovedValue(ctx context.Context, private privateState) diag.Diagnostics {
+	return private.SetKey(ctx, privateMovedKey, []byte("null"))
+}
+
+// nameRequiresReplace returns the plan modifier of the name attribute of resources that accept moved state,
+// which replaces the resource when the name changes. A resource that was just moved from another provider has
+// no name yet, and its first apply stores the moved value in the secret of the configured name instead.
+func nameRequiresReplace() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			_, moved, diags := movedValue(ctx, req.Private)
+			resp.Diagnostics.Append(diags...)
+			resp.RequiresReplace = !moved
+		},
+		"Changing the name replaces the resource, unless it was just moved from another provider.",
+		"Changing the name replaces the resource, unless it was just moved from another provider.",
+	)
+}
+
+// createMovedSecret stores value, the value of a resource of type resourceType that was moved from another
+// provider, in the secret name, and returns the version that it was stored in. An existing secret is only
+// overwritten when conflict_policy is overwrite, since adopting it would discard the moved value.
+func createMovedSecret(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, resourceType string, vaultUrl types.String, name string, conflictPolicy types.String, value string, metadata secretMetadata) (string, azrandom.SecretProperties, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	secretExists, err := secretExistsBeforeCreate(ctx, data, client, name)
+	if err != nil {
+		diags.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "check whether the secret already exists",
+			resourceType, name, resourceVaultUrl(data, vaultUrl), err)...)
+		return "", azrandom.SecretProperties{}, diags
+	}
+	if secretExists && ConflictPolicy(conflictPolicy.ValueString()) != ConflictPolicyOverwrite {
+		diags.AddError(
+			fmt.Sprintf("Update %s error", resourceType),
+			existingSecretMessage(ctx, client, resourceType, name, name)+
+				"\n\nAlternatively, set conflict_policy to \"overwrite\" to store the moved value as a new version "+
+				"of the existing secret.",
+		)
+		return "", azrandom.SecretProperties{}, diags
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, value, secretProperties(metadata, nil))
+	if err != nil {
+		diags.Append(diagnostics.CreateError(resourceType, name, resourceVaultUrl(data, vaultUrl), err)...)
+		return "", azrandom.SecretProperties{}, diags
+	}
+	return version, stored, diags
+}
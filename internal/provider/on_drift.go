@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// OnDrift represents how a resource responds to its secret being changed outside of Terraform.
+type OnDrift string
+
+const (
+	OnDriftRegenerate OnDrift = "regenerate"
+	OnDriftIgnore     OnDrift = "ignore"
+	OnDriftError      OnDrift = "error"
+)
+
+func (o OnDrift) String() string {
+	return string(o)
+}
+
+// onDriftAttribute returns the schema of the on_drift attribute. It only decides what happens after drift was
+// detected, so changing it never generates a new value.
+func onDriftAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("What to do when a new version of the secret was set outside of Terraform, for "+
+			"example by a rotation in the portal, or when the secret no longer holds the generated value, for "+
+			"example after it was restored from a backup. `%s` generates a new value on the next apply, `%s` takes the "+
+			"current version of the secret into state without generating a value, and `%s` fails the plan until "+
+			"the drift is resolved. Default value is `%s`.", OnDriftRegenerate, OnDriftIgnore, OnDriftError, OnDriftRegenerate),
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(OnDriftRegenerate.String()),
+		Validators: []validator.String{
+			stringvalidator.OneOf(OnDriftRegenerate.String(), OnDriftIgnore.String(), OnDriftError.String()),
+		},
+	}
+}
+
+// recordDrift records in private state that Read found version of the secret changed outside of Terraform, for
+// the reason given. With on_drift set to ignore the version is adopted, otherwise the value is marked for
+// regeneration and planDrift decides what the plan does. State written before on_drift existed holds null,
+// which regenerates.
+func recordDrift(ctx context.Context, private privateState, onDrift types.String, reason string, version string) diag.Diagnostics {
+	if OnDrift(onDrift.ValueString()) == OnDriftIgnore {
+		tflog.Info(ctx, "Adopting version of secret changed outside of Terraform", map[string]any{"version": version, "reason": reason})
+		return adoptVersion(ctx, private, version)
+	}
+	return markForRegeneration(ctx, private, reason)
+}
+
+// planDrift returns whether the plan generates a new value after Read detected drift for the reason given, as
+// configured by on_drift. The planned on_drift is used rather than the one in state, so that changing it takes
+// effect in the same plan: ignore adopts the current version instead, and error fails the plan. Resources without
+// on_drift always regenerate.
+func planDrift(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, reason string) bool {
+	if _, ok := req.Plan.Schema.GetAttributes()["on_drift"]; !ok {
+		return true
+	}
+
+	var onDrift, name, version types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("on_drift"), &onDrift)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	switch OnDrift(onDrift.ValueString()) {
+	case OnDriftIgnore:
+		// Update then only writes the metadata to the current version
+		resp.Diagnostics.Append(adoptVersion(ctx, resp.Private, version.ValueString())...)
+		return false
+	case OnDriftError:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("on_drift"),
+			"Secret changed outside of Terraform",
+			fmt.Sprintf("Drift was detected for version %s of secret %q, since %s, and on_drift is %q.\n\n"+
+				"Set on_drift to %q to generate a new value, or to %q to keep the current version.",
+				version.ValueString(), name.ValueString(), reason, OnDriftError, OnDriftRegenerate, OnDriftIgnore),
+		)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// protocol5Resources returns the given resources, with those whose schema has nested attributes, such as the rsa
+// attribute of azrandom_cryptographic_key, wrapped in a protocol6Resource. Protocol version 5 cannot express nested
+// attributes, and serving them as they are would fail the schema of the whole provider.
+func protocol5Resources(ctx context.Context, resources []func() resource.Resource) []func() resource.Resource {
+	supported := make([]func() resource.Resource, 0, len(resources))
+	for _, newResource := range resources {
+		var resp resource.SchemaResponse
+		newResource().Schema(ctx, resource.SchemaRequest{}, &resp)
+
+		if !hasNestedAttributes(resp.Schema) {
+			supported = append(supported, newResource)
+			continue
+		}
+		var metadata resource.MetadataResponse
+		newResource().Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "azrandom"}, &metadata)
+		supported = append(supported, func() resource.Resource {
+			return &protocol6Resource{Resource: newResource(), typeName: metadata.TypeName}
+		})
+	}
+	return supported
+}
+
+// hasNestedAttributes returns whether s has nested attributes. Nested blocks are supported by both protocol versions.
+func hasNestedAttributes(s schema.Schema) bool {
+	for _, attribute := range s.Attributes {
+		if _, ok := attribute.(schema.NestedAttribute); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ resource.ResourceWithValidateConfig = &protocol6Resource{}
+	_ resource.ResourceWithImportState    = &protocol6Resource{}
+	_ resource.ResourceWithUpgradeState   = &protocol6Resource{}
+	_ resource.ResourceWithMoveState      = &protocol6Resource{}
+)
+
+// protocol6Resource serves a resource whose schema has nested attributes over protocol version 5. Its schema
+// replaces the nested attributes with dynamic attributes, so that configurations of the resource type still
+// decode, and every request fails with an error naming protocol version 6. It is never configured, so it never
+// calls the resource it wraps but for its metadata and schema.
+type protocol6Resource struct {
+	resource.Resource
+
+	// typeName is the type name of the resource, for the error of its requests.
+	typeName string
+}
+
+// Schema defines the schema for the resource.
+func (r *protocol6Resource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	r.Resource.Schema(ctx, req, resp)
+
+	attributes := make(map[string]schema.Attribute, len(resp.Schema.Attributes))
+	for name, attribute := range resp.Schema.Attributes {
+		if _, ok := attribute.(schema.NestedAttribute); ok {
+			attribute = schema.DynamicAttribute{
+				Description:         attribute.GetDescription(),
+				MarkdownDescription: attribute.GetMarkdownDescription(),
+				DeprecationMessage:  attribute.GetDeprecationMessage(),
+				Required:            attribute.IsRequired(),
+				Optional:            attribute.IsOptional(),
+				Computed:            attribute.IsComputed(),
+				Sensitive:           attribute.IsSensitive(),
+			}
+		}
+		attributes[name] = attribute
+	}
+	resp.Schema.Attributes = attributes
+}
+
+// ValidateConfig fails, as the resource type needs protocol version 6.
+func (r *protocol6Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// Create fails, as the resource type needs protocol version 6.
+func (r *protocol6Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// Read fails, as the resource type needs protocol version 6. State of the resource type may only exist over
+// protocol version 5 when Terraform was downgraded after creating it.
+func (r *protocol6Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// Update fails, as the resource type needs protocol version 6.
+func (r *protocol6Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// Delete fails, as the resource type needs protocol version 6.
+func (r *protocol6Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// ImportState fails, as the resource type needs protocol version 6.
+func (r *protocol6Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(r.protocolError())
+}
+
+// UpgradeState fails to upgrade state from every prior version that the wrapped resource upgrades, as the
+// resource type needs protocol version 6.
+func (r *protocol6Resource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	wrapped, ok := r.Resource.(resource.ResourceWithUpgradeState)
+	if !ok {
+		return nil
+	}
+	upgraders := make(map[int64]resource.StateUpgrader)
+	for version := range wrapped.UpgradeState(ctx) {
+		upgraders[version] = resource.StateUpgrader{
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				resp.Diagnostics.Append(r.protocolError())
+			},
+		}
+	}
+	return upgraders
+}
+
+// MoveState fails to move state into the resource type, as it needs protocol version 6.
+func (r *protocol6Resource) MoveState(ctx context.Context) []resource.StateMover {
+	if _, ok := r.Resource.(resource.ResourceWithMoveState); !ok {
+		return nil
+	}
+	return []resource.StateMover{
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				resp.Diagnostics.Append(r.protocolError())
+			},
+		},
+	}
+}
+
+// protocolError returns the error of a resource type that needs protocol version 6.
+func (r *protocol6Resource) protocolError() diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Resource Type Requires Protocol Version 6",
+		fmt.Sprintf("The %s resource type has nested attributes, which Terraform only supports over version 6 of the "+
+			"plugin protocol, but the provider is served over version 5. Upgrade to Terraform 1.0 or later to use "+
+			"this resource type.", r.typeName),
+	)
+}
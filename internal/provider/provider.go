@@ -5,24 +5,42 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"math"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/validators"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &azrandomProvider{}
+	_ provider.Provider                   = &azrandomProvider{}
+	_ provider.ProviderWithValidateConfig = &azrandomProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -34,22 +52,73 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// NewProtocol5 is New for the provider served over protocol version 5, for Terraform releases before 1.0, which
+// do not support protocol version 6. See protocol5Resources for the resources that need protocol version 6.
+func NewProtocol5(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &azrandomProvider{
+			version:   version,
+			protocol5: true,
+		}
+	}
+}
+
 // azrandomProvider is the provider implementation.
 type azrandomProvider struct {
 	// version is set to the provider version on release, "dev" when the
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// protocol5 is set when the provider is served over protocol version 5.
+	protocol5 bool
 }
 
 // azrandomProviderModel maps provider schema data to a Go type.
 type azrandomProviderModel struct {
 	VaultUrl                           types.String `tfsdk:"vault_url"`
+	VaultName                          types.String `tfsdk:"vault_name"`
+	DNSSuffix                          types.String `tfsdk:"dns_suffix"`
+	Environment                        types.String `tfsdk:"environment"`
+	CustomAuthorityHost                types.String `tfsdk:"custom_authority_host"`
+	AuthMethod                         types.String `tfsdk:"auth_method"`
+	TenantID                           types.String `tfsdk:"tenant_id"`
+	ClientID                           types.String `tfsdk:"client_id"`
+	ClientSecret                       types.String `tfsdk:"client_secret"`
+	ClientCertificatePath              types.String `tfsdk:"client_certificate_path"`
+	ClientCertificatePassword          types.String `tfsdk:"client_certificate_password"`
+	ManagedIdentityClientID            types.String `tfsdk:"managed_identity_client_id"`
+	ManagedIdentityResourceID          types.String `tfsdk:"managed_identity_resource_id"`
+	AdditionallyAllowedTenants         types.List   `tfsdk:"additionally_allowed_tenants"`
+	DisableInstanceDiscovery           types.Bool   `tfsdk:"disable_instance_discovery"`
 	DisableManagedIdentityCredential   types.Bool   `tfsdk:"disable_managed_identity_credential"`
 	DisableWorkloadIdentityCredential  types.Bool   `tfsdk:"disable_workload_identity_credential"`
 	DisableAzureCLICredential          types.Bool   `tfsdk:"disable_azure_cli_credential"`
 	DisableAzureDeveloperCLICredential types.Bool   `tfsdk:"disable_azure_developer_cli_credential"`
 	DisableEnvironmentCredential       types.Bool   `tfsdk:"disable_environment_credential"`
+	UseInteractiveBrowser              types.Bool   `tfsdk:"use_interactive_browser"`
+	UseDeviceCode                      types.Bool   `tfsdk:"use_device_code"`
+	TokenCacheName                     types.String `tfsdk:"token_cache_name"`
+	ProxyUrl                           types.String `tfsdk:"proxy_url"`
+	ProxyUsername                      types.String `tfsdk:"proxy_username"`
+	ProxyPassword                      types.String `tfsdk:"proxy_password"`
+	PartnerID                          types.String `tfsdk:"partner_id"`
+	DisableTerraformPartnerID          types.Bool   `tfsdk:"disable_terraform_partner_id"`
+	OperationTimeout                   types.String `tfsdk:"operation_timeout"`
+	MaxConcurrentRequests              types.Int64  `tfsdk:"max_concurrent_requests"`
+	SDKLogLevel                        types.String `tfsdk:"sdk_log_level"`
+	CustomEndpoint                     types.String `tfsdk:"custom_endpoint"`
+	InsecureSkipVerify                 types.Bool   `tfsdk:"insecure_skip_verify"`
+	UseFakeCredential                  types.Bool   `tfsdk:"use_fake_credential"`
+	AllowInsecure                      types.Bool   `tfsdk:"allow_insecure"`
+	SkipCredentialValidation           types.Bool   `tfsdk:"skip_credential_validation"`
+	ValidateAccess                     types.Bool   `tfsdk:"validate_access"`
+	VerifyWrite                        types.Bool   `tfsdk:"verify_write"`
+	SkipExistenceCheck                 types.Bool   `tfsdk:"skip_existence_check"`
+	StrictImport                       types.Bool   `tfsdk:"strict_import"`
+	DefaultTags                        types.Map    `tfsdk:"default_tags"`
+	WriteMetadataTags                  types.Bool   `tfsdk:"write_metadata_tags"`
+	Retry                              *retryModel  `tfsdk:"retry"`
 }
 
 // Metadata returns the provider type name.
@@ -64,31 +133,490 @@ func (p *azrandomProvider) Schema(_ context.Context, _ provider.SchemaRequest, r
 		Description: "Interact with azrandom.",
 		Attributes: map[string]schema.Attribute{
 			"vault_url": schema.StringAttribute{
-				Description: "URL of the Azure Key Vault where the randomly generated outputs should be stored.",
-				Required:    true,
+				Description: "URL of the Azure Key Vault where the randomly generated outputs should be stored, such as " +
+					"`https://my-vault.vault.azure.net/`. It must use the https scheme, have no path and end with the Key " +
+					"Vault DNS suffix of the environment. Managed HSM pools are not supported, as they only store keys. " +
+					"Exactly one of vault_url and vault_name must be set. It may refer to a vault created in the same " +
+					"configuration: until it is known, Terraform defers the resources of the provider if it supports " +
+					"deferred actions, and fails to plan otherwise, so that the vault has to be created first.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.URLScheme(HTTPSScheme.String()),
+					stringvalidator.ConflictsWith(path.MatchRoot("vault_name")),
+				},
 			},
-			"disable_managed_identity_credential": schema.BoolAttribute{
-				Description: "Disable Managed Indentity credentials in the DefaultAzureCredential chain.",
+			"vault_name": schema.StringAttribute{
+				Description: "Name, or ARM resource ID, of the Azure Key Vault where the randomly generated outputs should be stored. " +
+					"The URL of the vault is assembled from dns_suffix, or the Key Vault DNS suffix of the environment. " +
+					"Exactly one of vault_url and vault_name must be set.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.VaultName(),
+				},
+			},
+			"dns_suffix": schema.StringAttribute{
+				Description: "Key Vault DNS suffix that the URL of the vault given by vault_name is assembled from, such as " +
+					"`vault.azure.net`, e.g. for private clouds. Can also be set with the AZRANDOM_DNS_SUFFIX environment " +
+					"variable. Defaults to the Key Vault DNS suffix of the environment. Cannot be combined with vault_url.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.DNSSuffix(),
+					stringvalidator.ConflictsWith(path.MatchRoot("vault_url")),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Description: "Azure cloud environment of the vault, one of " + strings.Join(azrandom.Environments(), ", ") +
+					". It selects the authority that tokens are requested from, and the DNS suffix of the vault given by " +
+					"vault_name. Can also be set with the AZRANDOM_ENVIRONMENT environment variable. Defaults to the " +
+					"environment of the DNS suffix of vault_url, or public. It must match the DNS suffix of vault_url.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(azrandom.Environments()...),
+				},
+			},
+			"custom_authority_host": schema.StringAttribute{
+				Description: "URL of the Microsoft Entra authority that tokens are requested from, instead of the " +
+					"authority of the environment, e.g. for private clouds. Can also be set with the " +
+					"AZRANDOM_CUSTOM_AUTHORITY_HOST environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.HTTPSURL(),
+				},
+			},
+			"auth_method": schema.StringAttribute{
+				Description: "Credential to authenticate with, one of " + strings.Join(azrandom.AuthMethods(), ", ") +
+					". Any other than default_chain builds only that credential instead of the DefaultAzureCredential " +
+					"chain, so it cannot be combined with the disable_*_credential attributes or a service principal. " +
+					"Can also be set with the AZRANDOM_AUTH_METHOD environment variable. Defaults to default_chain.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(azrandom.AuthMethods()...),
+				},
+			},
+			"tenant_id": schema.StringAttribute{
+				Description: "Tenant ID of the service principal to authenticate as. Can also be set with the " +
+					"AZRANDOM_TENANT_ID environment variable. " + servicePrincipalDescription,
+				Optional: true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "Client ID of the service principal to authenticate as. Can also be set with the " +
+					"AZRANDOM_CLIENT_ID environment variable. " + servicePrincipalDescription,
+				Optional: true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "Client secret of the service principal to authenticate as. Can also be set with the " +
+					"AZRANDOM_CLIENT_SECRET environment variable. " + servicePrincipalDescription,
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("client_certificate_path")),
+				},
+			},
+			"client_certificate_path": schema.StringAttribute{
+				Description: "Path to a PEM or PFX file holding the client certificate and private key of the service " +
+					"principal to authenticate as, instead of client_secret. Can also be set with the " +
+					"AZRANDOM_CLIENT_CERTIFICATE_PATH environment variable. " + servicePrincipalDescription,
+				Optional: true,
+			},
+			"client_certificate_password": schema.StringAttribute{
+				Description: "Password of the PFX file given by client_certificate_path. Can also be set with the " +
+					"AZRANDOM_CLIENT_CERTIFICATE_PASSWORD environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"managed_identity_client_id": schema.StringAttribute{
+				Description: "Client ID of the user-assigned managed identity to authenticate as with the Managed Identity " +
+					"credential, e.g. on hosts with several identities. Can also be set with the " +
+					"AZRANDOM_MANAGED_IDENTITY_CLIENT_ID environment variable. Defaults to the AZURE_CLIENT_ID environment " +
+					"variable, or the system-assigned identity.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("managed_identity_resource_id")),
+				},
+			},
+			"managed_identity_resource_id": schema.StringAttribute{
+				Description: "ARM resource ID of the user-assigned managed identity to authenticate as with the Managed " +
+					"Identity credential, instead of managed_identity_client_id. Can also be set with the " +
+					"AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID environment variable.",
+				Optional: true,
+			},
+			"additionally_allowed_tenants": schema.ListAttribute{
+				Description: "Tenants besides the home tenant of the identity that tokens may be requested for, e.g. " +
+					"when the vault is in another tenant than the identity. `*` allows any tenant. Can also be set with " +
+					"the AZRANDOM_ADDITIONALLY_ALLOWED_TENANTS environment variable, separating tenants with `;`.",
+				ElementType: types.StringType,
 				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"disable_instance_discovery": schema.BoolAttribute{
+				Description: "Skip requesting the metadata of the Microsoft Entra authority before authenticating, " +
+					"which is needed for authorities that do not serve it, such as ADFS and private clouds. Only set it " +
+					"when the authority is trusted. Can also be set with the AZRANDOM_DISABLE_INSTANCE_DISCOVERY " +
+					"environment variable. Default value is false.",
+				Optional: true,
+			},
+			"disable_managed_identity_credential": schema.BoolAttribute{
+				Description: "Disable Managed Indentity credentials in the DefaultAzureCredential chain. Can also be set with the " +
+					"AZRANDOM_DISABLE_MANAGED_IDENTITY_CREDENTIAL environment variable.",
+				Optional: true,
 			},
 			"disable_workload_identity_credential": schema.BoolAttribute{
-				Description: "Disable Workload Indentity credentials in the DefaultAzureCredential chain.",
-				Optional:    true,
+				Description: "Disable Workload Indentity credentials in the DefaultAzureCredential chain. Can also be set with the " +
+					"AZRANDOM_DISABLE_WORKLOAD_IDENTITY_CREDENTIAL environment variable.",
+				Optional: true,
 			},
 			"disable_azure_cli_credential": schema.BoolAttribute{
-				Description: "Disable CLI credentials in the DefaultAzureCredential chain.",
-				Optional:    true,
+				Description: "Disable CLI credentials in the DefaultAzureCredential chain. Can also be set with the " +
+					"AZRANDOM_DISABLE_CLI_CREDENTIAL environment variable.",
+				Optional: true,
 			},
 			"disable_azure_developer_cli_credential": schema.BoolAttribute{
-				Description: "Disable Developer CLI credentials in the DefaultAzureCredential chain.",
-				Optional:    true,
+				Description: "Disable Developer CLI credentials in the DefaultAzureCredential chain. Can also be set with the " +
+					"AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL environment variable.",
+				Optional: true,
 			},
 			"disable_environment_credential": schema.BoolAttribute{
-				Description: "Disable Environment credentials in the DefaultAzureCredential chain.",
+				Description: "Disable Environment credentials in the DefaultAzureCredential chain. Can also be set with the " +
+					"AZRANDOM_DISABLE_ENVIRONMENT_CREDENTIAL environment variable.",
+				Optional: true,
+			},
+			"use_interactive_browser": schema.BoolAttribute{
+				Description: "Sign in interactively in a browser when no other credential can authenticate, e.g. for " +
+					"local development without the Azure CLI. Cannot be enabled when the TF_IN_AUTOMATION environment " +
+					"variable is set, so that automated runs never wait for a sign-in. Can also be set with the " +
+					"AZRANDOM_USE_INTERACTIVE_BROWSER environment variable. Default value is false.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("use_device_code")),
+				},
+			},
+			"use_device_code": schema.BoolAttribute{
+				Description: "Sign in with a device code when no other credential can authenticate, e.g. for local " +
+					"development on a host without a browser. The device code and where to enter it are written to the " +
+					"provider log at warn level, so run with `TF_LOG_PROVIDER=WARN` to see them. Cannot be enabled when " +
+					"the TF_IN_AUTOMATION environment variable is set. Can also be set with the AZRANDOM_USE_DEVICE_CODE " +
+					"environment variable. Default value is false.",
+				Optional: true,
+			},
+			"token_cache_name": schema.StringAttribute{
+				Description: "Persist the tokens of the service principal, interactive browser and device code credentials " +
+					"in the encrypted token cache of the operating system under this name, so that they are reused across " +
+					"runs instead of signing in every time. The other credentials rely on the cache of their own tooling. " +
+					"When persistent caching is unavailable, e.g. on Linux hosts without a keyring, tokens are kept in memory " +
+					"with a warning. Can also be set with the AZRANDOM_TOKEN_CACHE_NAME environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(tokenCacheNameRegex, "must only contain letters, digits, `.`, `_` and `-`"),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of the proxy to send the requests to the vault and to Microsoft Entra ID through, with one " +
+					"of the schemes " + strings.Join(supportedProxySchemesStr(), ", ") + ", such as " +
+					"`http://proxy.example.com:3128`. Requests to managed identity endpoints on the host are not proxied. " +
+					"Can also be set with the AZRANDOM_PROXY_URL environment variable. By default the HTTPS_PROXY and " +
+					"NO_PROXY environment variables are used.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.URLScheme(supportedProxySchemesStr()...),
+				},
+			},
+			"proxy_username": schema.StringAttribute{
+				Description: "Username to authenticate to the proxy given by proxy_url with. Can also be set with the " +
+					"AZRANDOM_PROXY_USERNAME environment variable.",
+				Optional: true,
+			},
+			"proxy_password": schema.StringAttribute{
+				Description: "Password to authenticate to the proxy given by proxy_url with, together with proxy_username. " +
+					"Can also be set with the AZRANDOM_PROXY_PASSWORD environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"partner_id": schema.StringAttribute{
+				Description: "GUID of the partner to attribute the requests to the vault and to Microsoft Entra ID to, which " +
+					"is sent in their User-Agent together with the version of the provider. Can also be set with the " +
+					"AZRANDOM_PARTNER_ID environment variable. Defaults to the partner ID of Terraform.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.UUID(),
+				},
+			},
+			"disable_terraform_partner_id": schema.BoolAttribute{
+				Description: "Do not attribute requests to Terraform with its partner ID when partner_id is not set. Can " +
+					"also be set with the AZRANDOM_DISABLE_TERRAFORM_PARTNER_ID environment variable. Default value is false.",
+				Optional: true,
+			},
+			"operation_timeout": schema.StringAttribute{
+				Description: "Maximum duration of each operation on a secret in the vault, including the retries of its " +
+					"requests, such as \"30s\", after which the operation fails with an error naming the secret. It keeps " +
+					"resources of an unreachable vault, e.g. behind a firewall, from hanging. Can also be set with the " +
+					"AZRANDOM_OPERATION_TIMEOUT environment variable. By default operations are not timed out.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.PositiveDuration(),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of requests to the vaults in flight at once, shared by all resources of the " +
+					"provider regardless of the parallelism of Terraform, so that configurations with many resources stay " +
+					"below the transaction limits of Key Vault. Requests wait for a free slot, which counts towards " +
+					"operation_timeout. Can also be set with the AZRANDOM_MAX_CONCURRENT_REQUESTS environment variable. " +
+					"By default requests are not limited.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, math.MaxInt32),
+				},
+			},
+			"sdk_log_level": schema.StringAttribute{
+				Description: "Events of the Azure SDK to write to the log of Terraform, independently of TF_LOG, one of " +
+					strings.Join(azrandom.SDKLogLevels(), ", ") + ". debug writes the token requests of the credentials and " +
+					"the retries and errors of the requests to the vaults at the DEBUG level, and trace writes every request " +
+					"and response as well at the TRACE level. Authorization headers, tokens and secret values are redacted. " +
+					"Can also be set with the AZRANDOM_SDK_LOG_LEVEL environment variable. Defaults to debug.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(azrandom.SDKLogLevels()...),
+				},
+			},
+			"custom_endpoint": schema.StringAttribute{
+				Description: "URL of a service implementing the secrets API of Azure Key Vault, such as the emulator " +
+					"`https://localhost:8443`, to store the secrets in instead of the vault given by vault_url or vault_name. " +
+					"Its host may have any DNS suffix and a port. Requires allow_insecure. Can also be set with the " +
+					"AZRANDOM_CUSTOM_ENDPOINT environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.URLScheme(HTTPSScheme.String()),
+					stringvalidator.ConflictsWith(path.MatchRoot("vault_url"), path.MatchRoot("vault_name"), path.MatchRoot("dns_suffix")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip the verification of the TLS certificates of the vault and of the authority, e.g. to " +
+					"accept the self-signed certificate of an emulator. Requires allow_insecure. Can also be set with the " +
+					"AZRANDOM_INSECURE_SKIP_VERIFY environment variable. Default value is false.",
+				Optional: true,
+			},
+			"use_fake_credential": schema.BoolAttribute{
+				Description: "Authenticate with a static token instead of a token of Microsoft Entra ID, which emulators " +
+					"accept but vaults in Azure reject. Requires allow_insecure. Can also be set with the " +
+					"AZRANDOM_USE_FAKE_CREDENTIAL environment variable. Default value is false.",
+				Optional: true,
+			},
+			"allow_insecure": schema.BoolAttribute{
+				Description: "Allow custom_endpoint, insecure_skip_verify and use_fake_credential, which are meant for " +
+					"testing against an emulator only. It has no environment variable, so that they cannot be enabled " +
+					"without changing the configuration. Default value is false.",
+				Optional: true,
+			},
+			"skip_credential_validation": schema.BoolAttribute{
+				Description: "Skip acquiring a token for the vault while configuring the provider. By default, authentication " +
+					"failures are reported once, with a specific remedy, when the provider is configured, rather than by the " +
+					"first secret operation. Set it to plan offline. Can also be set with the AZRANDOM_SKIP_CREDENTIAL_VALIDATION " +
+					"environment variable. Default value is false.",
+				Optional: true,
+			},
+			"validate_access": schema.BoolAttribute{
+				Description: "Get a secret from the vault while configuring the provider, so that a vault that cannot be " +
+					"reached, e.g. because of its firewall, or an identity that may not get its secrets is reported once, " +
+					"rather than by every resource. The secret does not need to exist, so empty vaults pass. Can also be set " +
+					"with the AZRANDOM_VALIDATE_ACCESS environment variable. Default value is false.",
+				Optional: true,
+			},
+			"verify_write": schema.BoolAttribute{
+				Description: "Read back every value written to the vault and compare it with the value that was sent, " +
+					"failing the apply when they differ. Requires permission to get secret values. " +
+					"Can be overridden with the verify_write attribute of each resource. Default value is false.",
+				Optional: true,
+			},
+			"skip_existence_check": schema.BoolAttribute{
+				Description: "Skip checking whether the secret of a resource already exists before creating it, so that " +
+					"creating a resource takes a single request to the vault instead of two. A secret that already exists " +
+					"is then overwritten with a new version, as if conflict_policy were `overwrite`, rather than failing the " +
+					"apply. conflict_policy set to `adopt` still adopts the existing secret. Can also be set with the " +
+					"AZRANDOM_SKIP_EXISTENCE_CHECK environment variable. Default value is false.",
+				Optional: true,
+			},
+			"strict_import": schema.BoolAttribute{
+				Description: "Fail the import of a resource whose secret does not hold a value that the resource could " +
+					"have generated, e.g. an azrandom_uuid whose secret does not hold a UUID, instead of warning about it. " +
+					"Such a value is overwritten as soon as the resource generates a new value. Can also be set with the " +
+					"AZRANDOM_STRICT_IMPORT environment variable. Default value is false.",
+				Optional: true,
+			},
+			"default_tags": schema.MapAttribute{
+				Description: "Tags to set on every secret written by this provider. The tags attribute of a resource " +
+					"overrides the default tags with the same keys. Changing the default tags does not generate new values.",
+				ElementType: types.StringType,
 				Optional:    true,
+				Validators:  tagsValidators(),
+			},
+			"write_metadata_tags": schema.BoolAttribute{
+				Description: "Tag every secret written by this provider with `" + managedByTagName + "`, `" +
+					providerVersionTagName + "` and, when the `TF_WORKSPACE` or `TFC_WORKSPACE_NAME` environment variable " +
+					"is set, `" + workspaceTagName + "`, so that secrets managed by Terraform can be told apart when auditing " +
+					"a vault. The tags are written when a secret or its properties are updated. Default value is false.",
+				Optional: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"retry": retryBlock(),
+		},
+	}
+}
+
+// servicePrincipalDescription documents how the service principal attributes select the credential.
+const servicePrincipalDescription = "When tenant_id, client_id and either client_secret or client_certificate_path are " +
+	"set, the provider authenticates as that service principal instead of using the DefaultAzureCredential chain, so they cannot be combined with the " +
+	"disable_*_credential attributes."
+
+// servicePrincipalAttributes select a service principal instead of the DefaultAzureCredential chain.
+var servicePrincipalAttributes = []string{
+	"tenant_id",
+	"client_id",
+	"client_secret",
+	"client_certificate_path",
+	"client_certificate_password",
+}
+
+// managedIdentityAttributes select the user-assigned identity of the Managed Identity credential.
+var managedIdentityAttributes = []string{
+	"managed_identity_client_id",
+	"managed_identity_resource_id",
+}
+
+// proxyAttributes select the proxy that requests are sent through.
+var proxyAttributes = []string{
+	"proxy_url",
+	"proxy_username",
+	"proxy_password",
+}
+
+// tokenCacheNameRegex matches names of the persistent token cache, which become part of the name of its file.
+var tokenCacheNameRegex = regexp.MustCompile(`^[0-9a-zA-Z._-]+$`)
+
+// disableCredentialAttributes disable each of the credentials in the DefaultAzureCredential chain.
+var disableCredentialAttributes = []string{
+	"disable_environment_credential",
+	"disable_workload_identity_credential",
+	"disable_managed_identity_credential",
+	"disable_azure_cli_credential",
+	"disable_azure_developer_cli_credential",
+}
+
+// ValidateConfig rejects combinations of attributes that leave the provider unable to authenticate. Only
+// known values are checked, as unset attributes may still be given by environment variables.
+func (p *azrandomProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	allDisabled, anyDisableSet := true, false
+	for _, name := range disableCredentialAttributes {
+		var disabled types.Bool
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &disabled)...)
+		if !disabled.IsNull() {
+			anyDisableSet = true
+		}
+		if disabled.IsNull() || disabled.IsUnknown() || !disabled.ValueBool() {
+			allDisabled = false
+		}
+	}
+	servicePrincipalSet := false
+	for _, name := range servicePrincipalAttributes {
+		var value types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		if !value.IsNull() {
+			servicePrincipalSet = true
+		}
+	}
+	var authMethod types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth_method"), &authMethod)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An auth method pins the credential, which the other credential settings would contradict
+	if !authMethod.IsNull() && (anyDisableSet || servicePrincipalSet) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_method"),
+			"Conflicting Auth Method",
+			"The auth_method attribute selects the credential to authenticate with, so it cannot be combined with "+
+				strings.Join(disableCredentialAttributes, ", ")+" or the service principal given by "+
+				strings.Join(servicePrincipalAttributes, ", ")+". Remove either auth_method or the other credential "+
+				"attributes from the configuration.",
+		)
+		return
+	}
+
+	// The DefaultAzureCredential chain is not used when authenticating as a service principal
+	if servicePrincipalSet && anyDisableSet {
+		resp.Diagnostics.AddError(
+			"Conflicting Credential Settings",
+			"The provider authenticates as the service principal given by "+strings.Join(servicePrincipalAttributes, ", ")+
+				" instead of using the DefaultAzureCredential chain, so "+strings.Join(disableCredentialAttributes, ", ")+
+				" have no effect. Remove either the service principal or the disable_*_credential attributes from the configuration.",
+		)
+		return
+	}
+
+	var allowInsecure, insecureSkipVerify, useFakeCredential types.Bool
+	var customEndpoint types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("allow_insecure"), &allowInsecure)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("custom_endpoint"), &customEndpoint)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("insecure_skip_verify"), &insecureSkipVerify)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("use_fake_credential"), &useFakeCredential)...)
+	if !allowInsecure.IsUnknown() && !allowInsecure.ValueBool() {
+		set := map[string]bool{
+			"custom_endpoint":      !customEndpoint.IsNull(),
+			"insecure_skip_verify": insecureSkipVerify.ValueBool(),
+			"use_fake_credential":  useFakeCredential.ValueBool(),
+		}
+		for _, name := range insecureAttributes {
+			if set[name] {
+				resp.Diagnostics.Append(insecureDiagnostic(name))
+			}
+		}
+	}
+
+	if allDisabled {
+		resp.Diagnostics.AddError(
+			"No Credential Enabled",
+			"All credentials of the DefaultAzureCredential chain are disabled, so the provider has no way to authenticate. "+
+				"Set at least one of "+strings.Join(disableCredentialAttributes, ", ")+" to false, or remove it from the configuration.",
+		)
+	}
+}
+
+// insecureAttributes are meant for testing against an emulator of Key Vault only, and require allow_insecure.
+var insecureAttributes = []string{"custom_endpoint", "insecure_skip_verify", "use_fake_credential"}
+
+// insecureDiagnostic reports that the attribute name, which is one of insecureAttributes, is set without
+// allow_insecure.
+func insecureDiagnostic(name string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root(name),
+		"Insecure Azrandom Option Not Allowed",
+		name+" (possibly set through the AZRANDOM_"+strings.ToUpper(name)+" environment variable) is meant for "+
+			"testing against an emulator of Key Vault, and would send tokens or secrets to a server that is not verified "+
+			"to be a vault. Set allow_insecure = true in the configuration to use it.",
+	)
+}
+
+// managedHSMDiagnostic reports that the vault at vaultUrl, given by the attribute at attributePath, is a
+// Managed HSM pool, which the provider cannot store secrets in.
+func managedHSMDiagnostic(attributePath path.Path, vaultUrl string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		attributePath,
+		"Unsupported Azrandom Vault Type",
+		"The vault "+vaultUrl+" (possibly given through the AZRANDOM_VAULT_URL or AZRANDOM_DNS_SUFFIX environment "+
+			"variable) is a Managed HSM pool. Managed HSM pools only store keys, and have no secrets API that the "+
+			"values generated by this provider could be stored in. Use a Key Vault instead.",
+	)
+}
+
+// redactedProxyUrl returns proxyUrl without the password of its user info, so that it can be logged.
+func redactedProxyUrl(proxyUrl string) string {
+	u, err := url.Parse(proxyUrl)
+	if err != nil {
+		return ""
 	}
+	return u.Redacted()
 }
 
 func GetBoolEnv(envVarName string) (bool, error) {
@@ -119,12 +647,75 @@ func (p *azrandomProvider) Configure(ctx context.Context, req provider.Configure
 	// If practitioner provided a configuration value for any of the
 	// attributes, it must be a known value.
 
-	if config.VaultUrl.IsUnknown() {
+	// The vault may be given by another resource of the configuration, e.g. the azurerm_key_vault that holds the
+	// secrets. Until it is known, Terraform defers the resources of the provider when it supports deferred actions.
+	if config.VaultUrl.IsUnknown() || config.VaultName.IsUnknown() || config.DNSSuffix.IsUnknown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			tflog.Info(ctx, "The Azure Key Vault is not known yet, deferring the resources of the provider")
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		}
+
+		for _, attribute := range []struct {
+			name  string
+			value types.String
+		}{{"vault_url", config.VaultUrl}, {"vault_name", config.VaultName}, {"dns_suffix", config.DNSSuffix}} {
+			if attribute.value.IsUnknown() {
+				envVarName := "AZRANDOM_" + strings.ToUpper(attribute.name)
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Unknown Azrandom Vault",
+					"The provider cannot create the Azrandom API client as there is an unknown configuration value for "+
+						attribute.name+", e.g. the URL of an azurerm_key_vault created in the same configuration. This "+
+						"version of Terraform does not support deferred actions, so either target apply the source of the "+
+						"value first, set the value statically in the configuration, or use the "+envVarName+
+						" environment variable.",
+				)
+			}
+		}
+		return
+	}
+
+	if config.Environment.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("vault_url"),
-			"Unknown Azrandom Vault Url",
-			"The provider cannot create the Azrandom API client as there is an unknown configuration value for the Azrandom Vault Url. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the AZRANDOM_VAULT_URL environment variable.",
+			path.Root("environment"),
+			"Unknown Azrandom Environment",
+			"The provider cannot create the Azrandom API client as there is an unknown configuration value for the Azrandom Environment. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the AZRANDOM_ENVIRONMENT environment variable.",
+		)
+	}
+
+	for _, names := range [][]string{{"auth_method", "custom_authority_host", "token_cache_name", "partner_id", "operation_timeout", "sdk_log_level", "custom_endpoint"}, proxyAttributes, servicePrincipalAttributes, managedIdentityAttributes} {
+		for _, name := range names {
+			var value types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+			if value.IsUnknown() {
+				envVarName := "AZRANDOM_" + strings.ToUpper(name)
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name),
+					"Unknown Azrandom "+name,
+					"The provider cannot create the Azrandom API client as there is an unknown configuration value for "+name+". "+
+						"Either target apply the source of the value first, set the value statically in the configuration, or use the "+envVarName+" environment variable.",
+				)
+			}
+		}
+	}
+
+	if config.MaxConcurrentRequests.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_requests"),
+			"Unknown Azrandom Max Concurrent Requests",
+			"The provider cannot create the Azrandom API client as there is an unknown configuration value for the maximum number of concurrent requests. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the AZRANDOM_MAX_CONCURRENT_REQUESTS environment variable.",
+		)
+	}
+
+	if config.AdditionallyAllowedTenants.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("additionally_allowed_tenants"),
+			"Unknown Azrandom Additionally Allowed Tenants",
+			"The provider cannot create the Azrandom API client as there is an unknown configuration value for the additionally allowed tenants. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the AZRANDOM_ADDITIONALLY_ALLOWED_TENANTS environment variable.",
 		)
 	}
 
@@ -135,88 +726,786 @@ func (p *azrandomProvider) Configure(ctx context.Context, req provider.Configure
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
 
-	vault_url := os.Getenv("AZRANDOM_VAULT_URL")
-	disable_managed_identity_credential, err := GetBoolEnv("AZRANDOM_DISABLE_MANAGED_IDENTITY_CREDENTIAL")
+	vaultUrl := os.Getenv("AZRANDOM_VAULT_URL")
+	vaultName := os.Getenv("AZRANDOM_VAULT_NAME")
+	dnsSuffix := os.Getenv("AZRANDOM_DNS_SUFFIX")
+	environment := os.Getenv("AZRANDOM_ENVIRONMENT")
+	customAuthorityHost := os.Getenv("AZRANDOM_CUSTOM_AUTHORITY_HOST")
+	authMethod := os.Getenv("AZRANDOM_AUTH_METHOD")
+	tenantID := os.Getenv("AZRANDOM_TENANT_ID")
+	clientID := os.Getenv("AZRANDOM_CLIENT_ID")
+	clientSecret := os.Getenv("AZRANDOM_CLIENT_SECRET")
+	clientCertificatePath := os.Getenv("AZRANDOM_CLIENT_CERTIFICATE_PATH")
+	clientCertificatePassword := os.Getenv("AZRANDOM_CLIENT_CERTIFICATE_PASSWORD")
+	managedIdentityClientID := os.Getenv("AZRANDOM_MANAGED_IDENTITY_CLIENT_ID")
+	managedIdentityResourceID := os.Getenv("AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID")
+	var additionallyAllowedTenants []string
+	if tenants := os.Getenv("AZRANDOM_ADDITIONALLY_ALLOWED_TENANTS"); tenants != "" {
+		for _, tenant := range strings.Split(tenants, ";") {
+			if tenant = strings.TrimSpace(tenant); tenant != "" {
+				additionallyAllowedTenants = append(additionallyAllowedTenants, tenant)
+			}
+		}
+	}
+	disableInstanceDiscovery, err := GetBoolEnv("AZRANDOM_DISABLE_INSTANCE_DISCOVERY")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("disable_instance_discovery"),
+			"Error parsing AZRANDOM_DISABLE_INSTANCE_DISCOVERY", err.Error(),
+		)
+	}
+	disableManagedIdentityCredential, err := GetBoolEnv("AZRANDOM_DISABLE_MANAGED_IDENTITY_CREDENTIAL")
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("disable_managed_identity_credential"),
 			"Error parsing AZRANDOM_DISABLE_MANAGED_IDENTITY_CREDENTIAL", err.Error(),
 		)
 	}
-	disable_workload_identity_credential, err := GetBoolEnv("AZRANDOM_DISABLE_WORKLOAD_IDENTITY_CREDENTIAL")
+	disableWorkloadIdentityCredential, err := GetBoolEnv("AZRANDOM_DISABLE_WORKLOAD_IDENTITY_CREDENTIAL")
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("disable_workload_identity_credential"),
 			"Error parsing AZRANDOM_DISABLE_WORKLOAD_IDENTITY_CREDENTIAL", err.Error(),
 		)
 	}
-	disable_azure_cli_credential, err := GetBoolEnv("AZRANDOM_DISABLE_CLI_CREDENTIAL")
+	disableAzureCLICredential, err := GetBoolEnv("AZRANDOM_DISABLE_CLI_CREDENTIAL")
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("disable_azure_cli_credential"),
 			"Error parsing AZRANDOM_DISABLE_CLI_CREDENTIAL", err.Error(),
 		)
 	}
-	disable_environment_credential, err := GetBoolEnv("AZRANDOM_DISABLE_ENVIRONMENT_CREDENTIAL")
+	disableEnvironmentCredential, err := GetBoolEnv("AZRANDOM_DISABLE_ENVIRONMENT_CREDENTIAL")
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("disable_environment_credential"),
 			"Error parsing AZRANDOM_DISABLE_ENVIRONMENT_CREDENTIAL", err.Error(),
 		)
 	}
-	disable_azure_developer_cli_credential, err := GetBoolEnv("AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL")
+	// AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL is the misspelled name this variable was introduced with
+	developerCLIEnv := "AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL"
+	if os.Getenv("AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL") != "" {
+		if os.Getenv(developerCLIEnv) == "" {
+			developerCLIEnv = "AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL"
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("disable_azure_developer_cli_credential"),
+			"Deprecated Azrandom Environment Variable",
+			"The AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL environment variable is deprecated and will be removed in a "+
+				"future version. Use AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL instead, which takes precedence when both are set.",
+		)
+	}
+	disableAzureDeveloperCLICredential, err := GetBoolEnv(developerCLIEnv)
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("disable_azure_developer_cli_credential"),
-			"Error parsing AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL", err.Error(),
+			"Error parsing "+developerCLIEnv, err.Error(),
+		)
+	}
+	useInteractiveBrowser, err := GetBoolEnv("AZRANDOM_USE_INTERACTIVE_BROWSER")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_interactive_browser"),
+			"Error parsing AZRANDOM_USE_INTERACTIVE_BROWSER", err.Error(),
+		)
+	}
+	useDeviceCode, err := GetBoolEnv("AZRANDOM_USE_DEVICE_CODE")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_device_code"),
+			"Error parsing AZRANDOM_USE_DEVICE_CODE", err.Error(),
+		)
+	}
+	tokenCacheName := os.Getenv("AZRANDOM_TOKEN_CACHE_NAME")
+	proxyUrl := os.Getenv("AZRANDOM_PROXY_URL")
+	proxyUsername := os.Getenv("AZRANDOM_PROXY_USERNAME")
+	proxyPassword := os.Getenv("AZRANDOM_PROXY_PASSWORD")
+	partnerID := os.Getenv("AZRANDOM_PARTNER_ID")
+	disableTerraformPartnerID, err := GetBoolEnv("AZRANDOM_DISABLE_TERRAFORM_PARTNER_ID")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("disable_terraform_partner_id"),
+			"Error parsing AZRANDOM_DISABLE_TERRAFORM_PARTNER_ID", err.Error(),
+		)
+	}
+	operationTimeout := os.Getenv("AZRANDOM_OPERATION_TIMEOUT")
+	var maxConcurrentRequests int64
+	if env := os.Getenv("AZRANDOM_MAX_CONCURRENT_REQUESTS"); env != "" {
+		maxConcurrentRequests, err = strconv.ParseInt(env, 10, 32)
+		if err != nil || maxConcurrentRequests < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_requests"),
+				"Error parsing AZRANDOM_MAX_CONCURRENT_REQUESTS",
+				"The AZRANDOM_MAX_CONCURRENT_REQUESTS environment variable must be a positive number, got: "+env,
+			)
+		}
+	}
+	sdkLogLevel := os.Getenv("AZRANDOM_SDK_LOG_LEVEL")
+	customEndpoint := os.Getenv("AZRANDOM_CUSTOM_ENDPOINT")
+	insecureSkipVerify, err := GetBoolEnv("AZRANDOM_INSECURE_SKIP_VERIFY")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure_skip_verify"),
+			"Error parsing AZRANDOM_INSECURE_SKIP_VERIFY", err.Error(),
+		)
+	}
+	useFakeCredential, err := GetBoolEnv("AZRANDOM_USE_FAKE_CREDENTIAL")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_fake_credential"),
+			"Error parsing AZRANDOM_USE_FAKE_CREDENTIAL", err.Error(),
+		)
+	}
+	skipCredentialValidation, err := GetBoolEnv("AZRANDOM_SKIP_CREDENTIAL_VALIDATION")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_credential_validation"),
+			"Error parsing AZRANDOM_SKIP_CREDENTIAL_VALIDATION", err.Error(),
+		)
+	}
+	validateAccess, err := GetBoolEnv("AZRANDOM_VALIDATE_ACCESS")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("validate_access"),
+			"Error parsing AZRANDOM_VALIDATE_ACCESS", err.Error(),
+		)
+	}
+	verifyWrite, err := GetBoolEnv("AZRANDOM_VERIFY_WRITE")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("verify_write"),
+			"Error parsing AZRANDOM_VERIFY_WRITE", err.Error(),
+		)
+	}
+	skipExistenceCheck, err := GetBoolEnv("AZRANDOM_SKIP_EXISTENCE_CHECK")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_existence_check"),
+			"Error parsing AZRANDOM_SKIP_EXISTENCE_CHECK", err.Error(),
+		)
+	}
+	strictImport, err := GetBoolEnv("AZRANDOM_STRICT_IMPORT")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("strict_import"),
+			"Error parsing AZRANDOM_STRICT_IMPORT", err.Error(),
+		)
+	}
+	writeMetadataTags, err := GetBoolEnv("AZRANDOM_WRITE_METADATA_TAGS")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("write_metadata_tags"),
+			"Error parsing AZRANDOM_WRITE_METADATA_TAGS", err.Error(),
 		)
 	}
+	retryOptions, diags := retryOptions(config.Retry)
+	resp.Diagnostics.Append(diags...)
 
+	// A vault configured in the configuration takes precedence over either environment variable
 	if !config.VaultUrl.IsNull() {
-		vault_url = config.VaultUrl.ValueString()
+		vaultUrl = config.VaultUrl.ValueString()
+		vaultName = ""
+	}
+	if !config.VaultName.IsNull() {
+		vaultName = config.VaultName.ValueString()
+		if config.VaultUrl.IsNull() {
+			vaultUrl = ""
+		}
+	}
+	// Likewise, a custom endpoint in the configuration takes precedence over a vault in the environment, and vice versa
+	if !config.CustomEndpoint.IsNull() {
+		customEndpoint = config.CustomEndpoint.ValueString()
+		if config.VaultUrl.IsNull() && config.VaultName.IsNull() {
+			vaultUrl = ""
+			vaultName = ""
+		}
+	} else if !config.VaultUrl.IsNull() || !config.VaultName.IsNull() {
+		customEndpoint = ""
+	}
+	if !config.DNSSuffix.IsNull() {
+		dnsSuffix = config.DNSSuffix.ValueString()
+	}
+	if !config.Environment.IsNull() {
+		environment = config.Environment.ValueString()
+	}
+	if !config.CustomAuthorityHost.IsNull() {
+		customAuthorityHost = config.CustomAuthorityHost.ValueString()
+	}
+	if !config.AuthMethod.IsNull() {
+		authMethod = config.AuthMethod.ValueString()
+	}
+	if !config.TenantID.IsNull() {
+		tenantID = config.TenantID.ValueString()
+	}
+	if !config.ClientID.IsNull() {
+		clientID = config.ClientID.ValueString()
+	}
+	if !config.ClientSecret.IsNull() {
+		clientSecret = config.ClientSecret.ValueString()
+	}
+	if !config.ClientCertificatePath.IsNull() {
+		clientCertificatePath = config.ClientCertificatePath.ValueString()
+	}
+	if !config.ClientCertificatePassword.IsNull() {
+		clientCertificatePassword = config.ClientCertificatePassword.ValueString()
+	}
+	// An identity configured in the configuration takes precedence over either environment variable
+	if !config.ManagedIdentityClientID.IsNull() {
+		managedIdentityClientID = config.ManagedIdentityClientID.ValueString()
+		managedIdentityResourceID = ""
+	}
+	if !config.ManagedIdentityResourceID.IsNull() {
+		managedIdentityResourceID = config.ManagedIdentityResourceID.ValueString()
+		if config.ManagedIdentityClientID.IsNull() {
+			managedIdentityClientID = ""
+		}
+	}
+	if !config.AdditionallyAllowedTenants.IsNull() {
+		additionallyAllowedTenants = nil
+		resp.Diagnostics.Append(config.AdditionallyAllowedTenants.ElementsAs(ctx, &additionallyAllowedTenants, false)...)
+	}
+	if !config.DisableInstanceDiscovery.IsNull() {
+		disableInstanceDiscovery = config.DisableInstanceDiscovery.ValueBool()
 	}
 	if !config.DisableManagedIdentityCredential.IsNull() {
-		disable_managed_identity_credential = config.DisableManagedIdentityCredential.ValueBool()
+		disableManagedIdentityCredential = config.DisableManagedIdentityCredential.ValueBool()
 	}
 	if !config.DisableWorkloadIdentityCredential.IsNull() {
-		disable_workload_identity_credential = config.DisableWorkloadIdentityCredential.ValueBool()
+		disableWorkloadIdentityCredential = config.DisableWorkloadIdentityCredential.ValueBool()
 	}
 	if !config.DisableAzureCLICredential.IsNull() {
-		disable_azure_cli_credential = config.DisableAzureCLICredential.ValueBool()
+		disableAzureCLICredential = config.DisableAzureCLICredential.ValueBool()
 	}
 	if !config.DisableAzureDeveloperCLICredential.IsNull() {
-		disable_environment_credential = config.DisableAzureDeveloperCLICredential.ValueBool()
+		disableAzureDeveloperCLICredential = config.DisableAzureDeveloperCLICredential.ValueBool()
 	}
 	if !config.DisableEnvironmentCredential.IsNull() {
-		disable_azure_developer_cli_credential = config.DisableEnvironmentCredential.ValueBool()
+		disableEnvironmentCredential = config.DisableEnvironmentCredential.ValueBool()
+	}
+	if !config.UseInteractiveBrowser.IsNull() {
+		useInteractiveBrowser = config.UseInteractiveBrowser.ValueBool()
+	}
+	if !config.UseDeviceCode.IsNull() {
+		useDeviceCode = config.UseDeviceCode.ValueBool()
+	}
+	if !config.TokenCacheName.IsNull() {
+		tokenCacheName = config.TokenCacheName.ValueString()
+	}
+	if !config.ProxyUrl.IsNull() {
+		proxyUrl = config.ProxyUrl.ValueString()
+	}
+	if !config.ProxyUsername.IsNull() {
+		proxyUsername = config.ProxyUsername.ValueString()
+	}
+	if !config.ProxyPassword.IsNull() {
+		proxyPassword = config.ProxyPassword.ValueString()
+	}
+	if !config.PartnerID.IsNull() {
+		partnerID = config.PartnerID.ValueString()
+	}
+	if !config.DisableTerraformPartnerID.IsNull() {
+		disableTerraformPartnerID = config.DisableTerraformPartnerID.ValueBool()
+	}
+	if !config.OperationTimeout.IsNull() {
+		operationTimeout = config.OperationTimeout.ValueString()
+	}
+	if !config.SDKLogLevel.IsNull() {
+		sdkLogLevel = config.SDKLogLevel.ValueString()
+	}
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = config.MaxConcurrentRequests.ValueInt64()
+	}
+	if !config.InsecureSkipVerify.IsNull() {
+		insecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	}
+	if !config.UseFakeCredential.IsNull() {
+		useFakeCredential = config.UseFakeCredential.ValueBool()
+	}
+	if !config.SkipCredentialValidation.IsNull() {
+		skipCredentialValidation = config.SkipCredentialValidation.ValueBool()
+	}
+	if !config.ValidateAccess.IsNull() {
+		validateAccess = config.ValidateAccess.ValueBool()
+	}
+	if !config.VerifyWrite.IsNull() {
+		verifyWrite = config.VerifyWrite.ValueBool()
+	}
+	if !config.SkipExistenceCheck.IsNull() {
+		skipExistenceCheck = config.SkipExistenceCheck.ValueBool()
+	}
+	if !config.StrictImport.IsNull() {
+		strictImport = config.StrictImport.ValueBool()
+	}
+	if !config.WriteMetadataTags.IsNull() {
+		writeMetadataTags = config.WriteMetadataTags.ValueBool()
+	}
+
+	// The options for emulators are refused unless explicitly allowed in the configuration
+	if !config.AllowInsecure.ValueBool() {
+		set := map[string]bool{
+			"custom_endpoint":      customEndpoint != "",
+			"insecure_skip_verify": insecureSkipVerify,
+			"use_fake_credential":  useFakeCredential,
+		}
+		for _, name := range insecureAttributes {
+			if set[name] {
+				resp.Diagnostics.Append(insecureDiagnostic(name))
+			}
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	if vault_url == "" {
+	switch {
+	case customEndpoint != "" && (vaultUrl != "" || vaultName != "" || dnsSuffix != ""):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("custom_endpoint"),
+			"Conflicting Azrandom Custom Endpoint",
+			"The custom endpoint replaces the vault, so vault_url, vault_name and dns_suffix cannot be set with it "+
+				"(possibly through the AZRANDOM_VAULT_URL, AZRANDOM_VAULT_NAME and AZRANDOM_DNS_SUFFIX environment "+
+				"variables). Remove either custom_endpoint or the vault.",
+		)
+	case customEndpoint != "":
+		err = validators.ValidateURLScheme(customEndpoint, []string{HTTPSScheme.String()})
+		if err == nil {
+			err = azrandom.ValidateCustomEndpoint(customEndpoint)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("custom_endpoint"),
+				"Invalid Azrandom Custom Endpoint",
+				"The custom endpoint (possibly given through the AZRANDOM_CUSTOM_ENDPOINT environment variable) is not "+
+					"valid: "+err.Error(),
+			)
+		}
+		vaultUrl = customEndpoint
+	case vaultUrl != "" && vaultName != "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_name"),
+			"Conflicting Azrandom Vault Url and Vault Name",
+			"Only one of vault_url and vault_name may be set, but both are (possibly through the AZRANDOM_VAULT_URL and "+
+				"AZRANDOM_VAULT_NAME environment variables). Remove one of them.",
+		)
+	case vaultUrl == "" && vaultName == "":
 		resp.Diagnostics.AddAttributeError(
 			path.Root("vault_url"),
-			"Missing Azrandom API VaultUrl",
-			"The provider cannot create the Azrandom API client as there is a missing or empty value for the Azrandom API vault_url. "+
-				"Set the vault_url value in the configuration or use the AZRANDOM_VAUL_URL environment variable. "+
+			"Missing Azrandom Vault Url or Vault Name",
+			"The provider cannot create the Azrandom API client as neither vault_url nor vault_name is set. "+
+				"Set one of them in the configuration, or use the AZRANDOM_VAULT_URL or AZRANDOM_VAULT_NAME environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
+	case !config.DNSSuffix.IsNull() && vaultName == "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dns_suffix"),
+			"Conflicting Azrandom Vault Url and DNS Suffix",
+			"The dns_suffix attribute only applies to the vault given by vault_name, but the vault is given by its URL "+
+				"(possibly through the AZRANDOM_VAULT_URL environment variable). Remove dns_suffix, or set vault_name instead.",
+		)
+	case vaultName != "" && dnsSuffix != "":
+		if err := azrandom.ValidateDNSSuffix(dnsSuffix); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("dns_suffix"),
+				"Invalid Azrandom DNS Suffix",
+				"The DNS suffix (possibly given through the AZRANDOM_DNS_SUFFIX environment variable) is not valid: "+err.Error(),
+			)
+			break
+		}
+		vaultUrl, err = azrandom.VaultURLWithDNSSuffix(vaultName, dnsSuffix)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vault_name"),
+				"Invalid Azrandom Vault Name",
+				"The provider cannot assemble the URL of the vault: "+err.Error(),
+			)
+		} else if azrandom.IsManagedHSM(vaultUrl) {
+			resp.Diagnostics.Append(managedHSMDiagnostic(path.Root("dns_suffix"), vaultUrl))
+		}
+	case vaultName != "":
+		if environment == "" {
+			environment = azrandom.EnvironmentPublic.String()
+		}
+		vaultUrl, err = azrandom.VaultURL(vaultName, azrandom.Environment(environment))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vault_name"),
+				"Invalid Azrandom Vault Name",
+				"The provider cannot assemble the URL of the vault: "+err.Error(),
+			)
+		}
+	default:
+		err = validators.ValidateURLScheme(vaultUrl, []string{HTTPSScheme.String()})
+		if err == nil {
+			err = azrandom.ValidateVaultURL(vaultUrl)
+		}
+		if errors.Is(err, azrandom.ErrManagedHSM) {
+			resp.Diagnostics.Append(managedHSMDiagnostic(path.Root("vault_url"), vaultUrl))
+		} else if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vault_url"),
+				"Invalid Azrandom Vault Url",
+				"The URL of the vault (possibly given through the AZRANDOM_VAULT_URL environment variable) is not valid: "+
+					err.Error()+". For vaults outside of the known environments, set vault_name and dns_suffix instead.",
+			)
+		}
+	}
+
+	if authMethod != "" && !slices.Contains(azrandom.AuthMethods(), authMethod) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_method"),
+			"Invalid Azrandom Auth Method",
+			"The auth method "+authMethod+" (possibly given through the AZRANDOM_AUTH_METHOD environment variable) is not "+
+				"supported, expected one of "+strings.Join(azrandom.AuthMethods(), ", ")+".",
+		)
+	}
+	// Automated runs have nobody to sign in, and would wait for the sign-in until they time out
+	interactiveAuth := azrandom.InteractiveAuth("")
+	switch {
+	case useInteractiveBrowser && useDeviceCode:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_device_code"),
+			"Conflicting Azrandom Interactive Auth",
+			"Only one of use_interactive_browser and use_device_code may be enabled, but both are (possibly through the "+
+				"AZRANDOM_USE_INTERACTIVE_BROWSER and AZRANDOM_USE_DEVICE_CODE environment variables). Disable one of them.",
+		)
+	case (useInteractiveBrowser || useDeviceCode) && os.Getenv("TF_IN_AUTOMATION") != "":
+		name := "use_interactive_browser"
+		if useDeviceCode {
+			name = "use_device_code"
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root(name),
+			"Interactive Auth in Automation",
+			"The provider cannot sign in interactively since the TF_IN_AUTOMATION environment variable is set, and the "+
+				"run would wait for a sign-in that never happens. Disable "+name+" (possibly set through the "+
+				"AZRANDOM_"+strings.ToUpper(name)+" environment variable) for automated runs.",
+		)
+	case useInteractiveBrowser:
+		interactiveAuth = azrandom.InteractiveAuthBrowser
+	case useDeviceCode:
+		interactiveAuth = azrandom.InteractiveAuthDeviceCode
+	}
+	if tokenCacheName != "" && !tokenCacheNameRegex.MatchString(tokenCacheName) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_cache_name"),
+			"Invalid Azrandom Token Cache Name",
+			"The token cache name "+tokenCacheName+" (possibly given through the AZRANDOM_TOKEN_CACHE_NAME environment "+
+				"variable) must only contain letters, digits, `.`, `_` and `-`.",
+		)
+	}
+	switch {
+	case proxyUrl != "":
+		if err := validators.ValidateURLScheme(proxyUrl, supportedProxySchemesStr()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Azrandom Proxy Url",
+				"The proxy URL (possibly given through the AZRANDOM_PROXY_URL environment variable) is not valid: "+err.Error(),
+			)
+		}
+		if proxyPassword != "" && proxyUsername == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_username"),
+				"Missing Azrandom Proxy Username",
+				"A proxy password is set (possibly through the AZRANDOM_PROXY_PASSWORD environment variable) without a "+
+					"username. Set proxy_username, or use the AZRANDOM_PROXY_USERNAME environment variable.",
+			)
+		}
+	case proxyUsername != "" || proxyPassword != "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy_url"),
+			"Missing Azrandom Proxy Url",
+			"Proxy credentials are set (possibly through the AZRANDOM_PROXY_USERNAME and AZRANDOM_PROXY_PASSWORD "+
+				"environment variables) without a proxy. Set proxy_url, or use the AZRANDOM_PROXY_URL environment variable.",
+		)
+	}
+	if partnerID != "" {
+		if _, err := uuid.ParseUUID(partnerID); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("partner_id"),
+				"Invalid Azrandom Partner ID",
+				"The partner ID "+partnerID+" (possibly given through the AZRANDOM_PARTNER_ID environment variable) "+
+					"must be a GUID such as 00000000-0000-0000-0000-000000000000.",
+			)
+		}
+	} else if !disableTerraformPartnerID {
+		partnerID = azrandom.TerraformPartnerID
+	}
+	if sdkLogLevel == "" {
+		sdkLogLevel = azrandom.SDKLogLevelDebug.String()
+	} else if !slices.Contains(azrandom.SDKLogLevels(), sdkLogLevel) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sdk_log_level"),
+			"Invalid Azrandom SDK Log Level",
+			"The SDK log level "+sdkLogLevel+" (possibly given through the AZRANDOM_SDK_LOG_LEVEL environment variable) "+
+				"is not supported, expected one of "+strings.Join(azrandom.SDKLogLevels(), ", ")+".",
+		)
+	}
+	var operationTimeoutDuration time.Duration
+	if operationTimeout != "" {
+		operationTimeoutDuration, err = time.ParseDuration(operationTimeout)
+		if err != nil || operationTimeoutDuration <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operation_timeout"),
+				"Invalid Azrandom Operation Timeout",
+				"The operation timeout (possibly given through the AZRANDOM_OPERATION_TIMEOUT environment variable) must "+
+					"be a positive duration such as \"30s\" or \"2m\", got: "+operationTimeout,
+			)
+		}
+	}
+	if customAuthorityHost != "" {
+		if err := validators.ValidateHTTPSURL(customAuthorityHost); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("custom_authority_host"),
+				"Invalid Azrandom Custom Authority Host",
+				"The custom authority host (possibly given through the AZRANDOM_CUSTOM_AUTHORITY_HOST environment variable) "+
+					"is not valid: "+err.Error(),
+			)
+		}
 	}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "azrandom_vault_url", vault_url)
+	// A vault in a known environment defaults to that environment, which the configured one should match
+	vaultEnvironment, known := azrandom.VaultEnvironment(vaultUrl)
+	switch {
+	case environment == "" && known:
+		environment = vaultEnvironment.String()
+	case environment == "":
+		environment = azrandom.EnvironmentPublic.String()
+	case known && vaultEnvironment.String() != environment:
+		attribute := path.Root("vault_url")
+		if vaultName != "" {
+			attribute = path.Root("dns_suffix")
+		}
+		resp.Diagnostics.AddAttributeError(
+			attribute,
+			"Mismatched Azrandom Environment",
+			"The DNS suffix of the vault "+vaultUrl+" belongs to the "+vaultEnvironment.String()+" environment, but the "+
+				"environment is set to "+environment+" (possibly through the AZRANDOM_ENVIRONMENT environment variable). "+
+				"Tokens would be requested from the authority of the "+environment+" environment, which the vault rejects. "+
+				"Set environment to "+vaultEnvironment.String()+", or remove it to use the environment of the vault.",
+		)
+		return
+	}
+	cloudConfiguration := azrandom.Environment(environment).Cloud(customAuthorityHost)
 
-	tflog.Debug(ctx, "Creating Azrandom client")
+	// Resources compare the URL of the vault with their own, which may or may not end with a slash
+	vaultUrl = azrandom.NormalizeVaultURL(vaultUrl)
 
-	// Create a new Azrandom client using the configuration values
-	client, err := azrandom.CreateClient(vault_url, azidentity.DisabledCredentials{
-		ManagedIdentityCredential:   disable_managed_identity_credential,
-		WorkloadIdentityCredential:  disable_workload_identity_credential,
-		AzureCLICredential:          disable_azure_cli_credential,
-		AzureDeveloperCLICredential: disable_azure_developer_cli_credential,
-		EnvironmentCredential:       disable_environment_credential,
+	ctx = tflog.SetField(ctx, "azrandom_vault_url", vaultUrl)
+
+	// The events of the Azure SDK, e.g. of the token requests below, are logged with the fields of this provider
+	azrandom.SetSDKLog(ctx, azrandom.SDKLogLevel(sdkLogLevel))
+
+	tflog.Debug(ctx, "Resolved Azure Key Vault URL", map[string]any{
+		"vault_name":     vaultName,
+		"dns_suffix":     dnsSuffix,
+		"environment":    environment,
+		"authority_host": cloudConfiguration.ActiveDirectoryAuthorityHost,
+	})
+
+	tflog.Debug(ctx, "Creating Azrandom client", map[string]any{
+		"auth_method":                  authMethod,
+		"additionally_allowed_tenants": additionallyAllowedTenants,
+		"disable_instance_discovery":   disableInstanceDiscovery,
+		"interactive_auth":             interactiveAuth,
+		"token_cache_name":             tokenCacheName,
+		"proxy_url":                    redactedProxyUrl(proxyUrl),
+		"partner_id":                   partnerID,
+		"operation_timeout":            operationTimeoutDuration.String(),
+		"max_concurrent_requests":      maxConcurrentRequests,
+		"sdk_log_level":                sdkLogLevel,
+		"custom_endpoint":              customEndpoint,
+		"insecure_skip_verify":         insecureSkipVerify,
+		"use_fake_credential":          useFakeCredential,
+		"disabled_credentials": map[string]bool{
+			"managed_identity":    disableManagedIdentityCredential,
+			"workload_identity":   disableWorkloadIdentityCredential,
+			"azure_cli":           disableAzureCLICredential,
+			"azure_developer_cli": disableAzureDeveloperCLICredential,
+			"environment":         disableEnvironmentCredential,
+		},
+		"retry": map[string]any{
+			"max_retries":     retryOptions.MaxRetries,
+			"retry_delay":     retryOptions.RetryDelay.String(),
+			"max_retry_delay": retryOptions.MaxRetryDelay.String(),
+			"try_timeout":     retryOptions.TryTimeout.String(),
+		},
 	})
+
+	// The same proxy carries the requests of the credential and of the clients of the vaults
+	var transport policy.Transporter
+	if proxyUrl != "" {
+		transport, err = azrandom.ProxyTransport(proxyUrl, proxyUsername, proxyPassword)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Azrandom Proxy Url",
+				"The provider cannot send requests through the proxy: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if insecureSkipVerify {
+		tflog.Warn(ctx, "Skipping the verification of TLS certificates")
+		transport, err = azrandom.InsecureTransport(transport)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure_skip_verify"),
+				"Unable to Skip TLS Verification",
+				"The provider cannot skip the verification of TLS certificates: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	userAgent := azrandom.UserAgent{ProviderVersion: p.version, PartnerID: partnerID}
+
+	// An unavailable cache only costs signing in again on the next run, so it does not fail the run
+	if tokenCacheName != "" {
+		if err := azrandom.CheckTokenCache(tokenCacheName); err != nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("token_cache_name"),
+				"Azrandom Token Cache Unavailable",
+				"Tokens cannot be persisted on this host, so they are kept in memory for this run only. Remove "+
+					"token_cache_name (possibly set through the AZRANDOM_TOKEN_CACHE_NAME environment variable) to "+
+					"silence this warning.\n\nError: "+err.Error(),
+			)
+			tokenCacheName = ""
+		}
+	}
+
+	servicePrincipal := azrandom.ServicePrincipal{
+		TenantID:     tenantID,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+	if clientCertificatePath != "" {
+		servicePrincipal.Certificates, servicePrincipal.PrivateKey, err = azrandom.LoadClientCertificate(clientCertificatePath, clientCertificatePassword)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client_certificate_path"),
+				"Invalid Azrandom Client Certificate",
+				"The provider cannot load the client certificate of the service principal (possibly given through the "+
+					"AZRANDOM_CLIENT_CERTIFICATE_PATH environment variable), which must be a PEM or PFX file holding a "+
+					"certificate and its private key. A PFX file may need client_certificate_password.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	credentialOptions := azrandom.CredentialOptions{
+		AuthMethod:                 azrandom.AuthMethod(authMethod),
+		ServicePrincipal:           servicePrincipal,
+		ManagedIdentityClientID:    managedIdentityClientID,
+		ManagedIdentityResourceID:  managedIdentityResourceID,
+		Cloud:                      cloudConfiguration,
+		AdditionallyAllowedTenants: additionallyAllowedTenants,
+		DisableInstanceDiscovery:   disableInstanceDiscovery,
+		InteractiveAuth:            interactiveAuth,
+		TokenCacheName:             tokenCacheName,
+		Transport:                  transport,
+		UserAgent:                  userAgent,
+		UseFakeCredential:          useFakeCredential,
+		DeviceCodePrompt: func(ctx context.Context, message string) {
+			tflog.Warn(ctx, message)
+		},
+		DisabledCredentials: azidentity.DisabledCredentials{
+			ManagedIdentityCredential:   disableManagedIdentityCredential,
+			WorkloadIdentityCredential:  disableWorkloadIdentityCredential,
+			AzureCLICredential:          disableAzureCLICredential,
+			AzureDeveloperCLICredential: disableAzureDeveloperCLICredential,
+			EnvironmentCredential:       disableEnvironmentCredential,
+		},
+	}
+	if id := credentialOptions.ManagedIdentityID(); id != nil {
+		tflog.Debug(ctx, "Selected user-assigned managed identity", map[string]any{
+			"managed_identity_client_id":   managedIdentityClientID,
+			"managed_identity_resource_id": managedIdentityResourceID,
+		})
+	}
+
+	credential, err := azrandom.CreateCredential(credentialOptions)
+	switch {
+	case errors.Is(err, azrandom.ErrConflictingAuthMethod):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_method"),
+			"Conflicting Auth Method",
+			"The auth method "+authMethod+" selects the credential to authenticate with, but some credentials of the "+
+				"DefaultAzureCredential chain are disabled or a service principal is given (possibly through the "+
+				"AZRANDOM_DISABLE_*_CREDENTIAL, AZRANDOM_TENANT_ID, AZRANDOM_CLIENT_ID, AZRANDOM_CLIENT_SECRET and "+
+				"AZRANDOM_CLIENT_CERTIFICATE_PATH environment variables). Remove either auth_method or the other credential settings.",
+		)
+		return
+	case errors.Is(err, azrandom.ErrIncompleteServicePrincipal):
+		resp.Diagnostics.AddError(
+			"Incomplete Azrandom Service Principal",
+			"Only some of tenant_id, client_id, client_secret and client_certificate_path are set (possibly through the "+
+				"AZRANDOM_TENANT_ID, AZRANDOM_CLIENT_ID, AZRANDOM_CLIENT_SECRET and AZRANDOM_CLIENT_CERTIFICATE_PATH environment "+
+				"variables). Set tenant_id, client_id and exactly one of client_secret and client_certificate_path to "+
+				"authenticate as a service principal, or none of them to use the DefaultAzureCredential chain.",
+		)
+		return
+	case errors.Is(err, azrandom.ErrConflictingManagedIdentity):
+		resp.Diagnostics.AddError(
+			"Conflicting Azrandom Managed Identity",
+			"Only one of managed_identity_client_id and managed_identity_resource_id may be set, but both are (possibly "+
+				"through the AZRANDOM_MANAGED_IDENTITY_CLIENT_ID and AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID environment "+
+				"variables). Remove one of them.",
+		)
+		return
+	case errors.Is(err, azrandom.ErrConflictingCredentials):
+		resp.Diagnostics.AddError(
+			"Conflicting Credential Settings",
+			"The provider authenticates as the service principal given by tenant_id and client_id instead "+
+				"of using the DefaultAzureCredential chain, but some of its credentials are disabled (possibly through the "+
+				"AZRANDOM_DISABLE_*_CREDENTIAL environment variables). Remove either the service principal or the disabled credentials.",
+		)
+		return
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Unable to Create Azrandom API Client",
+			"An unexpected error occurred when creating the credential of the Azrandom API client. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Azrandom Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Fail early on authentication errors, which are otherwise only reported by the first secret operation
+	switch {
+	case skipCredentialValidation:
+		tflog.Debug(ctx, "Skipping credential validation")
+	default:
+		tflog.Debug(ctx, "Validating credential")
+		if err := azrandom.ValidateCredential(ctx, credential, vaultUrl); err != nil {
+			resp.Diagnostics.Append(diagnostics.CredentialError(err.Error())...)
+			return
+		}
+	}
+
+	// The clients of all vaults share the limiter, so that the limit holds across them
+	var limiter *azrandom.ConcurrencyLimiter
+	if maxConcurrentRequests > 0 {
+		limiter = azrandom.NewConcurrencyLimiter(int(maxConcurrentRequests))
+	}
+
+	// Create a new Azrandom client using the configuration values
+	clientOptions := azrandom.ClientOptions{
+		Cloud:            cloudConfiguration,
+		Retry:            retryOptions,
+		Transport:        transport,
+		UserAgent:        userAgent,
+		OperationTimeout: operationTimeoutDuration,
+		Limiter:          limiter,
+		// An emulator challenges for a resource of its own host, which is no Key Vault DNS suffix
+		DisableChallengeResourceVerification: customEndpoint != "",
+	}
+	client, err := azrandom.CreateClient(vaultUrl, credential, clientOptions)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Azrandom API Client",
@@ -227,10 +1516,31 @@ func (p *azrandomProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	if validateAccess {
+		tflog.Debug(ctx, "Validating access to vault")
+		if err := azrandom.ValidateAccess(ctx, client); err != nil {
+			resp.Diagnostics.Append(diagnostics.AccessError(vaultUrl, err)...)
+			return
+		}
+	}
+
 	// Make the Azrandom client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	providerData := &azrandomProviderData{
+		client:             client,
+		vaultUrl:           vaultUrl,
+		vaultClients:       newVaultClientCache(credential, clientOptions, vaultUrl, client),
+		verifyWrite:        verifyWrite,
+		defaultTags:        config.DefaultTags,
+		secretNames:        newSecretNameRegistry(),
+		skipExistenceCheck: skipExistenceCheck,
+		strictImport:       strictImport,
+	}
+	if writeMetadataTags {
+		providerData.metadataTags = metadataTags(p.version)
+	}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 
 	tflog.Info(ctx, "Configured Azrandom client", map[string]any{"success": true})
 }
@@ -241,10 +1551,26 @@ func (p *azrandomProvider) DataSources(_ context.Context) []func() datasource.Da
 }
 
 // Resources defines the resources implemented in the provider.
-func (p *azrandomProvider) Resources(_ context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+func (p *azrandomProvider) Resources(ctx context.Context) []func() resource.Resource {
+	resources := []func() resource.Resource{
 		NewUuidResource,
 		NewStringResource,
 		NewCryptographicKeyResource,
+		NewOAuthClientResource,
+		NewSigningSecretResource,
+		NewKDFParamsResource,
+		NewOpaqueTokenResource,
+		NewLicenseKeyResource,
+		NewSecretAliasResource,
+		NewPasswordSetResource,
+		NewBip39MnemonicResource,
+		NewCronResource,
+		NewWeightedChoiceResource,
+		NewStoredSecretResource,
+	}
+
+	if p.protocol5 {
+		return protocol5Resources(ctx, resources)
 	}
+	return resources
 }
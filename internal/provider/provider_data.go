@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// azrandomProviderData is made available to resources during Configure.
+type azrandomProviderData struct {
+	client   *azsecrets.Client
+	vaultUrl string
+	// vaultClients holds the clients of the vaults set by the vault_url attribute of resources
+	vaultClients *vaultClientCache
+	verifyWrite  bool
+	// skipExistenceCheck creates secrets without checking whether they already exist, see secretExistsBeforeCreate
+	skipExistenceCheck bool
+	// strictImport fails imports of secrets that hold a value the resource could not have generated
+	strictImport bool
+	defaultTags  types.Map
+	// metadataTags are written to every secret, see metadataTags
+	metadataTags map[string]string
+	secretNames  *secretNameRegistry
+}
+
+// secretNameRegistry records which resource claimed each (vault_url, name) pair during
+// planning, so that two resources writing to the same secret can be detected before apply.
+type secretNameRegistry struct {
+	mu     sync.Mutex
+	claims map[string]secretNameClaim
+}
+
+// secretNameClaim is the claim of a secret name by the attribute at attributePath of a resource of type
+// resourceType. owner identifies the resource instance, see planClaimOwner.
+type secretNameClaim struct {
+	owner         string
+	resourceType  string
+	attributePath path.Path
+}
+
+func newSecretNameRegistry() *secretNameRegistry {
+	return &secretNameRegistry{
+		claims: map[string]secretNameClaim{},
+	}
+}
+
+// claim registers claim as the resource planning to write the secret name in vaultUrl. If the pair was
+// already claimed by another resource, or by another attribute of the same resource, the earlier claim is
+// returned along with false. Terraform plans a resource instance twice when it is replaced, once with its prior
+// state and once without, so a claim by the same attribute of the same instance succeeds again.
+func (s *secretNameRegistry) claim(vaultUrl string, name string, claim secretNameClaim) (secretNameClaim, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Key Vault secret names are case-insensitive
+	key := vaultKey(vaultUrl) + "|" + strings.ToLower(name)
+
+	if existing, ok := s.claims[key]; ok {
+		if existing.owner == claim.owner && existing.attributePath.Equal(claim.attributePath) {
+			return existing, true
+		}
+		return existing, false
+	}
+	s.claims[key] = claim
+	return claim, true
+}
+
+// planClaimOwner returns the identifier with which the resource being planned claims secret names, which is
+// kept in its private state. When Terraform plans the replacement of a resource, it passes the private state
+// planned with the prior state along with the null prior state, so that both plans claim with the same owner.
+func planClaimOwner(ctx context.Context, resp *resource.ModifyPlanResponse) (string, diag.Diagnostics) {
+	value, diags := resp.Private.GetKey(ctx, privateClaimKey)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	var owner string
+	if value != nil {
+		if err := json.Unmarshal(value, &owner); err != nil {
+			diags.AddError("Unable to read the secret name claim from private state", err.Error())
+			return "", diags
+		}
+	}
+	if owner != "" {
+		return owner, diags
+	}
+
+	owner, err := uuid.GenerateUUID()
+	if err != nil {
+		diags.AddError("Unable to generate the secret name claim", err.Error())
+		return "", diags
+	}
+	value, err = json.Marshal(owner)
+	if err != nil {
+		diags.AddError("Unable to store the secret name claim in private state", err.Error())
+		return "", diags
+	}
+	diags.Append(resp.Private.SetKey(ctx, privateClaimKey, value)...)
+	return owner, diags
+}
+
+// checkDuplicateSecretName errors when another resource in the same configuration has already
+// planned to store its value in the same secret. Terraform does not expose resource addresses to
+// providers, but it attaches the diagnostic to the address of the resource currently being planned.
+func checkDuplicateSecretName(ctx context.Context, data *azrandomProviderData, resourceType string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretNames(ctx, data, resourceType, req, resp, "name")
+}
+
+// checkDuplicateSecretNames is checkDuplicateSecretName for resources that store values in the
+// secrets named by each of the given attributes.
+func checkDuplicateSecretNames(ctx context.Context, data *azrandomProviderData, resourceType string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attributeNames ...string) {
+	// Nothing to check when destroying, or when the provider has not been configured yet
+	if req.Plan.Raw.IsNull() || data == nil || data.secretNames == nil {
+		return
+	}
+
+	var vaultUrl types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("vault_url"), &vaultUrl)...)
+	// The vault is not known until apply
+	if vaultUrl.IsUnknown() {
+		return
+	}
+
+	for _, attributeName := range attributeNames {
+		var name types.String
+		diags := req.Plan.GetAttribute(ctx, path.Root(attributeName), &name)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			continue
+		}
+
+		// Name is not known until apply, or this secret is not used
+		if name.IsUnknown() || name.IsNull() {
+			continue
+		}
+
+		claimSecretName(ctx, data, resourceVaultUrl(data, vaultUrl), resourceType, path.Root(attributeName), name.ValueString(), resp)
+	}
+}
+
+// claimSecretName errors when another resource in the same configuration has already planned to
+// store its value in the secret name of vaultUrl, which the resource being planned derived from attributePath.
+func claimSecretName(ctx context.Context, data *azrandomProviderData, vaultUrl string, resourceType string, attributePath path.Path, name string, resp *resource.ModifyPlanResponse) {
+	owner, diags := planClaimOwner(ctx, resp)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	existing, ok := data.secretNames.claim(vaultUrl, name, secretNameClaim{
+		owner:         owner,
+		resourceType:  resourceType,
+		attributePath: attributePath,
+	})
+	if !ok {
+		other := fmt.Sprintf("another resource in this configuration (the %s of a %s)", existing.attributePath,
+			existing.resourceType)
+		if existing.owner == owner {
+			other = fmt.Sprintf("its %s", existing.attributePath)
+		}
+		resp.Diagnostics.AddAttributeError(
+			attributePath,
+			"Duplicate secret name",
+			fmt.Sprintf("The %s of this %s stores a value in the secret %q of vault %s, but %s already stores "+
+				"its value in that secret. Each azrandom resource must use a unique secret name, otherwise they "+
+				"will overwrite each other's values on every apply.",
+				attributePath, resourceType, name, vaultUrl, other),
+		)
+	}
+}
+
+// readSecret gets the secret name of a resource of type resourceType for Read, at the version that the resource
+// is pinned to, or else the latest version, with the client of the vault of the resource. Resources created
+// before they had an identity get it. When the secret was deleted outside of Terraform, the resource is removed
+// from state, so that the next apply creates it again, and ok is false, as it is on errors.
+func readSecret(ctx context.Context, data *azrandomProviderData, resourceType string, req resource.ReadRequest, resp *resource.ReadResponse, vaultUrl types.String, name types.String, stateVersion types.String) (*azsecrets.Client, string, azrandom.SecretProperties, bool) {
+	client, diags := data.vaultClient(ctx, vaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return nil, "", azrandom.SecretProperties{}, false
+	}
+
+	if resp.Identity.Raw.IsNull() {
+		resp.Diagnostics.Append(setSecretIdentity(ctx, data, resp.Identity, vaultUrl, name)...)
+	}
+
+	pinned, diags := pinnedVersion(ctx, req.Private, stateVersion)
+	resp.Diagnostics.Append(diags...)
+	version, properties, err := azrandom.GetSecret(ctx, client, name.ValueString(), pinned)
+	if secretDeletedOutOfBand(ctx, resourceType, name.ValueString(), err) {
+		resp.State.RemoveResource(ctx)
+		return nil, "", azrandom.SecretProperties{}, false
+	}
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ReadError(resourceType, name.ValueString(), resourceVaultUrl(data, vaultUrl), err)...)
+		return nil, "", azrandom.SecretProperties{}, false
+	}
+	return client, version, properties, true
+}
+
+// checkSecretsAbsent errors when any of the secrets that a resource of type resourceType creates already exists,
+// describing how to import the resource with importID instead. Null names are skipped.
+func checkSecretsAbsent(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, resourceType string, vaultUrl types.String, importID string, names ...types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range names {
+		if name.IsNull() {
+			continue
+		}
+
+		secretExists, err := secretExistsBeforeCreate(ctx, data, client, name.ValueString())
+		if err != nil {
+			diags.Append(diagnostics.VaultError(diagnostics.OperationCreate, "check whether the secret already exists",
+				resourceType, name.ValueString(), resourceVaultUrl(data, vaultUrl), err)...)
+			return diags
+		}
+		if secretExists {
+			diags.AddError(
+				fmt.Sprintf("Create %s error", resourceType),
+				existingSecretMessage(ctx, client, resourceType, name.ValueString(), importID),
+			)
+			return diags
+		}
+	}
+	return diags
+}
+
+// updateSecretMetadata updates the properties of the given version of the secret name of a resource of type
+// resourceType to metadata, when Update keeps its value.
+func updateSecretMetadata(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, resourceType string, vaultUrl types.String, name string, version string, metadata secretMetadata) (azrandom.SecretProperties, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	stored, err := updateSecretProperties(ctx, client, name, version, metadata)
+	if err != nil {
+		diags.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+			resourceType, name, resourceVaultUrl(data, vaultUrl), err)...)
+	}
+	return stored, diags
+}
+
+// deleteSecrets deletes the secrets of a resource of type resourceType on destroy, skipping null names. Deleted
+// secrets are waited for and purged as set by wait_for_deletion and purge_on_destroy.
+func deleteSecrets(ctx context.Context, data *azrandomProviderData, resourceType string, vaultUrl types.String, wait types.Bool, purge types.Bool, names ...types.String) diag.Diagnostics {
+	client, diags := data.vaultClient(ctx, vaultUrl)
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, name := range names {
+		if name.IsNull() {
+			continue
+		}
+
+		deleted, err := azrandom.DeleteSecret(ctx, client, name.ValueString())
+		if err != nil {
+			diags.Append(diagnostics.DeleteError(resourceType, name.ValueString(), resourceVaultUrl(data, vaultUrl), err)...)
+			return diags
+		}
+
+		if deleted {
+			diags.Append(waitForDeletion(ctx, client, wait, name.ValueString())...)
+			diags.Append(purgeOnDestroy(ctx, client, purge, name.ValueString())...)
+		}
+	}
+	return diags
+}
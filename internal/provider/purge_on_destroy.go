@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// purgeOnDestroyAttribute returns the schema of the purge_on_destroy attribute. It is only used by
+// Delete, so changing it never generates a new value.
+func purgeOnDestroyAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Purge the secrets of this resource after deleting them on destroy, instead of keeping them as " +
+			"soft-deleted secrets for the retention period of the vault, so that they can be re-created right away. " +
+			"Requires permission to purge secrets. When the vault has purge protection enabled, the deleted secrets " +
+			"are kept and a warning is shown. Default value is `false`.",
+		Optional: true,
+	}
+}
+
+// purgeOnDestroy purges the deleted secret name when purge_on_destroy is enabled. A vault with purge
+// protection keeps the deleted secret, which is reported as a warning rather than an error.
+func purgeOnDestroy(ctx context.Context, client *azsecrets.Client, enabled types.Bool, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !enabled.ValueBool() {
+		return diags
+	}
+
+	tflog.Debug(ctx, "Purging deleted secret", map[string]any{"name": name})
+
+	err := azrandom.PurgeSecret(ctx, client, name)
+	if errors.Is(err, azrandom.ErrPurgeProtected) {
+		diags.AddWarning(
+			"Deleted secret not purged",
+			"The secret "+name+" was deleted, but could not be purged since the vault has purge protection enabled. "+
+				"It is kept as a soft-deleted secret until its retention period has passed, and is recovered when a "+
+				"secret with the same name is created. Set purge_on_destroy to false to silence this warning.",
+		)
+	} else if err != nil {
+		diags.AddError(
+			"Purge on destroy failed",
+			"The secret "+name+" was deleted, but could not be purged, unexpected error: "+diagnostics.ErrorDetail(err),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,556 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Keys of the private state of a resource, which Terraform stores with its state without showing it.
+const (
+	// privateVersionKey holds the version of the secret that the resource last wrote its value to.
+	privateVersionKey = "version"
+	// privateRegenerateKey holds why the next apply generates a new value, or null when it does not.
+	privateRegenerateKey = "regenerate"
+	// privateChecksumKey holds a valueChecksum of the value that the resource last generated, never the value
+	// itself, or null when the value was adopted rather than generated.
+	privateChecksumKey = "checksum"
+	// privatePinnedKey holds the version of the secret that the resource was imported with, when it was imported
+	// by a secret identifier with a version, or null otherwise.
+	privatePinnedKey = "pinned"
+	// privateClaimKey holds the identifier with which the resource claims the names of its secrets while it is
+	// planned, see planClaimOwner.
+	privateClaimKey = "claim"
+	// privateMovedKey holds the value that the resource was moved from another provider with until the first
+	// apply stores it in the secret, or null once it was stored, see storeMovedValue.
+	privateMovedKey = "moved"
+	// privateImportedKey holds importedUnplanned while a resource that was imported has not been applied since,
+	// importedKept in the planned private state when the plan keeps its value, or null otherwise, see
+	// markImported.
+	privateImportedKey = "imported"
+	// privateLegacyNumericKey holds true while the value of an azrandom_string was generated while numeric
+	// defaulted to false, false once it was generated with the current default, or null when it was not
+	// recorded yet, see recordLegacyNumeric.
+	privateLegacyNumericKey = "legacy_numeric"
+)
+
+// Values of privateImportedKey.
+const (
+	importedUnplanned = "imported"
+	importedKept      = "kept"
+)
+
+// Reasons to generate a new value other than a change of configuration.
+const (
+	regenerateDrift         = "a new version of the secret was set outside of Terraform"
+	regenerateTampered      = "the value stored in the secret was changed outside of Terraform"
+	regenerateExpired       = "the value stored in the secret has expired"
+	regenerateSourceChanged = "a newer version of the source secret was found"
+	regenerateMigrated      = "an earlier release of the provider marked the value for regeneration in keepers"
+)
+
+// keeperMarkerPrefixes are the prefixes of the keepers entries with which earlier releases of the provider
+// forced an update, each followed by -id and -message.
+var keeperMarkerPrefixes = []string{"drift-detected", "expired", "source-changed"}
+
+// privateState is the private state of a resource in the requests and responses of the framework.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// storeWrittenVersion records version as the version of the secret that the resource wrote its value to,
+// which also clears any regeneration that was planned.
+func storeWrittenVersion(ctx context.Context, private privateState, version string) diag.Diagnostics {
+	value, err := json.Marshal(version)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the secret version in private state", err.Error())
+		return diags
+	}
+
+	diags := private.SetKey(ctx, privateVersionKey, value)
+	diags.Append(clearRegeneration(ctx, private)...)
+	return diags
+}
+
+// valueChecksum is an HMAC-SHA256 of a value that a resource wrote, keyed with a random key of its own. Unlike a
+// bare SHA-256, it cannot be matched against hashes of the value kept elsewhere, such as result_hash, or against
+// the checksums of other resources that hold the same value.
+type valueChecksum struct {
+	Key []byte `json:"key"`
+	MAC []byte `json:"mac"`
+}
+
+// newValueChecksum returns the checksum of value with a newly generated key.
+func newValueChecksum(value string) (valueChecksum, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return valueChecksum{}, err
+	}
+	return valueChecksum{Key: key, MAC: valueMAC(key, value)}, nil
+}
+
+// matches returns whether value is the value that the checksum was computed of.
+func (c valueChecksum) matches(value string) bool {
+	return hmac.Equal(c.MAC, valueMAC(c.Key, value))
+}
+
+// valueMAC returns the HMAC-SHA256 of value with key.
+func valueMAC(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// storeWrittenValue records version as the version of the secret that the resource wrote value to, along with
+// the checksum of value, so that Read can tell when the secret no longer holds it.
+func storeWrittenValue(ctx context.Context, private privateState, version string, value string) diag.Diagnostics {
+	valueChecksum, err := newValueChecksum(value)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to compute the value checksum", err.Error())
+		return diags
+	}
+	checksum, err := json.Marshal(valueChecksum)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the value checksum in private state", err.Error())
+		return diags
+	}
+
+	diags := storeWrittenVersion(ctx, private, version)
+	diags.Append(private.SetKey(ctx, privateChecksumKey, checksum)...)
+	return diags
+}
+
+// adoptVersion records version as the version of the secret that the resource holds, although the resource
+// did not generate its value. The checksum of the value is cleared, so that it is not compared anymore.
+func adoptVersion(ctx context.Context, private privateState, version string) diag.Diagnostics {
+	diags := storeWrittenVersion(ctx, private, version)
+	diags.Append(private.SetKey(ctx, privateChecksumKey, []byte("null"))...)
+	return diags
+}
+
+// clearRegeneration records that the value of the resource was written, so that the next apply does not
+// generate a new value unless Read marks it for regeneration again.
+func clearRegeneration(ctx context.Context, private privateState) diag.Diagnostics {
+	return private.SetKey(ctx, privateRegenerateKey, []byte("null"))
+}
+
+// versionDrifted returns whether version, the current version of a secret, differs from the version that the
+// resource last wrote to it. Resources written by earlier releases of the provider have no version in private
+// state, and are compared with stateVersion instead.
+func versionDrifted(ctx context.Context, private privateState, stateVersion types.String, version string) (bool, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateVersionKey)
+	if diags.HasError() || value == nil {
+		return stateVersion.ValueString() != version, diags
+	}
+
+	var written string
+	if err := json.Unmarshal(value, &written); err != nil {
+		diags.AddError("Unable to read the secret version from private state", err.Error())
+		return false, diags
+	}
+	return written != version, diags
+}
+
+// readDrift returns the version of the secret to keep in state after Read got version of it. When version
+// differs from the version that the resource last wrote, the secret was changed outside of Terraform, so the
+// value is marked for regeneration and version is returned along with true.
+func readDrift(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, stateVersion types.String, version string) (types.String, bool) {
+	drifted, diags := versionDrifted(ctx, req.Private, stateVersion, version)
+	resp.Diagnostics.Append(diags...)
+	if !drifted {
+		return stateVersion, false
+	}
+
+	resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateDrift)...)
+	return types.StringValue(version), true
+}
+
+// pinVersion pins the resource to the given version of its secret, which it was imported with. Read then gets
+// that version instead of the latest one, so that newer versions are not drift. An empty version pins nothing.
+func pinVersion(ctx context.Context, private privateState, version string) diag.Diagnostics {
+	if version == "" {
+		return nil
+	}
+
+	value, err := json.Marshal(version)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the pinned version in private state", err.Error())
+		return diags
+	}
+
+	tflog.Info(ctx, "Pinning the imported version of the secret", map[string]any{"version": version})
+	return private.SetKey(ctx, privatePinnedKey, value)
+}
+
+// pinnedVersion returns the version of the secret that Read gets, which is the pinned version as long as
+// stateVersion still holds it, or an empty string for the latest version. Generating a new value thus ends
+// the pin.
+func pinnedVersion(ctx context.Context, private privateState, stateVersion types.String) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privatePinnedKey)
+	if diags.HasError() || value == nil {
+		return "", diags
+	}
+
+	var pinned *string
+	if err := json.Unmarshal(value, &pinned); err != nil {
+		diags.AddError("Unable to read the pinned version from private state", err.Error())
+		return "", diags
+	}
+	if pinned == nil || *pinned != stateVersion.ValueString() {
+		return "", diags
+	}
+	return *pinned, diags
+}
+
+// markImported records that the resource was imported. The settings that an imported value was generated with
+// cannot be told from the value, so the first plan after the import decides whether the configured settings
+// keep the value, see keepImportedValue.
+func markImported(ctx context.Context, private privateState) diag.Diagnostics {
+	return storeImported(ctx, private, importedUnplanned)
+}
+
+// keepImportedValue records in the planned private state of an imported resource that its stored value
+// satisfies the configured settings, so that the apply records the settings instead of generating a new value.
+func keepImportedValue(ctx context.Context, private privateState) diag.Diagnostics {
+	return storeImported(ctx, private, importedKept)
+}
+
+// clearImported records that the resource was applied since it was imported.
+func clearImported(ctx context.Context, private privateState) diag.Diagnostics {
+	return private.SetKey(ctx, privateImportedKey, []byte("null"))
+}
+
+// storeImported stores state, a value of privateImportedKey.
+func storeImported(ctx context.Context, private privateState, state string) diag.Diagnostics {
+	value, err := json.Marshal(state)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the import in private state", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, privateImportedKey, value)
+}
+
+// importedState returns the value of privateImportedKey, or an empty string when the resource was applied
+// since it was imported, or never was.
+func importedState(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateImportedKey)
+	if diags.HasError() || value == nil {
+		return "", diags
+	}
+
+	var state *string
+	if err := json.Unmarshal(value, &state); err != nil {
+		diags.AddError("Unable to read the import from private state", err.Error())
+		return "", diags
+	}
+	if state == nil {
+		return "", diags
+	}
+	return *state, diags
+}
+
+// recordLegacyNumeric records in private state whether the value of a string was generated while numeric
+// defaulted to false, which is the case when its state was written by a release of schema version 0, before
+// private state was written, with numeric false. It is recorded once, by the first Read after the state was
+// upgraded, since the state upgrade cannot write private state.
+func recordLegacyNumeric(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, numeric types.Bool) diag.Diagnostics {
+	recorded, diags := req.Private.GetKey(ctx, privateLegacyNumericKey)
+	written, writtenDiags := req.Private.GetKey(ctx, privateVersionKey)
+	diags.Append(writtenDiags...)
+	if diags.HasError() || recorded != nil || written != nil {
+		return diags
+	}
+
+	legacy := !numeric.ValueBool()
+	if legacy {
+		tflog.Info(ctx, "Keeping numeric false for a value generated while it defaulted to false")
+	}
+	value, err := json.Marshal(legacy)
+	if err != nil {
+		diags.AddError("Unable to store the numeric default in private state", err.Error())
+		return diags
+	}
+	diags.Append(resp.Private.SetKey(ctx, privateLegacyNumericKey, value)...)
+	return diags
+}
+
+// legacyNumeric returns whether recordLegacyNumeric recorded that the value of a string was generated while
+// numeric defaulted to false, and it was not generated again since.
+func legacyNumeric(ctx context.Context, private privateState) (bool, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateLegacyNumericKey)
+	if diags.HasError() || value == nil {
+		return false, diags
+	}
+
+	var legacy bool
+	if err := json.Unmarshal(value, &legacy); err != nil {
+		diags.AddError("Unable to read the numeric default from private state", err.Error())
+		return false, diags
+	}
+	return legacy, diags
+}
+
+// clearLegacyNumeric records that the value of a string was generated with the current default of numeric.
+func clearLegacyNumeric(ctx context.Context, private privateState) diag.Diagnostics {
+	return private.SetKey(ctx, privateLegacyNumericKey, []byte("false"))
+}
+
+// readValueDrift returns why the value of a resource should be regenerated after Read got version of its secret,
+// holding value: regenerateDrift when version is not the version that the resource last wrote, regenerateTampered
+// when it is, but no longer holds the value that the resource generated, e.g. because the secret was restored from
+// a backup, or "" otherwise. A new version that still holds the generated value, e.g. because the value was set
+// again, is recorded as written instead of being drift. Only checksums are compared, so the value never leaves the
+// provider. value is nil for disabled secrets, whose value cannot be read, which are compared by version only, as
+// are values without a checksum in private state.
+func readValueDrift(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, stateVersion types.String, version string, value *string) (string, diag.Diagnostics) {
+	drifted, diags := versionDrifted(ctx, req.Private, stateVersion, version)
+	checksum, checksumDiags := writtenChecksum(ctx, req.Private)
+	diags.Append(checksumDiags...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	switch {
+	case checksum == nil || value == nil:
+		if value == nil {
+			tflog.Debug(ctx, "Not comparing the value of disabled secret", map[string]any{"version": version})
+		}
+		if drifted {
+			return regenerateDrift, diags
+		}
+	case !checksum.matches(*value) && drifted:
+		return regenerateDrift, diags
+	case !checksum.matches(*value):
+		return regenerateTampered, diags
+	case drifted:
+		tflog.Info(ctx, "New version of the secret holds the value of the resource", map[string]any{"version": version})
+		written, err := json.Marshal(version)
+		if err != nil {
+			diags.AddError("Unable to store the secret version in private state", err.Error())
+			return "", diags
+		}
+		diags.Append(resp.Private.SetKey(ctx, privateVersionKey, written)...)
+	}
+	return "", diags
+}
+
+// writtenChecksum returns the checksum of the value that the resource last wrote, or nil when the value was
+// adopted rather than generated, or written by a release that did not record its checksum.
+func writtenChecksum(ctx context.Context, private privateState) (*valueChecksum, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateChecksumKey)
+	if diags.HasError() || value == nil {
+		return nil, diags
+	}
+
+	var checksum *valueChecksum
+	if err := json.Unmarshal(value, &checksum); err != nil {
+		diags.AddError("Unable to read the value checksum from private state", err.Error())
+		return nil, diags
+	}
+	return checksum, diags
+}
+
+// markForRegeneration records that the next apply generates a new value for the reason given.
+func markForRegeneration(ctx context.Context, private privateState, reason string) diag.Diagnostics {
+	value, err := json.Marshal(reason)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store the regeneration in private state", err.Error())
+		return diags
+	}
+
+	tflog.Info(ctx, "Marking value for regeneration", map[string]any{"reason": reason})
+	return private.SetKey(ctx, privateRegenerateKey, value)
+}
+
+// regenerationReason returns why the next apply generates a new value, or an empty string when it does not.
+func regenerationReason(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateRegenerateKey)
+	if diags.HasError() || value == nil {
+		return "", diags
+	}
+
+	var reason *string
+	if err := json.Unmarshal(value, &reason); err != nil {
+		diags.AddError("Unable to read the regeneration from private state", err.Error())
+		return "", diags
+	}
+	if reason == nil {
+		return "", diags
+	}
+	return *reason, diags
+}
+
+// migrateKeeperMarkers removes the entries that earlier releases of the provider added to keepers to force an
+// update, and marks the value for regeneration instead when it finds any, so that the pending update is kept.
+func migrateKeeperMarkers(ctx context.Context, private privateState, keepers types.Map) (types.Map, diag.Diagnostics) {
+	if keepers.IsNull() || keepers.IsUnknown() {
+		return keepers, nil
+	}
+
+	elements := map[string]string{}
+	diags := keepers.ElementsAs(ctx, &elements, false)
+	if diags.HasError() {
+		return keepers, diags
+	}
+
+	found := false
+	for _, prefix := range keeperMarkerPrefixes {
+		for _, key := range []string{prefix + "-id", prefix + "-message"} {
+			if _, ok := elements[key]; ok {
+				delete(elements, key)
+				found = true
+			}
+		}
+	}
+	if !found {
+		return keepers, diags
+	}
+
+	diags.Append(markForRegeneration(ctx, private, regenerateMigrated)...)
+	if len(elements) == 0 {
+		return types.MapNull(types.StringType), diags
+	}
+	migrated, mapDiags := types.MapValueFrom(ctx, types.StringType, elements)
+	diags.Append(mapDiags...)
+	return migrated, diags
+}
+
+// planRegeneration plans a new value when Read marked the value of the resource for regeneration, by marking
+// the computed attributes that are not configured as unknown, as the framework does when the configuration
+// changes. Update then generates the new value. Drift is handled as configured by on_drift, see planDrift.
+func planRegeneration(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to regenerate when creating or destroying
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	reason, diags := regenerationReason(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if reason == "" || ((reason == regenerateDrift || reason == regenerateTampered) && !planDrift(ctx, req, resp, reason)) {
+		return
+	}
+
+	plan, err := tftypes.Transform(resp.Plan.Raw, func(attributePath *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if len(attributePath.Steps()) == 0 {
+			return value, nil
+		}
+
+		configValue, _, err := tftypes.WalkAttributePath(req.Config.Raw, attributePath)
+		if err != nil {
+			return value, nil
+		}
+		if configValue, ok := configValue.(tftypes.Value); !ok || !configValue.IsNull() {
+			return value, nil
+		}
+
+		// Elements of attributes, and blocks, have no schema attribute of their own
+		attribute, err := resp.Plan.Schema.AttributeAtTerraformPath(ctx, attributePath)
+		if err != nil || !attribute.IsComputed() || hasDefault(attribute) {
+			return value, nil
+		}
+
+		return tftypes.NewValue(value.Type(), tftypes.UnknownValue), nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to plan the regeneration of the value", err.Error())
+		return
+	}
+	resp.Plan.Raw = plan
+
+	resp.Diagnostics.AddWarning("New value planned",
+		fmt.Sprintf("A new value is generated and stored in the secret, since %s.", reason))
+}
+
+// planVersion plans the version attribute of a resource as unknown when apply generates a new value, i.e. when
+// an attribute other than the metadata and the given computed attributes changes, such as keepers or a
+// generation parameter, or when Read marked the value for regeneration. Otherwise the metadata is updated on the
+// current version, which is planned as is, so that resources referencing version only change with the value.
+func planVersion(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, computed ...string) {
+	// Nothing to plan when creating or destroying
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	changed, diags := valueAttributesChanged(resp.Plan.Raw, req.State.Raw, computed...)
+	resp.Diagnostics.Append(diags...)
+	// planDrift clears the regeneration in the planned private state when on_drift is ignore
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version := types.StringUnknown()
+	if !changed && !regenerate {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), version)...)
+}
+
+// hasDefault returns whether attribute has a default value, which the framework plans instead of an unknown
+// value when it is not configured.
+func hasDefault(attribute any) bool {
+	switch a := attribute.(type) {
+	case interface{ BoolDefaultValue() defaults.Bool }:
+		return a.BoolDefaultValue() != nil
+	case interface{ Float64DefaultValue() defaults.Float64 }:
+		return a.Float64DefaultValue() != nil
+	case interface{ Int64DefaultValue() defaults.Int64 }:
+		return a.Int64DefaultValue() != nil
+	case interface{ ListDefaultValue() defaults.List }:
+		return a.ListDefaultValue() != nil
+	case interface{ MapDefaultValue() defaults.Map }:
+		return a.MapDefaultValue() != nil
+	case interface{ NumberDefaultValue() defaults.Number }:
+		return a.NumberDefaultValue() != nil
+	case interface{ ObjectDefaultValue() defaults.Object }:
+		return a.ObjectDefaultValue() != nil
+	case interface{ SetDefaultValue() defaults.Set }:
+		return a.SetDefaultValue() != nil
+	case interface{ StringDefaultValue() defaults.String }:
+		return a.StringDefaultValue() != nil
+	}
+	return false
+}
+
+// regenerationPlanned returns whether Read marked the value of the resource for regeneration.
+func regenerationPlanned(ctx context.Context, private privateState) (bool, diag.Diagnostics) {
+	reason, diags := regenerationReason(ctx, private)
+	return reason != "", diags
+}
+
+// valueRegenerated returns whether Update generates a new value, i.e. when an attribute other than the
+// metadata and the given computed attributes changed, or when Read marked the value for regeneration, e.g.
+// because drift was detected. Otherwise only the properties of the current version of the secret are updated.
+func valueRegenerated(ctx context.Context, req resource.UpdateRequest, computed ...string) (bool, diag.Diagnostics) {
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, computed...)
+	if diags.HasError() || changed {
+		return changed, diags
+	}
+
+	regenerate, regenerateDiags := regenerationPlanned(ctx, req.Private)
+	diags.Append(regenerateDiags...)
+	return regenerate, diags
+}
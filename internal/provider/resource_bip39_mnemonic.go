@@ -0,0 +1,540 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+)
+
+var (
+	_ resource.Resource                   = (*bip39MnemonicResource)(nil)
+	_ resource.ResourceWithIdentity       = (*bip39MnemonicResource)(nil)
+	_ resource.ResourceWithImportState    = (*bip39MnemonicResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*bip39MnemonicResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*bip39MnemonicResource)(nil)
+)
+
+// bip39MnemonicComputedAttributes are unknown in the plan whenever anything changes.
+var bip39MnemonicComputedAttributes = []string{
+	"version",
+	"seed_version",
+}
+
+// bip39SeedAttributes only affect the seed, so changing them keeps the mnemonic.
+var bip39SeedAttributes = []string{
+	"seed_secret_name",
+	"passphrase",
+}
+
+func NewBip39MnemonicResource() resource.Resource {
+	return &bip39MnemonicResource{}
+}
+
+type bip39MnemonicModelV0 struct {
+	secretMetadataModel
+
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	Words           types.Int64  `tfsdk:"words"`
+	SeedSecretName  types.String `tfsdk:"seed_secret_name"`
+	Passphrase      types.String `tfsdk:"passphrase"`
+	SeedVersion     types.String `tfsdk:"seed_version"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+type bip39MnemonicResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *bip39MnemonicResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *bip39MnemonicResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bip39_mnemonic"
+}
+
+func (r *bip39MnemonicResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_bip39_mnemonic` generates a [BIP-39](https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki) " +
+			"mnemonic from the English wordlist, encoding random entropy and its checksum, and stores it in a azrandom vault.\n" +
+			"\n" +
+			"Optionally, the 64-byte seed derived from the mnemonic and `passphrase` is stored hex encoded in a second secret.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated mnemonic should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated mnemonic was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secrets, stored in the `azrandom:description` tag of the secrets. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of resource. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"words": schema.Int64Attribute{
+				Description: "The number of words of the mnemonic, one of `12`, `15`, `18`, `21` and `24`, encoding " +
+					"128 to 256 bits of entropy. Default value is `24`.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(24),
+				Validators: []validator.Int64{
+					int64validator.OneOf(12, 15, 18, 21, 24),
+				},
+			},
+			"seed_secret_name": schema.StringAttribute{
+				Description: "The name of the secret where the hex encoded seed derived from the mnemonic should be " +
+					"stored. Setting or changing this does not generate a new mnemonic.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"passphrase": schema.StringAttribute{
+				Description: "The passphrase used when deriving the seed. Changing this derives a new seed, but does " +
+					"not generate a new mnemonic. May only be set when `seed_secret_name` is set.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("seed_secret_name")),
+				},
+			},
+			"seed_version": schema.StringAttribute{
+				Description: "The version of the secret named by `seed_secret_name` under which the seed was stored.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *bip39MnemonicResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ValidateConfig ensures that the seed is not stored in the secret of the mnemonic.
+func (r *bip39MnemonicResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config bip39MnemonicModelV0
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsUnknown() || config.SeedSecretName.IsUnknown() || config.SeedSecretName.IsNull() {
+		return
+	}
+
+	if strings.EqualFold(config.Name.ValueString(), config.SeedSecretName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("seed_secret_name"),
+			"Invalid Attribute Combination",
+			"The attribute \"seed_secret_name\" must refer to another secret than \"name\".",
+		)
+	}
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secrets.
+func (r *bip39MnemonicResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretNames(ctx, r.providerData, "azrandom_bip39_mnemonic", req, resp, "name", "seed_secret_name")
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *bip39MnemonicResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan bip39MnemonicModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	mnemonic, err := random.CreateBip39Mnemonic(plan.Words.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	// Check if secrets exist yet
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_bip39_mnemonic", plan.VaultUrl,
+		plan.Name.ValueString(), plan.Name, plan.SeedSecretName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	properties := secretProperties(plan.metadata(r.providerData), nil)
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, plan.Name.ValueString(), mnemonic, properties)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_bip39_mnemonic", plan.Name.ValueString(),
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.Name.ValueString(), version, mnemonic)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.SeedVersion = types.StringNull()
+
+	if !plan.SeedSecretName.IsNull() {
+		seed := hex.EncodeToString(random.Bip39Seed(mnemonic, plan.Passphrase.ValueString()))
+
+		seedVersion, _, err := azrandom.CreateSecret(ctx, client, plan.SeedSecretName.ValueString(), seed, properties)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.CreateError("azrandom_bip39_mnemonic", plan.SeedSecretName.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.SeedSecretName.ValueString(), seedVersion, seed)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.SeedVersion = types.StringValue(seedVersion)
+	}
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *bip39MnemonicResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state bip39MnemonicModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_bip39_mnemonic", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	// If version number has changed we know that drift has occurred.
+	var drifted bool
+	state.Version, drifted = readDrift(ctx, req, resp, state.Version, version)
+
+	if !state.SeedSecretName.IsNull() {
+		seedVersion, _, err := azrandom.GetSecret(ctx, client, state.SeedSecretName.ValueString(), "")
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ReadError("azrandom_bip39_mnemonic", state.SeedSecretName.ValueString(),
+				resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+			return
+		}
+
+		if !drifted && state.SeedVersion.ValueString() != seedVersion {
+			resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateDrift)...)
+		}
+		state.SeedVersion = types.StringValue(seedVersion)
+	}
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *bip39MnemonicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state bip39MnemonicModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, bip39MnemonicComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only metadata changed, keep the current values and versions
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_bip39_mnemonic", plan.VaultUrl,
+			state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() && !state.SeedSecretName.IsNull() {
+			_, diags = updateSecretMetadata(ctx, r.providerData, client, "azrandom_bip39_mnemonic", plan.VaultUrl,
+				state.SeedSecretName.ValueString(), state.SeedVersion.ValueString(), plan.metadata(r.providerData))
+			resp.Diagnostics.Append(diags...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.SeedVersion = state.SeedVersion
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	mnemonicChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(bip39MnemonicComputedAttributes, bip39SeedAttributes...)...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	properties := secretProperties(plan.metadata(r.providerData), nil)
+
+	var mnemonic string
+	if mnemonicChanged || regenerate {
+		var err error
+		mnemonic, err = random.CreateBip39Mnemonic(plan.Words.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+
+		version, stored, err := azrandom.UpdateSecret(ctx, client, plan.Name.ValueString(), mnemonic, properties)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_bip39_mnemonic", plan.Name.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.Name.ValueString(), version, mnemonic)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Version = types.StringValue(version)
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	} else {
+		// Only the seed changes, derive it from the stored mnemonic
+		var err error
+		mnemonic, err = azrandom.GetSecretValue(ctx, client, state.Name.ValueString(), state.Version.ValueString())
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ReadError("azrandom_bip39_mnemonic", state.Name.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_bip39_mnemonic", plan.VaultUrl,
+			state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	}
+
+	plan.SeedVersion = types.StringNull()
+
+	// Remove the seed when it is no longer wanted, or is now stored under another name
+	if !state.SeedSecretName.IsNull() && !state.SeedSecretName.Equal(plan.SeedSecretName) {
+		_, err := azrandom.DeleteSecret(ctx, client, state.SeedSecretName.ValueString())
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "delete the secret",
+				"azrandom_bip39_mnemonic", state.SeedSecretName.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+	}
+
+	if !plan.SeedSecretName.IsNull() {
+		seedName := plan.SeedSecretName.ValueString()
+		seed := hex.EncodeToString(random.Bip39Seed(mnemonic, plan.Passphrase.ValueString()))
+
+		var seedVersion string
+		var err error
+		if state.SeedSecretName.Equal(plan.SeedSecretName) {
+			seedVersion, _, err = azrandom.UpdateSecret(ctx, client, seedName, seed, properties)
+		} else {
+			seedVersion, _, err = azrandom.CreateSecret(ctx, client, seedName, seed, properties)
+		}
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "create the secret",
+				"azrandom_bip39_mnemonic", seedName, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, seedName, seedVersion, seed)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.SeedVersion = types.StringValue(seedVersion)
+	}
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *bip39MnemonicResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state bip39MnemonicModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_bip39_mnemonic", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *bip39MnemonicResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_bip39_mnemonic", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	mnemonic, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_bip39_mnemonic", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	if _, err := random.Bip39EntropyFromMnemonic(mnemonic); err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_bip39_mnemonic error",
+			"The secret "+id+" does not contain a valid BIP-39 mnemonic: "+err.Error(),
+		)
+		return
+	}
+
+	state := bip39MnemonicModelV0{
+		Name:           types.StringValue(id),
+		VaultUrl:       vaultUrl,
+		Version:        types.StringValue(version),
+		Keepers:        types.MapNull(types.StringType),
+		Words:          types.Int64Value(int64(len(strings.Fields(mnemonic)))),
+		SeedSecretName: types.StringNull(),
+		Passphrase:     types.StringNull(),
+		SeedVersion:    types.StringNull(),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
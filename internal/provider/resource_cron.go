@@ -0,0 +1,433 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+var (
+	_ resource.Resource                = (*cronResource)(nil)
+	_ resource.ResourceWithIdentity    = (*cronResource)(nil)
+	_ resource.ResourceWithImportState = (*cronResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*cronResource)(nil)
+)
+
+// The template and the seed from which the random fields are derived are stored as secret tags, so
+// that changing the template keeps the random values, and so that the expression can be imported.
+const (
+	templateTagName = "azrandom:template"
+	seedTagName     = "azrandom:seed"
+)
+
+// cronComputedAttributes are unknown in the plan whenever anything changes.
+var cronComputedAttributes = []string{
+	"version",
+	"expression",
+}
+
+func NewCronResource() resource.Resource {
+	return &cronResource{}
+}
+
+type cronModelV0 struct {
+	secretMetadataModel
+
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	Template        types.String `tfsdk:"template"`
+	Expression      types.String `tfsdk:"expression"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+type cronResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *cronResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *cronResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cron"
+}
+
+func (r *cronResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_cron` picks a random, but stable, schedule within a window, to spread " +
+			"jobs across deployments. The resulting cron expression is stored in a azrandom vault.\n" +
+			"\n" +
+			"The random values are derived from a seed that is only regenerated when `keepers` change. Changing " +
+			"`template` keeps the value of random fields whose position and range did not change.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of resource. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"template": schema.StringAttribute{
+				Description: "A cron expression of 5 fields (minute, hour, day of month, month and day of week), or 6 " +
+					"fields starting with the second, such as `\"R R 2-4 * * *\"`. Fields that are `R` get a random " +
+					"valid value, and fields that are `R(min-max)`, such as `R(0-29)`, a random value in that range. " +
+					"Other fields are copied as-is, and may contain `*`, values, ranges, lists and steps.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+					validators.CronTemplate(),
+				},
+			},
+			"expression": schema.StringAttribute{
+				Description: "The cron expression with the random fields of `template` filled in.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *cronResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *cronResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_cron", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *cronResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan cronModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	seed, err := random.CreateBytes(16)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	expression, err := random.RenderCronTemplate(plan.Template.ValueString(), seed)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_cron error",
+			"Could not render the template of azrandom_cron, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_cron", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, expression, cronSecretProperties(r.providerData, plan, seed))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_cron", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, expression)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.Expression = types.StringValue(expression)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *cronResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state cronModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_cron", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	state.Version, _ = readDrift(ctx, req, resp, state.Version, version)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *cronResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state cronModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, cronComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_cron", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.Expression = state.Expression
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// Keep the seed unless keepers changed, so that only the fields that changed in the template change
+	var seed []byte
+	if plan.Keepers.Equal(state.Keepers) {
+		_, properties, err := azrandom.GetSecret(ctx, client, name, "")
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the secret",
+				"azrandom_cron", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+		seed, _ = hex.DecodeString(properties.Tags[seedTagName])
+	}
+	if len(seed) == 0 {
+		var err error
+		seed, err = random.CreateBytes(16)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+	}
+
+	expression, err := random.RenderCronTemplate(plan.Template.ValueString(), seed)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Update azrandom_cron error",
+			"Could not render the template of azrandom_cron, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, expression, cronSecretProperties(r.providerData, plan, seed))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_cron", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, expression)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.Expression = types.StringValue(expression)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *cronResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state cronModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_cron", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *cronResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_cron", id, resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	template, ok := properties.Tags[templateTagName]
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Import azrandom_cron error",
+			"The secret "+id+" does not have a "+templateTagName+" tag, so the template cannot be determined",
+		)
+		return
+	}
+
+	expression, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_cron", id, resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	state := cronModelV0{
+		Name:       types.StringValue(id),
+		VaultUrl:   vaultUrl,
+		Version:    types.StringValue(version),
+		Keepers:    types.MapNull(types.StringType),
+		Template:   types.StringValue(template),
+		Expression: types.StringValue(expression),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func cronSecretProperties(data *azrandomProviderData, plan cronModelV0, seed []byte) azrandom.SecretProperties {
+	properties := secretProperties(plan.metadata(data), nil)
+	properties.Tags[templateTagName] = plan.Template.ValueString()
+	properties.Tags[seedTagName] = hex.EncodeToString(seed)
+	return properties
+}
@@ -6,49 +6,140 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"encoding/pem"
 	azrandom "terraform-provider-azrandom/client"
-	"terraform-provider-azrandom/internal/utils"
+	"terraform-provider-azrandom/internal/diagnostics"
 )
 
 var (
-	_ resource.Resource                = (*cryptographicKeyResource)(nil)
-	_ resource.ResourceWithImportState = (*cryptographicKeyResource)(nil)
+	_ resource.Resource                     = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithIdentity         = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithImportState      = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithMoveState        = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithUpgradeState     = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*cryptographicKeyResource)(nil)
+	_ resource.ResourceWithValidateConfig   = (*cryptographicKeyResource)(nil)
 )
 
 func NewCryptographicKeyResource() resource.Resource {
 	return &cryptographicKeyResource{}
 }
 
-type cryptographicKeyModelV0 struct {
+type cryptographicKeyModelV1 struct {
 	Name                       types.String `tfsdk:"name"`
+	VaultUrl                   types.String `tfsdk:"vault_url"`
 	Version                    types.String `tfsdk:"version"`
 	Keepers                    types.Map    `tfsdk:"keepers"`
 	Algorithm                  types.String `tfsdk:"algorithm"`
-	RSABits                    types.Int64  `tfsdk:"rsa_bits"`
-	ECDSACurve                 types.String `tfsdk:"ecdsa_curve"`
-	HMACHashFunction           types.String `tfsdk:"hmac_hash_function"`
+	RSA                        types.Object `tfsdk:"rsa"`
+	ECDSA                      types.Object `tfsdk:"ecdsa"`
+	HMAC                       types.Object `tfsdk:"hmac"`
 	PublicKeyPem               types.String `tfsdk:"public_key_pem"`
 	PublicKeyOpenSSH           types.String `tfsdk:"public_key_openssh"`
 	PublicKeyFingerprintMD5    types.String `tfsdk:"public_key_fingerprint_md5"`
 	PublicKeyFingerprintSHA256 types.String `tfsdk:"public_key_fingerprint_sha256"`
+	Description                types.String `tfsdk:"description"`
+	ContentType                types.String `tfsdk:"content_type"`
+	ExpirationDate             types.String `tfsdk:"expiration_date"`
+	NotBefore                  types.String `tfsdk:"not_before"`
+	Enabled                    types.Bool   `tfsdk:"enabled"`
+	Tags                       types.Map    `tfsdk:"tags"`
+	TagsAll                    types.Map    `tfsdk:"tags_all"`
+	ConflictPolicy             types.String `tfsdk:"conflict_policy"`
+	OnDrift                    types.String `tfsdk:"on_drift"`
+	VerifyWrite                types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy             types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion            types.Bool   `tfsdk:"wait_for_deletion"`
+	DisablePreviousVersions    types.Bool   `tfsdk:"disable_previous_versions"`
+	MaxVersionsToKeep          types.Int64  `tfsdk:"max_versions_to_keep"`
+	TrimOutputs                types.Bool   `tfsdk:"trim_outputs"`
+	CreatedOn                  types.String `tfsdk:"created_on"`
+	UpdatedOn                  types.String `tfsdk:"updated_on"`
+	CreatedDate                types.String `tfsdk:"created_date"`
+	UpdatedDate                types.String `tfsdk:"updated_date"`
+}
+
+// metadata returns the attributes that are stored as properties of the secret, along with the tags
+// written by the provider.
+func (m cryptographicKeyModelV1) metadata(data *azrandomProviderData) secretMetadata {
+	return secretMetadata{
+		Description:    m.Description,
+		TagsAll:        m.TagsAll,
+		ContentType:    m.ContentType,
+		ExpirationDate: m.ExpirationDate,
+		NotBefore:      m.NotBefore,
+		Enabled:        m.Enabled,
+		ProviderTags:   providerMetadataTags(data),
+	}
+}
+
+type cryptographicKeyRSAModel struct {
+	Bits types.Int64 `tfsdk:"bits"`
+}
+
+type cryptographicKeyECDSAModel struct {
+	Curve types.String `tfsdk:"curve"`
+}
+
+type cryptographicKeyHMACModel struct {
+	HashFunction types.String `tfsdk:"hash_function"`
+	KeyLength    types.Int64  `tfsdk:"key_length"`
+}
+
+var cryptographicKeyRSAAttrTypes = map[string]attr.Type{
+	"bits": types.Int64Type,
+}
+
+var cryptographicKeyECDSAAttrTypes = map[string]attr.Type{
+	"curve": types.StringType,
+}
+
+var cryptographicKeyHMACAttrTypes = map[string]attr.Type{
+	"hash_function": types.StringType,
+	"key_length":    types.Int64Type,
+}
+
+// cryptographicKeyAttrTypes returns the attribute types of the nested attribute with the given name.
+func cryptographicKeyAttrTypes(attributeName string) map[string]attr.Type {
+	switch attributeName {
+	case "rsa":
+		return cryptographicKeyRSAAttrTypes
+	case "ecdsa":
+		return cryptographicKeyECDSAAttrTypes
+	default:
+		return cryptographicKeyHMACAttrTypes
+	}
+}
+
+// cryptographicKeyAlgorithmAttributes maps each algorithm to the nested attribute holding its settings.
+var cryptographicKeyAlgorithmAttributes = map[string]Algorithm{
+	"rsa":   RSA,
+	"ecdsa": ECDSA,
+	"hmac":  HMAC,
 }
 
 type cryptographicKeyResource struct {
-	client *azsecrets.Client
+	providerData *azrandomProviderData
 }
 
 // Configure adds the provider configured client to the resource.
@@ -57,18 +148,18 @@ func (r *cryptographicKeyResource) Configure(_ context.Context, req resource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*azsecrets.Client)
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *azsecrets.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = providerData
 }
 
 func (r *cryptographicKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -77,25 +168,58 @@ func (r *cryptographicKeyResource) Metadata(_ context.Context, req resource.Meta
 
 func (r *cryptographicKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Description: "The resource `azrandom_cryptographic_key` generates a random cryptographicKey string that is intended to be " +
 			"used as a unique identifier for other resources.\n" +
 			"\n" +
 			"This resource uses [hashicorp/go-cryptographicKey](https://github.com/hashicorp/go-cryptographicKey) to generate a " +
 			"UUID-formatted string for use with services needing a unique string identifier.\n" +
 			"\n" +
+			"A `tls_private_key` of the `hashicorp/tls` provider can be moved into an " +
+			"`azrandom_cryptographic_key` with a `moved` block, which keeps its private key: the next apply " +
+			"stores it in the configured secret.\n" +
+			"\n" +
 			"Finally, the generated string is stored in a azrandom vault",
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Description: "The name of the secret where the generated value should be stored",
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					nameRequiresReplace(),
 				},
 			},
 			"version": schema.StringAttribute{
 				Description: "The version to the secret under which the generated value was stored ",
 				Computed:    true,
 			},
+			"created_on":                createdOnAttribute(),
+			"updated_on":                updatedOnAttribute(),
+			"created_date":              createdDateAttribute(),
+			"updated_date":              updatedDateAttribute(),
+			"conflict_policy":           conflictPolicyAttribute(),
+			"on_drift":                  onDriftAttribute(),
+			"verify_write":              verifyWriteAttribute(),
+			"purge_on_destroy":          purgeOnDestroyAttribute(),
+			"wait_for_deletion":         waitForDeletionAttribute(),
+			"vault_url":                 vaultUrlAttribute(),
+			"disable_previous_versions": disablePreviousVersionsAttribute(),
+			"max_versions_to_keep":      maxVersionsToKeepAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypePEM),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
 			"keepers": schema.MapAttribute{
 				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
 					"resource. See [the main provider documentation](../index.html) for more information.",
@@ -105,45 +229,84 @@ func (r *cryptographicKeyResource) Schema(ctx context.Context, req resource.Sche
 			"algorithm": schema.StringAttribute{
 				Required: true,
 				Description: "Name of the algorithm to use when generating the private key. " +
-					fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(supportedAlgorithmsStr(), "`, `")),
+					fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(supportedAlgorithmsStr(), "`, `")) +
+					"Algorithm specific settings can be supplied in the nested attribute of the same name.",
 				Validators: []validator.String{
 					stringvalidator.OneOf(supportedAlgorithmsStr()...),
 				},
 			},
-			"rsa_bits": schema.Int64Attribute{
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(2048),
-				MarkdownDescription: "When `algorithm` is `RSA`, the size of the generated RSA key, in bits (default: `2048`).",
+			"rsa": schema.SingleNestedAttribute{
+				Optional: true,
+				Computed: true,
+				MarkdownDescription: "Settings used when `algorithm` is `RSA`. May only be set when `algorithm` is `RSA`. " +
+					"When omitted, the defaults below are used for new keys and the settings of an existing key are kept.",
+				Attributes: map[string]schema.Attribute{
+					"bits": schema.Int64Attribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             int64default.StaticInt64(defaultRSABits),
+						MarkdownDescription: fmt.Sprintf("The size of the generated RSA key, in bits (default: `%d`).", defaultRSABits),
+					},
+				},
 			},
-			"hmac_hash_function": schema.StringAttribute{
+			"ecdsa": schema.SingleNestedAttribute{
 				Optional: true,
 				Computed: true,
-				Default:  stringdefault.StaticString(SHA256.String()),
-				Validators: []validator.String{
-					stringvalidator.OneOf(supportedHMACHashFunctionsStr()...),
+				MarkdownDescription: "Settings used when `algorithm` is `ECDSA`. May only be set when `algorithm` is `ECDSA`. " +
+					"When omitted, the defaults below are used for new keys and the settings of an existing key are kept.",
+				Attributes: map[string]schema.Attribute{
+					"curve": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString(defaultECDSACurve.String()),
+						Validators: []validator.String{
+							stringvalidator.OneOf(supportedECDSACurvesStr()...),
+						},
+						MarkdownDescription: "The name of the elliptic curve to use. " +
+							fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(supportedECDSACurvesStr(), "`, `")) +
+							fmt.Sprintf("(default: `%s`).", defaultECDSACurve.String()),
+					},
 				},
-				MarkdownDescription: "When `algorithm` is `HMAC`, the hash function used to use (default: `SHA256`).",
 			},
-			"ecdsa_curve": schema.StringAttribute{
+			"hmac": schema.SingleNestedAttribute{
 				Optional: true,
 				Computed: true,
-				Default:  stringdefault.StaticString(P224.String()),
-				Validators: []validator.String{
-					stringvalidator.OneOf(supportedECDSACurvesStr()...),
+				MarkdownDescription: "Settings used when `algorithm` is `HMAC`. May only be set when `algorithm` is `HMAC`. " +
+					"When omitted, the defaults below are used for new keys and the settings of an existing key are kept.",
+				Attributes: map[string]schema.Attribute{
+					"hash_function": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString(defaultHMACHashFunction.String()),
+						Validators: []validator.String{
+							stringvalidator.OneOf(supportedHMACHashFunctionsStr()...),
+						},
+						MarkdownDescription: fmt.Sprintf("The hash function to use (default: `%s`).", defaultHMACHashFunction.String()),
+					},
+					"key_length": schema.Int64Attribute{
+						Optional: true,
+						Computed: true,
+						Default:  int64default.StaticInt64(defaultHMACKeyLength),
+						Validators: []validator.Int64{
+							int64validator.Between(16, 128),
+						},
+						MarkdownDescription: fmt.Sprintf("The length of the generated key, in bytes (default: `%d`).", defaultHMACKeyLength),
+					},
 				},
-				MarkdownDescription: "When `algorithm` is `ECDSA`, the name of the elliptic curve to use. " +
-					fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(supportedECDSACurvesStr(), "`, `")) +
-					fmt.Sprintf("(default: `%s`).", P224.String()),
+			},
+			"trim_outputs": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: "Whether to strip the trailing newline from `public_key_pem` and `public_key_openssh`. " +
+					"Changing this does not generate a new key. Default value is `false`.",
 			},
 			"public_key_pem": schema.StringAttribute{
 				Computed: true,
 				MarkdownDescription: "Public key data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
 					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
 					"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
-					"value append a `\\n` at the end of the PEM. " +
-					"In case this disrupts your use case, we recommend using " +
-					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+					"value append a `\\n` at the end of the PEM, unless `trim_outputs` is `true`.",
 			},
 			"public_key_openssh": schema.StringAttribute{
 				Computed: true,
@@ -152,9 +315,7 @@ func (r *cryptographicKeyResource) Schema(ctx context.Context, req resource.Sche
 					"This is not populated for `ECDSA` with curve `P224`, as it is [not supported](../../docs#limitations). " +
 					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
 					"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
-					"value append a `\\n` at the end of the PEM. " +
-					"In case this disrupts your use case, we recommend using " +
-					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+					"value append a `\\n` at the end, unless `trim_outputs` is `true`.",
 			},
 			"public_key_fingerprint_md5": schema.StringAttribute{
 				Computed: true,
@@ -172,10 +333,273 @@ func (r *cryptographicKeyResource) Schema(ctx context.Context, req resource.Sche
 	}
 }
 
+func (r *cryptographicKeyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+func (r *cryptographicKeyResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("rsa"),
+			path.MatchRoot("ecdsa"),
+			path.MatchRoot("hmac"),
+		),
+	}
+}
+
+// ValidateConfig ensures that algorithm specific settings are only supplied for the selected algorithm.
+func (r *cryptographicKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var algorithm types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("algorithm"), &algorithm)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delay validation until the algorithm is known
+	if algorithm.IsUnknown() || algorithm.IsNull() {
+		return
+	}
+
+	for attributeName, attributeAlgorithm := range cryptographicKeyAlgorithmAttributes {
+		var settings types.Object
+		diags := req.Config.GetAttribute(ctx, path.Root(attributeName), &settings)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			continue
+		}
+
+		if settings.IsNull() || Algorithm(algorithm.ValueString()) == attributeAlgorithm {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attributeName),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attribute %q may only be set when \"algorithm\" is %q, but \"algorithm\" is %q.",
+				attributeName, attributeAlgorithm.String(), algorithm.ValueString()),
+		)
+	}
+}
+
+func (r *cryptographicKeyResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := cryptographicKeySchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeCryptographicKeyStateV0toV1,
+		},
+	}
+}
+
+// MoveState moves the state of a tls_private_key of the hashicorp/tls provider into an azrandom_cryptographic_key.
+func (r *cryptographicKeyResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: moveCryptographicKeyState,
+		},
+	}
+}
+
+// tlsPrivateKeyState holds the attributes of the state of a tls_private_key that an azrandom_cryptographic_key
+// is moved with.
+type tlsPrivateKeyState struct {
+	Algorithm     string `json:"algorithm"`
+	RSABits       int64  `json:"rsa_bits"`
+	ECDSACurve    string `json:"ecdsa_curve"`
+	PrivateKeyPem string `json:"private_key_pem"`
+}
+
+// moveCryptographicKeyState takes the algorithm, its settings and the private key of a tls_private_key into
+// state, with the public key outputs derived from the private key and the other settings at their defaults.
+// The resource has no secret until the next apply, which stores the private key in the configured secret.
+func moveCryptographicKeyState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if !movedFrom(req, tlsProviderSource, "tls_private_key") {
+		return
+	}
+
+	var source tlsPrivateKeyState
+	resp.Diagnostics.Append(decodeMovedState(req, &source)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prvKey, err := parsePrivateKeyPEM(source.PrivateKeyPem)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			"The private_key_pem of the tls_private_key does not hold a private key in PEM format: "+err.Error(),
+		)
+		return
+	}
+
+	pubKeyBundle, err := getPublicKeyBundle(ctx, prvKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			"Error resolve public key, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	state := cryptographicKeyModelV1{
+		Name:                       types.StringNull(),
+		VaultUrl:                   types.StringNull(),
+		Version:                    types.StringNull(),
+		Keepers:                    types.MapNull(types.StringType),
+		Algorithm:                  types.StringValue(source.Algorithm),
+		RSA:                        types.ObjectNull(cryptographicKeyRSAAttrTypes),
+		ECDSA:                      types.ObjectNull(cryptographicKeyECDSAAttrTypes),
+		HMAC:                       types.ObjectNull(cryptographicKeyHMACAttrTypes),
+		PublicKeyFingerprintMD5:    types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5),
+		PublicKeyFingerprintSHA256: types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256),
+		ContentType:                types.StringValue(contentTypePEM),
+		Enabled:                    types.BoolValue(true),
+		Tags:                       types.MapNull(types.StringType),
+		TagsAll:                    types.MapNull(types.StringType),
+		ConflictPolicy:             types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:                    types.StringValue(OnDriftRegenerate.String()),
+		TrimOutputs:                types.BoolValue(false),
+	}
+	state.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), state.TrimOutputs)
+	state.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), state.TrimOutputs)
+
+	var diags diag.Diagnostics
+	switch Algorithm(source.Algorithm) {
+	case RSA:
+		state.RSA, diags = types.ObjectValue(cryptographicKeyRSAAttrTypes, map[string]attr.Value{
+			"bits": types.Int64Value(source.RSABits),
+		})
+	case ECDSA:
+		state.ECDSA, diags = types.ObjectValue(cryptographicKeyECDSAAttrTypes, map[string]attr.Value{
+			"curve": types.StringValue(source.ECDSACurve),
+		})
+	case ED25519:
+	default:
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf("The algorithm of the tls_private_key, %q, is not supported.", source.Algorithm),
+		)
+		return
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(storeMovedValue(ctx, resp.TargetPrivate, source.PrivateKeyPem)...)
+	resp.Diagnostics.Append(resp.TargetState.Set(ctx, state)...)
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret,
+// and resolves the algorithm specific settings that were omitted from configuration.
+func (r *cryptographicKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_cryptographic_key", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planAlgorithm, stateAlgorithm types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("algorithm"), &planAlgorithm)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("algorithm"), &stateAlgorithm)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for attributeName, attributeAlgorithm := range cryptographicKeyAlgorithmAttributes {
+		var configSettings, stateSettings types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attributeName), &configSettings)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Settings given in configuration are planned as is
+		if !configSettings.IsNull() {
+			continue
+		}
+
+		// Keep the settings an existing key was generated with as long as the algorithm is unchanged,
+		// so that omitting the settings (e.g. after upgrading from the flat attributes) does not rotate
+		// the key. A new key of this algorithm stores the defaults it is generated with, like an import
+		// does, and nothing is stored for other algorithms.
+		plannedSettings := types.ObjectNull(cryptographicKeyAttrTypes(attributeName))
+		if Algorithm(planAlgorithm.ValueString()) == attributeAlgorithm {
+			plannedSettings = defaultKeySettings(attributeName)
+		}
+		if !req.State.Raw.IsNull() && planAlgorithm.Equal(stateAlgorithm) {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(attributeName), &stateSettings)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			plannedSettings = stateSettings
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attributeName), plannedSettings)...)
+	}
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	planVersion(ctx, req, resp, cryptographicKeyComputedAttributes...)
+
+	// When the key is kept, the outputs are known at plan time, re-rendered for trim_outputs
+	changed, diags := valueAttributesChanged(resp.Plan.Raw, req.State.Raw, cryptographicKeyComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || changed || regenerate {
+		return
+	}
+
+	var plan, state cryptographicKeyModelV1
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.TrimOutputs.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_key_pem"), renderPublicKeyOutput(state.PublicKeyPem, plan.TrimOutputs))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_key_openssh"), renderPublicKeyOutput(state.PublicKeyOpenSSH, plan.TrimOutputs))...)
+}
+
+// cryptographicKeyComputedAttributes are unknown in the plan whenever anything changes. trim_outputs only
+// changes how the outputs are rendered, conflict_policy is only used by Create, and on_drift only after drift
+// was detected, so they are ignored as well when deciding whether to generate a new key.
+var cryptographicKeyComputedAttributes = []string{
+	"version",
+	"conflict_policy",
+	"on_drift",
+	"public_key_pem",
+	"public_key_openssh",
+	"public_key_fingerprint_md5",
+	"public_key_fingerprint_sha256",
+	"trim_outputs",
+}
+
+// renderPublicKeyOutput returns a public key output with its trailing whitespace stripped when trim is
+// true, or ending in the newline added by the underlying libraries otherwise.
+func renderPublicKeyOutput(value types.String, trim types.Bool) types.String {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return value
+	}
+
+	trimmed := strings.TrimRight(value.ValueString(), " \t\r\n")
+	if trim.ValueBool() {
+		return types.StringValue(trimmed)
+	}
+	return types.StringValue(trimmed + "\n")
+}
+
 func (r *cryptographicKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 
 	// Get plan
-	var plan cryptographicKeyModelV0
+	var plan cryptographicKeyModelV1
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -183,6 +607,33 @@ func (r *cryptographicKeyResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	name := plan.Name.ValueString()
+
+	conflictPolicy := ConflictPolicy(plan.ConflictPolicy.ValueString())
+
+	// Take the existing secret into state instead of generating a key
+	if conflictPolicy == ConflictPolicyAdopt {
+		version, found, err := adoptExistingSecret(ctx, client, name, azrandom.DefaultRecoveryBackoff)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "adopt the existing secret",
+				"azrandom_cryptographic_key", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		if found {
+			r.adopt(ctx, client, &plan, version, resp)
+			return
+		}
+	}
+
 	// Generate key
 	prvKey, prvKeyPemBlock, err := createKey(ctx, plan)
 	if err != nil {
@@ -204,37 +655,45 @@ func (r *cryptographicKeyResource) Create(ctx context.Context, req resource.Crea
 	}
 
 	// Check if secret exists yet
-	name := plan.Name.ValueString()
-	secretExists, err := azrandom.SecretExists(ctx, r.client, name)
-	if secretExists {
+	secretExists, err := secretExistsBeforeCreate(ctx, r.providerData, client, name)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "check whether the secret already exists",
+			"azrandom_cryptographic_key", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+	if secretExists && conflictPolicy == ConflictPolicyFail {
 		resp.Diagnostics.AddError(
 			"Create azrandom_cryptographic_key error",
-			"A azrandom_cryptographic_key with name  "+name+" already exists. To manage this in terraform you must import it",
+			existingSecretMessage(ctx, client, "azrandom_cryptographic_key", name, name)+
+				"\n\nAlternatively, set conflict_policy to \"overwrite\" or \"adopt\".",
 		)
 		return
 	}
 
 	// Create secret
-	version, err := azrandom.CreateSecret(ctx, r.client, name, string(pem.EncodeToMemory(prvKeyPemBlock)))
+	privateKeyPem := string(pem.EncodeToMemory(prvKeyPemBlock))
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, privateKeyPem, secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Create azrandom_cryptographic_key error",
-			"Could not read azrandom_cryptographic_key from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_cryptographic_key", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
 		return
 	}
 
-	// Set the version
-	plan.Version = types.StringValue(version)
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, privateKeyPem)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Set computed attributes
 	plan.Version = types.StringValue(version)
-	plan.PublicKeyPem = types.StringValue(pubKeyBundle.PublicKeyPem)
-	plan.PublicKeyOpenSSH = types.StringValue(pubKeyBundle.PublicKeySSH)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+	plan.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), plan.TrimOutputs)
+	plan.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), plan.TrimOutputs)
 	plan.PublicKeyFingerprintMD5 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5)
 	plan.PublicKeyFingerprintSHA256 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256)
 
 	// Update the state
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), privateKeyPem)...)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -242,30 +701,116 @@ func (r *cryptographicKeyResource) Create(ctx context.Context, req resource.Crea
 	}
 }
 
+// adopt takes the given version of the existing secret of plan, read with client, into state, deriving
+// the public key outputs from the private key that it holds.
+func (r *cryptographicKeyResource) adopt(ctx context.Context, client *azsecrets.Client, plan *cryptographicKeyModelV1, version string, resp *resource.CreateResponse) {
+	name := plan.Name.ValueString()
+
+	value, err := azrandom.GetSecretValue(ctx, client, name, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "adopt the existing secret",
+			"azrandom_cryptographic_key", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	prvKey, err := parsePrivateKeyPEM(value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_cryptographic_key error",
+			"Could not adopt the secret "+name+", since it does not hold a private key in PEM format: "+err.Error(),
+		)
+		return
+	}
+
+	pubKeyBundle, err := getPublicKeyBundle(ctx, prvKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_cryptographic_key error",
+			"Error resolve public key, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	stored, err := updateSecretProperties(ctx, client, name, version, plan.metadata(r.providerData))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "update the properties of the secret",
+			"azrandom_cryptographic_key", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+	plan.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), plan.TrimOutputs)
+	plan.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), plan.TrimOutputs)
+	plan.PublicKeyFingerprintMD5 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5)
+	plan.PublicKeyFingerprintSHA256 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
 func (r *cryptographicKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 
-	var state cryptographicKeyModelV0
+	var state cryptographicKeyModelV1
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	version, err := azrandom.GetSecret(ctx, r.client, state.Name.ValueString())
+	// A resource moved from tls_private_key has no secret until the next apply stores its key
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resources created before they had an identity get it on refresh
+	if resp.Identity.Raw.IsNull() {
+		resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	}
+
+	pinned, diags := pinnedVersion(ctx, req.Private, state.Version)
+	resp.Diagnostics.Append(diags...)
+	version, properties, value, err := azrandom.GetSecretWithValue(ctx, client, state.Name.ValueString(), pinned)
+	if secretDeletedOutOfBand(ctx, "azrandom_cryptographic_key", state.Name.ValueString(), err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Read azrandom_cryptographic_key error",
-			"Could not read azrandom_cryptographic_key from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_cryptographic_key", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
 		return
 	}
 
-	// If version number has changed we know that drift has occurred.
-	if state.Version.ValueString() != version {
-		state.Version = types.StringValue(version)
-		keepers, _ := utils.GenerateDriftKeepers(ctx)
-		state.Keepers = keepers
+	state.Description = descriptionFromProperties(properties)
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, state.ExpirationDate)
+	state.NotBefore = validityTimestamp(properties.NotBefore, state.NotBefore)
+	resp.Diagnostics.Append(disabledSecretDiagnostics(state.Name.ValueString(), state.Enabled, properties)...)
+	state.Enabled = enabledFromProperties(properties)
+	resp.Diagnostics.Append(expiredSecretDiagnostics(state.Name.ValueString(), properties)...)
+	state.TagsAll = tagsFromProperties(properties, state.TagsAll)
+	state.Tags, diags = resourceTags(r.providerData, state.TagsAll, state.Tags)
+	resp.Diagnostics.Append(diags...)
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
 
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// A new version, or a version that no longer holds the generated value, e.g. after a restore from backup, is
+	// drift
+	reason, diags := readValueDrift(ctx, req, resp, state.Version, version, value)
+	resp.Diagnostics.Append(diags...)
+	state.Version = types.StringValue(version)
+	if reason != "" {
+		resp.Diagnostics.Append(recordDrift(ctx, resp.Private, state.OnDrift, reason, version)...)
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -277,13 +822,72 @@ func (r *cryptographicKeyResource) Read(ctx context.Context, req resource.ReadRe
 
 func (r *cryptographicKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 
-	var plan cryptographicKeyModelV0
+	var plan cryptographicKeyModelV1
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state cryptographicKeyModelV1
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A resource moved from tls_private_key stores its key in a new secret
+	movedKey, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if moved {
+		r.updateMoved(ctx, client, req, resp, plan, movedKey)
+		return
+	}
+
+	valueChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, cryptographicKeyComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read marks the value for regeneration, e.g. when drift was detected
+	regenerate, diags := regenerationPlanned(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only metadata changed, keep the current value and version
+	if !valueChanged && !regenerate {
+		stored, err := updateSecretProperties(ctx, client, state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+				"azrandom_cryptographic_key", state.Name.ValueString(), resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+		plan.PublicKeyPem = renderPublicKeyOutput(state.PublicKeyPem, plan.TrimOutputs)
+		plan.PublicKeyOpenSSH = renderPublicKeyOutput(state.PublicKeyOpenSSH, plan.TrimOutputs)
+		plan.PublicKeyFingerprintMD5 = state.PublicKeyFingerprintMD5
+		plan.PublicKeyFingerprintSHA256 = state.PublicKeyFingerprintSHA256
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
 	// Create private key
 	prvKey, prvKeyPemBlock, err := createKey(ctx, plan)
 	if err != nil {
@@ -306,77 +910,358 @@ func (r *cryptographicKeyResource) Update(ctx context.Context, req resource.Upda
 
 	// Create secret
 	name := plan.Name.ValueString()
-	version, err := azrandom.UpdateSecret(ctx, r.client, name, string(pem.EncodeToMemory(prvKeyPemBlock)))
+	privateKeyPem := string(pem.EncodeToMemory(prvKeyPemBlock))
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, privateKeyPem, secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Create azrandom_cryptographic_key error",
-			"Could not read azrandom_cryptographic_key from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_cryptographic_key", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, privateKeyPem)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Set computed attributes
 	plan.Version = types.StringValue(version)
-	plan.PublicKeyPem = types.StringValue(pubKeyBundle.PublicKeyPem)
-	plan.PublicKeyOpenSSH = types.StringValue(pubKeyBundle.PublicKeySSH)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+	plan.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), plan.TrimOutputs)
+	plan.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), plan.TrimOutputs)
 	plan.PublicKeyFingerprintMD5 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5)
 	plan.PublicKeyFingerprintSHA256 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256)
 
 	// Update the state
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), privateKeyPem)...)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(disablePreviousVersions(ctx, client, plan.DisablePreviousVersions, name, version)...)
+	resp.Diagnostics.Append(pruneSecretVersions(ctx, client, plan.MaxVersionsToKeep, name, version)...)
 }
-func (r *cryptographicKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 
-	var state cryptographicKeyModelV0
-	diags := req.State.Get(ctx, &state)
+// updateMoved stores moved, the private key of the tls_private_key that the resource was moved from, in the
+// secret of plan. When the algorithm or its settings differ from the moved state, a new key is stored instead.
+func (r *cryptographicKeyResource) updateMoved(ctx context.Context, client *azsecrets.Client, req resource.UpdateRequest, resp *resource.UpdateResponse, plan cryptographicKeyModelV1, moved string) {
+	name := plan.Name.ValueString()
+
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(slices.Clone(cryptographicKeyComputedAttributes), "name")...)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := azrandom.DeleteSecret(ctx, r.client, state.Name.ValueString())
+	privateKeyPem := moved
+	prvKey, err := parsePrivateKeyPEM(moved)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Update azrandom_cryptographic_key error",
+			"The moved private key is not in PEM format: "+err.Error(),
+		)
+		return
+	}
+	if changed {
+		var prvKeyPemBlock *pem.Block
+		prvKey, prvKeyPemBlock, err = createKey(ctx, plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Update azrandom_cryptographic_key error",
+				"Error creating private key, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		privateKeyPem = string(pem.EncodeToMemory(prvKeyPemBlock))
+	}
 
+	pubKeyBundle, err := getPublicKeyBundle(ctx, prvKey)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Delete azrandom_cryptographic_key error",
-			"Could not delete azrandom_cryptographic_key from azrandom storage, unexpected error: "+err.Error(),
+			"Update azrandom_cryptographic_key error",
+			"Error resolve public key, unexpected error: "+err.Error(),
 		)
 		return
 	}
+
+	version, stored, diags := createMovedSecret(ctx, r.providerData, client, "azrandom_cryptographic_key", plan.VaultUrl,
+		name, plan.ConflictPolicy, privateKeyPem, plan.metadata(r.providerData))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, privateKeyPem)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+	plan.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), plan.TrimOutputs)
+	plan.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), plan.TrimOutputs)
+	plan.PublicKeyFingerprintMD5 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5)
+	plan.PublicKeyFingerprintSHA256 = types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256)
+
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), privateKeyPem)...)
+	resp.Diagnostics.Append(clearMovedValue(ctx, resp.Private)...)
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *cryptographicKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state cryptographicKeyModelV1
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A resource moved from tls_private_key has no secret to delete until an apply stored its key
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleted, err := azrandom.DeleteSecret(ctx, client, state.Name.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.DeleteError("azrandom_cryptographic_key", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+		return
+	}
+
+	if deleted {
+		resp.Diagnostics.Append(waitForDeletion(ctx, client, state.WaitForDeletion, state.Name.ValueString())...)
+		resp.Diagnostics.Append(purgeOnDestroy(ctx, client, state.PurgeOnDestroy, state.Name.ValueString())...)
+	}
 }
 
 func (r *cryptographicKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_cryptographic_key", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
 
-	version, err := azrandom.GetSecret(ctx, r.client, req.ID)
+	// The algorithm and its settings are derived from the stored key, so that the imported key is kept
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_cryptographic_key", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	prvKey, err := parsePrivateKeyPEM(value)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Import azrandom_cryptographic_key error",
-			"Could not read azrandom_cryptographic_key from azrandom storage, unexpected error: "+err.Error(),
+			"Could not import the secret "+id+", since it does not hold a private key in PEM format: "+err.Error(),
+		)
+		return
+	}
+
+	pubKeyBundle, err := getPublicKeyBundle(ctx, prvKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_cryptographic_key error",
+			"Error resolve public key, unexpected error: "+err.Error(),
 		)
 		return
 	}
 
-	state := cryptographicKeyModelV0{
-		Name:                       types.StringValue(req.ID),
+	state := cryptographicKeyModelV1{
+		Name:                       types.StringValue(id),
+		VaultUrl:                   vaultUrl,
 		Version:                    types.StringValue(version),
 		Keepers:                    types.MapNull(types.StringType),
-		Algorithm:                  types.StringNull(),
-		RSABits:                    types.Int64Value(0),
-		ECDSACurve:                 types.StringNull(),
-		PublicKeyPem:               types.StringNull(),
-		PublicKeyOpenSSH:           types.StringNull(),
-		PublicKeyFingerprintMD5:    types.StringNull(),
-		PublicKeyFingerprintSHA256: types.StringNull(),
+		PublicKeyFingerprintMD5:    types.StringValue(pubKeyBundle.PublicKeyFingerPrintMD5),
+		PublicKeyFingerprintSHA256: types.StringValue(pubKeyBundle.PublicKeyFingerPrintSHA256),
+		Description:                descriptionFromProperties(properties),
+		ContentType:                contentTypeFromProperties(properties),
+		ExpirationDate:             validityTimestamp(properties.Expires, types.StringNull()),
+		NotBefore:                  validityTimestamp(properties.NotBefore, types.StringNull()),
+		Enabled:                    enabledFromProperties(properties),
+		Tags:                       importedTagsFromProperties(properties),
+		TagsAll:                    importedTagsFromProperties(properties),
+		ConflictPolicy:             types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:                    types.StringValue(OnDriftRegenerate.String()),
+		VerifyWrite:                types.BoolNull(),
+		PurgeOnDestroy:             types.BoolNull(),
+		WaitForDeletion:            types.BoolNull(),
+		DisablePreviousVersions:    types.BoolNull(),
+		MaxVersionsToKeep:          types.Int64Null(),
+		TrimOutputs:                types.BoolValue(false),
+	}
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
+	state.PublicKeyPem = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeyPem), state.TrimOutputs)
+	state.PublicKeyOpenSSH = renderPublicKeyOutput(types.StringValue(pubKeyBundle.PublicKeySSH), state.TrimOutputs)
+	if err := keySettings(prvKey, &state); err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_cryptographic_key error",
+			"Could not import the secret "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+type cryptographicKeyModelV0 struct {
+	Name                       types.String `tfsdk:"name"`
+	Version                    types.String `tfsdk:"version"`
+	Keepers                    types.Map    `tfsdk:"keepers"`
+	Algorithm                  types.String `tfsdk:"algorithm"`
+	RSABits                    types.Int64  `tfsdk:"rsa_bits"`
+	ECDSACurve                 types.String `tfsdk:"ecdsa_curve"`
+	HMACHashFunction           types.String `tfsdk:"hmac_hash_function"`
+	PublicKeyPem               types.String `tfsdk:"public_key_pem"`
+	PublicKeyOpenSSH           types.String `tfsdk:"public_key_openssh"`
+	PublicKeyFingerprintMD5    types.String `tfsdk:"public_key_fingerprint_md5"`
+	PublicKeyFingerprintSHA256 types.String `tfsdk:"public_key_fingerprint_sha256"`
+}
+
+// cryptographicKeySchemaV0 is the schema prior to the algorithm specific settings being moved into
+// nested attributes. It is only used to read existing state during UpgradeState.
+func cryptographicKeySchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"version": schema.StringAttribute{
+				Computed: true,
+			},
+			"keepers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"algorithm": schema.StringAttribute{
+				Required: true,
+			},
+			"rsa_bits": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+			},
+			"hmac_hash_function": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"ecdsa_curve": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed: true,
+			},
+			"public_key_openssh": schema.StringAttribute{
+				Computed: true,
+			},
+			"public_key_fingerprint_md5": schema.StringAttribute{
+				Computed: true,
+			},
+			"public_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// upgradeCryptographicKeyStateV0toV1 moves the flat rsa_bits, ecdsa_curve and hmac_hash_function
+// attributes into the nested attribute matching the algorithm. Settings for other algorithms are
+// dropped from state.
+func upgradeCryptographicKeyStateV0toV1(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior cryptographicKeyModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := cryptographicKeyModelV1{
+		Name:                       prior.Name,
+		Version:                    prior.Version,
+		Keepers:                    prior.Keepers,
+		Algorithm:                  prior.Algorithm,
+		RSA:                        types.ObjectNull(cryptographicKeyRSAAttrTypes),
+		ECDSA:                      types.ObjectNull(cryptographicKeyECDSAAttrTypes),
+		HMAC:                       types.ObjectNull(cryptographicKeyHMACAttrTypes),
+		PublicKeyPem:               prior.PublicKeyPem,
+		PublicKeyOpenSSH:           prior.PublicKeyOpenSSH,
+		PublicKeyFingerprintMD5:    prior.PublicKeyFingerprintMD5,
+		PublicKeyFingerprintSHA256: prior.PublicKeyFingerprintSHA256,
+		Tags:                       types.MapNull(types.StringType),
+		TagsAll:                    types.MapNull(types.StringType),
+		ConflictPolicy:             types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:                    types.StringValue(OnDriftRegenerate.String()),
+		TrimOutputs:                types.BoolValue(false),
+		CreatedOn:                  types.StringNull(),
+		UpdatedOn:                  types.StringNull(),
+		CreatedDate:                types.StringNull(),
+		UpdatedDate:                types.StringNull(),
 	}
 
-	diags := resp.State.Set(ctx, &state)
+	var diags diag.Diagnostics
+	switch Algorithm(prior.Algorithm.ValueString()) {
+	case RSA:
+		bits := prior.RSABits
+		if bits.IsNull() {
+			bits = types.Int64Value(defaultRSABits)
+		}
+		upgraded.RSA, diags = types.ObjectValue(cryptographicKeyRSAAttrTypes, map[string]attr.Value{
+			"bits": bits,
+		})
+	case ECDSA:
+		curve := prior.ECDSACurve
+		if curve.IsNull() {
+			curve = types.StringValue(defaultECDSACurve.String())
+		}
+		upgraded.ECDSA, diags = types.ObjectValue(cryptographicKeyECDSAAttrTypes, map[string]attr.Value{
+			"curve": curve,
+		})
+	case HMAC:
+		hashFunction := prior.HMACHashFunction
+		if hashFunction.IsNull() {
+			hashFunction = types.StringValue(defaultHMACHashFunction.String())
+		}
+		// Keys created before key_length existed were always generated with the default length
+		upgraded.HMAC, diags = types.ObjectValue(cryptographicKeyHMACAttrTypes, map[string]attr.Value{
+			"hash_function": hashFunction,
+			"key_length":    types.Int64Value(defaultHMACKeyLength),
+		})
+	}
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
 }
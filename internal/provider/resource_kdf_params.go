@@ -0,0 +1,909 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+var (
+	_ resource.Resource                     = (*kdfParamsResource)(nil)
+	_ resource.ResourceWithIdentity         = (*kdfParamsResource)(nil)
+	_ resource.ResourceWithImportState      = (*kdfParamsResource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*kdfParamsResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*kdfParamsResource)(nil)
+	_ resource.ResourceWithValidateConfig   = (*kdfParamsResource)(nil)
+)
+
+// KDF represents a key derivation function.
+type KDF string
+
+const (
+	Argon2id KDF = "argon2id"
+	Scrypt   KDF = "scrypt"
+	PBKDF2   KDF = "pbkdf2"
+)
+
+func (k KDF) String() string {
+	return string(k)
+}
+
+// supportedKDFsStr returns the key derivation functions currently supported by this provider as a slice of string.
+func supportedKDFsStr() []string {
+	return []string{
+		Argon2id.String(),
+		Scrypt.String(),
+		PBKDF2.String(),
+	}
+}
+
+// Defaults follow the OWASP password storage recommendations.
+const (
+	defaultArgon2idMemory      int64 = 65536
+	defaultArgon2idIterations  int64 = 3
+	defaultArgon2idParallelism int64 = 4
+	defaultScryptN             int64 = 32768
+	defaultScryptR             int64 = 8
+	defaultScryptP             int64 = 1
+	defaultPBKDF2Iterations    int64 = 600000
+	defaultPBKDF2HashFunction        = "SHA256"
+	defaultKDFKeyLength        int64 = 32
+)
+
+// errKDFParamsUnknown is returned when parameters cannot be determined because part of the configuration is unknown.
+var errKDFParamsUnknown = errors.New("key derivation parameters are not known")
+
+// kdfParamsComputedAttributes are unknown in the plan whenever anything changes.
+var kdfParamsComputedAttributes = []string{
+	"version",
+	"json",
+}
+
+func NewKDFParamsResource() resource.Resource {
+	return &kdfParamsResource{}
+}
+
+type kdfParamsModelV0 struct {
+	secretMetadataModel
+
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	KDF             types.String `tfsdk:"kdf"`
+	SaltLength      types.Int64  `tfsdk:"salt_length"`
+	RotateSalt      types.Bool   `tfsdk:"rotate_salt"`
+	Argon2id        types.Object `tfsdk:"argon2id"`
+	Scrypt          types.Object `tfsdk:"scrypt"`
+	PBKDF2          types.Object `tfsdk:"pbkdf2"`
+	JSON            types.String `tfsdk:"json"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+type kdfArgon2idModel struct {
+	Memory      types.Int64 `tfsdk:"memory"`
+	Iterations  types.Int64 `tfsdk:"iterations"`
+	Parallelism types.Int64 `tfsdk:"parallelism"`
+	KeyLength   types.Int64 `tfsdk:"key_length"`
+}
+
+type kdfScryptModel struct {
+	N         types.Int64 `tfsdk:"n"`
+	R         types.Int64 `tfsdk:"r"`
+	P         types.Int64 `tfsdk:"p"`
+	KeyLength types.Int64 `tfsdk:"key_length"`
+}
+
+type kdfPBKDF2Model struct {
+	Iterations   types.Int64  `tfsdk:"iterations"`
+	HashFunction types.String `tfsdk:"hash_function"`
+	KeyLength    types.Int64  `tfsdk:"key_length"`
+}
+
+var kdfArgon2idAttrTypes = map[string]attr.Type{
+	"memory":      types.Int64Type,
+	"iterations":  types.Int64Type,
+	"parallelism": types.Int64Type,
+	"key_length":  types.Int64Type,
+}
+
+var kdfScryptAttrTypes = map[string]attr.Type{
+	"n":          types.Int64Type,
+	"r":          types.Int64Type,
+	"p":          types.Int64Type,
+	"key_length": types.Int64Type,
+}
+
+var kdfPBKDF2AttrTypes = map[string]attr.Type{
+	"iterations":    types.Int64Type,
+	"hash_function": types.StringType,
+	"key_length":    types.Int64Type,
+}
+
+// kdfAttributes maps each nested attribute to the key derivation function it holds the parameters of.
+var kdfAttributes = map[string]KDF{
+	"argon2id": Argon2id,
+	"scrypt":   Scrypt,
+	"pbkdf2":   PBKDF2,
+}
+
+// kdfParamsDocument is the canonical JSON document stored in the vault. Fields are ordered alphabetically
+// and params is a map, so that encoding/json produces sorted keys without insignificant whitespace.
+type kdfParamsDocument struct {
+	KDF    string         `json:"kdf"`
+	Params map[string]any `json:"params"`
+	Salt   string         `json:"salt"`
+}
+
+type kdfParamsResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *kdfParamsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *kdfParamsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kdf_params"
+}
+
+func (r *kdfParamsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keyLengthAttribute := schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Default:     int64default.StaticInt64(defaultKDFKeyLength),
+		Description: fmt.Sprintf("The length of the derived key, in bytes. Default value is `%d`.", defaultKDFKeyLength),
+		Validators: []validator.Int64{
+			int64validator.Between(16, 64),
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_kdf_params` generates a random salt and combines it with the parameters " +
+			"of a key derivation function, for applications deriving keys at runtime.\n" +
+			"\n" +
+			"The salt and parameters are stored in a azrandom vault as canonical JSON of the form " +
+			"`{\"kdf\":\"argon2id\",\"params\":{...},\"salt\":\"<base64>\"}`. Since salts and parameters are not " +
+			"confidential, the same document is also exposed in the `json` attribute.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeJSON),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger generation of a new salt. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"kdf": schema.StringAttribute{
+				Description: "The key derivation function. " +
+					fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(supportedKDFsStr(), "`, `")) +
+					"Its parameters can be supplied in the nested attribute of the same name.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedKDFsStr()...),
+				},
+			},
+			"salt_length": schema.Int64Attribute{
+				Description: "The length of the generated salt, in bytes. Changing this generates a new salt. " +
+					"Default value is `16`.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(16),
+				Validators: []validator.Int64{
+					int64validator.Between(16, 64),
+				},
+			},
+			"rotate_salt": schema.BoolAttribute{
+				Description: "Generate a new salt whenever the key derivation function or its parameters change. " +
+					"By default the salt is kept, and only changes when `keepers` or `salt_length` change. " +
+					"Default value is `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"argon2id": schema.SingleNestedAttribute{
+				Description: "Parameters used when `kdf` is `argon2id`. May only be set when `kdf` is `argon2id`.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"memory": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultArgon2idMemory),
+						Description: fmt.Sprintf("The amount of memory to use, in KiB. Default value is `%d`.", defaultArgon2idMemory),
+						Validators: []validator.Int64{
+							int64validator.Between(8192, 4194304),
+						},
+					},
+					"iterations": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultArgon2idIterations),
+						Description: fmt.Sprintf("The number of passes over the memory. Default value is `%d`.", defaultArgon2idIterations),
+						Validators: []validator.Int64{
+							int64validator.Between(1, 100),
+						},
+					},
+					"parallelism": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultArgon2idParallelism),
+						Description: fmt.Sprintf("The number of threads to use. Default value is `%d`.", defaultArgon2idParallelism),
+						Validators: []validator.Int64{
+							int64validator.Between(1, 255),
+						},
+					},
+					"key_length": keyLengthAttribute,
+				},
+			},
+			"scrypt": schema.SingleNestedAttribute{
+				Description: "Parameters used when `kdf` is `scrypt`. May only be set when `kdf` is `scrypt`.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"n": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultScryptN),
+						Description: fmt.Sprintf("The CPU/memory cost parameter, a power of two. Default value is `%d`.", defaultScryptN),
+						Validators: []validator.Int64{
+							int64validator.Between(16384, 16777216),
+							validators.PowerOfTwo(),
+						},
+					},
+					"r": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultScryptR),
+						Description: fmt.Sprintf("The block size parameter. Default value is `%d`.", defaultScryptR),
+						Validators: []validator.Int64{
+							int64validator.Between(1, 64),
+						},
+					},
+					"p": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultScryptP),
+						Description: fmt.Sprintf("The parallelization parameter. Default value is `%d`.", defaultScryptP),
+						Validators: []validator.Int64{
+							int64validator.Between(1, 64),
+						},
+					},
+					"key_length": keyLengthAttribute,
+				},
+			},
+			"pbkdf2": schema.SingleNestedAttribute{
+				Description: "Parameters used when `kdf` is `pbkdf2`. May only be set when `kdf` is `pbkdf2`.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"iterations": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(defaultPBKDF2Iterations),
+						Description: fmt.Sprintf("The number of iterations. Default value is `%d`.", defaultPBKDF2Iterations),
+						Validators: []validator.Int64{
+							int64validator.Between(100000, 10000000),
+						},
+					},
+					"hash_function": schema.StringAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     stringdefault.StaticString(defaultPBKDF2HashFunction),
+						Description: fmt.Sprintf("The hash function used by the HMAC, either `SHA256` or `SHA512`. Default value is `%s`.", defaultPBKDF2HashFunction),
+						Validators: []validator.String{
+							stringvalidator.OneOf("SHA256", "SHA512"),
+						},
+					},
+					"key_length": keyLengthAttribute,
+				},
+			},
+			"json": schema.StringAttribute{
+				Description: "The canonical JSON document holding the key derivation function, its parameters and the " +
+					"base64 encoded salt, as stored in the vault.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *kdfParamsResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+func (r *kdfParamsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("argon2id"),
+			path.MatchRoot("scrypt"),
+			path.MatchRoot("pbkdf2"),
+		),
+	}
+}
+
+// ValidateConfig ensures that parameters are only supplied for the selected key derivation function.
+func (r *kdfParamsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var kdf types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("kdf"), &kdf)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delay validation until the kdf is known
+	if kdf.IsUnknown() || kdf.IsNull() {
+		return
+	}
+
+	for attributeName, attributeKDF := range kdfAttributes {
+		var params types.Object
+		diags := req.Config.GetAttribute(ctx, path.Root(attributeName), &params)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			continue
+		}
+
+		if params.IsNull() || KDF(kdf.ValueString()) == attributeKDF {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attributeName),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attribute %q may only be set when \"kdf\" is %q, but \"kdf\" is %q.",
+				attributeName, attributeKDF.String(), kdf.ValueString()),
+		)
+	}
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+// When the salt is kept, the resulting document is known at plan time and planned as the json attribute.
+func (r *kdfParamsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_kdf_params", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state kdfParamsModelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(kdfParamsComputedAttributes, "rotate_salt")...)
+	resp.Diagnostics.Append(diags...)
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	changed = changed || regenerate
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only metadata changed, the stored document is kept
+	if !changed {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("json"), state.JSON)...)
+		return
+	}
+
+	if kdfParamsRotateSalt(plan, state) {
+		return
+	}
+
+	var previous kdfParamsDocument
+	if err := json.Unmarshal([]byte(state.JSON.ValueString()), &previous); err != nil {
+		return
+	}
+
+	document, err := kdfParamsFromModel(ctx, plan, previous.Salt)
+	if err != nil {
+		// Parameters are not known yet, json stays unknown
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("json"), document)...)
+}
+
+func (r *kdfParamsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan kdfParamsModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	salt, err := createSalt(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	document, err := kdfParamsFromModel(ctx, plan, salt)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_kdf_params error",
+			"Could not encode azrandom_kdf_params, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_kdf_params", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, document, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_kdf_params", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, document)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.JSON = types.StringValue(document)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *kdfParamsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state kdfParamsModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_kdf_params", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	state.Version, _ = readDrift(ctx, req, resp, state.Version, version)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *kdfParamsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state kdfParamsModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, append(kdfParamsComputedAttributes, "rotate_salt")...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_kdf_params", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.JSON = state.JSON
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// The plan only leaves json unknown when a new salt should be generated
+	document := plan.JSON.ValueString()
+	if plan.JSON.IsUnknown() {
+		salt, err := createSalt(plan)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+
+		document, err = kdfParamsFromModel(ctx, plan, salt)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Update azrandom_kdf_params error",
+				"Could not encode azrandom_kdf_params, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, document, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_kdf_params", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, document)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.JSON = types.StringValue(document)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *kdfParamsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state kdfParamsModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_kdf_params", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *kdfParamsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_kdf_params", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_kdf_params", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	state, err := kdfParamsModelFromDocument(value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_kdf_params error",
+			"The secret "+id+" does not contain a supported key derivation parameters document: "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(id)
+	state.VaultUrl = vaultUrl
+	state.Version = types.StringValue(version)
+	state.Keepers = types.MapNull(types.StringType)
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// kdfParamsRotateSalt reports whether an update of an existing resource should generate a new salt.
+func kdfParamsRotateSalt(plan kdfParamsModelV0, state kdfParamsModelV0) bool {
+	return plan.RotateSalt.IsUnknown() || plan.RotateSalt.ValueBool() ||
+		!plan.Keepers.Equal(state.Keepers) ||
+		!plan.SaltLength.Equal(state.SaltLength)
+}
+
+func createSalt(plan kdfParamsModelV0) (string, error) {
+	salt, err := random.CreateBytes(plan.SaltLength.ValueInt64())
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(salt), nil
+}
+
+// kdfParamsFromModel returns the canonical JSON document for the configured key derivation function and the
+// given salt. Parameters that were not configured take their default values.
+func kdfParamsFromModel(ctx context.Context, model kdfParamsModelV0, salt string) (string, error) {
+	if model.KDF.IsUnknown() {
+		return "", errKDFParamsUnknown
+	}
+
+	var values map[string]attr.Value
+	kdf := KDF(model.KDF.ValueString())
+
+	switch kdf {
+	case Argon2id:
+		params := kdfArgon2idModel{
+			Memory:      types.Int64Value(defaultArgon2idMemory),
+			Iterations:  types.Int64Value(defaultArgon2idIterations),
+			Parallelism: types.Int64Value(defaultArgon2idParallelism),
+			KeyLength:   types.Int64Value(defaultKDFKeyLength),
+		}
+		if err := objectAs(ctx, model.Argon2id, &params); err != nil {
+			return "", errKDFParamsUnknown
+		}
+		values = map[string]attr.Value{
+			"memory":      params.Memory,
+			"iterations":  params.Iterations,
+			"parallelism": params.Parallelism,
+			"key_length":  params.KeyLength,
+		}
+	case Scrypt:
+		params := kdfScryptModel{
+			N:         types.Int64Value(defaultScryptN),
+			R:         types.Int64Value(defaultScryptR),
+			P:         types.Int64Value(defaultScryptP),
+			KeyLength: types.Int64Value(defaultKDFKeyLength),
+		}
+		if err := objectAs(ctx, model.Scrypt, &params); err != nil {
+			return "", errKDFParamsUnknown
+		}
+		values = map[string]attr.Value{
+			"n":          params.N,
+			"r":          params.R,
+			"p":          params.P,
+			"key_length": params.KeyLength,
+		}
+	case PBKDF2:
+		params := kdfPBKDF2Model{
+			Iterations:   types.Int64Value(defaultPBKDF2Iterations),
+			HashFunction: types.StringValue(defaultPBKDF2HashFunction),
+			KeyLength:    types.Int64Value(defaultKDFKeyLength),
+		}
+		if err := objectAs(ctx, model.PBKDF2, &params); err != nil {
+			return "", errKDFParamsUnknown
+		}
+		values = map[string]attr.Value{
+			"iterations":    params.Iterations,
+			"hash_function": params.HashFunction,
+			"key_length":    params.KeyLength,
+		}
+	default:
+		return "", fmt.Errorf("unsupported key derivation function %q", kdf)
+	}
+
+	document := kdfParamsDocument{
+		KDF:    kdf.String(),
+		Params: map[string]any{},
+		Salt:   salt,
+	}
+	for name, value := range values {
+		switch v := value.(type) {
+		case types.Int64:
+			if v.IsUnknown() {
+				return "", errKDFParamsUnknown
+			}
+			document.Params[name] = v.ValueInt64()
+		case types.String:
+			if v.IsUnknown() {
+				return "", errKDFParamsUnknown
+			}
+			document.Params[name] = v.ValueString()
+		}
+	}
+
+	result, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// kdfParamsModelFromDocument populates the kdf, salt_length and parameters from a stored document.
+func kdfParamsModelFromDocument(value string) (kdfParamsModelV0, error) {
+	model := kdfParamsModelV0{
+		RotateSalt: types.BoolValue(false),
+		Argon2id:   types.ObjectNull(kdfArgon2idAttrTypes),
+		Scrypt:     types.ObjectNull(kdfScryptAttrTypes),
+		PBKDF2:     types.ObjectNull(kdfPBKDF2AttrTypes),
+		JSON:       types.StringValue(value),
+	}
+
+	var document struct {
+		KDF    string                     `json:"kdf"`
+		Params map[string]json.RawMessage `json:"params"`
+		Salt   string                     `json:"salt"`
+	}
+	if err := json.Unmarshal([]byte(value), &document); err != nil {
+		return model, err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(document.Salt)
+	if err != nil {
+		return model, fmt.Errorf("salt is not base64 encoded: %w", err)
+	}
+	model.KDF = types.StringValue(document.KDF)
+	model.SaltLength = types.Int64Value(int64(len(salt)))
+
+	var attrTypes map[string]attr.Type
+	switch KDF(document.KDF) {
+	case Argon2id:
+		attrTypes = kdfArgon2idAttrTypes
+	case Scrypt:
+		attrTypes = kdfScryptAttrTypes
+	case PBKDF2:
+		attrTypes = kdfPBKDF2AttrTypes
+	default:
+		return model, fmt.Errorf("unsupported key derivation function %q", document.KDF)
+	}
+
+	values := map[string]attr.Value{}
+	for name, attrType := range attrTypes {
+		raw, ok := document.Params[name]
+		if !ok {
+			return model, fmt.Errorf("missing parameter %q", name)
+		}
+
+		if attrType == types.StringType {
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return model, fmt.Errorf("invalid parameter %q: %w", name, err)
+			}
+			values[name] = types.StringValue(v)
+		} else {
+			var v int64
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return model, fmt.Errorf("invalid parameter %q: %w", name, err)
+			}
+			values[name] = types.Int64Value(v)
+		}
+	}
+
+	params, diags := types.ObjectValue(attrTypes, values)
+	if diags.HasError() {
+		return model, fmt.Errorf("invalid parameters: %v", diags)
+	}
+
+	switch KDF(document.KDF) {
+	case Argon2id:
+		model.Argon2id = params
+	case Scrypt:
+		model.Scrypt = params
+	case PBKDF2:
+		model.PBKDF2 = params
+	}
+
+	return model, nil
+}
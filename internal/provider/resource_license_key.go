@@ -0,0 +1,487 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+var (
+	_ resource.Resource                = (*licenseKeyResource)(nil)
+	_ resource.ResourceWithIdentity    = (*licenseKeyResource)(nil)
+	_ resource.ResourceWithImportState = (*licenseKeyResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*licenseKeyResource)(nil)
+)
+
+const (
+	// crockfordBase32Alphabet omits I, L, O and U to avoid ambiguity when keys are read out or typed.
+	crockfordBase32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	licenseKeySeparator = "-"
+
+	// alphabetTagName is the secret tag under which a custom alphabet is stored, so that the key can be imported.
+	alphabetTagName = "azrandom:alphabet"
+)
+
+// licenseKeyComputedAttributes are unknown in the plan whenever anything changes.
+var licenseKeyComputedAttributes = []string{
+	"version",
+	"key_id",
+}
+
+func NewLicenseKeyResource() resource.Resource {
+	return &licenseKeyResource{}
+}
+
+type licenseKeyModelV0 struct {
+	secretMetadataModel
+
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	Groups          types.Int64  `tfsdk:"groups"`
+	GroupLength     types.Int64  `tfsdk:"group_length"`
+	Alphabet        types.String `tfsdk:"alphabet"`
+	Checksum        types.Bool   `tfsdk:"checksum"`
+	KeyID           types.String `tfsdk:"key_id"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+type licenseKeyResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *licenseKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *licenseKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_license_key"
+}
+
+func (r *licenseKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_license_key` generates a license-style key such as `AB7D-93KF-Q2MT-8XLP` " +
+			"and stores it in a azrandom vault.\n" +
+			"\n" +
+			"When `checksum` is enabled, the last character of the key is a Luhn mod N check character, computed over " +
+			"the preceding characters of the key without separators, where N is the length of `alphabet` and the code " +
+			"point of a character is its position in `alphabet`.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of resource. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"groups": schema.Int64Attribute{
+				Description: "The number of groups, separated by `-`. Default value is `4`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(4),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 16),
+				},
+			},
+			"group_length": schema.Int64Attribute{
+				Description: "The number of characters in each group. Default value is `4`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(4),
+				Validators: []validator.Int64{
+					int64validator.Between(2, 32),
+				},
+			},
+			"alphabet": schema.StringAttribute{
+				Description: "The characters to choose from. Must consist of unique printable ASCII characters other " +
+					"than `-`. Default value is the Crockford base32 alphabet `" + crockfordBase32Alphabet + "`, which " +
+					"omits ambiguous characters.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(crockfordBase32Alphabet),
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 94),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[!-,.-~]+$`), "must only contain printable ASCII characters other than space and \"-\""),
+					validators.UniqueCharacters(),
+				},
+			},
+			"checksum": schema.BoolAttribute{
+				Description: "Replace the last character of the key with a Luhn mod N check character. " +
+					"Default value is `true`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"key_id": schema.StringAttribute{
+				Description: "The first group of the key, which is not secret and can be used for correlation.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *licenseKeyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *licenseKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_license_key", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *licenseKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan licenseKeyModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	key, err := createLicenseKey(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_license_key", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, key, licenseKeySecretProperties(r.providerData, plan))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_license_key", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, key)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.KeyID = types.StringValue(strings.Split(key, licenseKeySeparator)[0])
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *licenseKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state licenseKeyModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_license_key", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	state.Version, _ = readDrift(ctx, req, resp, state.Version, version)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *licenseKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state licenseKeyModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, licenseKeyComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_license_key", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.KeyID = state.KeyID
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	key, err := createLicenseKey(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, key, licenseKeySecretProperties(r.providerData, plan))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_license_key", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, key)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.KeyID = types.StringValue(strings.Split(key, licenseKeySeparator)[0])
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *licenseKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state licenseKeyModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_license_key", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *licenseKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_license_key", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	key, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_license_key", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	alphabet, ok := properties.Tags[alphabetTagName]
+	if !ok {
+		alphabet = crockfordBase32Alphabet
+	}
+
+	// The format is inferred from the key, which must consist of groups of equal length
+	groups := strings.Split(key, licenseKeySeparator)
+	for _, group := range groups {
+		if len(group) != len(groups[0]) || len(group) < 2 || strings.Trim(group, alphabet) != "" {
+			resp.Diagnostics.AddError(
+				"Import azrandom_license_key error",
+				"The secret "+id+" does not contain a license key consisting of groups of equal length, "+
+					"using the alphabet "+alphabet,
+			)
+			return
+		}
+	}
+
+	state := licenseKeyModelV0{
+		Name:        types.StringValue(id),
+		VaultUrl:    vaultUrl,
+		Version:     types.StringValue(version),
+		Keepers:     types.MapNull(types.StringType),
+		Groups:      types.Int64Value(int64(len(groups))),
+		GroupLength: types.Int64Value(int64(len(groups[0]))),
+		Alphabet:    types.StringValue(alphabet),
+		Checksum:    types.BoolValue(random.ValidLuhnModN(strings.Join(groups, ""), alphabet)),
+		KeyID:       types.StringValue(groups[0]),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// createLicenseKey returns a new key, grouped and with a check character as configured in plan.
+func createLicenseKey(plan licenseKeyModelV0) (string, error) {
+	alphabet := plan.Alphabet.ValueString()
+	length := plan.Groups.ValueInt64() * plan.GroupLength.ValueInt64()
+
+	if plan.Checksum.ValueBool() {
+		length--
+	}
+
+	characters, err := random.CreateFromCharset(alphabet, length)
+	if err != nil {
+		return "", err
+	}
+
+	key := string(characters)
+	if plan.Checksum.ValueBool() {
+		check, err := random.LuhnModNCheckCharacter(key, alphabet)
+		if err != nil {
+			return "", err
+		}
+		key += string(check)
+	}
+
+	groupLength := int(plan.GroupLength.ValueInt64())
+	groups := make([]string, 0, plan.Groups.ValueInt64())
+	for i := 0; i < len(key); i += groupLength {
+		groups = append(groups, key[i:i+groupLength])
+	}
+
+	return strings.Join(groups, licenseKeySeparator), nil
+}
+
+func licenseKeySecretProperties(data *azrandomProviderData, plan licenseKeyModelV0) azrandom.SecretProperties {
+	properties := secretProperties(plan.metadata(data), nil)
+	if plan.Alphabet.ValueString() != crockfordBase32Alphabet {
+		properties.Tags[alphabetTagName] = plan.Alphabet.ValueString()
+	}
+	return properties
+}
@@ -0,0 +1,625 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+)
+
+var (
+	_ resource.Resource                = (*oauthClientResource)(nil)
+	_ resource.ResourceWithIdentity    = (*oauthClientResource)(nil)
+	_ resource.ResourceWithImportState = (*oauthClientResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*oauthClientResource)(nil)
+)
+
+// clientIDTagName is the secret tag under which the (non-secret) client_id is stored, so that it
+// can be refreshed and imported alongside the client_secret.
+const clientIDTagName = "azrandom:client_id"
+
+// ClientIDFormat represents the ways in which a client_id can be generated.
+type ClientIDFormat string
+
+const (
+	ClientIDFormatUUID   ClientIDFormat = "uuid"
+	ClientIDFormatRandom ClientIDFormat = "random"
+)
+
+func (f ClientIDFormat) String() string {
+	return string(f)
+}
+
+// supportedClientIDFormatsStr returns the client_id formats currently supported by this provider as a slice of string.
+func supportedClientIDFormatsStr() []string {
+	return []string{
+		ClientIDFormatUUID.String(),
+		ClientIDFormatRandom.String(),
+	}
+}
+
+// oauthClientComputedAttributes are unknown in the plan whenever anything changes.
+var oauthClientComputedAttributes = []string{
+	"version",
+	"client_id",
+	"client_secret_basic_version",
+}
+
+func NewOAuthClientResource() resource.Resource {
+	return &oauthClientResource{}
+}
+
+type oauthClientModelV0 struct {
+	secretMetadataModel
+
+	Name                     types.String `tfsdk:"name"`
+	VaultUrl                 types.String `tfsdk:"vault_url"`
+	Version                  types.String `tfsdk:"version"`
+	Keepers                  types.Map    `tfsdk:"keepers"`
+	VerifyWrite              types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy           types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion          types.Bool   `tfsdk:"wait_for_deletion"`
+	ClientIDFormat           types.String `tfsdk:"client_id_format"`
+	ClientIDPrefix           types.String `tfsdk:"client_id_prefix"`
+	RotateClientID           types.Bool   `tfsdk:"rotate_client_id"`
+	SecretLength             types.Int64  `tfsdk:"secret_length"`
+	ClientSecretBasicName    types.String `tfsdk:"client_secret_basic_name"`
+	ClientID                 types.String `tfsdk:"client_id"`
+	ClientSecretBasicVersion types.String `tfsdk:"client_secret_basic_version"`
+	CreatedOn                types.String `tfsdk:"created_on"`
+	UpdatedOn                types.String `tfsdk:"updated_on"`
+}
+
+type oauthClientResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *oauthClientResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *oauthClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_oauth_client"
+}
+
+func (r *oauthClientResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_oauth_client` generates a client identifier and a high-entropy client " +
+			"secret for provisioning OAuth/OIDC clients.\n" +
+			"\n" +
+			"The `client_id` is not secret and is exposed as an attribute. The client secret is stored in a azrandom " +
+			"vault. Changing `keepers` only rotates the client secret, unless `rotate_client_id` is set.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated client secret should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated client secret was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger rotation of the client secret. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"client_id_format": schema.StringAttribute{
+				Description: "How the client identifier is generated. `uuid` generates a random UUID, `random` generates " +
+					"a random string of 32 lowercase alphanumeric characters. Changing this generates a new client identifier. " +
+					"Default value is `uuid`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(ClientIDFormatUUID.String()),
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedClientIDFormatsStr()...),
+				},
+			},
+			"client_id_prefix": schema.StringAttribute{
+				Description: "A prefix to prepend to the generated client identifier. Changing this generates a new " +
+					"client identifier.",
+				Optional: true,
+			},
+			"rotate_client_id": schema.BoolAttribute{
+				Description: "Generate a new client identifier whenever the client secret is rotated. Default value is `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"secret_length": schema.Int64Attribute{
+				Description: "The length of the generated client secret. The secret consists of upper and lowercase " +
+					"alphabet and numeric characters. Default value is `48`.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(48),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(32),
+				},
+			},
+			"client_secret_basic_name": schema.StringAttribute{
+				Description: "The name of an additional secret in which the base64 encoding of `<client_id>:<client_secret>` " +
+					"is stored, for systems that expect a pre-encoded HTTP Basic authorization header. The value is derived " +
+					"when the client secret is generated, so setting or changing this rotates the client secret.",
+				Optional: true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "The generated client identifier.",
+				Computed:    true,
+			},
+			"client_secret_basic_version": schema.StringAttribute{
+				Description: "The version of the secret named by `client_secret_basic_name` under which the encoded " +
+					"credentials were stored",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *oauthClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret,
+// and keeps the client_id unless a new one will be generated.
+func (r *oauthClientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretNames(ctx, r.providerData, "azrandom_oauth_client", req, resp, "name", "client_secret_basic_name")
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state oauthClientModelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rotating, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(oauthClientComputedAttributes, "rotate_client_id")...)
+	resp.Diagnostics.Append(diags...)
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	rotating = rotating || regenerate
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newClientID := rotating && !plan.RotateClientID.IsUnknown() && plan.RotateClientID.ValueBool()
+	newClientID = newClientID || !plan.ClientIDFormat.Equal(state.ClientIDFormat) || !plan.ClientIDPrefix.Equal(state.ClientIDPrefix)
+
+	if !newClientID {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("client_id"), state.ClientID)...)
+	}
+}
+
+func (r *oauthClientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan oauthClientModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	clientID, err := createClientID(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	clientSecret, err := createClientSecret(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	// Check if secrets exist yet
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_oauth_client", plan.VaultUrl,
+		plan.Name.ValueString(), plan.Name, plan.ClientSecretBasicName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	properties := oauthClientSecretProperties(plan.metadata(r.providerData), clientID)
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, plan.Name.ValueString(), clientSecret, properties)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_oauth_client", plan.Name.ValueString(),
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.Name.ValueString(), version, clientSecret)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.ClientID = types.StringValue(clientID)
+	plan.ClientSecretBasicVersion = types.StringNull()
+
+	if !plan.ClientSecretBasicName.IsNull() {
+		basicVersion, _, err := azrandom.CreateSecret(ctx, client, plan.ClientSecretBasicName.ValueString(), clientSecretBasic(clientID, clientSecret), properties)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.CreateError("azrandom_oauth_client", plan.ClientSecretBasicName.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.ClientSecretBasicName.ValueString(), basicVersion, clientSecretBasic(clientID, clientSecret))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.ClientSecretBasicVersion = types.StringValue(basicVersion)
+	}
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *oauthClientResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state oauthClientModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_oauth_client", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+	if clientID, ok := properties.Tags[clientIDTagName]; ok {
+		state.ClientID = types.StringValue(clientID)
+	}
+
+	// If version number has changed we know that drift has occurred.
+	var drifted bool
+	state.Version, drifted = readDrift(ctx, req, resp, state.Version, version)
+
+	if !state.ClientSecretBasicName.IsNull() {
+		basicVersion, _, err := azrandom.GetSecret(ctx, client, state.ClientSecretBasicName.ValueString(), "")
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ReadError("azrandom_oauth_client", state.ClientSecretBasicName.ValueString(),
+				resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+			return
+		}
+
+		if !drifted && state.ClientSecretBasicVersion.ValueString() != basicVersion {
+			resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateDrift)...)
+		}
+		state.ClientSecretBasicVersion = types.StringValue(basicVersion)
+	}
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *oauthClientResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state oauthClientModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, append(oauthClientComputedAttributes, "rotate_client_id")...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only metadata changed, keep the current values and versions
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_oauth_client", plan.VaultUrl,
+			state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() && !state.ClientSecretBasicName.IsNull() {
+			_, diags = updateSecretMetadata(ctx, r.providerData, client, "azrandom_oauth_client", plan.VaultUrl,
+				state.ClientSecretBasicName.ValueString(), state.ClientSecretBasicVersion.ValueString(), plan.metadata(r.providerData))
+			resp.Diagnostics.Append(diags...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.ClientID = state.ClientID
+		plan.ClientSecretBasicVersion = state.ClientSecretBasicVersion
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// The plan only leaves the client_id unknown when a new one should be generated
+	clientID := plan.ClientID.ValueString()
+	if plan.ClientID.IsUnknown() {
+		var err error
+		clientID, err = createClientID(plan)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+	}
+
+	clientSecret, err := createClientSecret(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	properties := oauthClientSecretProperties(plan.metadata(r.providerData), clientID)
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, plan.Name.ValueString(), clientSecret, properties)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_oauth_client", plan.Name.ValueString(),
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, plan.Name.ValueString(), version, clientSecret)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.ClientID = types.StringValue(clientID)
+	plan.ClientSecretBasicVersion = types.StringNull()
+
+	// Remove the basic credentials when they are no longer wanted, or are now stored under another name
+	if !state.ClientSecretBasicName.IsNull() && !state.ClientSecretBasicName.Equal(plan.ClientSecretBasicName) {
+		_, err := azrandom.DeleteSecret(ctx, client, state.ClientSecretBasicName.ValueString())
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "delete the secret",
+				"azrandom_oauth_client", state.ClientSecretBasicName.ValueString(),
+				resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+	}
+
+	if !plan.ClientSecretBasicName.IsNull() {
+		basicName := plan.ClientSecretBasicName.ValueString()
+		basic := clientSecretBasic(clientID, clientSecret)
+
+		var basicVersion string
+		if state.ClientSecretBasicName.Equal(plan.ClientSecretBasicName) {
+			basicVersion, _, err = azrandom.UpdateSecret(ctx, client, basicName, basic, properties)
+		} else {
+			basicVersion, _, err = azrandom.CreateSecret(ctx, client, basicName, basic, properties)
+		}
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "create the secret",
+				"azrandom_oauth_client", basicName, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, basicName, basicVersion, basic)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.ClientSecretBasicVersion = types.StringValue(basicVersion)
+	}
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *oauthClientResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state oauthClientModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_oauth_client", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *oauthClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_oauth_client", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	clientID, ok := properties.Tags[clientIDTagName]
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Import azrandom_oauth_client error",
+			"The secret "+id+" does not have a "+clientIDTagName+" tag, so the client_id cannot be determined",
+		)
+		return
+	}
+
+	state := oauthClientModelV0{
+		Name:                     types.StringValue(id),
+		VaultUrl:                 vaultUrl,
+		Version:                  types.StringValue(version),
+		Keepers:                  types.MapNull(types.StringType),
+		ClientIDFormat:           types.StringValue(ClientIDFormatUUID.String()),
+		ClientIDPrefix:           types.StringNull(),
+		RotateClientID:           types.BoolValue(false),
+		SecretLength:             types.Int64Value(48),
+		ClientSecretBasicName:    types.StringNull(),
+		ClientID:                 types.StringValue(clientID),
+		ClientSecretBasicVersion: types.StringNull(),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func createClientID(plan oauthClientModelV0) (string, error) {
+	var clientID string
+
+	switch ClientIDFormat(plan.ClientIDFormat.ValueString()) {
+	case ClientIDFormatRandom:
+		result, err := random.CreateString(random.StringParams{
+			Length:  32,
+			Lower:   true,
+			Numeric: true,
+		})
+		if err != nil {
+			return "", err
+		}
+		clientID = string(result)
+	default:
+		result, err := uuid.GenerateUUID()
+		if err != nil {
+			return "", err
+		}
+		clientID = result
+	}
+
+	return plan.ClientIDPrefix.ValueString() + clientID, nil
+}
+
+func createClientSecret(plan oauthClientModelV0) (string, error) {
+	result, err := random.CreateString(random.StringParams{
+		Length:  plan.SecretLength.ValueInt64(),
+		Upper:   true,
+		Lower:   true,
+		Numeric: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// clientSecretBasic returns the credentials encoded as expected in an HTTP Basic authorization header.
+func clientSecretBasic(clientID string, clientSecret string) string {
+	return base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+}
+
+func oauthClientSecretProperties(metadata secretMetadata, clientID string) azrandom.SecretProperties {
+	properties := secretProperties(metadata, nil)
+	properties.Tags[clientIDTagName] = clientID
+	return properties
+}
@@ -0,0 +1,543 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+var (
+	_ resource.Resource                   = (*opaqueTokenResource)(nil)
+	_ resource.ResourceWithIdentity       = (*opaqueTokenResource)(nil)
+	_ resource.ResourceWithImportState    = (*opaqueTokenResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*opaqueTokenResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*opaqueTokenResource)(nil)
+)
+
+// The ttl and signing key are stored as secret tags, so that the token can be imported.
+const (
+	ttlTagName                  = "azrandom:ttl"
+	signingKeySecretNameTagName = "azrandom:signing_key_secret_name"
+)
+
+// opaqueTokenComputedAttributes are unknown in the plan whenever anything changes.
+var opaqueTokenComputedAttributes = []string{
+	"version",
+	"expires_at",
+}
+
+func NewOpaqueTokenResource() resource.Resource {
+	return &opaqueTokenResource{}
+}
+
+type opaqueTokenModelV0 struct {
+	secretMetadataModel
+
+	Name                 types.String `tfsdk:"name"`
+	VaultUrl             types.String `tfsdk:"vault_url"`
+	Version              types.String `tfsdk:"version"`
+	Keepers              types.Map    `tfsdk:"keepers"`
+	VerifyWrite          types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy       types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion      types.Bool   `tfsdk:"wait_for_deletion"`
+	TTL                  types.String `tfsdk:"ttl"`
+	SigningKeySecretName types.String `tfsdk:"signing_key_secret_name"`
+	PayloadLength        types.Int64  `tfsdk:"payload_length"`
+	ExpiresAt            types.String `tfsdk:"expires_at"`
+	CreatedOn            types.String `tfsdk:"created_on"`
+	UpdatedOn            types.String `tfsdk:"updated_on"`
+}
+
+type opaqueTokenResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *opaqueTokenResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *opaqueTokenResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_opaque_token"
+}
+
+func (r *opaqueTokenResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_opaque_token` generates a random payload, appends an expiry timestamp " +
+			"and signs both with HMAC-SHA256, using a key that is held in another secret of the same vault. This is " +
+			"intended for pre-shared enrollment tokens.\n" +
+			"\n" +
+			"The token has the form `payload.expiry.signature`, where each part is base64url encoded without padding. " +
+			"The expiry is the number of seconds since the Unix epoch, and the signature is computed over " +
+			"`payload.expiry` as it appears in the token. The token is stored in a azrandom vault. Once the expiry has " +
+			"passed, the next refresh plans the generation of a new token.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of resource. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ttl": schema.StringAttribute{
+				Description: "How long a generated token is valid, as a duration such as `\"720h\"`. " +
+					"Changing this generates a new token.",
+				Required: true,
+				Validators: []validator.String{
+					validators.PositiveDuration(),
+				},
+			},
+			"signing_key_secret_name": schema.StringAttribute{
+				Description: "The name of the secret, in the same vault, holding the HMAC key. The value of the " +
+					"secret is used as key as-is, for instance the value of an `azrandom_string`. Changing this " +
+					"generates a new token. Rotating the key itself does not; add its `version` to `keepers` for that.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"payload_length": schema.Int64Attribute{
+				Description: "The length of the random payload, in bytes. Default value is `32`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.Between(16, 128),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "The time at which the token expires, in RFC 3339 format.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *opaqueTokenResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ValidateConfig ensures that a token is not signed with a key stored in its own secret.
+func (r *opaqueTokenResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config opaqueTokenModelV0
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsUnknown() || config.SigningKeySecretName.IsUnknown() {
+		return
+	}
+
+	if strings.EqualFold(config.Name.ValueString(), config.SigningKeySecretName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("signing_key_secret_name"),
+			"Invalid Attribute Combination",
+			"The attribute \"signing_key_secret_name\" must refer to another secret than \"name\".",
+		)
+	}
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *opaqueTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_opaque_token", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *opaqueTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan opaqueTokenModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_opaque_token", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, expiresAt, err := r.generate(ctx, client, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_opaque_token error",
+			"Could not generate azrandom_opaque_token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, token, opaqueTokenSecretProperties(r.providerData, plan))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_opaque_token", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, token)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.ExpiresAt = types.StringValue(expiresAt.Format(time.RFC3339))
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *opaqueTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state opaqueTokenModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_opaque_token", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	var drifted bool
+	state.Version, drifted = readDrift(ctx, req, resp, state.Version, version)
+	if expiresAt, err := time.Parse(time.RFC3339, state.ExpiresAt.ValueString()); !drifted && err == nil && !time.Now().Before(expiresAt) {
+		resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateExpired)...)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *opaqueTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state opaqueTokenModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, opaqueTokenComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_opaque_token", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.ExpiresAt = state.ExpiresAt
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	token, expiresAt, err := r.generate(ctx, client, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Update azrandom_opaque_token error",
+			"Could not generate azrandom_opaque_token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, token, opaqueTokenSecretProperties(r.providerData, plan))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_opaque_token", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, token)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.ExpiresAt = types.StringValue(expiresAt.Format(time.RFC3339))
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *opaqueTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state opaqueTokenModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_opaque_token", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *opaqueTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_opaque_token", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	for _, tag := range []string{ttlTagName, signingKeySecretNameTagName} {
+		if _, ok := properties.Tags[tag]; !ok {
+			resp.Diagnostics.AddError(
+				"Import azrandom_opaque_token error",
+				"The secret "+id+" does not have a "+tag+" tag, so it was not created by azrandom_opaque_token",
+			)
+			return
+		}
+	}
+
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_opaque_token", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	payload, expiresAt, err := parseOpaqueToken(value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_opaque_token error",
+			"The secret "+id+" does not contain an opaque token: "+err.Error(),
+		)
+		return
+	}
+
+	state := opaqueTokenModelV0{
+		Name:                 types.StringValue(id),
+		VaultUrl:             vaultUrl,
+		Version:              types.StringValue(version),
+		Keepers:              types.MapNull(types.StringType),
+		TTL:                  types.StringValue(properties.Tags[ttlTagName]),
+		SigningKeySecretName: types.StringValue(properties.Tags[signingKeySecretNameTagName]),
+		PayloadLength:        types.Int64Value(int64(len(payload))),
+		ExpiresAt:            types.StringValue(expiresAt.Format(time.RFC3339)),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// generate reads the signing key from the vault of client and returns a new token together with its expiry.
+func (r *opaqueTokenResource) generate(ctx context.Context, client *azsecrets.Client, plan opaqueTokenModelV0) (string, time.Time, error) {
+	ttl, err := time.ParseDuration(plan.TTL.ValueString())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	keyName := plan.SigningKeySecretName.ValueString()
+	key, err := azrandom.GetSecretValue(ctx, client, keyName, "")
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not read signing key %s: %w", keyName, err)
+	}
+	if key == "" {
+		return "", time.Time{}, fmt.Errorf("signing key %s is empty", keyName)
+	}
+
+	payload, err := random.CreateBytes(plan.PayloadLength.ValueInt64())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not generate payload: %w", err)
+	}
+
+	// Truncated to seconds, since that is the precision of the expiry embedded in the token
+	expiresAt := time.Now().UTC().Add(ttl).Truncate(time.Second)
+
+	return createOpaqueToken(payload, expiresAt, []byte(key)), expiresAt, nil
+}
+
+// createOpaqueToken encodes the payload and expiry, and appends the HMAC-SHA256 signature over both.
+func createOpaqueToken(payload []byte, expiresAt time.Time, key []byte) string {
+	encoding := base64.RawURLEncoding
+
+	signed := encoding.EncodeToString(payload) + "." +
+		encoding.EncodeToString([]byte(strconv.FormatInt(expiresAt.Unix(), 10)))
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+
+	return signed + "." + encoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseOpaqueToken returns the payload and expiry embedded in a token. The signature is not verified.
+func parseOpaqueToken(token string) ([]byte, time.Time, error) {
+	encoding := base64.RawURLEncoding
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, time.Time{}, fmt.Errorf("expected 3 parts separated by \".\", got %d", len(parts))
+	}
+
+	payload, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	expiry, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid expiry: %w", err)
+	}
+
+	seconds, err := strconv.ParseInt(string(expiry), 10, 64)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid expiry: %w", err)
+	}
+
+	return payload, time.Unix(seconds, 0).UTC(), nil
+}
+
+func opaqueTokenSecretProperties(data *azrandomProviderData, plan opaqueTokenModelV0) azrandom.SecretProperties {
+	properties := secretProperties(plan.metadata(data), nil)
+	properties.Tags[ttlTagName] = plan.TTL.ValueString()
+	properties.Tags[signingKeySecretNameTagName] = plan.SigningKeySecretName.ValueString()
+	return properties
+}
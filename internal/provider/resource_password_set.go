@@ -0,0 +1,915 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+)
+
+var (
+	_ resource.Resource                     = (*passwordSetResource)(nil)
+	_ resource.ResourceWithImportState      = (*passwordSetResource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*passwordSetResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*passwordSetResource)(nil)
+)
+
+// OnError represents how a password set proceeds when one of its entries fails.
+type OnError string
+
+const (
+	OnErrorAbort    OnError = "abort"
+	OnErrorContinue OnError = "continue"
+)
+
+func (o OnError) String() string {
+	return string(o)
+}
+
+// secretNamePartRegex matches the characters Key Vault allows in secret names.
+var secretNamePartRegex = regexp.MustCompile(`^[0-9a-zA-Z-]+$`)
+
+func NewPasswordSetResource() resource.Resource {
+	return &passwordSetResource{}
+}
+
+type passwordSetModelV0 struct {
+	secretMetadataModel
+
+	Prefix          types.String `tfsdk:"prefix"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Names           types.Set    `tfsdk:"names"`
+	Entries         types.Map    `tfsdk:"entries"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	Length          types.Int64  `tfsdk:"length"`
+	Upper           types.Bool   `tfsdk:"upper"`
+	Lower           types.Bool   `tfsdk:"lower"`
+	Numeric         types.Bool   `tfsdk:"numeric"`
+	Special         types.Bool   `tfsdk:"special"`
+	OnError         types.String `tfsdk:"on_error"`
+	Versions        types.Map    `tfsdk:"versions"`
+	CreatedOn       types.Map    `tfsdk:"created_on"`
+	UpdatedOn       types.Map    `tfsdk:"updated_on"`
+}
+
+// passwordSetEntryModel holds the per-entry overrides. Null attributes take the value of the resource.
+type passwordSetEntryModel struct {
+	Length  types.Int64 `tfsdk:"length"`
+	Upper   types.Bool  `tfsdk:"upper"`
+	Lower   types.Bool  `tfsdk:"lower"`
+	Numeric types.Bool  `tfsdk:"numeric"`
+	Special types.Bool  `tfsdk:"special"`
+}
+
+type passwordSetResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *passwordSetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *passwordSetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_password_set"
+}
+
+func (r *passwordSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	namePartValidators := []validator.String{
+		stringvalidator.LengthBetween(1, 63),
+		stringvalidator.RegexMatches(secretNamePartRegex, "must only contain alphanumeric characters and dashes"),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_password_set` generates a password for each of a set of names, and " +
+			"stores each in its own secret named `<prefix>-<name>` in a azrandom vault.\n" +
+			"\n" +
+			"Adding a name only generates a password for that name, and removing a name only deletes its secret. " +
+			"Changing `keepers` generates new passwords for all names.\n" +
+			"\n" +
+			"When generating or deleting the secret of an entry fails, `on_error` decides what happens to the other " +
+			"entries. With `abort`, the remaining entries are left untouched and the apply fails. With `continue`, " +
+			"the remaining entries are processed and the failures are reported as warnings. In both cases the " +
+			"state only records the entries that succeeded, so the next plan retries the ones that failed. Note " +
+			"that when an apply fails while the resource is being created, Terraform plans to replace it.",
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				Description: "The prefix of the names of the secrets where the generated passwords should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: namePartValidators,
+			},
+			"names": schema.SetAttribute{
+				Description: "The names to generate a password for. Exactly one of `names` and `entries` must be set.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(namePartValidators...),
+				},
+			},
+			"entries": schema.MapNestedAttribute{
+				Description: "The names to generate a password for, mapped to settings that override those of the " +
+					"resource for that name. Exactly one of `names` and `entries` must be set.",
+				Optional: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(namePartValidators...),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"length": schema.Int64Attribute{
+							Description: "Overrides `length` for this name.",
+							Optional:    true,
+							Validators: []validator.Int64{
+								int64validator.Between(8, 256),
+							},
+						},
+						"upper": schema.BoolAttribute{
+							Description: "Overrides `upper` for this name.",
+							Optional:    true,
+						},
+						"lower": schema.BoolAttribute{
+							Description: "Overrides `lower` for this name.",
+							Optional:    true,
+						},
+						"numeric": schema.BoolAttribute{
+							Description: "Overrides `numeric` for this name.",
+							Optional:    true,
+						},
+						"special": schema.BoolAttribute{
+							Description: "Overrides `special` for this name.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger generation of new passwords for " +
+					"all names. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secrets, stored in the `azrandom:description` tag of each secret. " +
+					"Changing the description does not generate new values.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"length": schema.Int64Attribute{
+				Description: "The length of the passwords. Default value is `32`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.Between(8, 256),
+				},
+			},
+			"upper": schema.BoolAttribute{
+				Description: "Include uppercase alphabet characters in the passwords. Default value is `true`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"lower": schema.BoolAttribute{
+				Description: "Include lowercase alphabet characters in the passwords. Default value is `true`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"numeric": schema.BoolAttribute{
+				Description: "Include numeric characters in the passwords. Default value is `true`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"special": schema.BoolAttribute{
+				Description: "Include special characters in the passwords. These are `!@#$%&*()-_=+[]{}<>:?`. " +
+					"Default value is `true`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"on_error": schema.StringAttribute{
+				Description: fmt.Sprintf("What to do with the other entries when one fails, either `%s` or `%s`. "+
+					"Default value is `%s`.", OnErrorAbort, OnErrorContinue, OnErrorAbort),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(OnErrorAbort.String()),
+				Validators: []validator.String{
+					stringvalidator.OneOf(OnErrorAbort.String(), OnErrorContinue.String()),
+				},
+			},
+			"versions": schema.MapAttribute{
+				Description: "The versions of the secrets under which the generated passwords were stored, by name.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"created_on": schema.MapAttribute{
+				Description: "The times at which the secrets were created, by name, in RFC 3339 format.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"updated_on": schema.MapAttribute{
+				Description: "The times at which the secrets were last updated, by name, in RFC 3339 format.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *passwordSetResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("names"),
+			path.MatchRoot("entries"),
+		),
+	}
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in one of the
+// same secrets. On update, it plans the versions of entries that keep their password, so that only
+// the versions of new and changed entries are unknown.
+func (r *passwordSetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan passwordSetModelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	entries, known, diags := passwordSetEntries(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known || plan.Prefix.IsUnknown() {
+		return
+	}
+
+	if r.providerData != nil && r.providerData.secretNames != nil && !plan.VaultUrl.IsUnknown() {
+		vaultUrl := resourceVaultUrl(r.providerData, plan.VaultUrl)
+		for _, name := range sortedKeys(entries) {
+			claimSecretName(ctx, r.providerData, vaultUrl, "azrandom_password_set", passwordSetEntryPath(plan, name),
+				passwordSetSecretName(plan, name), resp)
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state passwordSetModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All passwords are regenerated, so all versions are unknown
+	if regenerate || plan.Keepers.IsUnknown() || !plan.Keepers.Equal(state.Keepers) {
+		return
+	}
+
+	stateEntries, _, diags := passwordSetEntries(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	stateVersions := map[string]string{}
+	resp.Diagnostics.Append(state.Versions.ElementsAs(ctx, &stateVersions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versions := map[string]attr.Value{}
+	for name, params := range entries {
+		version, ok := stateVersions[name]
+		if ok && stateEntries[name] == params {
+			versions[name] = types.StringValue(version)
+		} else {
+			versions[name] = types.StringUnknown()
+		}
+	}
+
+	plannedVersions, diags := types.MapValue(types.StringType, versions)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("versions"), plannedVersions)...)
+}
+
+func (r *passwordSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan passwordSetModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, nil, false, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *passwordSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state passwordSetModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versions := map[string]string{}
+	resp.Diagnostics.Append(state.Versions.ElementsAs(ctx, &versions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	drifted := false
+	removed := false
+	createdOn := map[string]string{}
+	updatedOn := map[string]string{}
+	for _, name := range sortedKeys(versions) {
+		secretName := passwordSetSecretName(state, name)
+
+		version, properties, err := azrandom.GetSecret(ctx, client, secretName, "")
+		if secretDeletedOutOfBand(ctx, "azrandom_password_set", secretName, err) {
+			// The next apply creates the secret of the entry again, leaving the other entries untouched
+			delete(versions, name)
+			removed = true
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ReadError("azrandom_password_set", secretName,
+				resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+			return
+		}
+
+		// Reflect properties that were changed on any of the secrets, so that they are planned back
+		resp.Diagnostics.Append(state.readMetadata(secretName, properties)...)
+
+		setPasswordSetTimestamps(name, properties, createdOn, updatedOn)
+
+		// If version number has changed we know that drift has occurred.
+		if versions[name] != version {
+			versions[name] = version
+			drifted = true
+		}
+	}
+
+	// Every secret of the set was deleted, so the next apply creates the set again
+	if removed && len(versions) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.CreatedOn, state.UpdatedOn, diags = passwordSetTimestamps(ctx, createdOn, updatedOn)
+	resp.Diagnostics.Append(diags...)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	if drifted || removed {
+		state.Versions, diags = types.MapValueFrom(ctx, types.StringType, versions)
+		resp.Diagnostics.Append(diags...)
+	}
+	if drifted {
+		resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateDrift)...)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *passwordSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state passwordSetModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read marks the passwords for regeneration, e.g. when drift was detected
+	regenerate, diags := regenerationPlanned(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &state, regenerate, &resp.Diagnostics)
+
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(clearRegeneration(ctx, resp.Private)...)
+	}
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *passwordSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state passwordSetModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versions := map[string]string{}
+	resp.Diagnostics.Append(state.Versions.ElementsAs(ctx, &versions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Entries whose secret could not be deleted remain in state, so that deleting is retried
+	remaining := map[string]string{}
+	aborted := false
+	for _, name := range sortedKeys(versions) {
+		if aborted {
+			remaining[name] = versions[name]
+			continue
+		}
+
+		secretName := passwordSetSecretName(state, name)
+		deleted, err := azrandom.DeleteSecret(ctx, client, secretName)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.DeleteError("azrandom_password_set", secretName,
+				resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+			remaining[name] = versions[name]
+			aborted = OnError(state.OnError.ValueString()) != OnErrorContinue
+			continue
+		}
+
+		if deleted {
+			resp.Diagnostics.Append(waitForDeletion(ctx, client, state.WaitForDeletion, secretName)...)
+			resp.Diagnostics.Append(purgeOnDestroy(ctx, client, state.PurgeOnDestroy, secretName)...)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		state.Versions, diags = types.MapValueFrom(ctx, types.StringType, remaining)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	}
+}
+
+// ImportState imports the secrets of a password set, given an ID of the form `<prefix>/<name>,<name>,...`.
+// The generation settings take their default values, except for length, which is inferred when all
+// passwords have the same length.
+func (r *passwordSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client, vaultUrl, id, diags := importVaultClient(ctx, r.providerData, req.ID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix, namesList, ok := strings.Cut(id, "/")
+	if !ok || prefix == "" || namesList == "" {
+		resp.Diagnostics.AddError(
+			"Import azrandom_password_set error",
+			"Expected an import ID of the form <prefix>/<name>,<name>,..., got: "+id,
+		)
+		return
+	}
+
+	state := passwordSetModelV0{
+		Prefix:   types.StringValue(prefix),
+		VaultUrl: vaultUrl,
+		Entries:  types.MapNull(types.ObjectType{AttrTypes: passwordSetEntryAttrTypes}),
+		Keepers:  types.MapNull(types.StringType),
+		Length:   types.Int64Value(32),
+		Upper:    types.BoolValue(true),
+		Lower:    types.BoolValue(true),
+		Numeric:  types.BoolValue(true),
+		Special:  types.BoolValue(true),
+		OnError:  types.StringValue(OnErrorAbort.String()),
+	}
+
+	names := strings.Split(namesList, ",")
+	versions := map[string]string{}
+	createdOn := map[string]string{}
+	updatedOn := map[string]string{}
+	lengths := map[int]bool{}
+	for _, name := range names {
+		secretName := passwordSetSecretName(state, name)
+
+		version, properties, err := azrandom.GetSecret(ctx, client, secretName, "")
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ImportError("azrandom_password_set", secretName,
+				resourceVaultUrl(r.providerData, vaultUrl), err)...)
+			return
+		}
+
+		value, err := azrandom.GetSecretValue(ctx, client, secretName, version)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.ImportError("azrandom_password_set", secretName,
+				resourceVaultUrl(r.providerData, vaultUrl), err)...)
+			return
+		}
+
+		versions[name] = version
+		setPasswordSetTimestamps(name, properties, createdOn, updatedOn)
+		lengths[len(value)] = true
+		state.secretMetadataModel = importedSecretMetadata(properties)
+	}
+
+	if len(lengths) == 1 {
+		for length := range lengths {
+			state.Length = types.Int64Value(int64(length))
+		}
+	}
+
+	state.Names, diags = types.SetValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	state.Versions, diags = types.MapValueFrom(ctx, types.StringType, versions)
+	resp.Diagnostics.Append(diags...)
+	state.CreatedOn, state.UpdatedOn, diags = passwordSetTimestamps(ctx, createdOn, updatedOn)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// apply brings the secrets of the password set in line with plan, given the prior state, which is nil
+// on create, generating new passwords for all entries when regenerate is true. It sets the versions and timestamps of the entries that are stored after applying on plan.
+// Failures are handled according to on_error.
+func (r *passwordSetResource) apply(ctx context.Context, plan *passwordSetModelV0, prior *passwordSetModelV0, regenerate bool, respDiagnostics *diag.Diagnostics) {
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	respDiagnostics.Append(diags...)
+	if respDiagnostics.HasError() {
+		return
+	}
+
+	entries, _, diags := passwordSetEntries(ctx, *plan)
+	respDiagnostics.Append(diags...)
+
+	priorEntries := map[string]random.StringParams{}
+	priorVersions := map[string]string{}
+	priorCreatedOn := map[string]string{}
+	priorUpdatedOn := map[string]string{}
+	rotateAll := false
+	metadataChanged := false
+	if prior != nil {
+		priorEntries, _, diags = passwordSetEntries(ctx, *prior)
+		respDiagnostics.Append(diags...)
+		respDiagnostics.Append(prior.Versions.ElementsAs(ctx, &priorVersions, false)...)
+		if !prior.CreatedOn.IsNull() {
+			respDiagnostics.Append(prior.CreatedOn.ElementsAs(ctx, &priorCreatedOn, false)...)
+		}
+		if !prior.UpdatedOn.IsNull() {
+			respDiagnostics.Append(prior.UpdatedOn.ElementsAs(ctx, &priorUpdatedOn, false)...)
+		}
+		rotateAll = regenerate || !plan.Keepers.Equal(prior.Keepers)
+		metadataChanged = !plan.metadata(r.providerData).Equal(prior.metadata(r.providerData))
+	}
+	if respDiagnostics.HasError() {
+		return
+	}
+
+	operation := diagnostics.OperationCreate
+	if prior != nil {
+		operation = diagnostics.OperationUpdate
+	}
+
+	versions := map[string]string{}
+	stored := map[string]azrandom.SecretProperties{}
+	aborted := false
+	fail := func(secretName string, action string, err error) {
+		failure := diagnostics.VaultError(operation, action, "azrandom_password_set", secretName,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)[0]
+		summary, detail := failure.Summary(), failure.Detail()
+
+		if OnError(plan.OnError.ValueString()) == OnErrorContinue {
+			respDiagnostics.AddWarning(summary, detail+"\n\nThe other entries were processed since on_error is \"continue\". "+
+				"This entry will be retried during the next apply.")
+			return
+		}
+
+		respDiagnostics.AddError(summary, detail+"\n\nThe remaining entries were not processed since on_error is \"abort\".")
+		aborted = true
+	}
+
+	// Delete the secrets of removed entries
+	for _, name := range sortedKeys(priorVersions) {
+		if _, ok := entries[name]; ok {
+			continue
+		}
+		if aborted {
+			versions[name] = priorVersions[name]
+			continue
+		}
+
+		secretName := passwordSetSecretName(*plan, name)
+		if _, err := azrandom.DeleteSecret(ctx, client, secretName); err != nil {
+			versions[name] = priorVersions[name]
+			fail(secretName, "delete the secret", err)
+		}
+	}
+
+	for _, name := range sortedKeys(entries) {
+		params := entries[name]
+		priorVersion, existed := priorVersions[name]
+
+		if aborted {
+			if existed {
+				versions[name] = priorVersion
+			}
+			continue
+		}
+
+		secretName := passwordSetSecretName(*plan, name)
+
+		// Keep the password, only updating its metadata when needed
+		if existed && !rotateAll && priorEntries[name] == params {
+			versions[name] = priorVersion
+			if metadataChanged {
+				properties, err := updateSecretProperties(ctx, client, secretName, priorVersion, plan.metadata(r.providerData))
+				if err != nil {
+					fail(secretName, "update the properties of the secret", err)
+					continue
+				}
+				stored[name] = properties
+			}
+			continue
+		}
+
+		result, err := random.CreateString(params)
+		if err != nil {
+			if existed {
+				versions[name] = priorVersion
+			}
+			fail(secretName, "generate the password of the secret", err)
+			continue
+		}
+
+		var version string
+		var properties azrandom.SecretProperties
+		var secretExists bool
+		if existed {
+			version, properties, err = azrandom.UpdateSecret(ctx, client, secretName, string(result), secretProperties(plan.metadata(r.providerData), nil))
+		} else if secretExists, err = secretExistsBeforeCreate(ctx, r.providerData, client, secretName); err == nil && secretExists {
+			importID := plan.Prefix.ValueString() + "/" + strings.Join(sortedKeys(entries), ",")
+			err = errors.New(existingSecretMessage(ctx, client, "azrandom_password_set", secretName, importID))
+		} else if err == nil {
+			version, properties, err = azrandom.CreateSecret(ctx, client, secretName, string(result), secretProperties(plan.metadata(r.providerData), nil))
+		}
+
+		if err != nil {
+			if existed {
+				versions[name] = priorVersion
+			}
+			fail(secretName, "store the secret", err)
+			continue
+		}
+
+		if verifyWriteEnabled(r.providerData, plan.VerifyWrite) {
+			if err := azrandom.VerifySecretValue(ctx, client, secretName, version, string(result)); err != nil {
+				if existed {
+					versions[name] = priorVersion
+				}
+				fail(secretName, "verify the value of the secret", err)
+				continue
+			}
+		}
+
+		versions[name] = version
+		stored[name] = properties
+	}
+
+	// Entries that were not written keep their prior timestamps
+	createdOn := map[string]string{}
+	updatedOn := map[string]string{}
+	for name := range versions {
+		if properties, ok := stored[name]; ok {
+			setPasswordSetTimestamps(name, properties, createdOn, updatedOn)
+			continue
+		}
+		if value, ok := priorCreatedOn[name]; ok {
+			createdOn[name] = value
+		}
+		if value, ok := priorUpdatedOn[name]; ok {
+			updatedOn[name] = value
+		}
+	}
+
+	plan.Versions, diags = types.MapValueFrom(ctx, types.StringType, versions)
+	respDiagnostics.Append(diags...)
+	plan.CreatedOn, plan.UpdatedOn, diags = passwordSetTimestamps(ctx, createdOn, updatedOn)
+	respDiagnostics.Append(diags...)
+}
+
+// setPasswordSetTimestamps records the timestamps of the secret of entry name from its properties.
+func setPasswordSetTimestamps(name string, properties azrandom.SecretProperties, createdOn map[string]string, updatedOn map[string]string) {
+	created, updated := timestampsFromProperties(properties)
+	if !created.IsNull() {
+		createdOn[name] = created.ValueString()
+	}
+	if !updated.IsNull() {
+		updatedOn[name] = updated.ValueString()
+	}
+}
+
+// passwordSetTimestamps converts the timestamps of the entries into the created_on and updated_on maps.
+func passwordSetTimestamps(ctx context.Context, createdOn map[string]string, updatedOn map[string]string) (types.Map, types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	created, d := types.MapValueFrom(ctx, types.StringType, createdOn)
+	diags.Append(d...)
+	updated, d := types.MapValueFrom(ctx, types.StringType, updatedOn)
+	diags.Append(d...)
+	return created, updated, diags
+}
+
+var passwordSetEntryAttrTypes = map[string]attr.Type{
+	"length":  types.Int64Type,
+	"upper":   types.BoolType,
+	"lower":   types.BoolType,
+	"numeric": types.BoolType,
+	"special": types.BoolType,
+}
+
+// passwordSetEntries returns the generation settings of each entry, applying the per-entry overrides to the
+// settings of the resource. known is false when the entries cannot be determined yet.
+func passwordSetEntries(ctx context.Context, model passwordSetModelV0) (map[string]random.StringParams, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	entries := map[string]random.StringParams{}
+
+	if model.Names.IsUnknown() || model.Entries.IsUnknown() || model.Length.IsUnknown() ||
+		model.Upper.IsUnknown() || model.Lower.IsUnknown() || model.Numeric.IsUnknown() || model.Special.IsUnknown() {
+		return entries, false, diags
+	}
+
+	defaults := random.StringParams{
+		Length:  model.Length.ValueInt64(),
+		Upper:   model.Upper.ValueBool(),
+		Lower:   model.Lower.ValueBool(),
+		Numeric: model.Numeric.ValueBool(),
+		Special: model.Special.ValueBool(),
+	}
+
+	if !model.Names.IsNull() {
+		for _, element := range model.Names.Elements() {
+			name, ok := element.(types.String)
+			if !ok || name.IsUnknown() {
+				return entries, false, diags
+			}
+			entries[name.ValueString()] = defaults
+		}
+	}
+
+	if !model.Entries.IsNull() {
+		overrides := map[string]passwordSetEntryModel{}
+		diags.Append(model.Entries.ElementsAs(ctx, &overrides, false)...)
+		if diags.HasError() {
+			return entries, false, diags
+		}
+
+		for name, override := range overrides {
+			params := defaults
+			for _, value := range []attr.Value{override.Length, override.Upper, override.Lower, override.Numeric, override.Special} {
+				if value.IsUnknown() {
+					return entries, false, diags
+				}
+			}
+			if !override.Length.IsNull() {
+				params.Length = override.Length.ValueInt64()
+			}
+			if !override.Upper.IsNull() {
+				params.Upper = override.Upper.ValueBool()
+			}
+			if !override.Lower.IsNull() {
+				params.Lower = override.Lower.ValueBool()
+			}
+			if !override.Numeric.IsNull() {
+				params.Numeric = override.Numeric.ValueBool()
+			}
+			if !override.Special.IsNull() {
+				params.Special = override.Special.ValueBool()
+			}
+			entries[name] = params
+		}
+	}
+
+	return entries, true, diags
+}
+
+func passwordSetSecretName(model passwordSetModelV0, name string) string {
+	return model.Prefix.ValueString() + "-" + name
+}
+
+// passwordSetEntryPath returns the path of the attribute that configures the given entry.
+func passwordSetEntryPath(model passwordSetModelV0, name string) path.Path {
+	if !model.Entries.IsNull() {
+		return path.Root("entries").AtMapKey(name)
+	}
+	return path.Root("names").AtSetValue(types.StringValue(name))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,449 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+var (
+	_ resource.Resource                   = (*secretAliasResource)(nil)
+	_ resource.ResourceWithIdentity       = (*secretAliasResource)(nil)
+	_ resource.ResourceWithImportState    = (*secretAliasResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*secretAliasResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*secretAliasResource)(nil)
+)
+
+// The source secret and the version that was copied are stored as secret tags, so that the alias can be imported.
+const (
+	sourceSecretNameTagName = "azrandom:source_secret_name"
+	sourceVersionTagName    = "azrandom:source_version"
+)
+
+// secretAliasComputedAttributes are unknown in the plan whenever anything changes.
+var secretAliasComputedAttributes = []string{
+	"version",
+	"source_version",
+}
+
+func NewSecretAliasResource() resource.Resource {
+	return &secretAliasResource{}
+}
+
+type secretAliasModelV0 struct {
+	secretMetadataModel
+
+	Name             types.String `tfsdk:"name"`
+	VaultUrl         types.String `tfsdk:"vault_url"`
+	Version          types.String `tfsdk:"version"`
+	Keepers          types.Map    `tfsdk:"keepers"`
+	VerifyWrite      types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy   types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion  types.Bool   `tfsdk:"wait_for_deletion"`
+	SourceSecretName types.String `tfsdk:"source_secret_name"`
+	SourceVersion    types.String `tfsdk:"source_version"`
+	CreatedOn        types.String `tfsdk:"created_on"`
+	UpdatedOn        types.String `tfsdk:"updated_on"`
+}
+
+type secretAliasResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *secretAliasResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *secretAliasResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_alias"
+}
+
+func (r *secretAliasResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_secret_alias` keeps a secret with a stable name whose value equals the " +
+			"current value of another secret in the same vault, typically one managed by another azrandom resource. " +
+			"Consumers can reference the alias while the source is rotated or replaced.\n" +
+			"\n" +
+			"The value is copied on create and update. When a refresh finds that the source has a newer version than " +
+			"the one last copied, an update is planned to copy it again. To copy a version of the source in the same " +
+			"apply in which it is created, add the `version` of the source resource to `keepers`. The value is never " +
+			"stored in state.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the copied value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the copied value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not copy the value again.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger the value to be copied again. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"source_secret_name": schema.StringAttribute{
+				Description: "The name of the secret, in the same vault, whose current value is copied. Changing " +
+					"this copies the value of the new source.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"source_version": schema.StringAttribute{
+				Description: "The version of the source secret that was last copied.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *secretAliasResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ValidateConfig ensures that an alias does not refer to itself.
+func (r *secretAliasResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config secretAliasModelV0
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsUnknown() || config.SourceSecretName.IsUnknown() {
+		return
+	}
+
+	if strings.EqualFold(config.Name.ValueString(), config.SourceSecretName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_secret_name"),
+			"Invalid Attribute Combination",
+			"The attribute \"source_secret_name\" must refer to another secret than \"name\".",
+		)
+	}
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *secretAliasResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_secret_alias", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *secretAliasResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan secretAliasModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_secret_alias", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, sourceVersion, err := r.readSource(ctx, client, plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "read the source secret",
+			"azrandom_secret_alias", plan.SourceSecretName.ValueString(), resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, value, secretAliasSecretProperties(r.providerData, plan, sourceVersion))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_secret_alias", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, value)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.SourceVersion = types.StringValue(sourceVersion)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *secretAliasResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state secretAliasModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_secret_alias", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	sourceVersion, _, err := azrandom.GetSecret(ctx, client, state.SourceSecretName.ValueString(), "")
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_secret_alias", state.SourceSecretName.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred. If the version of the source has
+	// changed, the alias is out of sync.
+	var drifted bool
+	state.Version, drifted = readDrift(ctx, req, resp, state.Version, version)
+	if !drifted && state.SourceVersion.ValueString() != sourceVersion {
+		resp.Diagnostics.Append(markForRegeneration(ctx, resp.Private, regenerateSourceChanged)...)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *secretAliasResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state secretAliasModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, secretAliasComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_secret_alias", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.SourceVersion = state.SourceVersion
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	value, sourceVersion, err := r.readSource(ctx, client, plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the source secret",
+			"azrandom_secret_alias", plan.SourceSecretName.ValueString(), resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, value, secretAliasSecretProperties(r.providerData, plan, sourceVersion))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_secret_alias", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, value)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.SourceVersion = types.StringValue(sourceVersion)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *secretAliasResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state secretAliasModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_secret_alias", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *secretAliasResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_secret_alias", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	sourceSecretName, ok := properties.Tags[sourceSecretNameTagName]
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Import azrandom_secret_alias error",
+			"The secret "+id+" does not have a "+sourceSecretNameTagName+" tag, so the source secret cannot be determined",
+		)
+		return
+	}
+
+	state := secretAliasModelV0{
+		Name:             types.StringValue(id),
+		VaultUrl:         vaultUrl,
+		Version:          types.StringValue(version),
+		Keepers:          types.MapNull(types.StringType),
+		SourceSecretName: types.StringValue(sourceSecretName),
+		SourceVersion:    types.StringValue(properties.Tags[sourceVersionTagName]),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// readSource returns the current value and version of the source secret, which is in the vault of client.
+func (r *secretAliasResource) readSource(ctx context.Context, client *azsecrets.Client, plan secretAliasModelV0) (string, string, error) {
+	sourceSecretName := plan.SourceSecretName.ValueString()
+
+	sourceVersion, _, err := azrandom.GetSecret(ctx, client, sourceSecretName, "")
+	if err != nil {
+		return "", "", err
+	}
+
+	value, err := azrandom.GetSecretValue(ctx, client, sourceSecretName, sourceVersion)
+	if err != nil {
+		return "", "", err
+	}
+
+	return value, sourceVersion, nil
+}
+
+func secretAliasSecretProperties(data *azrandomProviderData, plan secretAliasModelV0, sourceVersion string) azrandom.SecretProperties {
+	properties := secretProperties(plan.metadata(data), nil)
+	properties.Tags[sourceSecretNameTagName] = plan.SourceSecretName.ValueString()
+	properties.Tags[sourceVersionTagName] = sourceVersion
+	return properties
+}
@@ -0,0 +1,528 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+)
+
+var (
+	_ resource.Resource                = (*signingSecretResource)(nil)
+	_ resource.ResourceWithIdentity    = (*signingSecretResource)(nil)
+	_ resource.ResourceWithImportState = (*signingSecretResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*signingSecretResource)(nil)
+)
+
+// signingSecretComputedAttributes are unknown in the plan whenever anything changes.
+var signingSecretComputedAttributes = []string{
+	"version",
+	"rotated_at",
+	"current_key_id",
+	"previous_key_id",
+}
+
+func NewSigningSecretResource() resource.Resource {
+	return &signingSecretResource{}
+}
+
+type signingSecretModelV0 struct {
+	secretMetadataModel
+
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	Length          types.Int64  `tfsdk:"length"`
+	RotatedAt       types.String `tfsdk:"rotated_at"`
+	CurrentKeyID    types.String `tfsdk:"current_key_id"`
+	PreviousKeyID   types.String `tfsdk:"previous_key_id"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+// signingSecretDocument is the JSON document stored in the vault.
+type signingSecretDocument struct {
+	Current   string `json:"current"`
+	Previous  string `json:"previous,omitempty"`
+	RotatedAt string `json:"rotated_at"`
+}
+
+type signingSecretResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *signingSecretResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *signingSecretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_signing_secret"
+}
+
+func (r *signingSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_signing_secret` generates a secret for signing webhooks or other HMAC " +
+			"signed payloads, keeping the previous secret available during rotation.\n" +
+			"\n" +
+			"The secret is stored in a azrandom vault as a JSON document of the form " +
+			"`{\"current\": \"...\", \"previous\": \"...\", \"rotated_at\": \"...\"}`. When `keepers` change, the current " +
+			"secret becomes the previous secret and a new current secret is generated, so that verifiers can accept " +
+			"both during a grace window. Each secret is the base64 encoding of `length` random bytes.\n" +
+			"\n" +
+			"A rotation fails when the secret has a newer version than the one in state, e.g. after a concurrent " +
+			"rotation by another run. Key Vault has no conditional writes, so this check is best-effort: a version " +
+			"set while the rotation is being applied is replaced without its secret being kept as previous. A resource " +
+			"imported at an older version is pinned to it, so its rotation does not fail on newer versions, and keeps " +
+			"the secret of the pinned version as previous.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeJSON),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger rotation of the secret. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"length": schema.Int64Attribute{
+				Description: "The number of random bytes in each generated secret. Default value is `32`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(16),
+				},
+			},
+			"rotated_at": schema.StringAttribute{
+				Description: "The time, in RFC 3339 format, at which the current secret was generated.",
+				Computed:    true,
+			},
+			"current_key_id": schema.StringAttribute{
+				Description: "A non-secret identifier of the current secret: the first 16 hexadecimal characters of " +
+					"its SHA-256 digest.",
+				Computed: true,
+			},
+			"previous_key_id": schema.StringAttribute{
+				Description: "A non-secret identifier of the previous secret, computed like `current_key_id`. " +
+					"Null until the secret has been rotated.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *signingSecretResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *signingSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_signing_secret", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+}
+
+func (r *signingSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan signingSecretModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	current, err := createSigningSecret(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	document := signingSecretDocument{
+		Current:   current,
+		RotatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_signing_secret", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, err := json.Marshal(document)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create azrandom_signing_secret error",
+			"Could not encode azrandom_signing_secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, string(value), secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_signing_secret", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, string(value))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	setSigningSecretComputedAttributes(&plan, document)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *signingSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state signingSecretModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_signing_secret", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	state.Version, _ = readDrift(ctx, req, resp, state.Version, version)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *signingSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state signingSecretModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regenerate, diags := valueRegenerated(ctx, req, signingSecretComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// Only metadata changed, keep the current value and version
+	if !regenerate {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_signing_secret", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.RotatedAt = state.RotatedAt
+		plan.CurrentKeyID = state.CurrentKeyID
+		plan.PreviousKeyID = state.PreviousKeyID
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// Guard against rotating on top of a version this resource has not seen, for instance when another
+	// run rotated the secret concurrently. Otherwise the secret that verifiers still accept as previous
+	// would be lost. Key Vault has no conditional writes, so this is best-effort: a version set between
+	// this read and UpdateSecret below is still replaced, and its secret is not kept as previous. A
+	// resource imported at an older version is pinned to it, so newer versions are expected, and the
+	// rotation keeps the secret of the pinned version as previous.
+	pinned, diags := pinnedVersion(ctx, req.Private, state.Version)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	latestVersion, _, err := azrandom.GetSecret(ctx, client, name, "")
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the secret",
+			"azrandom_signing_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+	if pinned == "" && latestVersion != state.Version.ValueString() {
+		resp.Diagnostics.AddError(
+			"Update azrandom_signing_secret error",
+			fmt.Sprintf("The azrandom_signing_secret %s was modified concurrently: expected version %s, found version %s. "+
+				"Refresh the state and retry the operation.", name, state.Version.ValueString(), latestVersion),
+		)
+		return
+	}
+
+	value, err := azrandom.GetSecretValue(ctx, client, name, state.Version.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the secret",
+			"azrandom_signing_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	var previous signingSecretDocument
+	if err := json.Unmarshal([]byte(value), &previous); err != nil || previous.Current == "" {
+		resp.Diagnostics.AddWarning(
+			"Update azrandom_signing_secret warning",
+			fmt.Sprintf("The value stored in version %s of %s is not a signing secret document, so no previous "+
+				"secret will be kept after this rotation.", state.Version.ValueString(), name),
+		)
+		previous = signingSecretDocument{}
+	}
+
+	current, err := createSigningSecret(plan)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+		return
+	}
+
+	document := signingSecretDocument{
+		Current:   current,
+		Previous:  previous.Current,
+		RotatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	newValue, err := json.Marshal(document)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Update azrandom_signing_secret error",
+			"Could not encode azrandom_signing_secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, string(newValue), secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_signing_secret", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, string(newValue))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	setSigningSecretComputedAttributes(&plan, document)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *signingSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state signingSecretModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_signing_secret", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+func (r *signingSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_signing_secret", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_signing_secret", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	var document signingSecretDocument
+	if err := json.Unmarshal([]byte(value), &document); err != nil || document.Current == "" {
+		resp.Diagnostics.AddError(
+			"Import azrandom_signing_secret error",
+			"The secret "+id+" does not contain a signing secret document",
+		)
+		return
+	}
+
+	currentBytes, err := base64.StdEncoding.DecodeString(document.Current)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Import azrandom_signing_secret error",
+			"The current secret in "+id+" is not base64 encoded: "+err.Error(),
+		)
+		return
+	}
+
+	state := signingSecretModelV0{
+		Name:     types.StringValue(id),
+		VaultUrl: vaultUrl,
+		Version:  types.StringValue(version),
+		Keepers:  types.MapNull(types.StringType),
+		Length:   types.Int64Value(int64(len(currentBytes))),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+	setSigningSecretComputedAttributes(&state, document)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func createSigningSecret(plan signingSecretModelV0) (string, error) {
+	bytes, err := random.CreateBytes(plan.Length.ValueInt64())
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(bytes), nil
+}
+
+// signingKeyID returns a non-secret identifier for a signing secret.
+func signingKeyID(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+func setSigningSecretComputedAttributes(model *signingSecretModelV0, document signingSecretDocument) {
+	model.RotatedAt = types.StringValue(document.RotatedAt)
+	model.CurrentKeyID = types.StringValue(signingKeyID(document.Current))
+	model.PreviousKeyID = types.StringNull()
+	if document.Previous != "" {
+		model.PreviousKeyID = types.StringValue(signingKeyID(document.Previous))
+	}
+}
@@ -0,0 +1,559 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+var (
+	_ resource.Resource                = (*storedSecretResource)(nil)
+	_ resource.ResourceWithIdentity    = (*storedSecretResource)(nil)
+	_ resource.ResourceWithImportState = (*storedSecretResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*storedSecretResource)(nil)
+)
+
+// storedSecretComputedAttributes are unknown in the plan whenever anything changes, conflict_policy is only used
+// by Create, and on_drift only after drift was detected, so they are ignored when deciding whether to write the
+// value again. value is write-only, so it is always null in plan and state, and only value_version tells when it
+// changed.
+var storedSecretComputedAttributes = []string{
+	"version",
+	"conflict_policy",
+	"on_drift",
+	"value",
+}
+
+func NewStoredSecretResource() resource.Resource {
+	return &storedSecretResource{}
+}
+
+type storedSecretModelV0 struct {
+	Name            types.String `tfsdk:"name"`
+	Version         types.String `tfsdk:"version"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Value           types.String `tfsdk:"value"`
+	ValueVersion    types.Int64  `tfsdk:"value_version"`
+	Description     types.String `tfsdk:"description"`
+	Tags            types.Map    `tfsdk:"tags"`
+	TagsAll         types.Map    `tfsdk:"tags_all"`
+	ContentType     types.String `tfsdk:"content_type"`
+	ExpirationDate  types.String `tfsdk:"expiration_date"`
+	NotBefore       types.String `tfsdk:"not_before"`
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	ConflictPolicy  types.String `tfsdk:"conflict_policy"`
+	OnDrift         types.String `tfsdk:"on_drift"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+}
+
+// metadata returns the attributes that are stored as properties of the secret, along with the tags
+// written by the provider.
+func (m storedSecretModelV0) metadata(data *azrandomProviderData) secretMetadata {
+	return secretMetadata{
+		Description:    m.Description,
+		TagsAll:        m.TagsAll,
+		ContentType:    m.ContentType,
+		ExpirationDate: m.ExpirationDate,
+		NotBefore:      m.NotBefore,
+		Enabled:        m.Enabled,
+		ProviderTags:   providerMetadataTags(data),
+	}
+}
+
+type storedSecretResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *storedSecretResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *storedSecretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stored_secret"
+}
+
+func (r *storedSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	onDrift := onDriftAttribute()
+	onDrift.Description = fmt.Sprintf("What to do when a new version of the secret was set outside of Terraform, "+
+		"for example by a rotation in the portal, or when the secret no longer holds the value that was written, "+
+		"for example after it was restored from a backup. `%s` writes the configured value again on the next apply, "+
+		"`%s` takes the current version of the secret into state, and `%s` fails the plan until the drift is "+
+		"resolved. Default value is `%s`.", OnDriftRegenerate, OnDriftIgnore, OnDriftError, OnDriftRegenerate)
+
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_stored_secret` stores a value that is given to it, rather than one " +
+			"that it generates, e.g. an ephemeral output of another provider, with the versioning, drift detection " +
+			"and recovery of the other azrandom resources.\n" +
+			"\n" +
+			"`value` is write-only, so it is never stored in the plan or state, which requires Terraform 1.11 or " +
+			"later. Since Terraform cannot tell when a write-only value changes, the value is only written again " +
+			"when `value_version` changes, or after drift as configured by `on_drift`.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"value": schema.StringAttribute{
+				Description: "The value to store in the secret. It is write-only, so it is never stored in the plan " +
+					"or state. Changing it has no effect until `value_version` changes.",
+				Required:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"value_version": schema.Int64Attribute{
+				Description: "A number to change, e.g. to increment, whenever `value` changes, which writes the value " +
+					"as a new version of the secret. The first apply after an import writes the value unless the " +
+					"secret already holds it.",
+				Required: true,
+			},
+
+			"vault_url":         vaultUrlAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not write the value again.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+
+			"conflict_policy": conflictPolicyAttribute(),
+			"on_drift":        onDrift,
+
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the value was stored ",
+				Computed:    true,
+			},
+
+			"created_on": createdOnAttribute(),
+
+			"updated_on": updatedOnAttribute(),
+		},
+	}
+}
+
+func (r *storedSecretResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+func (r *storedSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_stored_secret", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	planVersion(ctx, req, resp, storedSecretComputedAttributes...)
+}
+
+func (r *storedSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan storedSecretModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, diags := configuredStoredSecretValue(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	name := plan.Name.ValueString()
+
+	conflictPolicy := ConflictPolicy(plan.ConflictPolicy.ValueString())
+
+	// Take the existing secret into state instead of writing the value
+	if conflictPolicy == ConflictPolicyAdopt {
+		version, found, err := adoptExistingSecret(ctx, client, name, azrandom.DefaultRecoveryBackoff)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "adopt the existing secret",
+				"azrandom_stored_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		if found {
+			stored, err := updateSecretProperties(ctx, client, name, version, plan.metadata(r.providerData))
+			if err != nil {
+				resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "update the properties of the secret",
+					"azrandom_stored_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+				return
+			}
+
+			plan.Version = types.StringValue(version)
+			plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+
+			resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+			diags = resp.State.Set(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// Check if secret exists yet
+	secretExists, err := secretExistsBeforeCreate(ctx, r.providerData, client, name)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "check whether the secret already exists",
+			"azrandom_stored_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+	if secretExists && conflictPolicy == ConflictPolicyFail {
+		resp.Diagnostics.AddError(
+			"Create azrandom_stored_secret error",
+			existingSecretMessage(ctx, client, "azrandom_stored_secret", name, name)+
+				"\n\nAlternatively, set conflict_policy to \"overwrite\" or \"adopt\".",
+		)
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, value, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_stored_secret", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, value)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), value)...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *storedSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state storedSecretModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resources created before they had an identity get it on refresh
+	if resp.Identity.Raw.IsNull() {
+		resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	}
+
+	pinned, diags := pinnedVersion(ctx, req.Private, state.Version)
+	resp.Diagnostics.Append(diags...)
+	version, properties, value, err := azrandom.GetSecretWithValue(ctx, client, state.Name.ValueString(), pinned)
+	if secretDeletedOutOfBand(ctx, "azrandom_stored_secret", state.Name.ValueString(), err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_stored_secret", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+		return
+	}
+
+	state.Description = descriptionFromProperties(properties)
+	state.TagsAll = tagsFromProperties(properties, state.TagsAll)
+	state.Tags, diags = resourceTags(r.providerData, state.TagsAll, state.Tags)
+	resp.Diagnostics.Append(diags...)
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, state.ExpirationDate)
+	state.NotBefore = validityTimestamp(properties.NotBefore, state.NotBefore)
+	resp.Diagnostics.Append(disabledSecretDiagnostics(state.Name.ValueString(), state.Enabled, properties)...)
+	state.Enabled = enabledFromProperties(properties)
+	resp.Diagnostics.Append(expiredSecretDiagnostics(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	// A new version, or a version that no longer holds the generated value, e.g. after a restore from backup, is
+	// drift
+	reason, diags := readValueDrift(ctx, req, resp, state.Version, version, value)
+	resp.Diagnostics.Append(diags...)
+	state.Version = types.StringValue(version)
+	if reason != "" {
+		resp.Diagnostics.Append(recordDrift(ctx, resp.Private, state.OnDrift, reason, version)...)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *storedSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state storedSecretModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	valueChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, storedSecretComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read marks the value to be written again, e.g. when drift was detected
+	regenerate, diags := regenerationPlanned(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	value, diags := configuredStoredSecretValue(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An imported resource has no value_version yet, so its first apply only writes a value that the secret
+	// does not hold already
+	if valueChanged && !regenerate && state.ValueVersion.IsNull() {
+		held, err := secretHoldsValue(ctx, client, name, state.Version.ValueString(), value)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "compare the value of the imported secret",
+				"azrandom_stored_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+		valueChanged = !held
+	}
+
+	// Only metadata changed, keep the current value and version
+	if !valueChanged && !regenerate {
+		stored, err := updateSecretProperties(ctx, client, name, state.Version.ValueString(), plan.metadata(r.providerData))
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+				"azrandom_stored_secret", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+
+		if state.ValueVersion.IsNull() {
+			resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), value)...)
+		} else {
+			resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		}
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, value, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_stored_secret", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, value)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), value)...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *storedSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state storedSecretModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleted, err := azrandom.DeleteSecret(ctx, client, state.Name.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.DeleteError("azrandom_stored_secret", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+		return
+	}
+
+	if deleted {
+		resp.Diagnostics.Append(waitForDeletion(ctx, client, state.WaitForDeletion, state.Name.ValueString())...)
+		resp.Diagnostics.Append(purgeOnDestroy(ctx, client, state.PurgeOnDestroy, state.Name.ValueString())...)
+	}
+}
+
+// ImportState imports the current version of the secret without its value, which is write-only. value_version
+// is left null, so that the first apply compares the configured value with the stored one, see Update.
+func (r *storedSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_stored_secret", id, resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	state := storedSecretModelV0{
+		Name:           types.StringValue(id),
+		Version:        types.StringValue(version),
+		VaultUrl:       vaultUrl,
+		Value:          types.StringNull(),
+		ValueVersion:   types.Int64Null(),
+		Description:    descriptionFromProperties(properties),
+		ConflictPolicy: types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:        types.StringValue(OnDriftRegenerate.String()),
+	}
+	state.Tags = importedTagsFromProperties(properties)
+	state.TagsAll = state.Tags
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, types.StringNull())
+	state.NotBefore = validityTimestamp(properties.NotBefore, types.StringNull())
+	state.Enabled = enabledFromProperties(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// configuredStoredSecretValue returns the value of an azrandom_stored_secret, which is only found in its
+// configuration, since it is write-only.
+func configuredStoredSecretValue(ctx context.Context, config tfsdk.Config) (string, diag.Diagnostics) {
+	var value types.String
+	diags := config.GetAttribute(ctx, path.Root("value"), &value)
+	if diags.HasError() {
+		return "", diags
+	}
+	if value.IsNull() || value.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("value"),
+			"Missing value",
+			"The value of the azrandom_stored_secret is not known while applying. Write-only attributes require "+
+				"Terraform 1.11 or later.",
+		)
+	}
+	return value.ValueString(), diags
+}
+
+// secretHoldsValue returns whether the given version of the secret name holds value. The value of a disabled
+// secret cannot be read, so it is not assumed to be held.
+func secretHoldsValue(ctx context.Context, client *azsecrets.Client, name string, version string, value string) (bool, error) {
+	stored, err := azrandom.GetSecretValue(ctx, client, name, version)
+	if azrandom.IsDisabled(err) {
+		tflog.Debug(ctx, "Not comparing the value of disabled secret", map[string]any{"name": name})
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return stored == value, nil
+}
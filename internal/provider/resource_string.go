@@ -6,53 +6,144 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"terraform-provider-azrandom/internal/random"
 	"terraform-provider-azrandom/internal/validators"
 
 	azrandom "terraform-provider-azrandom/client"
 	"terraform-provider-azrandom/internal/diagnostics"
-	"terraform-provider-azrandom/internal/utils"
 )
 
 var (
-	_ resource.Resource                = (*stringResource)(nil)
-	_ resource.ResourceWithImportState = (*stringResource)(nil)
+	_ resource.Resource                     = (*stringResource)(nil)
+	_ resource.ResourceWithIdentity         = (*stringResource)(nil)
+	_ resource.ResourceWithImportState      = (*stringResource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*stringResource)(nil)
+	_ resource.ResourceWithMoveState        = (*stringResource)(nil)
+	_ resource.ResourceWithConfigValidators = (*stringResource)(nil)
+	_ resource.ResourceWithUpgradeState     = (*stringResource)(nil)
+	_ resource.ResourceWithValidateConfig   = (*stringResource)(nil)
 )
 
+// stringComputedAttributes are unknown in the plan whenever anything changes or describe the value,
+// conflict_policy is only used by Create, on_drift only after drift was detected, preset and number only set
+// other attributes, and restore_version restores rather than generates a value, so they are ignored when
+// deciding whether to generate a new value.
+var stringComputedAttributes = []string{
+	"version",
+	"result_hash",
+	"conflict_policy",
+	"on_drift",
+	"preset",
+	"number",
+	"restore_version",
+}
+
+// stringSettingAttributes are the generation settings that an imported value is checked against, see
+// keepImportedString.
+var stringSettingAttributes = []string{
+	"length",
+	"upper",
+	"lower",
+	"numeric",
+	"special",
+	"min_upper",
+	"min_lower",
+	"min_numeric",
+	"min_special",
+	"override_special",
+	"charset",
+	"exclude_characters",
+	"first_char_alpha",
+	"prefix",
+	"suffix",
+}
+
 func NewStringResource() resource.Resource {
 	return &stringResource{}
 }
 
-type stringModelV0 struct {
-	Name            types.String `tfsdk:"name"`
-	Version         types.String `tfsdk:"version"`
-	Keepers         types.Map    `tfsdk:"keepers"`
-	Length          types.Int64  `tfsdk:"length"`
-	Special         types.Bool   `tfsdk:"special"`
-	Upper           types.Bool   `tfsdk:"upper"`
-	Lower           types.Bool   `tfsdk:"lower"`
-	Numeric         types.Bool   `tfsdk:"numeric"`
-	MinNumeric      types.Int64  `tfsdk:"min_numeric"`
-	MinUpper        types.Int64  `tfsdk:"min_upper"`
-	MinLower        types.Int64  `tfsdk:"min_lower"`
-	MinSpecial      types.Int64  `tfsdk:"min_special"`
-	OverrideSpecial types.String `tfsdk:"override_special"`
+type stringModelV3 struct {
+	Name                    types.String `tfsdk:"name"`
+	VaultUrl                types.String `tfsdk:"vault_url"`
+	Version                 types.String `tfsdk:"version"`
+	Keepers                 types.Map    `tfsdk:"keepers"`
+	Length                  types.Int64  `tfsdk:"length"`
+	Special                 types.Bool   `tfsdk:"special"`
+	Upper                   types.Bool   `tfsdk:"upper"`
+	Lower                   types.Bool   `tfsdk:"lower"`
+	Numeric                 types.Bool   `tfsdk:"numeric"`
+	Number                  types.Bool   `tfsdk:"number"`
+	MinNumeric              types.Int64  `tfsdk:"min_numeric"`
+	MinUpper                types.Int64  `tfsdk:"min_upper"`
+	MinLower                types.Int64  `tfsdk:"min_lower"`
+	MinSpecial              types.Int64  `tfsdk:"min_special"`
+	OverrideSpecial         types.String `tfsdk:"override_special"`
+	Charset                 types.String `tfsdk:"charset"`
+	ExcludeCharacters       types.String `tfsdk:"exclude_characters"`
+	FirstCharAlpha          types.Bool   `tfsdk:"first_char_alpha"`
+	Pattern                 types.String `tfsdk:"pattern"`
+	Encoding                types.String `tfsdk:"encoding"`
+	ResultHash              types.String `tfsdk:"result_hash"`
+	Prefix                  types.String `tfsdk:"prefix"`
+	Suffix                  types.String `tfsdk:"suffix"`
+	Description             types.String `tfsdk:"description"`
+	ContentType             types.String `tfsdk:"content_type"`
+	ExpirationDate          types.String `tfsdk:"expiration_date"`
+	NotBefore               types.String `tfsdk:"not_before"`
+	Enabled                 types.Bool   `tfsdk:"enabled"`
+	Tags                    types.Map    `tfsdk:"tags"`
+	TagsAll                 types.Map    `tfsdk:"tags_all"`
+	VerifyWrite             types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy          types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion         types.Bool   `tfsdk:"wait_for_deletion"`
+	DisablePreviousVersions types.Bool   `tfsdk:"disable_previous_versions"`
+	MaxVersionsToKeep       types.Int64  `tfsdk:"max_versions_to_keep"`
+	RestoreVersion          types.String `tfsdk:"restore_version"`
+	ConflictPolicy          types.String `tfsdk:"conflict_policy"`
+	OnDrift                 types.String `tfsdk:"on_drift"`
+	Preset                  types.String `tfsdk:"preset"`
+	CreatedOn               types.String `tfsdk:"created_on"`
+	UpdatedOn               types.String `tfsdk:"updated_on"`
+	CreatedDate             types.String `tfsdk:"created_date"`
+	UpdatedDate             types.String `tfsdk:"updated_date"`
+}
+
+// metadata returns the attributes that are stored as properties of the secret, along with the tags
+// written by the provider.
+func (m stringModelV3) metadata(data *azrandomProviderData) secretMetadata {
+	return secretMetadata{
+		Description:    m.Description,
+		TagsAll:        m.TagsAll,
+		ContentType:    m.ContentType,
+		ExpirationDate: m.ExpirationDate,
+		NotBefore:      m.NotBefore,
+		Enabled:        m.Enabled,
+		ProviderTags:   providerMetadataTags(data),
+	}
 }
 
 type stringResource struct {
-	client *azsecrets.Client
+	providerData *azrandomProviderData
 }
 
 // Configure adds the provider configured client to the resource.
@@ -61,18 +152,18 @@ func (r *stringResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*azsecrets.Client)
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *azsecrets.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = providerData
 }
 
 func (r *stringResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -81,12 +172,26 @@ func (r *stringResource) Metadata(_ context.Context, req resource.MetadataReques
 
 func (r *stringResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 3,
 		Description: "The resource `azrandom_string` generates a random permutation of alphanumeric " +
 			"characters and optionally special characters.\n" +
 			"\n" +
 			"This resource *does* use a cryptographic random number generator.\n" +
 			"\n" +
-			"Finally, the generated string is stored in a azrandom vault",
+			"Finally, the generated string is stored in a azrandom vault.\n" +
+			"\n" +
+			"On import, `length` is taken from the stored string and the other generation settings take their " +
+			"default values, since the settings a string was generated with cannot be told from the string. The " +
+			"first apply after the import records other configured settings without generating a new value as " +
+			"long as the stored string satisfies them: it starts with the configured `prefix` and ends with the " +
+			"configured `suffix`, and the characters between them are `length` characters of the configured " +
+			"character sets, with at least the `min_*` counts. Otherwise, and with a `pattern`, a new value is " +
+			"generated. The stored string is not decoded, so it only satisfies the settings with an `encoding` of " +
+			"`plain`. The configured `encoding` is recorded on the next apply without generating a new value.\n" +
+			"\n" +
+			"A `random_string` or `random_password` of the `hashicorp/random` provider can be moved into an " +
+			"`azrandom_string` with a `moved` block, which keeps its result: the next apply stores it in the " +
+			"configured secret, unless the configuration generates the string with other settings.",
 
 		Attributes: map[string]schema.Attribute{
 			"keepers": schema.MapAttribute{
@@ -96,10 +201,27 @@ func (r *stringResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:    true,
 			},
 
+			"preset": schema.StringAttribute{
+				Description: "A named set of settings that satisfies the password policy or naming rules of a common " +
+					"target system. " +
+					fmt.Sprintf("Currently-supported values are: `%s`. ", strings.Join(random.StringPresetNames(), "`, `")) +
+					"The preset fills `length`, `upper`, `lower`, `numeric`, `special`, the `min_*` counts, " +
+					"`override_special` and `first_char_alpha`; attributes that are set explicitly override the preset. " +
+					"The naming presets `storage_account`, `container_registry` and `dns_label` reject attributes set " +
+					"to values that would break the naming rules of their target, counting `prefix` and `suffix` " +
+					"towards the length of the name.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(random.StringPresetNames()...),
+				},
+			},
+
 			"length": schema.Int64Attribute{
 				Description: "The length of the string desired. The minimum value for length is 1 and, length " +
-					"must also be >= (`min_upper` + `min_lower` + `min_numeric` + `min_special`).",
-				Required: true,
+					"must also be >= (`min_upper` + `min_lower` + `min_numeric` + `min_special`). " +
+					"Required unless `preset` or `pattern` is set. With `pattern`, it is the length of the pattern.",
+				Optional: true,
+				Computed: true,
 				Validators: []validator.Int64{
 					int64validator.AtLeast(1),
 					int64validator.AtLeastSumOf(
@@ -135,7 +257,9 @@ func (r *stringResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"numeric": schema.BoolAttribute{
 				Description: "Include numeric characters in the result. Default value is `true`. " +
 					"If `numeric`, `upper`, `lower`, and `special` are all configured, at least one " +
-					"of them must be set to `true`.",
+					"of them must be set to `true`. Releases before schema version 1 defaulted to `false`: a " +
+					"string created by one of them keeps `false` while `numeric` is not configured, until a " +
+					"new value is generated for another reason, which uses the default of `true`.",
 				Optional: true,
 				Computed: true,
 				Validators: []validator.Bool{
@@ -145,7 +269,14 @@ func (r *stringResource) Schema(ctx context.Context, req resource.SchemaRequest,
 						path.MatchRoot("lower"),
 					),
 				},
-				Default: booldefault.StaticBool(false),
+				Default: booldefault.StaticBool(true),
+			},
+
+			"number": schema.BoolAttribute{
+				Description: "Include numeric characters in the result, like `numeric`, with which it must agree when " +
+					"both are set. Kept for configurations written for `random_string`; use `numeric` instead.",
+				Optional:           true,
+				DeprecationMessage: "Use numeric instead.",
 			},
 
 			"min_numeric": schema.Int64Attribute{
@@ -181,24 +312,678 @@ func (r *stringResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					"overrides the default character list in the special argument.  The `special` argument must " +
 					"still be set to true for any overwritten characters to be used in generation.",
 				Optional: true,
+				Computed: true,
 			},
 
+			"charset": schema.StringAttribute{
+				Description: "Supply your own alphabet to draw every character of the string from, such as " +
+					"`0123456789abcdef` for hexadecimal strings. Each distinct character is equally likely, and `length` " +
+					"counts characters. Cannot be combined with `preset`, `upper`, `lower`, `numeric`, `special`, " +
+					"the `min_*` counts or `override_special`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"exclude_characters": schema.StringAttribute{
+				Description: "Characters that are never used in the result, such as `l1IO0` to avoid characters " +
+					"that are easily confused when transcribing it. They are removed from all character sets, " +
+					"including `override_special` and `charset`. Every `min_*` count that is greater than zero must " +
+					"leave characters to choose from.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"first_char_alpha": schema.BoolAttribute{
+				Description: "Start the result with a letter, chosen from the enabled uppercase and lowercase " +
+					"characters, e.g. for resource names or identifiers that must not start with a digit. The other " +
+					"characters are unconstrained, and the `min_*` counts are still met. Cannot be combined with " +
+					"`charset`. Default value is `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+
+			"pattern": schema.StringAttribute{
+				Description: "A template of the string, such as `AAA-9{4}` for strings like `XKC-0421`. Each `a` is " +
+					"replaced by a random lowercase letter, each `A` by an uppercase letter, each `9` by a digit and each " +
+					"`#` by one of the special characters `!@#$%&*()-_=+[]{}<>:?`. Other characters are kept as they are, " +
+					"and `\\` keeps the character that follows it, e.g. `\\9` or `\\{`. `{n}` repeats the preceding " +
+					"character `n` times. Cannot be combined with `length`, `preset`, `upper`, `lower`, `numeric`, " +
+					"`special`, the `min_*` counts, `override_special`, `charset`, `exclude_characters` or " +
+					"`first_char_alpha`.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.StringPattern(),
+				},
+			},
+
+			"prefix": schema.StringAttribute{
+				Description: "A string to store in front of the generated characters, such as `svc-`. It does not " +
+					"count towards `length` or the `min_*` counts, and is encoded along with them. Changing it generates " +
+					"a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"suffix": schema.StringAttribute{
+				Description: "A string to store after the generated characters, such as `-ro`. It does not count " +
+					"towards `length` or the `min_*` counts, and is encoded along with them. Changing it generates a " +
+					"new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"encoding": stringEncodingAttribute(),
+
+			"verify_write":              verifyWriteAttribute(),
+			"purge_on_destroy":          purgeOnDestroyAttribute(),
+			"wait_for_deletion":         waitForDeletionAttribute(),
+			"vault_url":                 vaultUrlAttribute(),
+			"disable_previous_versions": disablePreviousVersionsAttribute(),
+			"max_versions_to_keep":      maxVersionsToKeepAttribute(),
+			"restore_version":           restoreVersionAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+
+			"conflict_policy": conflictPolicyAttribute(),
+			"on_drift":        onDriftAttribute(),
+
 			"version": schema.StringAttribute{
 				Description: "The version to the secret under which the generated value was stored ",
 				Computed:    true,
 			},
 
+			"result_hash": schema.StringAttribute{
+				Description: "The hex encoded SHA-256 of the value stored in the secret, as encoded by `encoding`. " +
+					"It changes whenever a new value is stored, so that it can be used e.g. in the `keepers` of other " +
+					"resources, without the value itself being kept in state. When the value is changed outside of " +
+					"Terraform, it is updated on refresh. It is null for values taken into state from disabled versions, " +
+					"whose value cannot be read.",
+				Computed: true,
+			},
+
+			"created_on": createdOnAttribute(),
+
+			"updated_on": updatedOnAttribute(),
+
+			"created_date": createdDateAttribute(),
+
+			"updated_date": updatedDateAttribute(),
+
 			"name": schema.StringAttribute{
 				Description: "The name of the secret where the generated value should be stored",
 				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					nameRequiresReplace(),
+				},
 			},
 		},
 	}
 }
 
+func (r *stringResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// UpgradeState upgrades state of schema versions 0 to 2.
+func (r *stringResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := stringSchemaV0()
+	schemaV1 := stringSchemaV1()
+	schemaV2 := stringSchemaV2()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeStringStateV0,
+		},
+		1: {
+			PriorSchema:   &schemaV1,
+			StateUpgrader: upgradeStringState,
+		},
+		2: {
+			PriorSchema:   &schemaV2,
+			StateUpgrader: upgradeStringState,
+		},
+	}
+}
+
+// upgradeStringState sets the settings that were added after the resource was created to their default
+// values, so that they are not planned as an update.
+func upgradeStringState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	upgradeStringStateWith(ctx, req, resp, fillStringStateDefaults)
+}
+
+// upgradeStringStateV0 upgrades state of schema version 0 like upgradeStringState, and stores the numeric of
+// the string explicitly as false, its default in schema version 0. State upgrades cannot write private state,
+// so the first Read after the upgrade records that the value was generated with that default, see
+// recordLegacyNumeric.
+func upgradeStringStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	upgradeStringStateWith(ctx, req, resp, func(state *stringModelV3) {
+		fillStringStateDefaults(state)
+		if state.Numeric.IsNull() {
+			state.Numeric = types.BoolValue(false)
+		}
+	})
+}
+
+// upgradeStringStateWith copies the prior state of a string into the current schema and completes it with fill.
+func upgradeStringStateWith(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, fill func(*stringModelV3)) {
+	var state stringModelV3
+
+	copyPriorState(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fill(&state)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// fillStringStateDefaults sets the settings of state that were added in later releases, and are null in state
+// written before, to their default values.
+func fillStringStateDefaults(state *stringModelV3) {
+	if state.ConflictPolicy.IsNull() {
+		state.ConflictPolicy = types.StringValue(ConflictPolicyFail.String())
+	}
+	if state.OnDrift.IsNull() {
+		state.OnDrift = types.StringValue(OnDriftRegenerate.String())
+	}
+	if state.FirstCharAlpha.IsNull() {
+		state.FirstCharAlpha = types.BoolValue(false)
+	}
+	if state.Encoding.IsNull() {
+		state.Encoding = types.StringValue(stringEncodingPlain)
+	}
+}
+
+// MoveState moves the state of a random_string or random_password of the hashicorp/random provider into an
+// azrandom_string.
+func (r *stringResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: moveStringState,
+		},
+	}
+}
+
+// randomStringState holds the attributes of the state of a random_string or random_password that an
+// azrandom_string is moved with. Settings that are missing from the state of earlier releases of the
+// hashicorp/random provider are nil.
+type randomStringState struct {
+	Keepers         map[string]*string `json:"keepers"`
+	Length          int64              `json:"length"`
+	Special         *bool              `json:"special"`
+	Upper           *bool              `json:"upper"`
+	Lower           *bool              `json:"lower"`
+	Numeric         *bool              `json:"numeric"`
+	Number          *bool              `json:"number"`
+	MinNumeric      int64              `json:"min_numeric"`
+	MinUpper        int64              `json:"min_upper"`
+	MinLower        int64              `json:"min_lower"`
+	MinSpecial      int64              `json:"min_special"`
+	OverrideSpecial *string            `json:"override_special"`
+	Result          string             `json:"result"`
+}
+
+// moveStringState takes the keepers, the generation settings and the result of a random_string or
+// random_password into state, with the other settings at their defaults. The resource has no secret until the
+// next apply, which stores the result in the configured secret.
+func moveStringState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if !movedFrom(req, randomProviderSource, "random_string") && !movedFrom(req, randomProviderSource, "random_password") {
+		return
+	}
+
+	var source randomStringState
+	resp.Diagnostics.Append(decodeMovedState(req, &source)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if source.Result == "" {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf("The %s to move has no result.", req.SourceTypeName),
+		)
+		return
+	}
+
+	keepers, diags := movedKeepers(ctx, source.Keepers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The settings default to true, and numeric was called number before
+	setting := func(values ...*bool) types.Bool {
+		for _, value := range values {
+			if value != nil {
+				return types.BoolValue(*value)
+			}
+		}
+		return types.BoolValue(true)
+	}
+	// Earlier releases of the hashicorp/random provider stored an empty override_special when it was omitted
+	overrideSpecial := types.StringNull()
+	if source.OverrideSpecial != nil && *source.OverrideSpecial != "" {
+		overrideSpecial = types.StringValue(*source.OverrideSpecial)
+	}
+
+	state := stringModelV3{
+		Name:              types.StringNull(),
+		VaultUrl:          types.StringNull(),
+		Version:           types.StringNull(),
+		Keepers:           keepers,
+		Length:            types.Int64Value(source.Length),
+		Special:           setting(source.Special),
+		Upper:             setting(source.Upper),
+		Lower:             setting(source.Lower),
+		Numeric:           setting(source.Numeric, source.Number),
+		Number:            types.BoolNull(),
+		MinNumeric:        types.Int64Value(source.MinNumeric),
+		MinUpper:          types.Int64Value(source.MinUpper),
+		MinLower:          types.Int64Value(source.MinLower),
+		MinSpecial:        types.Int64Value(source.MinSpecial),
+		OverrideSpecial:   overrideSpecial,
+		Charset:           types.StringNull(),
+		ExcludeCharacters: types.StringNull(),
+		FirstCharAlpha:    types.BoolValue(false),
+		Pattern:           types.StringNull(),
+		Encoding:          types.StringValue(stringEncodingPlain),
+		ResultHash:        types.StringValue(azrandom.HashSecretValue(source.Result)),
+		Prefix:            types.StringNull(),
+		Suffix:            types.StringNull(),
+		ContentType:       types.StringValue(contentTypeText),
+		Enabled:           types.BoolValue(true),
+		Tags:              types.MapNull(types.StringType),
+		TagsAll:           types.MapNull(types.StringType),
+		RestoreVersion:    types.StringNull(),
+		ConflictPolicy:    types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:           types.StringValue(OnDriftRegenerate.String()),
+		Preset:            types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(storeMovedValue(ctx, resp.TargetPrivate, source.Result)...)
+	resp.Diagnostics.Append(resp.TargetState.Set(ctx, state)...)
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+// The attributes that were omitted from configuration are filled from the preset, and length from the pattern.
+func (r *stringResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_string", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// The plan is taken from the response, which already holds the planned tags_all
+	var config, plan stringModelV3
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The deprecated number attribute stands in for numeric
+	if config.Numeric.IsNull() && !config.Number.IsNull() {
+		config.Numeric = config.Number
+		plan.Numeric = config.Number
+	}
+
+	legacyNumericKept := false
+	configValues := stringPresetValues(&config)
+	omitted := func(name string) bool {
+		return configValues[name].IsNull()
+	}
+
+	switch {
+	case config.Preset.IsUnknown():
+		// The preset is resolved during apply
+		markStringPresetUnknown(&plan, omitted)
+	case config.Preset.IsNull():
+		if omitted("override_special") {
+			plan.OverrideSpecial = types.StringNull()
+		}
+		// Values upgraded from schema version 0 were generated while numeric defaulted to false, and are not
+		// regenerated for the corrected default
+		if omitted("numeric") && !req.State.Raw.IsNull() {
+			legacy, diags := legacyNumeric(ctx, req.Private)
+			resp.Diagnostics.Append(diags...)
+			if legacy {
+				plan.Numeric = types.BoolValue(false)
+				legacyNumericKept = true
+			}
+		}
+	default:
+		fillStringPreset(&plan, random.StringPresets[config.Preset.ValueString()], omitted)
+	}
+
+	switch {
+	case config.Pattern.IsUnknown():
+		// The length of the pattern is known during apply
+		plan.Length = types.Int64Unknown()
+	case !config.Pattern.IsNull():
+		pattern, err := random.ParseStringPattern(config.Pattern.ValueString())
+		if err != nil {
+			// Reported by the validator of the attribute
+			return
+		}
+		if pattern.Size() > maxSecretValueLength {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pattern"),
+				"Invalid Attribute Value",
+				fmt.Sprintf("The attribute \"pattern\" generates strings of %d bytes, more than the %d bytes a "+
+					"secret can hold.", pattern.Size(), maxSecretValueLength),
+			)
+			return
+		}
+		plan.Length = types.Int64Value(pattern.Length())
+	}
+
+	if !plan.Length.IsUnknown() && !plan.MinUpper.IsUnknown() && !plan.MinLower.IsUnknown() &&
+		!plan.MinNumeric.IsUnknown() && !plan.MinSpecial.IsUnknown() {
+		minimum := plan.MinUpper.ValueInt64() + plan.MinLower.ValueInt64() + plan.MinNumeric.ValueInt64() + plan.MinSpecial.ValueInt64()
+		if plan.Length.ValueInt64() < minimum {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("length"),
+				"Invalid Attribute Value",
+				fmt.Sprintf("The length %d is less than the sum of the min_* attributes, %d, after applying preset %q.",
+					plan.Length.ValueInt64(), minimum, config.Preset.ValueString()),
+			)
+			return
+		}
+	}
+
+	if !stringPresetUnknown(&plan) && !plan.Charset.IsUnknown() && !plan.ExcludeCharacters.IsUnknown() {
+		if err := random.ValidateExclusions(stringParams(&plan)); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("exclude_characters"),
+				"Invalid Attribute Value",
+				fmt.Sprintf("The attribute \"exclude_characters\" leaves no characters to generate the string "+
+					"from: %s.", err),
+			)
+			return
+		}
+		if err := random.ValidateFirstCharAlpha(stringParams(&plan)); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("first_char_alpha"),
+				"Invalid Attribute Value",
+				fmt.Sprintf("The attribute \"first_char_alpha\" cannot be met: %s.", err),
+			)
+			return
+		}
+	}
+
+	if !stringPresetUnknown(&plan) && !plan.Charset.IsUnknown() && !plan.Pattern.IsUnknown() &&
+		!plan.Prefix.IsUnknown() && !plan.Suffix.IsUnknown() && !plan.Encoding.IsUnknown() {
+		if size := maxStringValueSize(&plan); size > maxSecretValueLength {
+			resp.Diagnostics.AddError(
+				"Invalid Attribute Combination",
+				fmt.Sprintf("The stored string, including its prefix, suffix and encoding, can take up to %d bytes, "+
+					"more than the %d bytes a secret can hold.", size, maxSecretValueLength),
+			)
+			return
+		}
+	}
+
+	var stateEncoding types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("encoding"), &stateEncoding)...)
+	}
+
+	ignored := stringIgnoredAttributes(stateEncoding)
+	if r.keepImportedString(ctx, req, resp, &plan) {
+		ignored = append(slices.Clone(ignored), stringSettingAttributes...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+	planVersion(ctx, req, resp, ignored...)
+	if legacyNumericKept {
+		planLegacyNumeric(ctx, resp)
+	}
+	planRestoreVersion(ctx, r.providerData, "azrandom_string", req, resp)
+	planStringResultHash(ctx, req, resp)
+}
+
+// planLegacyNumeric plans the current default of numeric for a string whose numeric was kept at its former
+// default of false, when a new value is generated anyway.
+func planLegacyNumeric(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var version types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("version"), &version)...)
+	if version.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("numeric"), types.BoolValue(true))...)
+	}
+}
+
+// keepImportedString returns whether the plan of a resource that was imported and not applied since keeps its
+// stored value although the planned generation settings differ from the imported ones, because the value
+// satisfies them, and records so in the planned private state for Update. An import cannot tell the settings
+// from the value and takes their defaults, so that configuring the settings the value was generated with still
+// keeps it.
+func (r *stringResource) keepImportedString(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan *stringModelV3) bool {
+	// The provider is not configured yet when its configuration depends on other resources
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() || r.providerData == nil {
+		return false
+	}
+
+	imported, diags := importedState(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || imported == "" {
+		return false
+	}
+
+	// An encoded value cannot be checked against the settings of the string before it was encoded
+	if stringPresetUnknown(plan) || plan.Length.IsUnknown() || plan.Charset.IsUnknown() ||
+		plan.ExcludeCharacters.IsUnknown() || plan.FirstCharAlpha.IsUnknown() || plan.Pattern.IsUnknown() ||
+		plan.Prefix.IsUnknown() || plan.Suffix.IsUnknown() || plan.Encoding.ValueString() != stringEncodingPlain {
+		return false
+	}
+
+	var state stringModelV3
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	// The value of a disabled version cannot be read, and is replaced as before
+	value, err := azrandom.GetSecretValue(ctx, client, state.Name.ValueString(), state.Version.ValueString())
+	if azrandom.IsDisabled(err) {
+		return false
+	}
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_string", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
+		return false
+	}
+	if !stringParams(plan).Satisfies(value) {
+		return false
+	}
+
+	tflog.Info(ctx, "Keeping the imported value, which satisfies the configured settings")
+	resp.Diagnostics.Append(keepImportedValue(ctx, resp.Private)...)
+	return true
+}
+
+// planStringResultHash plans result_hash to change along with the version, so that it stays known when only
+// metadata changes.
+func planStringResultHash(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var version types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("version"), &version)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hash := types.StringUnknown()
+	if !version.IsUnknown() && !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("result_hash"), &hash)...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("result_hash"), hash)...)
+}
+
+// storedStringHash returns the result_hash of the value stored in the given version of the secret name, or hash
+// when the version is disabled, since its value cannot be read.
+func storedStringHash(ctx context.Context, client *azsecrets.Client, name string, version string, hash types.String) (types.String, error) {
+	stored, err := azrandom.GetSecretValue(ctx, client, name, version)
+	if azrandom.IsDisabled(err) {
+		return hash, nil
+	}
+	if err != nil {
+		return hash, err
+	}
+	return types.StringValue(azrandom.HashSecretValue(stored)), nil
+}
+
+func (r *stringResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("length"),
+			path.MatchRoot("preset"),
+			path.MatchRoot("pattern"),
+		),
+		// The charset replaces the character sets that the other settings select
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("preset")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("upper")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("lower")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("numeric")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("number")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("special")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("min_upper")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("min_lower")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("min_numeric")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("min_special")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("override_special")),
+		resourcevalidator.Conflicting(path.MatchRoot("charset"), path.MatchRoot("first_char_alpha")),
+		// The pattern sets the length and the characters of each position
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("length")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("preset")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("upper")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("lower")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("numeric")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("number")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("special")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("min_upper")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("min_lower")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("min_numeric")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("min_special")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("override_special")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("charset")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("exclude_characters")),
+		resourcevalidator.Conflicting(path.MatchRoot("pattern"), path.MatchRoot("first_char_alpha")),
+		stringNamingPresetValidator{},
+	}
+}
+
+var _ resource.ConfigValidator = stringNamingPresetValidator{}
+
+// stringNamingPresetValidator rejects configurations of a naming preset whose explicit settings would generate
+// strings that break the naming rules of its target.
+type stringNamingPresetValidator struct{}
+
+func (v stringNamingPresetValidator) Description(_ context.Context) string {
+	return "the attributes must not break the naming rules of the preset"
+}
+
+func (v stringNamingPresetValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringNamingPresetValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config stringModelV3
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Preset.IsNull() || config.Preset.IsUnknown() {
+		return
+	}
+	rule, ok := random.StringNamingRules[config.Preset.ValueString()]
+	if !ok {
+		return
+	}
+
+	// The deprecated number attribute stands in for numeric
+	if config.Numeric.IsNull() {
+		config.Numeric = config.Number
+	}
+	values := stringPresetValues(&config)
+	if stringPresetUnknown(&config) || config.ExcludeCharacters.IsUnknown() || config.Prefix.IsUnknown() ||
+		config.Suffix.IsUnknown() {
+		return
+	}
+	fillStringPreset(&config, random.StringPresets[config.Preset.ValueString()], func(name string) bool {
+		return values[name].IsNull()
+	})
+
+	if err := rule.Check(stringParams(&config)); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("preset"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attributes set along with preset %q break the naming rules of its target: %s.",
+				config.Preset.ValueString(), err),
+		)
+	}
+}
+
+// ValidateConfig rejects configurations where numeric and the deprecated number attribute disagree.
+func (r *stringResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var numeric, number types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("numeric"), &numeric)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("number"), &number)...)
+	if resp.Diagnostics.HasError() || numeric.IsNull() || numeric.IsUnknown() || number.IsNull() || number.IsUnknown() {
+		return
+	}
+
+	if numeric.ValueBool() != number.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("number"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attribute \"number\" is %t, but \"numeric\" is %t. Remove \"number\", which is "+
+				"deprecated in favor of \"numeric\".", number.ValueBool(), numeric.ValueBool()),
+		)
+	}
+}
+
 func (r *stringResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 
-	var plan stringModelV0
+	var plan stringModelV3
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -206,7 +991,15 @@ func (r *stringResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	result, err := createString(plan)
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	result, err := createString(&plan)
 	if err != nil {
 		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
 		return
@@ -214,27 +1007,75 @@ func (r *stringResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	name := plan.Name.ValueString()
 
+	conflictPolicy := ConflictPolicy(plan.ConflictPolicy.ValueString())
+
+	// Take the existing secret into state instead of generating a value
+	if conflictPolicy == ConflictPolicyAdopt {
+		version, found, err := adoptExistingSecret(ctx, client, name, azrandom.DefaultRecoveryBackoff)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "adopt the existing secret",
+				"azrandom_string", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		if found {
+			stored, err := updateSecretProperties(ctx, client, name, version, plan.metadata(r.providerData))
+			if err != nil {
+				resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "update the properties of the secret",
+					"azrandom_string", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+				return
+			}
+
+			plan.ResultHash, err = storedStringHash(ctx, client, name, version, types.StringNull())
+			if err != nil {
+				resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "read the value of the adopted secret",
+					"azrandom_string", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+				return
+			}
+
+			plan.Version = types.StringValue(version)
+			plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+			resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+			diags = resp.State.Set(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
 	// Check if secret exists yet
-	secretExists, err := azrandom.SecretExists(ctx, r.client, name)
-	if secretExists {
+	secretExists, err := secretExistsBeforeCreate(ctx, r.providerData, client, name)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "check whether the secret already exists",
+			"azrandom_string", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+	if secretExists && conflictPolicy == ConflictPolicyFail {
 		resp.Diagnostics.AddError(
 			"Create azrandom_string error",
-			"A azrandom_string with name  "+name+" already exists. To manage this in terraform you must import it"+err.Error(),
+			existingSecretMessage(ctx, client, "azrandom_string", name, name)+
+				"\n\nAlternatively, set conflict_policy to \"overwrite\" or \"adopt\".",
 		)
 		return
 	}
 
-	version, err := azrandom.CreateSecret(ctx, r.client, name, string(result))
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, string(result), secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Create azrandom_string error",
-			"Could not read azrandom_string from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_string", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, string(result))...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Version = types.StringValue(version)
+	plan.ResultHash = types.StringValue(azrandom.HashSecretValue(string(result)))
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
 
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), string(result))...)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -244,27 +1085,78 @@ func (r *stringResource) Create(ctx context.Context, req resource.CreateRequest,
 
 func (r *stringResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 
-	var state stringModelV0
+	var state stringModelV3
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	version, err := azrandom.GetSecret(ctx, r.client, state.Name.ValueString())
+	// A resource moved from random_string or random_password has no secret until the next apply stores its value
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resources created before they had an identity get it on refresh
+	if resp.Identity.Raw.IsNull() {
+		resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	}
+
+	pinned, diags := pinnedVersion(ctx, req.Private, state.Version)
+	resp.Diagnostics.Append(diags...)
+	version, properties, value, err := azrandom.GetSecretWithValue(ctx, client, state.Name.ValueString(), pinned)
+	if secretDeletedOutOfBand(ctx, "azrandom_string", state.Name.ValueString(), err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Read azrandom_string error",
-			"Could not read azrandom_string from azrandom storeage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_string", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
 		return
 	}
 
-	// If version number has changed we know that drift has occurred.
-	if state.Version.ValueString() != version {
-		state.Version = types.StringValue(version)
-		keepers, _ := utils.GenerateDriftKeepers(ctx)
-		state.Keepers = keepers
+	state.Description = descriptionFromProperties(properties)
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, state.ExpirationDate)
+	state.NotBefore = validityTimestamp(properties.NotBefore, state.NotBefore)
+	resp.Diagnostics.Append(disabledSecretDiagnostics(state.Name.ValueString(), state.Enabled, properties)...)
+	state.Enabled = enabledFromProperties(properties)
+	resp.Diagnostics.Append(expiredSecretDiagnostics(state.Name.ValueString(), properties)...)
+	state.TagsAll = tagsFromProperties(properties, state.TagsAll)
+	state.Tags, diags = resourceTags(r.providerData, state.TagsAll, state.Tags)
+	resp.Diagnostics.Append(diags...)
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(recordLegacyNumeric(ctx, req, resp, state.Numeric)...)
+
+	// A new version, or a version that no longer holds the generated value, e.g. after a restore from backup, is
+	// drift
+	reason, diags := readValueDrift(ctx, req, resp, state.Version, version, value)
+	resp.Diagnostics.Append(diags...)
+	state.Version = types.StringValue(version)
+	if reason != "" {
+		resp.Diagnostics.Append(recordDrift(ctx, resp.Private, state.OnDrift, reason, version)...)
+	}
+
+	switch {
+	case reason == regenerateDrift && OnDrift(state.OnDrift.ValueString()) != OnDriftIgnore:
+		// The next apply generates a new value, which updates the hash
+	case value != nil:
+		// This also fills the hash of state written before result_hash existed
+		state.ResultHash = types.StringValue(azrandom.HashSecretValue(*value))
+	case reason == regenerateDrift:
+		// The adopted version is disabled, so its value cannot be read
+		state.ResultHash = types.StringNull()
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -274,118 +1166,529 @@ func (r *stringResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 }
 
-func createString(plan stringModelV0) ([]byte, error) {
-	params := random.StringParams{
-		Length:          plan.Length.ValueInt64(),
-		Upper:           plan.Upper.ValueBool(),
-		MinUpper:        plan.MinUpper.ValueInt64(),
-		Lower:           plan.Lower.ValueBool(),
-		MinLower:        plan.MinLower.ValueInt64(),
-		Numeric:         plan.Numeric.ValueBool(),
-		MinNumeric:      plan.MinNumeric.ValueInt64(),
-		Special:         plan.Special.ValueBool(),
-		MinSpecial:      plan.MinSpecial.ValueInt64(),
-		OverrideSpecial: plan.OverrideSpecial.ValueString(),
+// stringPresetValues returns the attributes of model that a preset fills, by name.
+func stringPresetValues(model *stringModelV3) map[string]attr.Value {
+	return map[string]attr.Value{
+		"length":           model.Length,
+		"upper":            model.Upper,
+		"lower":            model.Lower,
+		"numeric":          model.Numeric,
+		"special":          model.Special,
+		"min_upper":        model.MinUpper,
+		"min_lower":        model.MinLower,
+		"min_numeric":      model.MinNumeric,
+		"min_special":      model.MinSpecial,
+		"override_special": model.OverrideSpecial,
+		"first_char_alpha": model.FirstCharAlpha,
+	}
+}
+
+// fillStringPreset sets the attributes of model that are filled by a preset, and for which omitted returns
+// true, to the values of preset.
+func fillStringPreset(model *stringModelV3, preset random.StringParams, omitted func(name string) bool) {
+	if omitted("length") {
+		model.Length = types.Int64Value(preset.Length)
+	}
+	if omitted("upper") {
+		model.Upper = types.BoolValue(preset.Upper)
+	}
+	if omitted("lower") {
+		model.Lower = types.BoolValue(preset.Lower)
+	}
+	if omitted("numeric") {
+		model.Numeric = types.BoolValue(preset.Numeric)
+	}
+	if omitted("special") {
+		model.Special = types.BoolValue(preset.Special)
+	}
+	if omitted("min_upper") {
+		model.MinUpper = types.Int64Value(preset.MinUpper)
 	}
+	if omitted("min_lower") {
+		model.MinLower = types.Int64Value(preset.MinLower)
+	}
+	if omitted("min_numeric") {
+		model.MinNumeric = types.Int64Value(preset.MinNumeric)
+	}
+	if omitted("min_special") {
+		model.MinSpecial = types.Int64Value(preset.MinSpecial)
+	}
+	if omitted("override_special") {
+		model.OverrideSpecial = types.StringNull()
+		if preset.OverrideSpecial != "" {
+			model.OverrideSpecial = types.StringValue(preset.OverrideSpecial)
+		}
+	}
+	if omitted("first_char_alpha") {
+		model.FirstCharAlpha = types.BoolValue(preset.FirstCharAlpha)
+	}
+}
 
-	return random.CreateString(params)
+// markStringPresetUnknown marks the attributes of model that are filled by a preset, and for which omitted
+// returns true, as unknown.
+func markStringPresetUnknown(model *stringModelV3, omitted func(name string) bool) {
+	if omitted("length") {
+		model.Length = types.Int64Unknown()
+	}
+	if omitted("upper") {
+		model.Upper = types.BoolUnknown()
+	}
+	if omitted("lower") {
+		model.Lower = types.BoolUnknown()
+	}
+	if omitted("numeric") {
+		model.Numeric = types.BoolUnknown()
+	}
+	if omitted("special") {
+		model.Special = types.BoolUnknown()
+	}
+	if omitted("min_upper") {
+		model.MinUpper = types.Int64Unknown()
+	}
+	if omitted("min_lower") {
+		model.MinLower = types.Int64Unknown()
+	}
+	if omitted("min_numeric") {
+		model.MinNumeric = types.Int64Unknown()
+	}
+	if omitted("min_special") {
+		model.MinSpecial = types.Int64Unknown()
+	}
+	if omitted("override_special") {
+		model.OverrideSpecial = types.StringUnknown()
+	}
+	if omitted("first_char_alpha") {
+		model.FirstCharAlpha = types.BoolUnknown()
+	}
+}
+
+// stringPresetUnknown returns whether any of the attributes of model that a preset fills is unknown.
+func stringPresetUnknown(model *stringModelV3) bool {
+	for _, value := range stringPresetValues(model) {
+		if value.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
+
+func createString(plan *stringModelV3) ([]byte, error) {
+	// Attributes left unknown by a preset or pattern that was unknown during planning
+	values := stringPresetValues(plan)
+	fillStringPreset(plan, random.StringPresets[plan.Preset.ValueString()], func(name string) bool {
+		return values[name].IsUnknown()
+	})
+	if !plan.Pattern.IsNull() {
+		pattern, err := random.ParseStringPattern(plan.Pattern.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		plan.Length = types.Int64Value(pattern.Length())
+	}
+
+	result, err := random.CreateString(stringParams(plan))
+	if err != nil {
+		return nil, err
+	}
+	return encodeStringValue(result, plan.Encoding.ValueString()), nil
+}
+
+// stringIgnoredAttributes returns the attributes that are ignored when deciding whether to generate a new value
+// for a string whose encoding in state is encoding. The encoding of an imported string is unknown, and null in
+// state, so the configured encoding is recorded for it instead.
+func stringIgnoredAttributes(encoding types.String) []string {
+	if encoding.IsNull() {
+		return append(slices.Clone(stringComputedAttributes), "encoding")
+	}
+	return stringComputedAttributes
+}
+
+// stringParams returns the parameters to generate the string of model with.
+func stringParams(model *stringModelV3) random.StringParams {
+	return random.StringParams{
+		Length:            model.Length.ValueInt64(),
+		Upper:             model.Upper.ValueBool(),
+		MinUpper:          model.MinUpper.ValueInt64(),
+		Lower:             model.Lower.ValueBool(),
+		MinLower:          model.MinLower.ValueInt64(),
+		Numeric:           model.Numeric.ValueBool(),
+		MinNumeric:        model.MinNumeric.ValueInt64(),
+		Special:           model.Special.ValueBool(),
+		MinSpecial:        model.MinSpecial.ValueInt64(),
+		OverrideSpecial:   model.OverrideSpecial.ValueString(),
+		Charset:           model.Charset.ValueString(),
+		ExcludeCharacters: model.ExcludeCharacters.ValueString(),
+		FirstCharAlpha:    model.FirstCharAlpha.ValueBool(),
+		Pattern:           model.Pattern.ValueString(),
+		Prefix:            model.Prefix.ValueString(),
+		Suffix:            model.Suffix.ValueString(),
+	}
 }
 
 func (r *stringResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 
-	var plan stringModelV0
+	var plan stringModelV3
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	result, err := createString(plan)
-	if err != nil {
-		resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Update azrandom_string error",
-			"There was an error during generation of a UUID.\n\n"+
-				diagnostics.RetryMsg+
-				fmt.Sprintf("Original Error: %s", err),
-		)
+	var state stringModelV3
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A resource moved from random_string or random_password stores its result in a new secret
+	movedResult, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if moved {
+		r.updateMoved(ctx, client, req, resp, plan, movedResult)
+		return
+	}
+
+	// The plan keeps an imported value that satisfies the configured settings, which are only recorded
+	ignored := stringIgnoredAttributes(state.Encoding)
+	imported, diags := importedState(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if imported == importedKept {
+		ignored = append(slices.Clone(ignored), stringSettingAttributes...)
+	}
+	resp.Diagnostics.Append(clearImported(ctx, resp.Private)...)
+
+	valueChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, ignored...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read marks the value for regeneration, e.g. when drift was detected
+	regenerate, diags := regenerationPlanned(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A restore stores the value of a previous version instead of generating one
+	restore := restorePlanned(plan.RestoreVersion, state.RestoreVersion)
+
+	// Only metadata changed, keep the current value and version
+	if !valueChanged && !regenerate && !restore {
+		stored, err := updateSecretProperties(ctx, client, state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+				"azrandom_string", state.Name.ValueString(), resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		plan.Version = state.Version
+		plan.ResultHash = state.ResultHash
+		plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
 		return
 	}
 
 	name := plan.Name.ValueString()
 
-	version, err := azrandom.UpdateSecret(ctx, r.client, name, string(result))
+	var result []byte
+	if restore {
+		value, diags := restoredValue(ctx, r.providerData, client, "azrandom_string", name, plan.VaultUrl, plan.RestoreVersion.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		result = []byte(value)
+	} else {
+		var err error
+		result, err = createString(&plan)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+		resp.Diagnostics.Append(clearLegacyNumeric(ctx, resp.Private)...)
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, string(result), secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Update azrandom_string error",
-			"Could not update azrandom_string in azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_string", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, string(result))...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Version = types.StringValue(version)
+	plan.ResultHash = types.StringValue(azrandom.HashSecretValue(string(result)))
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
 
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), string(result))...)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(disablePreviousVersions(ctx, client, plan.DisablePreviousVersions, name, version)...)
+	resp.Diagnostics.Append(pruneSecretVersions(ctx, client, plan.MaxVersionsToKeep, name, version)...)
+}
+
+// updateMoved stores moved, the result of the random_string or random_password that the resource was moved
+// from, in the secret of plan. When the generation settings differ from the moved state, e.g. because a longer
+// string was configured, a new string is stored instead.
+func (r *stringResource) updateMoved(ctx context.Context, client *azsecrets.Client, req resource.UpdateRequest, resp *resource.UpdateResponse, plan stringModelV3, moved string) {
+	name := plan.Name.ValueString()
+
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(slices.Clone(stringComputedAttributes), "name")...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result := []byte(moved)
+	if changed {
+		var err error
+		result, err = createString(&plan)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.RandomReadError(err.Error())...)
+			return
+		}
+	}
+
+	version, stored, diags := createMovedSecret(ctx, r.providerData, client, "azrandom_string", plan.VaultUrl, name,
+		plan.ConflictPolicy, string(result), plan.metadata(r.providerData))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, string(result))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.ResultHash = types.StringValue(azrandom.HashSecretValue(string(result)))
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), string(result))...)
+	resp.Diagnostics.Append(clearMovedValue(ctx, resp.Private)...)
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 func (r *stringResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 
-	var state stringModelV0
+	var state stringModelV3
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := azrandom.DeleteSecret(ctx, r.client, state.Name.ValueString())
+	// A resource moved from random_string or random_password has no secret to delete until an apply stored its value
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleted, err := azrandom.DeleteSecret(ctx, client, state.Name.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Delete azrandom_string error",
-			"Could not delete azrandom_string from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.DeleteError("azrandom_string", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
 		return
 	}
+
+	if deleted {
+		resp.Diagnostics.Append(waitForDeletion(ctx, client, state.WaitForDeletion, state.Name.ValueString())...)
+		resp.Diagnostics.Append(purgeOnDestroy(ctx, client, state.PurgeOnDestroy, state.Name.ValueString())...)
+	}
 }
 
 func (r *stringResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	version, err := azrandom.GetSecret(ctx, r.client, req.ID)
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_string", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+	resp.Diagnostics.Append(markImported(ctx, resp.Private)...)
+
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
 	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_string", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	if value == "" {
 		resp.Diagnostics.AddError(
 			"Import azrandom_string error",
-			"Could not read azrandom_string from azrandom storage, unexpected error: "+err.Error(),
+			"The secret "+id+" does not contain a string",
 		)
 		return
 	}
 
-	state := stringModelV0{
-		Name:            types.StringValue(req.ID),
-		Version:         types.StringValue(version),
-		Length:          types.Int64Value(0),
-		Special:         types.BoolValue(true),
-		Upper:           types.BoolValue(true),
-		Lower:           types.BoolValue(true),
-		Numeric:         types.BoolValue(true),
-		MinSpecial:      types.Int64Value(0),
-		MinUpper:        types.Int64Value(0),
-		MinLower:        types.Int64Value(0),
-		MinNumeric:      types.Int64Value(0),
-		OverrideSpecial: types.StringNull(),
-		Keepers:         types.MapNull(types.StringType),
+	// The length counts characters rather than bytes, so that multi-byte characters of a charset count once. The
+	// prefix, suffix and encoding cannot be told from the value, and are counted as characters of the string.
+	state := stringModelV3{
+		Name:              types.StringValue(id),
+		VaultUrl:          vaultUrl,
+		Version:           types.StringValue(version),
+		OverrideSpecial:   types.StringNull(),
+		Charset:           types.StringNull(),
+		ExcludeCharacters: types.StringNull(),
+		FirstCharAlpha:    types.BoolValue(false),
+		Pattern:           types.StringNull(),
+		Encoding:          types.StringNull(),
+		Prefix:            types.StringNull(),
+		Suffix:            types.StringNull(),
+		ResultHash:        types.StringValue(azrandom.HashSecretValue(value)),
+		Keepers:           types.MapNull(types.StringType),
+		Description:       descriptionFromProperties(properties),
+		ContentType:       contentTypeFromProperties(properties),
+		ExpirationDate:    validityTimestamp(properties.Expires, types.StringNull()),
+		NotBefore:         validityTimestamp(properties.NotBefore, types.StringNull()),
+		Enabled:           enabledFromProperties(properties),
+		Tags:              importedTagsFromProperties(properties),
+		TagsAll:           importedTagsFromProperties(properties),
+		ConflictPolicy:    types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:           types.StringValue(OnDriftRegenerate.String()),
+		Preset:            types.StringNull(),
+		RestoreVersion:    types.StringNull(),
+		Length:            types.Int64Value(int64(utf8.RuneCountInString(value))),
+		Special:           types.BoolValue(true),
+		Upper:             types.BoolValue(true),
+		Lower:             types.BoolValue(true),
+		Numeric:           types.BoolValue(true),
+		Number:            types.BoolNull(),
+		MinSpecial:        types.Int64Value(0),
+		MinUpper:          types.Int64Value(0),
+		MinLower:          types.Int64Value(0),
+		MinNumeric:        types.Int64Value(0),
 	}
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
 
-	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+// stringSchemaV0 returns the attributes of schema version 0.
+func stringSchemaV0() schema.Schema {
+	attributes := priorSecretAttributes()
+	attributes["preset"] = schema.StringAttribute{
+		Optional: true,
+	}
+	attributes["length"] = schema.Int64Attribute{
+		Optional: true,
+		Computed: true,
+	}
+	for _, name := range []string{"special", "upper", "lower", "numeric"} {
+		attributes[name] = schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+		}
+	}
+	for _, name := range []string{"min_numeric", "min_upper", "min_lower", "min_special"} {
+		attributes[name] = schema.Int64Attribute{
+			Optional: true,
+			Computed: true,
+		}
+	}
+	attributes["override_special"] = schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+	}
+	attributes["disable_previous_versions"] = schema.BoolAttribute{
+		Optional: true,
+	}
+	attributes["max_versions_to_keep"] = schema.Int64Attribute{
+		Optional: true,
+	}
+
+	return schema.Schema{
+		Attributes: attributes,
+	}
+}
+
+// stringSchemaV1 returns the attributes of schema version 1, which added charset, exclude_characters, number and
+// restore_version.
+func stringSchemaV1() schema.Schema {
+	prior := stringSchemaV0()
+	attributes := prior.Attributes
+	attributes["charset"] = schema.StringAttribute{
+		Optional: true,
+	}
+	attributes["exclude_characters"] = schema.StringAttribute{
+		Optional: true,
+	}
+	attributes["number"] = schema.BoolAttribute{
+		Optional: true,
+	}
+	attributes["restore_version"] = schema.StringAttribute{
+		Optional: true,
+	}
+
+	return schema.Schema{
+		Version:    1,
+		Attributes: attributes,
+	}
+}
+
+// stringSchemaV2 returns the attributes of schema version 2, which added first_char_alpha and pattern.
+func stringSchemaV2() schema.Schema {
+	prior := stringSchemaV1()
+	attributes := prior.Attributes
+	attributes["first_char_alpha"] = schema.BoolAttribute{
+		Optional: true,
+		Computed: true,
+	}
+	attributes["pattern"] = schema.StringAttribute{
+		Optional: true,
+	}
+
+	return schema.Schema{
+		Version:    2,
+		Attributes: attributes,
+	}
+}
@@ -6,37 +6,117 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	azrandom "terraform-provider-azrandom/client"
 	"terraform-provider-azrandom/internal/diagnostics"
-	"terraform-provider-azrandom/internal/utils"
+	"terraform-provider-azrandom/internal/random"
+	"terraform-provider-azrandom/internal/validators"
 )
 
 var (
-	_ resource.Resource                = (*uuidResource)(nil)
-	_ resource.ResourceWithImportState = (*uuidResource)(nil)
+	_ resource.Resource                   = (*uuidResource)(nil)
+	_ resource.ResourceWithIdentity       = (*uuidResource)(nil)
+	_ resource.ResourceWithImportState    = (*uuidResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*uuidResource)(nil)
+	_ resource.ResourceWithMoveState      = (*uuidResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*uuidResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*uuidResource)(nil)
 )
 
+// The values of uuid_version.
+const (
+	uuidVersion4 = "v4"
+	uuidVersion5 = "v5"
+	uuidVersion7 = "v7"
+)
+
+// uuidComputedAttributes are unknown in the plan whenever anything changes, conflict_policy is only used by
+// Create, and on_drift only after drift was detected, so they are ignored when deciding whether to generate
+// a new value.
+var uuidComputedAttributes = []string{
+	"version",
+	"conflict_policy",
+	"on_drift",
+}
+
+// uuidShapeAttributes only change how the UUID is written, so changing only them stores the same UUID again
+// instead of generating a new one.
+var uuidShapeAttributes = []string{
+	"format",
+	"uppercase",
+	"prefix",
+}
+
 func NewUuidResource() resource.Resource {
 	return &uuidResource{}
 }
 
-type uuidModelV0 struct {
-	Name    types.String `tfsdk:"name"`
-	Version types.String `tfsdk:"version"`
-	Keepers types.Map    `tfsdk:"keepers"`
+type uuidModelV3 struct {
+	Name            types.String `tfsdk:"name"`
+	VaultUrl        types.String `tfsdk:"vault_url"`
+	Version         types.String `tfsdk:"version"`
+	Keepers         types.Map    `tfsdk:"keepers"`
+	Description     types.String `tfsdk:"description"`
+	ContentType     types.String `tfsdk:"content_type"`
+	ExpirationDate  types.String `tfsdk:"expiration_date"`
+	NotBefore       types.String `tfsdk:"not_before"`
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	Tags            types.Map    `tfsdk:"tags"`
+	TagsAll         types.Map    `tfsdk:"tags_all"`
+	VerifyWrite     types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy  types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion types.Bool   `tfsdk:"wait_for_deletion"`
+	ConflictPolicy  types.String `tfsdk:"conflict_policy"`
+	OnDrift         types.String `tfsdk:"on_drift"`
+	UUIDVersion     types.String `tfsdk:"uuid_version"`
+	Namespace       types.String `tfsdk:"namespace"`
+	NameInput       types.String `tfsdk:"name_input"`
+	Format          types.String `tfsdk:"format"`
+	Uppercase       types.Bool   `tfsdk:"uppercase"`
+	Prefix          types.String `tfsdk:"prefix"`
+	CreatedOn       types.String `tfsdk:"created_on"`
+	UpdatedOn       types.String `tfsdk:"updated_on"`
+	CreatedDate     types.String `tfsdk:"created_date"`
+	UpdatedDate     types.String `tfsdk:"updated_date"`
+}
+
+// storedValue returns canonical, a UUID in canonical lowercase form, as it is stored in the secret: after the
+// prefix of m, and in its format and case.
+func (m uuidModelV3) storedValue(canonical string) string {
+	return m.Prefix.ValueString() + formatUUIDValue(canonical, m.Format.ValueString(), m.Uppercase.ValueBool())
+}
+
+// metadata returns the attributes that are stored as properties of the secret, along with the tags
+// written by the provider.
+func (m uuidModelV3) metadata(data *azrandomProviderData) secretMetadata {
+	return secretMetadata{
+		Description:    m.Description,
+		TagsAll:        m.TagsAll,
+		ContentType:    m.ContentType,
+		ExpirationDate: m.ExpirationDate,
+		NotBefore:      m.NotBefore,
+		Enabled:        m.Enabled,
+		ProviderTags:   providerMetadataTags(data),
+	}
 }
 
 type uuidResource struct {
-	client *azsecrets.Client
+	providerData *azrandomProviderData
 }
 
 // Configure adds the provider configured client to the resource.
@@ -45,18 +125,18 @@ func (r *uuidResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	client, ok := req.ProviderData.(*azsecrets.Client)
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *azsecrets.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.providerData = providerData
 }
 
 func (r *uuidResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -65,11 +145,21 @@ func (r *uuidResource) Metadata(_ context.Context, req resource.MetadataRequest,
 
 func (r *uuidResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 3,
 		Description: "The resource `azrandom_uuid` generates a random uuid string that is intended to be " +
 			"used as a unique identifier for other resources.\n" +
 			"\n" +
 			"This resource uses [hashicorp/go-uuid](https://github.com/hashicorp/go-uuid) to generate a " +
-			"UUID-formatted string for use with services needing a unique string identifier.\n" +
+			"UUID-formatted string for use with services needing a unique string identifier, or generates a " +
+			"time-ordered version 7 UUID when `uuid_version` is `v7`. When `namespace` and `name_input` are set, " +
+			"the value is instead the version 5 UUID derived from them, so the same inputs always store the same " +
+			"value.\n" +
+			"\n" +
+			"The UUID is stored in the shape given by `format` and `uppercase`, e.g. without hyphens or as a URN, " +
+			"after `prefix` when it is set.\n" +
+			"\n" +
+			"A `random_uuid` of the `hashicorp/random` provider can be moved into an `azrandom_uuid` with a " +
+			"`moved` block, which keeps its UUID: the next apply stores it in the configured secret.\n" +
 			"\n" +
 			"Finally, the generated string is stored in a remote vault",
 		Attributes: map[string]schema.Attribute{
@@ -81,24 +171,284 @@ func (r *uuidResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				Optional:    true,
 			},
 
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+
+			"conflict_policy": conflictPolicyAttribute(),
+			"on_drift":        onDriftAttribute(),
+
+			"uuid_version": schema.StringAttribute{
+				Description: fmt.Sprintf("The version of the generated UUID. `%s` generates a random UUID, and `%s` a "+
+					"UUID whose first 48 bits hold the time of generation in milliseconds, so that values generated "+
+					"later sort after earlier ones, e.g. for database keys. `%s` is the name-based UUID of `namespace` "+
+					"and `name_input`, and is used whenever they are set. Changing it generates a new value. "+
+					"Default value is `%s`, or `%s` when `namespace` is set.",
+					uuidVersion4, uuidVersion7, uuidVersion5, uuidVersion4, uuidVersion5),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(uuidVersion4),
+				Validators: []validator.String{
+					stringvalidator.OneOf(uuidVersion4, uuidVersion5, uuidVersion7),
+				},
+			},
+
+			"namespace": schema.StringAttribute{
+				Description: "The namespace of the name-based version 5 UUID to store, itself a UUID, such as " +
+					"`6ba7b810-9dad-11d1-80b4-00c04fd430c8` for DNS names. Requires `name_input`. Changing it " +
+					"generates a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.UUID(),
+					stringvalidator.AlsoRequires(path.MatchRoot("name_input")),
+				},
+			},
+
+			"name_input": schema.StringAttribute{
+				Description: "The name from which the version 5 UUID to store is derived within `namespace`. The same " +
+					"`namespace` and `name_input` always derive the same UUID, also when a new value is generated after " +
+					"drift. Requires `namespace`. Changing it generates a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("namespace")),
+				},
+			},
+
+			"format":    uuidFormatAttribute(),
+			"uppercase": uuidUppercaseAttribute(),
+			"prefix":    uuidPrefixAttribute(),
+
 			"version": schema.StringAttribute{
 				Description: "The version to the secret under which the generated value was stored ",
 				Computed:    true,
 			},
 
+			"created_on": createdOnAttribute(),
+
+			"updated_on": updatedOnAttribute(),
+
+			"created_date": createdDateAttribute(),
+
+			"updated_date": updatedDateAttribute(),
+
 			"name": schema.StringAttribute{
 				Description: "The name of the secret where the generated value should be stored",
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					nameRequiresReplace(),
 				},
 			},
 		},
 	}
 }
 
+func (r *uuidResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// UpgradeState upgrades state of schema versions 0 to 2. Version 1 only changed the defaults that the upgrade
+// fills, so versions 0 and 1 have the same attributes.
+func (r *uuidResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV1 := uuidSchemaV1()
+	schemaV2 := uuidSchemaV2()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV1,
+			StateUpgrader: upgradeUUIDState,
+		},
+		1: {
+			PriorSchema:   &schemaV1,
+			StateUpgrader: upgradeUUIDState,
+		},
+		2: {
+			PriorSchema:   &schemaV2,
+			StateUpgrader: upgradeUUIDState,
+		},
+	}
+}
+
+// upgradeUUIDState sets the settings that were added after the resource was created, which are null in its
+// state, to their default values, so that they are not planned as an update. Values generated before
+// uuid_version existed are version 4 UUIDs, and those generated before format existed are canonical and
+// lowercase.
+func upgradeUUIDState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state uuidModelV3
+
+	copyPriorState(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.ConflictPolicy.IsNull() {
+		state.ConflictPolicy = types.StringValue(ConflictPolicyFail.String())
+	}
+	if state.OnDrift.IsNull() {
+		state.OnDrift = types.StringValue(OnDriftRegenerate.String())
+	}
+	if state.UUIDVersion.IsNull() {
+		state.UUIDVersion = types.StringValue(uuidVersion4)
+	}
+	if state.Format.IsNull() {
+		state.Format = types.StringValue(uuidFormatCanonical)
+	}
+	if state.Uppercase.IsNull() {
+		state.Uppercase = types.BoolValue(false)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// MoveState moves the state of a random_uuid of the hashicorp/random provider into an azrandom_uuid.
+func (r *uuidResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: moveUUIDState,
+		},
+	}
+}
+
+// randomUUIDState holds the attributes of the state of a random_uuid that an azrandom_uuid is moved with.
+type randomUUIDState struct {
+	Keepers map[string]*string `json:"keepers"`
+	Result  string             `json:"result"`
+}
+
+// moveUUIDState takes the keepers and the UUID of a random_uuid into state, with the other settings at their
+// defaults. The resource has no secret until the next apply, which stores the UUID in the configured secret.
+func moveUUIDState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if !movedFrom(req, randomProviderSource, "random_uuid") {
+		return
+	}
+
+	var source randomUUIDState
+	resp.Diagnostics.Append(decodeMovedState(req, &source)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	canonical, format, uppercase, ok := parseUUIDValue(source.Result)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf("The result of the random_uuid, %q, is not a UUID.", source.Result),
+		)
+		return
+	}
+
+	keepers, diags := movedKeepers(ctx, source.Keepers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := uuidModelV3{
+		Name:           types.StringNull(),
+		VaultUrl:       types.StringNull(),
+		Version:        types.StringNull(),
+		Keepers:        keepers,
+		ContentType:    types.StringValue(contentTypeText),
+		Enabled:        types.BoolValue(true),
+		Tags:           types.MapNull(types.StringType),
+		TagsAll:        types.MapNull(types.StringType),
+		ConflictPolicy: types.StringValue(ConflictPolicyFail.String()),
+		OnDrift:        types.StringValue(OnDriftRegenerate.String()),
+		UUIDVersion:    types.StringValue(importedUUIDVersion(canonical)),
+		Format:         types.StringValue(format),
+		Uppercase:      types.BoolValue(uppercase),
+		Prefix:         types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(storeMovedValue(ctx, resp.TargetPrivate, canonical)...)
+	resp.Diagnostics.Append(resp.TargetState.Set(ctx, state)...)
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+// A configured namespace plans uuid_version as v5 when it is omitted.
+func (r *uuidResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_uuid", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var uuidVersion, namespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("uuid_version"), &uuidVersion)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if uuidVersion.IsNull() && !namespace.IsNull() {
+		planned := types.StringValue(uuidVersion5)
+		if namespace.IsUnknown() {
+			planned = types.StringUnknown()
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uuid_version"), planned)...)
+	}
+
+	planVersion(ctx, req, resp, uuidComputedAttributes...)
+}
+
+// ValidateConfig ensures that uuid_version is v5 exactly when namespace is set.
+func (r *uuidResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var uuidVersion, namespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("uuid_version"), &uuidVersion)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	if resp.Diagnostics.HasError() || uuidVersion.IsNull() || uuidVersion.IsUnknown() || namespace.IsUnknown() {
+		return
+	}
+
+	switch {
+	case !namespace.IsNull() && uuidVersion.ValueString() != uuidVersion5:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("uuid_version"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attribute \"uuid_version\" must be %q or omitted when \"namespace\" is set, but is %q.",
+				uuidVersion5, uuidVersion.ValueString()),
+		)
+	case namespace.IsNull() && uuidVersion.ValueString() == uuidVersion5:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("uuid_version"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("The attribute \"uuid_version\" may only be %q when \"namespace\" and \"name_input\" are set.",
+				uuidVersion5),
+		)
+	}
+}
+
 func (r *uuidResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	result, err := uuid.GenerateUUID()
+	var plan uuidModelV3
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result, err := generateUUID(plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Create azrandom_uuid error",
@@ -109,41 +459,100 @@ func (r *uuidResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	var plan uuidModelV0
-
-	diags := req.Plan.Get(ctx, &plan)
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
 	name := plan.Name.ValueString()
 
+	conflictPolicy := ConflictPolicy(plan.ConflictPolicy.ValueString())
+
+	// Take the existing secret into state instead of generating a value
+	if conflictPolicy == ConflictPolicyAdopt {
+		version, found, err := adoptExistingSecret(ctx, client, name, azrandom.DefaultRecoveryBackoff)
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "adopt the existing secret",
+				"azrandom_uuid", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		if found {
+			stored, err := updateSecretProperties(ctx, client, name, version, plan.metadata(r.providerData))
+			if err != nil {
+				resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "update the properties of the secret",
+					"azrandom_uuid", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+				return
+			}
+
+			plan.Version = types.StringValue(version)
+			plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+			resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+			diags = resp.State.Set(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
 	// Check if secret exists yet
-	secretExists, err := azrandom.SecretExists(ctx, r.client, name)
-	if secretExists {
+	secretExists, err := secretExistsBeforeCreate(ctx, r.providerData, client, name)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationCreate, "check whether the secret already exists",
+			"azrandom_uuid", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+	if secretExists && conflictPolicy == ConflictPolicyFail {
 		resp.Diagnostics.AddError(
 			"Create azrandom_uuid error",
-			"A azrandom_uuid with name  "+name+" already exists. To manage this in terraform you must import it"+err.Error(),
+			existingSecretMessage(ctx, client, "azrandom_uuid", name, name)+
+				"\n\nAlternatively, set conflict_policy to \"overwrite\" or \"adopt\".",
 		)
 		return
 	}
 
-	version, err := azrandom.CreateSecret(ctx, r.client, name, result)
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, result, secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Create azrandom_uuid error",
-			"Could not read azrandom_uuid from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_uuid", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, result)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	u := &uuidModelV0{
-		Version: types.StringValue(version),
-		Name:    types.StringValue(name),
-		Keepers: plan.Keepers,
+	u := &uuidModelV3{
+		Version:         types.StringValue(version),
+		Name:            types.StringValue(name),
+		VaultUrl:        plan.VaultUrl,
+		Keepers:         plan.Keepers,
+		Description:     plan.Description,
+		Tags:            plan.Tags,
+		TagsAll:         plan.TagsAll,
+		ContentType:     plan.ContentType,
+		ExpirationDate:  plan.ExpirationDate,
+		NotBefore:       plan.NotBefore,
+		Enabled:         plan.Enabled,
+		ConflictPolicy:  plan.ConflictPolicy,
+		OnDrift:         plan.OnDrift,
+		UUIDVersion:     plan.UUIDVersion,
+		Namespace:       plan.Namespace,
+		NameInput:       plan.NameInput,
+		Format:          plan.Format,
+		Uppercase:       plan.Uppercase,
+		Prefix:          plan.Prefix,
+		VerifyWrite:     plan.VerifyWrite,
+		PurgeOnDestroy:  plan.PurgeOnDestroy,
+		WaitForDeletion: plan.WaitForDeletion,
 	}
+	u.CreatedOn, u.UpdatedOn, u.CreatedDate, u.UpdatedDate = aliasedTimestampsFromProperties(stored)
 
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, u.Version.ValueString(), result)...)
 	diags = resp.State.Set(ctx, u)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -153,28 +562,75 @@ func (r *uuidResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 func (r *uuidResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 
-	var state uuidModelV0
+	var state uuidModelV3
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	version, err := azrandom.GetSecret(ctx, r.client, state.Name.ValueString())
+	// A resource moved from random_uuid has no secret until the next apply stores its value
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Resources created before they had an identity get it on refresh
+	if resp.Identity.Raw.IsNull() {
+		resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	}
+
+	pinned, diags := pinnedVersion(ctx, req.Private, state.Version)
+	resp.Diagnostics.Append(diags...)
+	version, properties, value, err := azrandom.GetSecretWithValue(ctx, client, state.Name.ValueString(), pinned)
+	if secretDeletedOutOfBand(ctx, "azrandom_uuid", state.Name.ValueString(), err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Read azrandom_uuid error",
-			"Could not read azrandom_uuid from azrandom storeage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.ReadError("azrandom_uuid", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
 		return
 	}
 
-	// If version number has changed we know that drift has occurred.
-	if state.Version.ValueString() != version {
-		state.Version = types.StringValue(version)
-		keepers, _ := utils.GenerateDriftKeepers(ctx)
-		state.Keepers = keepers
+	state.Description = descriptionFromProperties(properties)
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, state.ExpirationDate)
+	state.NotBefore = validityTimestamp(properties.NotBefore, state.NotBefore)
+	resp.Diagnostics.Append(disabledSecretDiagnostics(state.Name.ValueString(), state.Enabled, properties)...)
+	state.Enabled = enabledFromProperties(properties)
+	resp.Diagnostics.Append(expiredSecretDiagnostics(state.Name.ValueString(), properties)...)
+	state.TagsAll = tagsFromProperties(properties, state.TagsAll)
+	state.Tags, diags = resourceTags(r.providerData, state.TagsAll, state.Tags)
+	resp.Diagnostics.Append(diags...)
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
 
+	// A new version, or a version that no longer holds the generated value, e.g. after a restore from backup, is
+	// drift
+	reason, diags := readValueDrift(ctx, req, resp, state.Version, version, value)
+	resp.Diagnostics.Append(diags...)
+	state.Version = types.StringValue(version)
+	if reason != "" {
+		resp.Diagnostics.Append(recordDrift(ctx, resp.Private, state.OnDrift, reason, version)...)
+	}
+
+	// With verify_write, the value itself is checked as well
+	if verifyWriteEnabled(r.providerData, state.VerifyWrite) {
+		if value == nil {
+			tflog.Debug(ctx, "Not checking the value of disabled secret", map[string]any{"name": state.Name.ValueString()})
+		} else if detail := invalidUUIDDetail(state.Name.ValueString(), version, *value, state.Prefix.ValueString()); detail != "" {
+			resp.Diagnostics.AddWarning("Secret does not hold a UUID", detail)
+		}
 	}
 
 	diags = resp.State.Set(ctx, state)
@@ -184,39 +640,170 @@ func (r *uuidResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 }
 
+// generateUUID returns a new UUID of the uuid_version of model, as it is stored in the secret. Version 5 UUIDs
+// are derived from the namespace and name_input of model, so generating them again returns the same value.
+func generateUUID(model uuidModelV3) (string, error) {
+	var canonical string
+	var err error
+	switch model.UUIDVersion.ValueString() {
+	case uuidVersion5:
+		canonical, err = random.CreateUUIDv5(model.Namespace.ValueString(), model.NameInput.ValueString())
+	case uuidVersion7:
+		canonical, err = random.CreateUUIDv7(time.Now())
+	default:
+		canonical, err = uuid.GenerateUUID()
+	}
+	if err != nil {
+		return "", err
+	}
+	return model.storedValue(canonical), nil
+}
+
+// importedUUIDVersion returns the uuid_version that generates UUIDs like value, a UUID in canonical form, as told
+// by its version digit.
+func importedUUIDVersion(value string) string {
+	if len(value) > 14 {
+		switch value[14] {
+		case '5':
+			return uuidVersion5
+		case '7':
+			return uuidVersion7
+		}
+	}
+	return uuidVersion4
+}
+
+// invalidUUIDDetail describes why the given version of the secret name, which holds value, was not generated by
+// an azrandom_uuid with the given prefix, or returns an empty string when value is prefix followed by a UUID in
+// any of the formats of azrandom_uuid.
+func invalidUUIDDetail(name string, version string, value string, prefix string) string {
+	if _, _, _, ok := parseUUIDValue(strings.TrimPrefix(value, prefix)); ok && strings.HasPrefix(value, prefix) {
+		return ""
+	}
+
+	held := "an RFC 4122 UUID"
+	if prefix != "" {
+		held = fmt.Sprintf("an RFC 4122 UUID after the prefix %q", prefix)
+	}
+	return fmt.Sprintf("Version %s of secret %q does not hold %s, so it was not generated by an "+
+		"azrandom_uuid. The value is kept until a new value is generated, e.g. when the keepers change, which "+
+		"overwrites it with a UUID.", version, name, held)
+}
+
 func (r *uuidResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 
-	var plan uuidModelV0
+	var plan, state uuidModelV3
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	result, err := uuid.GenerateUUID()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Update azrandom_uuid error",
-			"There was an error during generation of a UUID.\n\n"+
-				diagnostics.RetryMsg+
-				fmt.Sprintf("Original Error: %s", err),
-		)
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A resource moved from random_uuid stores its UUID in a new secret
+	movedUUID, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if moved {
+		r.updateMoved(ctx, client, req, resp, plan, movedUUID)
+		return
+	}
+
+	valueChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, uuidComputedAttributes...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read marks the value for regeneration, e.g. when drift was detected
+	regenerate, diags := regenerationPlanned(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only metadata changed, keep the current value and version
+	if !valueChanged && !regenerate {
+		stored, err := updateSecretProperties(ctx, client, state.Name.ValueString(), state.Version.ValueString(), plan.metadata(r.providerData))
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+				"azrandom_uuid", state.Name.ValueString(), resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
 		return
 	}
 
 	name := plan.Name.ValueString()
 
-	version, err := azrandom.UpdateSecret(ctx, r.client, name, result)
+	// When only format, uppercase or prefix changed, the current UUID is stored again in its new shape
+	otherChanged, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(uuidShapeAttributes, uuidComputedAttributes...)...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var result string
+	if !otherChanged && !regenerate {
+		current, err := azrandom.GetSecretValue(ctx, client, name, state.Version.ValueString())
+		if err != nil {
+			resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the value to change its format",
+				"azrandom_uuid", name, resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+			return
+		}
+		canonical, _, _, ok := parseUUIDValue(strings.TrimPrefix(current, state.Prefix.ValueString()))
+		if ok && strings.HasPrefix(current, state.Prefix.ValueString()) {
+			result = plan.storedValue(canonical)
+		} else {
+			tflog.Debug(ctx, "Generating a new value, as the secret does not hold a UUID to change the format of", map[string]any{"name": name})
+		}
+	}
+
+	if result == "" {
+		var err error
+		result, err = generateUUID(plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Update azrandom_uuid error",
+				"There was an error during generation of a UUID.\n\n"+
+					diagnostics.RetryMsg+
+					fmt.Sprintf("Original Error: %s", err),
+			)
+			return
+		}
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, result, secretProperties(plan.metadata(r.providerData), nil))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Update azrandom_uuid error",
-			"Could not update azrandom_uuid in azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_uuid", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, result)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
 
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), result)...)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -224,46 +811,187 @@ func (r *uuidResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 }
 
+// updateMoved stores moved, the UUID of the random_uuid that the resource was moved from, in the secret of plan.
+// Only the shape of the UUID may differ from the moved state, e.g. its format; when other settings, such as
+// the keepers, differ, a new UUID is stored instead.
+func (r *uuidResource) updateMoved(ctx context.Context, client *azsecrets.Client, req resource.UpdateRequest, resp *resource.UpdateResponse, plan uuidModelV3, moved string) {
+	name := plan.Name.ValueString()
+
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(slices.Concat(uuidShapeAttributes, uuidComputedAttributes), "name")...)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result := plan.storedValue(moved)
+	if changed {
+		var err error
+		result, err = generateUUID(plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Update azrandom_uuid error",
+				"There was an error during generation of a UUID.\n\n"+
+					diagnostics.RetryMsg+
+					fmt.Sprintf("Original Error: %s", err),
+			)
+			return
+		}
+	}
+
+	version, stored, diags := createMovedSecret(ctx, r.providerData, client, "azrandom_uuid", plan.VaultUrl, name,
+		plan.ConflictPolicy, result, plan.metadata(r.providerData))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn, plan.CreatedDate, plan.UpdatedDate = aliasedTimestampsFromProperties(stored)
+
+	resp.Diagnostics.Append(storeWrittenValue(ctx, resp.Private, plan.Version.ValueString(), result)...)
+	resp.Diagnostics.Append(clearMovedValue(ctx, resp.Private)...)
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
 func (r *uuidResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 
-	var state uuidModelV0
+	var state uuidModelV3
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := azrandom.DeleteSecret(ctx, r.client, state.Name.ValueString())
+	// A resource moved from random_uuid has no secret to delete until an apply stored its value
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, state.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleted, err := azrandom.DeleteSecret(ctx, client, state.Name.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Delete azrandom_uuid error",
-			"Could not delete azrandom_uuid from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.DeleteError("azrandom_uuid", state.Name.ValueString(),
+			resourceVaultUrl(r.providerData, state.VaultUrl), err)...)
 		return
 	}
+
+	if deleted {
+		resp.Diagnostics.Append(waitForDeletion(ctx, client, state.WaitForDeletion, state.Name.ValueString())...)
+		resp.Diagnostics.Append(purgeOnDestroy(ctx, client, state.PurgeOnDestroy, state.Name.ValueString())...)
+	}
 }
 
 func (r *uuidResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	version, err := azrandom.GetSecret(ctx, r.client, req.ID)
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Import azrandom_uuid error",
-			"Could not read azrandom_uuid from azrandom storage, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_uuid", id, resourceVaultUrl(r.providerData, vaultUrl), err)...)
 		return
 	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
 
-	var state uuidModelV0
+	value, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_uuid", id, resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	prefix, canonical, format, uppercase, isUUID := splitUUIDValue(value)
+	if detail := invalidUUIDDetail(id, version, value, prefix); detail != "" {
+		if r.providerData.strictImport {
+			resp.Diagnostics.AddError("Secret does not hold a UUID",
+				detail+"\n\nSet strict_import on the provider to false to import it nonetheless.")
+			return
+		}
+		resp.Diagnostics.AddWarning("Secret does not hold a UUID",
+			detail+"\n\nSet strict_import on the provider to true to fail such imports instead.")
+	}
 
-	state.Name = types.StringValue(req.ID)
+	var state uuidModelV3
+
+	state.Name = types.StringValue(id)
+	state.VaultUrl = vaultUrl
 	state.Version = types.StringValue(version)
 	state.Keepers = types.MapNull(types.StringType)
+	state.Description = descriptionFromProperties(properties)
+	state.ContentType = contentTypeFromProperties(properties)
+	state.ExpirationDate = validityTimestamp(properties.Expires, types.StringNull())
+	state.NotBefore = validityTimestamp(properties.NotBefore, types.StringNull())
+	state.Enabled = enabledFromProperties(properties)
+	state.Tags = importedTagsFromProperties(properties)
+	state.TagsAll = state.Tags
+	state.CreatedOn, state.UpdatedOn, state.CreatedDate, state.UpdatedDate = aliasedTimestampsFromProperties(properties)
+	state.ConflictPolicy = types.StringValue(ConflictPolicyFail.String())
+	state.OnDrift = types.StringValue(OnDriftRegenerate.String())
+	state.UUIDVersion = types.StringValue(uuidVersion4)
+	state.Format = types.StringValue(uuidFormatCanonical)
+	state.Uppercase = types.BoolValue(false)
+	state.Prefix = types.StringNull()
+	if isUUID {
+		state.UUIDVersion = types.StringValue(importedUUIDVersion(canonical))
+		state.Format = types.StringValue(format)
+		state.Uppercase = types.BoolValue(uppercase)
+		if prefix != "" {
+			state.Prefix = types.StringValue(prefix)
+		}
+	}
 
-	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+// uuidSchemaV1 returns the attributes of schema versions 0 and 1.
+func uuidSchemaV1() schema.Schema {
+	return schema.Schema{
+		Version:    1,
+		Attributes: priorSecretAttributes(),
+	}
+}
+
+// uuidSchemaV2 returns the attributes of schema version 2, which added uuid_version, namespace and name_input.
+func uuidSchemaV2() schema.Schema {
+	attributes := priorSecretAttributes()
+	attributes["uuid_version"] = schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+	}
+	attributes["namespace"] = schema.StringAttribute{
+		Optional: true,
+	}
+	attributes["name_input"] = schema.StringAttribute{
+		Optional: true,
+	}
+
+	return schema.Schema{
+		Version:    2,
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/random"
+)
+
+var (
+	_ resource.Resource                   = (*weightedChoiceResource)(nil)
+	_ resource.ResourceWithIdentity       = (*weightedChoiceResource)(nil)
+	_ resource.ResourceWithImportState    = (*weightedChoiceResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*weightedChoiceResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*weightedChoiceResource)(nil)
+)
+
+// weightedChoiceComputedAttributes are unknown in the plan whenever anything changes.
+var weightedChoiceComputedAttributes = []string{
+	"version",
+	"result",
+}
+
+func NewWeightedChoiceResource() resource.Resource {
+	return &weightedChoiceResource{}
+}
+
+type weightedChoiceModelV0 struct {
+	secretMetadataModel
+
+	Name                 types.String `tfsdk:"name"`
+	VaultUrl             types.String `tfsdk:"vault_url"`
+	Version              types.String `tfsdk:"version"`
+	Keepers              types.Map    `tfsdk:"keepers"`
+	VerifyWrite          types.Bool   `tfsdk:"verify_write"`
+	PurgeOnDestroy       types.Bool   `tfsdk:"purge_on_destroy"`
+	WaitForDeletion      types.Bool   `tfsdk:"wait_for_deletion"`
+	Options              types.Map    `tfsdk:"options"`
+	RepickOnWeightChange types.Bool   `tfsdk:"repick_on_weight_change"`
+	Result               types.String `tfsdk:"result"`
+	CreatedOn            types.String `tfsdk:"created_on"`
+	UpdatedOn            types.String `tfsdk:"updated_on"`
+}
+
+type weightedChoiceResource struct {
+	providerData *azrandomProviderData
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *weightedChoiceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*azrandomProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azrandomProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *weightedChoiceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_weighted_choice"
+}
+
+func (r *weightedChoiceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The resource `azrandom_weighted_choice` picks one of a set of options, with a probability " +
+			"proportional to the weight of each option, and stores the choice in a azrandom vault. This can be used " +
+			"to persist the assignment of an environment in a gradual rollout.\n" +
+			"\n" +
+			"The choice is kept when weights change, unless `repick_on_weight_change` is `true`. A new choice is " +
+			"made when `keepers` change, or when the chosen option is removed or its weight set to `0`.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the secret where the generated value should be stored",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "The version to the secret under which the generated value was stored ",
+				Computed:    true,
+			},
+			"created_on":        createdOnAttribute(),
+			"updated_on":        updatedOnAttribute(),
+			"verify_write":      verifyWriteAttribute(),
+			"purge_on_destroy":  purgeOnDestroyAttribute(),
+			"wait_for_deletion": waitForDeletionAttribute(),
+			"vault_url":         vaultUrlAttribute(),
+			"description": schema.StringAttribute{
+				Description: "A description of the secret, stored in the `azrandom:description` tag of the secret. " +
+					"Changing the description does not generate a new value.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxTagValueLength),
+				},
+			},
+
+			"content_type":    contentTypeAttribute(contentTypeText),
+			"expiration_date": expirationDateAttribute(),
+			"not_before":      notBeforeAttribute(),
+			"enabled":         enabledAttribute(),
+			"tags_all":        tagsAllAttribute(),
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of resource. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"options": schema.MapAttribute{
+				Description: "A map of option to weight, such as `{ v1 = 80, v2 = 20 }`. Weights may not be " +
+					"negative, and at least one weight must be positive.",
+				ElementType: types.Int64Type,
+				Required:    true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueInt64sAre(int64validator.AtLeast(0)),
+				},
+			},
+			"repick_on_weight_change": schema.BoolAttribute{
+				Description: "Whether changing the weights of `options` makes a new choice. Default value is `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"result": schema.StringAttribute{
+				Description: "The chosen option.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *weightedChoiceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = secretIdentitySchema()
+}
+
+// ValidateConfig ensures that at least one option can be chosen.
+func (r *weightedChoiceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var options types.Map
+	diags := req.Config.GetAttribute(ctx, path.Root("options"), &options)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delay validation until all weights are known
+	if options.IsNull() || !weightedChoiceOptionsKnown(options) {
+		return
+	}
+
+	weights, diags := weightedChoiceWeights(ctx, options)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, weight := range weights {
+		if weight > 0 {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("options"),
+		"Invalid Attribute Value",
+		"At least one option must have a positive weight.",
+	)
+}
+
+// ModifyPlan rejects configurations where another resource already stores its value in the same secret.
+// When the current choice is kept, it is planned as the result attribute together with the current version.
+func (r *weightedChoiceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDuplicateSecretName(ctx, r.providerData, "azrandom_weighted_choice", req, resp)
+	planVaultUrl(ctx, r.providerData, req, resp)
+	planRegeneration(ctx, req, resp)
+	planTagsAll(ctx, r.providerData, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state weightedChoiceModelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed, diags := valueAttributesChanged(req.Plan.Raw, req.State.Raw, append(weightedChoiceComputedAttributes, "repick_on_weight_change")...)
+	resp.Diagnostics.Append(diags...)
+	regenerate, diags := regenerationPlanned(ctx, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	changed = changed || regenerate
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if changed {
+		// Options are not known yet, result stays unknown
+		if !weightedChoiceOptionsKnown(plan.Options) || plan.RepickOnWeightChange.IsUnknown() {
+			return
+		}
+
+		if regenerate || !plan.Keepers.Equal(state.Keepers) {
+			return
+		}
+
+		weights, diags := weightedChoiceWeights(ctx, plan.Options)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if weights[state.Result.ValueString()] < 1 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("options"),
+				"Chosen option removed",
+				fmt.Sprintf("The current choice %q of %s is no longer one of the options, or its weight is 0. "+
+					"A new option will be chosen.", state.Result.ValueString(), plan.Name.ValueString()),
+			)
+			return
+		}
+
+		if plan.RepickOnWeightChange.ValueBool() && !plan.Options.Equal(state.Options) {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("result"), state.Result)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), state.Version)...)
+}
+
+func (r *weightedChoiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	var plan weightedChoiceModelV0
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, plan.VaultUrl, plan.Name)...)
+
+	result, diags := weightedChoiceFromModel(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	resp.Diagnostics.Append(checkSecretsAbsent(ctx, r.providerData, client, "azrandom_weighted_choice", plan.VaultUrl, name, plan.Name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, stored, err := azrandom.CreateSecret(ctx, client, name, result, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.CreateError("azrandom_weighted_choice", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.Result = types.StringValue(result)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *weightedChoiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	var state weightedChoiceModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, version, properties, ok := readSecret(ctx, r.providerData, "azrandom_weighted_choice", req, resp, state.VaultUrl, state.Name, state.Version)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readMetadata(state.Name.ValueString(), properties)...)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	state.Keepers, diags = migrateKeeperMarkers(ctx, resp.Private, state.Keepers)
+	resp.Diagnostics.Append(diags...)
+
+	// If version number has changed we know that drift has occurred.
+	state.Version, _ = readDrift(ctx, req, resp, state.Version, version)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *weightedChoiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan, state weightedChoiceModelV0
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.providerData.vaultClient(ctx, plan.VaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+
+	// The plan only leaves result unknown when a new option should be chosen
+	if !plan.Result.IsUnknown() {
+		stored, diags := updateSecretMetadata(ctx, r.providerData, client, "azrandom_weighted_choice", plan.VaultUrl, name,
+			state.Version.ValueString(), plan.metadata(r.providerData))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Version = state.Version
+		plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+		plan.Result = state.Result
+
+		resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	result, diags := weightedChoiceFromModel(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, stored, err := azrandom.UpdateSecret(ctx, client, name, result, secretProperties(plan.metadata(r.providerData), nil))
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.UpdateError("azrandom_weighted_choice", name,
+			resourceVaultUrl(r.providerData, plan.VaultUrl), err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(verifyWrite(ctx, r.providerData, client, plan.VerifyWrite, name, version, result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Version = types.StringValue(version)
+	plan.CreatedOn, plan.UpdatedOn = timestampsFromProperties(stored)
+	plan.Result = types.StringValue(result)
+
+	resp.Diagnostics.Append(storeWrittenVersion(ctx, resp.Private, plan.Version.ValueString())...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *weightedChoiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	var state weightedChoiceModelV0
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(deleteSecrets(ctx, r.providerData, "azrandom_weighted_choice", state.VaultUrl, state.WaitForDeletion,
+		state.PurgeOnDestroy, state.Name)...)
+}
+
+// ImportState reads the chosen option from the secret. The options are not stored, and are taken from the
+// configuration on the next apply; the choice is kept as long as it is still one of the options.
+func (r *weightedChoiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID, diags := secretImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, vaultUrl, id, importedVersion, diags := importSecretVaultClient(ctx, r.providerData, importID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, properties, err := azrandom.GetSecret(ctx, client, id, importedVersion)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_weighted_choice", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+	resp.Diagnostics.Append(pinVersion(ctx, resp.Private, importedVersion)...)
+
+	result, err := azrandom.GetSecretValue(ctx, client, id, version)
+	if err != nil {
+		resp.Diagnostics.Append(diagnostics.ImportError("azrandom_weighted_choice", id,
+			resourceVaultUrl(r.providerData, vaultUrl), err)...)
+		return
+	}
+
+	state := weightedChoiceModelV0{
+		Name:                 types.StringValue(id),
+		VaultUrl:             vaultUrl,
+		Version:              types.StringValue(version),
+		Keepers:              types.MapNull(types.StringType),
+		Options:              types.MapNull(types.Int64Type),
+		RepickOnWeightChange: types.BoolValue(false),
+		Result:               types.StringValue(result),
+	}
+	state.secretMetadataModel = importedSecretMetadata(properties)
+	state.CreatedOn, state.UpdatedOn = timestampsFromProperties(properties)
+
+	resp.Diagnostics.Append(setSecretIdentity(ctx, r.providerData, resp.Identity, state.VaultUrl, state.Name)...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// weightedChoiceOptionsKnown reports whether the options and all their weights are known.
+func weightedChoiceOptionsKnown(options types.Map) bool {
+	if options.IsUnknown() {
+		return false
+	}
+	for _, weight := range options.Elements() {
+		if weight.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+func weightedChoiceWeights(ctx context.Context, options types.Map) (map[string]int64, diag.Diagnostics) {
+	weights := map[string]int64{}
+	diags := options.ElementsAs(ctx, &weights, false)
+	return weights, diags
+}
+
+func weightedChoiceFromModel(ctx context.Context, plan weightedChoiceModelV0) (string, diag.Diagnostics) {
+	weights, diags := weightedChoiceWeights(ctx, plan.Options)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	result, err := random.WeightedChoice(weights)
+	if err != nil {
+		diags.Append(diagnostics.RandomReadError(err.Error())...)
+	}
+
+	return result, diags
+}
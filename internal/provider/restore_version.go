@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// restoreVersionAttribute returns the schema of the restore_version attribute. It only takes effect when it is
+// changed, so clearing it never generates a new value.
+func restoreVersionAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "A previous version of the secret to roll back to, e.g. after a bad rotation. When it is set or " +
+			"changed on an existing resource, the next apply stores the value of that version as a new version of the " +
+			"secret instead of generating a value, and `version` becomes the new version. Clearing it does not change " +
+			"the value, and new values are generated as usual again. Setting it on a new resource has no effect. " +
+			"Requires permission to get secrets, and the version must exist and be enabled.",
+		Optional: true,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// restorePlanned returns whether apply restores the version of the secret given in restore, i.e. whether it was
+// set or changed on an existing resource.
+func restorePlanned(restore types.String, stateRestore types.String) bool {
+	return !restore.IsNull() && !restore.IsUnknown() && !restore.Equal(stateRestore)
+}
+
+// planRestoreVersion plans the version attribute of a resource as unknown when apply restores a previous version
+// of its secret, after checking that the version exists in the vault and is enabled.
+func planRestoreVersion(ctx context.Context, data *azrandomProviderData, resourceType string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to restore when creating or destroying
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var restore, stateRestore, name, vaultUrl types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("restore_version"), &restore)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("restore_version"), &stateRestore)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("vault_url"), &vaultUrl)...)
+	if resp.Diagnostics.HasError() || !restorePlanned(restore, stateRestore) {
+		return
+	}
+
+	// A resource that was moved from another provider has no secret to restore a version of yet
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	if moved {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("restore_version"),
+			"Invalid restore_version",
+			"The resource was moved from another provider and has not stored its value in a secret yet, so no "+
+				"version can be restored. Remove restore_version until the moved value was applied.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), types.StringUnknown())...)
+
+	// The provider is not configured yet when its configuration depends on other resources
+	if data == nil {
+		return
+	}
+	client, diags := data.vaultClient(ctx, vaultUrl)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, properties, err := azrandom.GetSecret(ctx, client, name.ValueString(), restore.ValueString())
+	switch {
+	case azrandom.IsNotFound(err):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("restore_version"),
+			"Invalid restore_version",
+			fmt.Sprintf("Version %s of secret %q does not exist, so it cannot be restored.", restore.ValueString(), name.ValueString()),
+		)
+	case err != nil:
+		resp.Diagnostics.Append(diagnostics.VaultError(diagnostics.OperationRead, "read the version to restore",
+			resourceType, name.ValueString(), resourceVaultUrl(data, vaultUrl), err)...)
+	case properties.Enabled != nil && !*properties.Enabled:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("restore_version"),
+			"Invalid restore_version",
+			fmt.Sprintf("Version %s of secret %q is disabled, so its value cannot be read to restore it. Enable the "+
+				"version first.", restore.ValueString(), name.ValueString()),
+		)
+	}
+}
+
+// restoredValue returns the value stored in the given version of the secret name, to store it again as the
+// newest version.
+func restoredValue(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, resourceType string, name string, vaultUrl types.String, version string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	value, err := azrandom.GetSecretValue(ctx, client, name, version)
+	if azrandom.IsNotFound(err) {
+		diags.AddAttributeError(
+			path.Root("restore_version"),
+			"Invalid restore_version",
+			fmt.Sprintf("Version %s of secret %q does not exist, so it cannot be restored.", version, name),
+		)
+		return "", diags
+	}
+	if err != nil {
+		diags.Append(diagnostics.VaultError(diagnostics.OperationUpdate, "read the version to restore",
+			resourceType, name, resourceVaultUrl(data, vaultUrl), err)...)
+		return "", diags
+	}
+	return value, diags
+}
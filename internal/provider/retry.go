@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-azrandom/internal/validators"
+)
+
+// retryModel maps the retry block of the provider schema to a Go type.
+type retryModel struct {
+	MaxRetries    types.Int64  `tfsdk:"max_retries"`
+	RetryDelay    types.String `tfsdk:"retry_delay"`
+	MaxRetryDelay types.String `tfsdk:"max_retry_delay"`
+	TryTimeout    types.String `tfsdk:"try_timeout"`
+}
+
+// retryBlock returns the schema of the retry block, which configures the retry policy of the client of the vault.
+func retryBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Retry policy of the requests to the vault that fail transiently, e.g. on a network error, a timeout " +
+			"or a 5xx response. Throttled requests are retried as well, waiting as long as their Retry-After header asks to " +
+			"unless that exceeds max_retry_delay.",
+		Attributes: map[string]schema.Attribute{
+			"max_retries": schema.Int64Attribute{
+				Description: "Number of times a failed request is retried, 0 disabling retries. Can also be set with the " +
+					"AZRANDOM_MAX_RETRIES environment variable. Default value is 3.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, math.MaxInt32),
+				},
+			},
+			"retry_delay": schema.StringAttribute{
+				Description: "Delay before the first retry of a failed request, such as \"2s\", which grows exponentially " +
+					"with each retry. It is only used when the response does not have a Retry-After header. Can also be " +
+					"set with the AZRANDOM_RETRY_DELAY environment variable. Default value is 800ms.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.PositiveDuration(),
+				},
+			},
+			"max_retry_delay": schema.StringAttribute{
+				Description: "Maximum delay between two retries of a failed request, such as \"2m\". Can also be set with " +
+					"the AZRANDOM_MAX_RETRY_DELAY environment variable. Default value is 60s.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.PositiveDuration(),
+				},
+			},
+			"try_timeout": schema.StringAttribute{
+				Description: "Maximum duration of a single try of a request, such as \"30s\", after which it is retried. " +
+					"Can also be set with the AZRANDOM_TRY_TIMEOUT environment variable. By default a try is not timed out.",
+				Optional: true,
+				Validators: []validator.String{
+					validators.PositiveDuration(),
+				},
+			},
+		},
+	}
+}
+
+// retryOptions resolves the retry policy of the client of the vault from the AZRANDOM_MAX_RETRIES,
+// AZRANDOM_RETRY_DELAY, AZRANDOM_MAX_RETRY_DELAY and AZRANDOM_TRY_TIMEOUT environment variables, overridden
+// by the retry block config when it is set. Unset values keep the defaults of azcore.
+func retryOptions(config *retryModel) (policy.RetryOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var options policy.RetryOptions
+
+	if config == nil {
+		config = &retryModel{}
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries.IsNull() {
+		if env := os.Getenv("AZRANDOM_MAX_RETRIES"); env != "" {
+			value, err := strconv.ParseInt(env, 10, 32)
+			if err != nil || value < 0 {
+				diags.AddAttributeError(
+					path.Root("retry").AtName("max_retries"),
+					"Error parsing AZRANDOM_MAX_RETRIES",
+					"The AZRANDOM_MAX_RETRIES environment variable must be a number that is not negative, got: "+env,
+				)
+			} else {
+				maxRetries = types.Int64Value(value)
+			}
+		}
+	}
+	switch {
+	case maxRetries.IsUnknown():
+		diags.Append(unknownRetryDiagnostic("max_retries", "AZRANDOM_MAX_RETRIES"))
+	case maxRetries.IsNull():
+	case maxRetries.ValueInt64() == 0:
+		// azcore reads zero as its default, and a negative number as no retries
+		options.MaxRetries = -1
+	default:
+		options.MaxRetries = int32(maxRetries.ValueInt64())
+	}
+
+	for _, duration := range []struct {
+		name   string
+		env    string
+		config types.String
+		target *time.Duration
+	}{
+		{name: "retry_delay", env: "AZRANDOM_RETRY_DELAY", config: config.RetryDelay, target: &options.RetryDelay},
+		{name: "max_retry_delay", env: "AZRANDOM_MAX_RETRY_DELAY", config: config.MaxRetryDelay, target: &options.MaxRetryDelay},
+		{name: "try_timeout", env: "AZRANDOM_TRY_TIMEOUT", config: config.TryTimeout, target: &options.TryTimeout},
+	} {
+		if duration.config.IsUnknown() {
+			diags.Append(unknownRetryDiagnostic(duration.name, duration.env))
+			continue
+		}
+
+		value := os.Getenv(duration.env)
+		if !duration.config.IsNull() {
+			value = duration.config.ValueString()
+		}
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName(duration.name),
+				"Invalid Azrandom Retry Policy",
+				"The "+duration.name+" of the retry policy (possibly given through the "+duration.env+" environment "+
+					"variable) must be a positive duration such as \"30s\" or \"2m\", got: "+value,
+			)
+			continue
+		}
+		*duration.target = parsed
+	}
+
+	return options, diags
+}
+
+// unknownRetryDiagnostic reports an unknown value of the attribute name of the retry block.
+func unknownRetryDiagnostic(name string, envVarName string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root("retry").AtName(name),
+		"Unknown Azrandom "+name,
+		"The provider cannot create the Azrandom API client as there is an unknown configuration value for "+name+". "+
+			"Either target apply the source of the value first, set the value statically in the configuration, or use the "+envVarName+" environment variable.",
+	)
+}
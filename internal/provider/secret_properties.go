@@ -0,0 +1,617 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+	"terraform-provider-azrandom/internal/utils"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+// managedTagPrefix is the prefix of the secret tags in which the provider stores its own data.
+const managedTagPrefix = "azrandom:"
+
+// descriptionTagName is the secret tag under which the description attribute is stored.
+const descriptionTagName = managedTagPrefix + "description"
+
+// The secret tags written when the write_metadata_tags setting of the provider is enabled.
+const (
+	managedByTagName       = managedTagPrefix + "managed-by"
+	workspaceTagName       = managedTagPrefix + "workspace"
+	providerVersionTagName = managedTagPrefix + "provider-version"
+)
+
+// providerMetadataTags returns the tags that the provider writes to every secret, if any.
+func providerMetadataTags(data *azrandomProviderData) map[string]string {
+	if data == nil {
+		return nil
+	}
+	return data.metadataTags
+}
+
+// metadataTags returns the tags written when write_metadata_tags is enabled. The workspace is taken from
+// TF_WORKSPACE, or TFC_WORKSPACE_NAME in HCP Terraform, since Terraform does not pass it to providers.
+func metadataTags(version string) map[string]string {
+	tags := map[string]string{
+		managedByTagName:       "terraform-provider-azrandom",
+		providerVersionTagName: version,
+	}
+	for _, name := range []string{"TF_WORKSPACE", "TFC_WORKSPACE_NAME"} {
+		if workspace := os.Getenv(name); workspace != "" {
+			tags[workspaceTagName] = workspace
+			break
+		}
+	}
+	return tags
+}
+
+// maxTagValueLength is the maximum length Key Vault allows for a tag value.
+const maxTagValueLength = 256
+
+// maxSecretValueLength is the maximum size in bytes Key Vault allows for a secret value.
+const maxSecretValueLength = 25 * 1024
+
+// metadataAttributes are stored as properties of the secret. Changing them updates the
+// properties of the current secret version, and never causes a new value to be generated.
+var metadataAttributes = []string{
+	"description",
+	"tags",
+	"tags_all",
+	"content_type",
+	"expiration_date",
+	"not_before",
+	"enabled",
+}
+
+// timestampAttributes are set by the vault whenever a secret is written, and never cause a new value
+// to be generated.
+var timestampAttributes = []string{
+	"created_on",
+	"updated_on",
+	"created_date",
+	"updated_date",
+}
+
+// writeSettingAttributes only change how values are written or deleted, and never cause a new value to be generated.
+// Changes of vault_url only reach Update when the secrets stay in the same vault, see planVaultUrl.
+var writeSettingAttributes = []string{
+	"verify_write",
+	"purge_on_destroy",
+	"wait_for_deletion",
+	"disable_previous_versions",
+	"max_versions_to_keep",
+	"vault_url",
+}
+
+// createdOnAttribute returns the schema of the created_on attribute.
+func createdOnAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time at which the current version of the secret was created, in RFC 3339 format.",
+		Computed:    true,
+	}
+}
+
+// updatedOnAttribute returns the schema of the updated_on attribute.
+func updatedOnAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time at which the current version of the secret, or its metadata, was last updated, " +
+			"in RFC 3339 format.",
+		Computed: true,
+	}
+}
+
+// createdDateAttribute returns the schema of the created_date attribute, which holds the same value as
+// created_on.
+func createdDateAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time at which the current version of the secret was created, in RFC 3339 format. " +
+			"The same as created_on.",
+		Computed: true,
+	}
+}
+
+// updatedDateAttribute returns the schema of the updated_date attribute, which holds the same value as
+// updated_on.
+func updatedDateAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time at which the current version of the secret, or its metadata, was last updated, " +
+			"in RFC 3339 format. The same as updated_on.",
+		Computed: true,
+	}
+}
+
+// timestampsFromProperties returns the created_on and updated_on values of a secret.
+func timestampsFromProperties(properties azrandom.SecretProperties) (types.String, types.String) {
+	toString := func(t *time.Time) types.String {
+		if t == nil {
+			return types.StringNull()
+		}
+		return types.StringValue(t.UTC().Format(time.RFC3339))
+	}
+	return toString(properties.Created), toString(properties.Updated)
+}
+
+// aliasedTimestampsFromProperties returns the created_on and updated_on values of a secret, followed by the
+// same values for the created_date and updated_date attributes of the resources that have them.
+func aliasedTimestampsFromProperties(properties azrandom.SecretProperties) (types.String, types.String, types.String, types.String) {
+	createdOn, updatedOn := timestampsFromProperties(properties)
+	return createdOn, updatedOn, createdOn, updatedOn
+}
+
+// secretMetadata holds the attributes of a resource that are stored as properties of its secrets.
+// ProviderTags are written by the provider rather than configured on the resource, so they are left
+// out of Equal.
+type secretMetadata struct {
+	Description    types.String
+	TagsAll        types.Map
+	ContentType    types.String
+	ExpirationDate types.String
+	NotBefore      types.String
+	Enabled        types.Bool
+	ProviderTags   map[string]string
+}
+
+// Equal returns whether the metadata equals other, so that the properties of the secrets need no update.
+func (m secretMetadata) Equal(other secretMetadata) bool {
+	return m.Description.Equal(other.Description) &&
+		m.TagsAll.Equal(other.TagsAll) &&
+		m.ContentType.Equal(other.ContentType) &&
+		m.ExpirationDate.Equal(other.ExpirationDate) &&
+		m.NotBefore.Equal(other.NotBefore) &&
+		m.Enabled.Equal(other.Enabled)
+}
+
+// secretMetadataModel holds the attributes that resources without a tags attribute store as properties of
+// their secrets. It is embedded in the models of those resources.
+type secretMetadataModel struct {
+	Description    types.String `tfsdk:"description"`
+	TagsAll        types.Map    `tfsdk:"tags_all"`
+	ContentType    types.String `tfsdk:"content_type"`
+	ExpirationDate types.String `tfsdk:"expiration_date"`
+	NotBefore      types.String `tfsdk:"not_before"`
+	Enabled        types.Bool   `tfsdk:"enabled"`
+}
+
+// metadata returns the attributes that are stored as properties of the secret, along with the tags
+// written by the provider.
+func (m secretMetadataModel) metadata(data *azrandomProviderData) secretMetadata {
+	return secretMetadata{
+		Description:    m.Description,
+		TagsAll:        m.TagsAll,
+		ContentType:    m.ContentType,
+		ExpirationDate: m.ExpirationDate,
+		NotBefore:      m.NotBefore,
+		Enabled:        m.Enabled,
+		ProviderTags:   providerMetadataTags(data),
+	}
+}
+
+// readMetadata sets the attributes from the properties of the secret name, as got by Read, warning when the
+// secret was disabled or has expired.
+func (m *secretMetadataModel) readMetadata(name string, properties azrandom.SecretProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Description = descriptionFromProperties(properties)
+	m.TagsAll = tagsFromProperties(properties, m.TagsAll)
+	m.ContentType = contentTypeFromProperties(properties)
+	m.ExpirationDate = validityTimestamp(properties.Expires, m.ExpirationDate)
+	m.NotBefore = validityTimestamp(properties.NotBefore, m.NotBefore)
+	diags.Append(disabledSecretDiagnostics(name, m.Enabled, properties)...)
+	m.Enabled = enabledFromProperties(properties)
+	diags.Append(expiredSecretDiagnostics(name, properties)...)
+	return diags
+}
+
+// importedSecretMetadata returns the attributes of a resource imported from a secret with the given properties.
+func importedSecretMetadata(properties azrandom.SecretProperties) secretMetadataModel {
+	return secretMetadataModel{
+		Description:    descriptionFromProperties(properties),
+		TagsAll:        importedTagsFromProperties(properties),
+		ContentType:    contentTypeFromProperties(properties),
+		ExpirationDate: validityTimestamp(properties.Expires, types.StringNull()),
+		NotBefore:      validityTimestamp(properties.NotBefore, types.StringNull()),
+		Enabled:        enabledFromProperties(properties),
+	}
+}
+
+// enabledAttribute returns the schema of the enabled attribute.
+func enabledAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Whether the secret is enabled. The value of a disabled secret cannot be read, so values " +
+			"written while the secret is disabled are not verified, and updates that derive from the stored value " +
+			"fail until it is enabled again. Changing it does not generate a new value. Default value is `true`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(true),
+	}
+}
+
+// enabledFromProperties returns whether a secret is enabled. The vault enables secrets by default.
+func enabledFromProperties(properties azrandom.SecretProperties) types.Bool {
+	return types.BoolValue(properties.Enabled == nil || *properties.Enabled)
+}
+
+// expirationDateAttribute returns the schema of the expiration_date attribute.
+func expirationDateAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time after which the secret should no longer be used, as an RFC3339 timestamp such as " +
+			"`2030-01-01T00:00:00Z`. Changing the expiration date does not generate a new value.",
+		Optional: true,
+		Validators: []validator.String{
+			validators.RFC3339(),
+		},
+	}
+}
+
+// notBeforeAttribute returns the schema of the not_before attribute.
+func notBeforeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The time before which the secret should not be used, as an RFC3339 timestamp such as " +
+			"`2030-01-01T00:00:00Z`. Changing it does not generate a new value.",
+		Optional: true,
+		Validators: []validator.String{
+			validators.RFC3339(),
+		},
+	}
+}
+
+// parseValidityTimestamp returns the time of an expiration_date or not_before attribute, or nil when it is
+// not set.
+func parseValidityTimestamp(value types.String) *time.Time {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value.ValueString())
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// validityTimestamp returns the expiration_date or not_before value of a secret. current is kept when it
+// denotes the same time, so that a timestamp written in another time zone does not show up as drift.
+func validityTimestamp(t *time.Time, current types.String) types.String {
+	if t == nil {
+		return types.StringNull()
+	}
+	if currentTime := parseValidityTimestamp(current); currentTime != nil && currentTime.Equal(*t) {
+		return current
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}
+
+// disabledSecretDiagnostics warns when the secret name was disabled outside of Terraform while enabled, the
+// value in state, expects it to be enabled, since clients can no longer read its value. The next apply enables
+// it again.
+func disabledSecretDiagnostics(name string, enabled types.Bool, properties azrandom.SecretProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if enabled.ValueBool() && properties.Enabled != nil && !*properties.Enabled {
+		diags.AddWarning(
+			"Secret disabled",
+			"The secret "+name+" was disabled outside of Terraform, so clients can no longer read its value. "+
+				"The next apply enables it again, unless enabled is set to false.",
+		)
+	}
+	return diags
+}
+
+// expiredSecretDiagnostics warns when the secret name has expired, since clients honouring the
+// expiration date no longer use it.
+func expiredSecretDiagnostics(name string, properties azrandom.SecretProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if properties.Expires != nil && properties.Expires.Before(time.Now()) {
+		diags.AddWarning(
+			"Secret expired",
+			"The secret "+name+" expired at "+properties.Expires.UTC().Format(time.RFC3339)+". Clients that honour "+
+				"the expiration date no longer use it. Change expiration_date to extend its validity.",
+		)
+	}
+	return diags
+}
+
+// Content types set on the secrets by default, depending on the value they hold.
+const (
+	contentTypeText = "text/plain"
+	contentTypeJSON = "application/json"
+	contentTypePEM  = "application/x-pem-file"
+)
+
+// maxContentTypeLength is the maximum length of the content type of a secret.
+const maxContentTypeLength = 255
+
+// contentTypeAttribute returns the schema of the content_type attribute, defaulting to defaultContentType.
+func contentTypeAttribute(defaultContentType string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "The content type of the secret, used by tools reading the secret to interpret its value. " +
+			"Changing the content type does not generate a new value. Default value is `" + defaultContentType + "`.",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(defaultContentType),
+		Validators: []validator.String{
+			stringvalidator.LengthBetween(1, maxContentTypeLength),
+		},
+	}
+}
+
+// tagsValidators validate the tags attribute of resources, and the default_tags of the provider.
+func tagsValidators() []validator.Map {
+	return []validator.Map{
+		mapvalidator.KeysAre(validators.NoPrefix(managedTagPrefix)),
+		mapvalidator.ValueStringsAre(stringvalidator.LengthAtMost(maxTagValueLength)),
+	}
+}
+
+// tagsAttribute returns the schema of the tags attribute.
+func tagsAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		Description: "Tags to set on the secret, overriding the default_tags of the provider with the same keys. " +
+			"When tags or default_tags are set, tags that were added to the secret outside of Terraform are removed; " +
+			"otherwise, they are kept. Keys starting with `" + managedTagPrefix + "` are reserved for the provider. " +
+			"Changing the tags does not generate a new value.",
+		ElementType: types.StringType,
+		Optional:    true,
+		Validators:  tagsValidators(),
+	}
+}
+
+// tagsAllAttribute returns the schema of the tags_all attribute.
+func tagsAllAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		Description: "The tags set on the secret: the default_tags of the provider merged with the tags of the resource, " +
+			"if it has any.",
+		ElementType: types.StringType,
+		Computed:    true,
+	}
+}
+
+// planTagsAll plans the tags_all attribute as the default_tags of the provider merged with the tags
+// attribute of the resource, when it has one.
+func planTagsAll(ctx context.Context, data *azrandomProviderData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	tags := types.MapNull(types.StringType)
+	if _, ok := req.Plan.Schema.GetAttributes()["tags"]; ok {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags"), &tags)...)
+	}
+
+	defaultTags := types.MapUnknown(types.StringType)
+	if data != nil {
+		defaultTags = data.defaultTags
+	}
+
+	tagsAll, diags := utils.MergeTags(defaultTags, tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), tagsAll)...)
+}
+
+// resourceTags returns the tags attribute of a resource given the tags_all read from its secret.
+func resourceTags(data *azrandomProviderData, tagsAll types.Map, current types.Map) (types.Map, diag.Diagnostics) {
+	defaultTags := types.MapNull(types.StringType)
+	if data != nil {
+		defaultTags = data.defaultTags
+	}
+	return utils.RemoveDefaultTags(tagsAll, defaultTags, current)
+}
+
+// isManagedTag returns whether the secret tag name holds data of the provider.
+func isManagedTag(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), managedTagPrefix)
+}
+
+// secretProperties builds the properties to store alongside the generated value. Tags that are
+// not managed by the provider are carried over from existingTags, unless tags_all is set.
+func secretProperties(metadata secretMetadata, existingTags map[string]string) azrandom.SecretProperties {
+	tags := map[string]string{}
+	for k, v := range existingTags {
+		if !metadata.TagsAll.IsNull() && !isManagedTag(k) {
+			continue
+		}
+		tags[k] = v
+	}
+
+	delete(tags, descriptionTagName)
+	if !metadata.Description.IsNull() && !metadata.Description.IsUnknown() {
+		tags[descriptionTagName] = metadata.Description.ValueString()
+	}
+
+	for k, v := range metadata.ProviderTags {
+		tags[k] = v
+	}
+
+	for k, v := range metadata.TagsAll.Elements() {
+		if value, ok := v.(types.String); ok && !value.IsNull() && !value.IsUnknown() {
+			tags[k] = value.ValueString()
+		}
+	}
+
+	return azrandom.SecretProperties{
+		Tags:        tags,
+		ContentType: metadata.ContentType.ValueString(),
+		Expires:     parseValidityTimestamp(metadata.ExpirationDate),
+		NotBefore:   parseValidityTimestamp(metadata.NotBefore),
+		Enabled:     metadata.Enabled.ValueBoolPointer(),
+	}
+}
+
+// descriptionFromProperties returns the description stored in the tags of a secret.
+func descriptionFromProperties(properties azrandom.SecretProperties) types.String {
+	description, ok := properties.Tags[descriptionTagName]
+	if !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(description)
+}
+
+// contentTypeFromProperties returns the content type of a secret, or null when it is not set.
+func contentTypeFromProperties(properties azrandom.SecretProperties) types.String {
+	if properties.ContentType == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(properties.ContentType)
+}
+
+// tagsFromProperties returns the tags of a secret that are not managed by the provider. current is
+// returned when it is null, as the tags of the resource are then not managed.
+func tagsFromProperties(properties azrandom.SecretProperties, current types.Map) types.Map {
+	if current.IsNull() {
+		return current
+	}
+
+	elements := map[string]attr.Value{}
+	for k, v := range properties.Tags {
+		if !isManagedTag(k) {
+			elements[k] = types.StringValue(v)
+		}
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// importedTagsFromProperties returns the tags of an imported secret that are not managed by the
+// provider, or null when there are none.
+func importedTagsFromProperties(properties azrandom.SecretProperties) types.Map {
+	tags := tagsFromProperties(properties, types.MapValueMust(types.StringType, nil))
+	if len(tags.Elements()) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	return tags
+}
+
+// valueAttributesChanged reports whether a planned change affects any attribute that the generated
+// value depends on. Metadata attributes are ignored, as are the given computed attributes, which are
+// unknown in the plan whenever anything changes.
+func valueAttributesChanged(plan tftypes.Value, state tftypes.Value, computed ...string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var planAttributes, stateAttributes map[string]tftypes.Value
+	if err := plan.As(&planAttributes); err != nil {
+		diags.AddError("Unable to read plan", err.Error())
+		return false, diags
+	}
+	if err := state.As(&stateAttributes); err != nil {
+		diags.AddError("Unable to read state", err.Error())
+		return false, diags
+	}
+
+	ignored := map[string]bool{}
+	for _, name := range append(append(append(metadataAttributes, timestampAttributes...), writeSettingAttributes...), computed...) {
+		ignored[name] = true
+	}
+
+	for name, planValue := range planAttributes {
+		if ignored[name] {
+			continue
+		}
+		if !planValue.Equal(stateAttributes[name]) {
+			return true, diags
+		}
+	}
+
+	return false, diags
+}
+
+// updateSecretProperties updates the metadata of the given secret version in place, keeping any tags of that version
+// that are not managed by the provider, and returns the updated properties.
+func updateSecretProperties(ctx context.Context, client *azsecrets.Client, name string, version string, metadata secretMetadata) (azrandom.SecretProperties, error) {
+	_, existing, err := azrandom.GetSecret(ctx, client, name, version)
+	if err != nil {
+		return azrandom.SecretProperties{}, err
+	}
+
+	return azrandom.UpdateSecretProperties(ctx, client, name, version, secretProperties(metadata, existing.Tags))
+}
+
+// secretDeletedOutOfBand returns whether err, returned when Read got the secret name of a resource of type
+// resourceType, means that the secret no longer exists, e.g. because it was deleted in the portal, and logs a
+// warning when it does. Read then removes the resource from state, so that the next apply creates it again.
+func secretDeletedOutOfBand(ctx context.Context, resourceType string, name string, err error) bool {
+	if !azrandom.IsNotFound(err) {
+		return false
+	}
+	tflog.Warn(ctx, "Secret not found, removing resource from state", map[string]any{
+		"resource_type": resourceType,
+		"name":          name,
+	})
+	return true
+}
+
+// existingSecretMessage describes why a resource cannot be created because the secret name already
+// exists, including the metadata of the existing secret so that it can be told apart from a secret
+// that belongs to someone else. importID is the ID with which the resource can be imported.
+func existingSecretMessage(ctx context.Context, client *azsecrets.Client, resourceType string, name string, importID string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "A secret with name %s already exists.\n\n", name)
+
+	_, properties, err := azrandom.GetSecret(ctx, client, name, "")
+	if err != nil {
+		fmt.Fprintf(&b, "The metadata of the existing secret could not be read: %s\n\n", diagnostics.ErrorDetail(err))
+	} else {
+		formatTime := func(t *time.Time) string {
+			if t == nil {
+				return "unknown"
+			}
+			return t.UTC().Format(time.RFC3339)
+		}
+
+		tags := make([]string, 0, len(properties.Tags))
+		managed := false
+		for k, v := range properties.Tags {
+			tags = append(tags, k+"="+v)
+			if isManagedTag(k) {
+				managed = true
+			}
+		}
+		sort.Strings(tags)
+		if len(tags) == 0 {
+			tags = append(tags, "none")
+		}
+
+		b.WriteString("Existing secret:\n")
+		fmt.Fprintf(&b, "  Created:       %s\n", formatTime(properties.Created))
+		fmt.Fprintf(&b, "  Last updated:  %s\n", formatTime(properties.Updated))
+		fmt.Fprintf(&b, "  Tags:          %s\n", strings.Join(tags, ", "))
+		if managed {
+			b.WriteString("  It has azrandom tags, so it was most likely created by this provider.\n\n")
+		} else {
+			b.WriteString("  It has no azrandom tags, so it may not have been created by this provider.\n\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "If this secret was left behind by a workspace whose state was lost, import it into this "+
+		"%s with the following command, where ADDRESS is the address of this resource:\n\n", resourceType)
+	fmt.Fprintf(&b, "  terraform import ADDRESS %s\n\n", importID)
+	b.WriteString("Otherwise, choose a different name so that the existing secret is not overwritten.")
+
+	return b.String()
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+// The values of the encoding attribute of azrandom_string.
+const (
+	stringEncodingPlain  = "plain"
+	stringEncodingBase64 = "base64"
+	stringEncodingHex    = "hex"
+)
+
+// stringEncodingAttribute returns the schema of the encoding attribute of azrandom_string.
+func stringEncodingAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("The encoding of the generated string in the secret. `%s` stores it as it is, "+
+			"`%s` stores the standard, padded base64 encoding of its UTF-8 bytes, e.g. for Kubernetes secret "+
+			"manifests, and `%s` their lowercase hexadecimal encoding. `length` and the `min_*` counts apply to the "+
+			"string before it is encoded, so that e.g. a `length` of 32 stores 44 base64 or 64 hexadecimal characters. "+
+			"Changing it generates a new value. Default value is `%s`.",
+			stringEncodingPlain, stringEncodingBase64, stringEncodingHex, stringEncodingPlain),
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(stringEncodingPlain),
+		Validators: []validator.String{
+			stringvalidator.OneOf(stringEncodingPlain, stringEncodingBase64, stringEncodingHex),
+		},
+	}
+}
+
+// encodeStringValue returns value in the given encoding.
+func encodeStringValue(value []byte, encoding string) []byte {
+	switch encoding {
+	case stringEncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(value))
+	case stringEncodingHex:
+		return []byte(hex.EncodeToString(value))
+	}
+	return value
+}
+
+// maxStringValueSize returns the largest size in bytes of the values stored for model, including the prefix, the
+// suffix and the encoding.
+func maxStringValueSize(model *stringModelV3) int64 {
+	size := model.Length.ValueInt64()
+	switch {
+	case !model.Pattern.IsNull():
+		if pattern, err := random.ParseStringPattern(model.Pattern.ValueString()); err == nil {
+			size = pattern.Size()
+		}
+	case !model.Charset.IsNull():
+		// The characters of a charset may take up to 4 bytes each
+		widest := 1
+		for _, character := range model.Charset.ValueString() {
+			widest = max(widest, utf8.RuneLen(character))
+		}
+		size *= int64(widest)
+	}
+	size += int64(len(model.Prefix.ValueString()) + len(model.Suffix.ValueString()))
+
+	switch model.Encoding.ValueString() {
+	case stringEncodingBase64:
+		return int64(base64.StdEncoding.EncodedLen(int(size)))
+	case stringEncodingHex:
+		return int64(hex.EncodedLen(int(size)))
+	}
+	return size
+}
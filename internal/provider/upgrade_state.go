@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// priorSecretAttributes returns the attributes that the prior schemas of azrandom_uuid and azrandom_string have in
+// common: the name and version of the secret, keepers, and the metadata of the secret. Only their types matter to
+// read prior state, so descriptions, defaults and validators are left out.
+func priorSecretAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required: true,
+		},
+		"version": schema.StringAttribute{
+			Computed: true,
+		},
+		"keepers": schema.MapAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"vault_url": schema.StringAttribute{
+			Optional: true,
+		},
+		"description": schema.StringAttribute{
+			Optional: true,
+		},
+		"content_type": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"expiration_date": schema.StringAttribute{
+			Optional: true,
+		},
+		"not_before": schema.StringAttribute{
+			Optional: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"tags": schema.MapAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"tags_all": schema.MapAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+		},
+		"conflict_policy": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"on_drift": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"verify_write": schema.BoolAttribute{
+			Optional: true,
+		},
+		"purge_on_destroy": schema.BoolAttribute{
+			Optional: true,
+		},
+		"wait_for_deletion": schema.BoolAttribute{
+			Optional: true,
+		},
+		"created_on": schema.StringAttribute{
+			Computed: true,
+		},
+		"updated_on": schema.StringAttribute{
+			Computed: true,
+		},
+	}
+}
+
+// copyPriorState sets the state of resp, whose schema is the current schema of the resource, to the prior state
+// of req. Attributes that were added since are null, and attributes that were removed are dropped, so that the
+// state can be read into the current model and the settings that are null filled with their defaults.
+func copyPriorState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior map[string]tftypes.Value
+	if err := req.State.Raw.As(&prior); err != nil {
+		resp.Diagnostics.AddError("Unable to read the prior state", err.Error())
+		return
+	}
+
+	currentType := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(currentType.AttributeTypes))
+	for name, attributeType := range currentType.AttributeTypes {
+		value, ok := prior[name]
+		if !ok || !value.Type().Equal(attributeType) {
+			value = tftypes.NewValue(attributeType, nil)
+		}
+		values[name] = value
+	}
+	resp.State.Raw = tftypes.NewValue(currentType, values)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// The values of the format attribute of azrandom_uuid.
+const (
+	uuidFormatCanonical = "canonical"
+	uuidFormatNoHyphens = "no_hyphens"
+	uuidFormatBraced    = "braced"
+	uuidFormatURN       = "urn"
+)
+
+// uuidURNPrefix precedes a UUID in the urn format, see RFC 4122.
+const uuidURNPrefix = "urn:uuid:"
+
+// maxUUIDValueLength is the length of a UUID in its longest format, urn.
+const maxUUIDValueLength = len(uuidURNPrefix) + 36
+
+// uuidFormatAttribute returns the schema of the format attribute of azrandom_uuid.
+func uuidFormatAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("The shape in which the UUID is stored. `%s` is the 36 character form with hyphens, "+
+			"`%s` leaves out the hyphens, `%s` encloses the canonical form in curly braces, and `%s` prefixes it with "+
+			"`%s`. Changing it stores the same UUID in the new shape as a new version of the secret. Default value is `%s`.",
+			uuidFormatCanonical, uuidFormatNoHyphens, uuidFormatBraced, uuidFormatURN, uuidURNPrefix, uuidFormatCanonical),
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(uuidFormatCanonical),
+		Validators: []validator.String{
+			stringvalidator.OneOf(uuidFormatCanonical, uuidFormatNoHyphens, uuidFormatBraced, uuidFormatURN),
+		},
+	}
+}
+
+// uuidUppercaseAttribute returns the schema of the uppercase attribute of azrandom_uuid.
+func uuidUppercaseAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Store the hexadecimal digits of the UUID in uppercase. The `urn:uuid:` prefix of the `urn` " +
+			"format is kept in lowercase. Changing it stores the same UUID in the new case as a new version of the " +
+			"secret. Default value is `false`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+// uuidPrefixAttribute returns the schema of the prefix attribute of azrandom_uuid.
+func uuidPrefixAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "A string to store in front of the UUID, such as `tenant-`, so that the secret holds e.g. " +
+			"`tenant-<uuid>`. It is kept when a new value is generated, e.g. after drift. Changing it stores the same " +
+			"UUID with the new prefix as a new version of the secret.",
+		Optional: true,
+		Validators: []validator.String{
+			stringvalidator.LengthBetween(1, maxSecretValueLength-maxUUIDValueLength),
+		},
+	}
+}
+
+// formatUUIDValue returns canonical, a UUID in canonical lowercase form, in the given format and case.
+func formatUUIDValue(canonical string, format string, uppercase bool) string {
+	if uppercase {
+		canonical = strings.ToUpper(canonical)
+	}
+
+	switch format {
+	case uuidFormatNoHyphens:
+		return strings.ReplaceAll(canonical, "-", "")
+	case uuidFormatBraced:
+		return "{" + canonical + "}"
+	case uuidFormatURN:
+		return uuidURNPrefix + canonical
+	}
+	return canonical
+}
+
+// parseUUIDValue returns the UUID held by value, which may be in any of the formats and cases of azrandom_uuid,
+// in canonical lowercase form, along with its format and case. ok is false when value does not hold a UUID.
+func parseUUIDValue(value string) (canonical string, format string, uppercase bool, ok bool) {
+	format = uuidFormatCanonical
+	switch {
+	case len(value) > len(uuidURNPrefix) && strings.EqualFold(value[:len(uuidURNPrefix)], uuidURNPrefix):
+		format = uuidFormatURN
+		value = value[len(uuidURNPrefix):]
+	case strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+		format = uuidFormatBraced
+		value = value[1 : len(value)-1]
+	case len(value) == 32:
+		format = uuidFormatNoHyphens
+		value = value[0:8] + "-" + value[8:12] + "-" + value[12:16] + "-" + value[16:20] + "-" + value[20:32]
+	}
+
+	if _, err := uuid.ParseUUID(value); err != nil {
+		return "", "", false, false
+	}
+	canonical = strings.ToLower(value)
+	return canonical, format, canonical != value, true
+}
+
+// splitUUIDValue returns the prefix of value and the UUID that follows it, in canonical lowercase form, along
+// with its format and case. ok is false when value does not end with a UUID.
+func splitUUIDValue(value string) (prefix string, canonical string, format string, uppercase bool, ok bool) {
+	// Try the longest formats first, so that e.g. the braces are not taken for the prefix
+	for _, length := range []int{maxUUIDValueLength, 38, 36, 32} {
+		if len(value) < length {
+			continue
+		}
+		if canonical, format, uppercase, ok := parseUUIDValue(value[len(value)-length:]); ok {
+			return value[:len(value)-length], canonical, format, uppercase, true
+		}
+	}
+	return "", "", "", false, false
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/validators"
+)
+
+// vaultUrlAttribute returns the schema of the vault_url attribute of a resource. Moving a resource to another
+// vault replaces it, see planVaultUrl.
+func vaultUrlAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "URL of the Azure Key Vault to store the secrets of this resource in, instead of the vault of the " +
+			"provider, authenticating with the credential of the provider. Managed HSM pools are not supported, as they " +
+			"only store keys. Changing it to another vault forces a new resource. To import a resource from this vault, prefix the import ID with `<vault_url>/secrets/`, as in the " +
+			"secret identifiers shown in the portal. An import ID that ends with a version, such as `<name>:<version>` " +
+			"or `<vault_url>/secrets/<name>/<version>`, pins the resource to that version: newer versions of the secret " +
+			"are not drift, until the resource generates a new value.",
+		Optional: true,
+		Validators: []validator.String{
+			validators.HTTPSURL(),
+		},
+	}
+}
+
+// vaultKey identifies the vault at vaultUrl, which is case-insensitive and may end with a slash.
+func vaultKey(vaultUrl string) string {
+	return azrandom.NormalizeVaultURL(vaultUrl)
+}
+
+// vaultClientCache creates a client for each vault that resources store their secrets in, and shares it
+// between all resources of that vault.
+type vaultClientCache struct {
+	mu         sync.Mutex
+	credential azcore.TokenCredential
+	options    azrandom.ClientOptions
+	clients    map[string]*azsecrets.Client
+}
+
+// newVaultClientCache returns a cache holding client as the client of the vault of the provider at vaultUrl.
+func newVaultClientCache(credential azcore.TokenCredential, options azrandom.ClientOptions, vaultUrl string, client *azsecrets.Client) *vaultClientCache {
+	return &vaultClientCache{
+		credential: credential,
+		options:    options,
+		clients:    map[string]*azsecrets.Client{vaultKey(vaultUrl): client},
+	}
+}
+
+// get returns the client of the vault at vaultUrl, creating it on first use.
+func (c *vaultClientCache) get(ctx context.Context, vaultUrl string) (*azsecrets.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := vaultKey(vaultUrl)
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+
+	tflog.Debug(ctx, "Creating Azrandom client for resource vault", map[string]any{"vault_url": vaultUrl})
+
+	client, err := azrandom.CreateClient(vaultUrl, c.credential, c.options)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = client
+	return client, nil
+}
+
+// resourceVaultUrl returns the URL of the vault that a resource with the given vault_url stores its secrets
+// in, which is the vault of the provider when vault_url is null.
+func resourceVaultUrl(data *azrandomProviderData, vaultUrl types.String) string {
+	if vaultUrl.IsNull() || vaultUrl.IsUnknown() || vaultUrl.ValueString() == "" {
+		return data.vaultUrl
+	}
+	return vaultUrl.ValueString()
+}
+
+// vaultClient returns the client of the vault that a resource with the given vault_url stores its secrets in.
+func (d *azrandomProviderData) vaultClient(ctx context.Context, vaultUrl types.String) (*azsecrets.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if vaultUrl.IsNull() || vaultUrl.ValueString() == "" || d.vaultClients == nil {
+		return d.client, diags
+	}
+
+	client, err := d.vaultClients.get(ctx, vaultUrl.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("vault_url"),
+			"Unable to Create Azrandom API Client",
+			"An unexpected error occurred when creating the client of the vault "+vaultUrl.ValueString()+".\n\n"+
+				"Azrandom Client Error: "+err.Error(),
+		)
+	}
+	return client, diags
+}
+
+// importVaultClient splits an import ID of the form <vault_url>/secrets/<id> into the vault_url of the imported
+// resource and its import ID within that vault, and returns the client of that vault. Import IDs without a
+// vault URL, or with the URL of the vault of the provider, are imported from the vault of the provider, with a
+// null vault_url.
+func importVaultClient(ctx context.Context, data *azrandomProviderData, importID string) (*azsecrets.Client, types.String, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !strings.HasPrefix(strings.ToLower(importID), "https://") {
+		client, diags := data.vaultClient(ctx, types.StringNull())
+		return client, types.StringNull(), importID, diags
+	}
+
+	var id string
+	found := false
+	u, err := url.Parse(importID)
+	if err == nil {
+		id, found = strings.CutPrefix(u.EscapedPath(), "/secrets/")
+	}
+	if !found || id == "" || u.Host == "" {
+		diags.AddError(
+			"Invalid Import ID",
+			"Expected an import ID of the form <vault_url>/secrets/<id> to import from another vault than the vault of "+
+				"the provider, got: "+importID,
+		)
+		return nil, types.StringNull(), "", diags
+	}
+	if id, err = url.PathUnescape(id); err != nil {
+		diags.AddError("Invalid Import ID", "Could not unescape the import ID "+importID+": "+err.Error())
+		return nil, types.StringNull(), "", diags
+	}
+
+	vaultUrl := types.StringValue("https://" + u.Host + "/")
+	if vaultKey(vaultUrl.ValueString()) == vaultKey(data.vaultUrl) {
+		vaultUrl = types.StringNull()
+	}
+	client, diags := data.vaultClient(ctx, vaultUrl)
+	return client, vaultUrl, id, diags
+}
+
+// importSecretVaultClient is importVaultClient for resources that store a single secret, whose import ID is
+// the name of that secret. The name may be followed by :<version>, or by /<version> like the secret
+// identifiers shown in the portal. That version is returned separately, for the resource to be pinned to.
+func importSecretVaultClient(ctx context.Context, data *azrandomProviderData, importID string) (*azsecrets.Client, types.String, string, string, diag.Diagnostics) {
+	client, vaultUrl, id, diags := importVaultClient(ctx, data, importID)
+	if diags.HasError() {
+		return nil, types.StringNull(), "", "", diags
+	}
+
+	id = strings.TrimSuffix(id, "/")
+	name, version, found := strings.Cut(id, ":")
+	if !found {
+		name, version, found = strings.Cut(id, "/")
+	}
+	if name == "" || (found && version == "") || strings.ContainsAny(version, "/:") {
+		diags.AddError(
+			"Invalid Import ID",
+			"Expected an import ID of the form <name>, <name>:<version> or a secret identifier such as "+
+				"https://<vault>.vault.azure.net/secrets/<name>/<version>, got: "+importID,
+		)
+		return nil, types.StringNull(), "", "", diags
+	}
+	return client, vaultUrl, name, version, diags
+}
+
+// planVaultUrl replaces a resource whose secrets move to another vault. Changes of vault_url that keep the
+// secrets in the same vault, such as setting it to the vault of the provider, are applied in place.
+func planVaultUrl(ctx context.Context, data *azrandomProviderData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing moves when creating or destroying, or when the provider has not been configured yet
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || data == nil {
+		return
+	}
+
+	// A resource that was moved from another provider has no secrets yet, so it is stored in the planned vault
+	_, moved, diags := movedValue(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if moved || diags.HasError() {
+		return
+	}
+
+	var planVaultUrl, stateVaultUrl types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("vault_url"), &planVaultUrl)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("vault_url"), &stateVaultUrl)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if planVaultUrl.IsUnknown() || vaultKey(resourceVaultUrl(data, planVaultUrl)) != vaultKey(resourceVaultUrl(data, stateVaultUrl)) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("vault_url"))
+	}
+}
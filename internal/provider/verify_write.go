@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// verifyWriteAttribute returns the schema of the verify_write attribute. It only changes how values
+// are written, so changing it never generates a new value.
+func verifyWriteAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Read back every value written by this resource and compare it with the value that was sent, " +
+			"failing the apply when they differ. Requires permission to get secret values. " +
+			"Defaults to the verify_write setting of the provider.",
+		Optional: true,
+	}
+}
+
+// verifyWriteEnabled returns whether the values written by a resource are read back, given its
+// verify_write attribute.
+func verifyWriteEnabled(data *azrandomProviderData, override types.Bool) bool {
+	if !override.IsNull() && !override.IsUnknown() {
+		return override.ValueBool()
+	}
+	return data != nil && data.verifyWrite
+}
+
+// verifyWrite reads back the given version of the secret name with client when verification is enabled,
+// and reports an error when it does not hold value.
+func verifyWrite(ctx context.Context, data *azrandomProviderData, client *azsecrets.Client, override types.Bool, name string, version string, value string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !verifyWriteEnabled(data, override) {
+		return diags
+	}
+
+	tflog.Debug(ctx, "Verifying written secret value", map[string]any{"name": name, "version": version})
+
+	err := azrandom.VerifySecretValue(ctx, client, name, version, value)
+	if azrandom.IsDisabled(err) {
+		diags.AddWarning(
+			"Write not verified",
+			"The value of "+name+" was written, but could not be read back for verification since the secret is disabled.",
+		)
+	} else if err != nil {
+		diags.AddError("Write verification failed", verifyWriteErrorDetail(err))
+	}
+	return diags
+}
+
+// verifyWriteErrorDetail describes an error returned by azrandom.VerifySecretValue.
+func verifyWriteErrorDetail(err error) string {
+	var mismatch *azrandom.WriteVerificationError
+	if errors.As(err, &mismatch) {
+		return "The value read back from the vault differs from the value that was sent, so the secret may hold a " +
+			"truncated or otherwise altered value. This usually means that a proxy or other middlebox between Terraform " +
+			"and the vault modified the request. The version was not recorded in state.\n\n" + diagnostics.ErrorDetail(err)
+	}
+
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+		return "The value was written, but could not be read back for verification, since the identity of the provider " +
+			"may not get secret values. Grant it permission to get secrets, or set verify_write to false.\n\n" + diagnostics.ErrorDetail(err)
+	}
+
+	return "The value was written, but could not be read back for verification, unexpected error: " + diagnostics.ErrorDetail(err)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// waitForDeletionAttribute returns the schema of the wait_for_deletion attribute. It is only used by
+// Delete, so changing it never generates a new value.
+func waitForDeletionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Wait on destroy until the deletion of the secrets of this resource has completed, for up to a " +
+			"minute, so that secrets with the same names can be created right away, e.g. when a module is destroyed " +
+			"and applied again in the same run. Requires permission to get deleted secrets. Default value is `false`.",
+		Optional: true,
+	}
+}
+
+// waitForDeletion waits until the deletion of the secret name has completed when wait_for_deletion is enabled.
+func waitForDeletion(ctx context.Context, client *azsecrets.Client, enabled types.Bool, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !enabled.ValueBool() {
+		return diags
+	}
+
+	tflog.Debug(ctx, "Waiting for deletion of secret", map[string]any{"name": name})
+
+	if err := azrandom.WaitForDeletion(ctx, client, name); err != nil {
+		diags.AddError(
+			"Wait for deletion failed",
+			"The secret "+name+" was deleted, but its deletion could not be confirmed: "+diagnostics.ErrorDetail(err),
+		)
+	}
+	return diags
+}
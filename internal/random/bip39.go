@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// bip39EnglishWordlist is the English wordlist from the BIP-39 specification, one word per line.
+//
+//go:embed bip39_english.txt
+var bip39EnglishWordlist string
+
+var (
+	bip39Words   = strings.Fields(bip39EnglishWordlist)
+	bip39Indices = bip39WordIndices()
+)
+
+func bip39WordIndices() map[string]int {
+	indices := make(map[string]int, len(bip39Words))
+	for i, word := range bip39Words {
+		indices[word] = i
+	}
+	return indices
+}
+
+// CreateBip39Mnemonic returns a mnemonic of the given number of words, which must be 12, 15, 18, 21 or 24,
+// encoding random entropy.
+func CreateBip39Mnemonic(words int64) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words, got %d", words)
+	}
+
+	// Every 3 words encode 32 bits of entropy and 1 bit of checksum
+	entropy := make([]byte, words/3*4)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+
+	return Bip39MnemonicFromEntropy(entropy)
+}
+
+// Bip39MnemonicFromEntropy encodes entropy of 16 to 32 bytes, in steps of 4, as a mnemonic.
+func Bip39MnemonicFromEntropy(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("entropy must be 16 to 32 bytes in steps of 4, got %d bytes", len(entropy))
+	}
+
+	// The checksum is the first len(entropy)*8/32 bits of the SHA-256 hash of the entropy
+	checksumBits := uint(len(entropy) / 4)
+	hash := sha256.Sum256(entropy)
+
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, checksumBits)
+	bits.Or(bits, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	// Each word encodes 11 bits, most significant first
+	count := (len(entropy)*8 + int(checksumBits)) / 11
+	words := make([]string, count)
+	mask := big.NewInt(2047)
+	for i := count - 1; i >= 0; i-- {
+		words[i] = bip39Words[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// Bip39EntropyFromMnemonic decodes a mnemonic into its entropy, verifying its words and checksum.
+func Bip39EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+
+	bits := new(big.Int)
+	for _, word := range words {
+		index, ok := bip39Indices[word]
+		if !ok {
+			return nil, fmt.Errorf("%q is not in the BIP-39 English wordlist", word)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(bits, big.NewInt(int64(1<<checksumBits-1))).Int64()
+	bits.Rsh(bits, checksumBits)
+
+	entropy := bits.FillBytes(make([]byte, len(words)/3*4))
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-checksumBits)) != checksum {
+		return nil, fmt.Errorf("invalid checksum")
+	}
+
+	return entropy, nil
+}
+
+// Bip39Seed derives the 64-byte seed from a mnemonic and an optional passphrase.
+func Bip39Seed(mnemonic string, passphrase string) []byte {
+	password := norm.NFKD.String(mnemonic)
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), 2048, 64, sha512.New)
+}
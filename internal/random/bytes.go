@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// CreateBytes returns length bytes read from a cryptographic random number generator.
+func CreateBytes(length int64) ([]byte, error) {
+	if length < 1 {
+		return nil, errors.New("length must be at least 1")
+	}
+
+	bytes := make([]byte, length)
+	n, err := rand.Read(bytes)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n) != length {
+		return nil, fmt.Errorf("generated %d random bytes, expected %d", n, length)
+	}
+
+	return bytes, nil
+}
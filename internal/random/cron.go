@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDay    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC",
+	}}
+	// 7 is accepted for Sunday, but random values are chosen from 0 to 6
+	cronWeekday = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT",
+	}}
+)
+
+// cronRandomRegex matches a random field, optionally constrained to a range, such as R or R(10-20).
+var cronRandomRegex = regexp.MustCompile(`^R(?:\((\w+)-(\w+)\))?$`)
+
+// cronTemplateFields returns the fields of a template with 5 fields (minute hour day-of-month month
+// day-of-week), or 6 fields where the first is the second.
+func cronTemplateFields(template string) ([]string, []cronField, error) {
+	values := strings.Fields(template)
+
+	switch len(values) {
+	case 5:
+		return values, []cronField{cronMinute, cronHour, cronDay, cronMonth, cronWeekday}, nil
+	case 6:
+		return values, []cronField{cronSecond, cronMinute, cronHour, cronDay, cronMonth, cronWeekday}, nil
+	default:
+		return nil, nil, fmt.Errorf("expected 5 or 6 fields, got %d", len(values))
+	}
+}
+
+// ValidateCronTemplate returns an error describing the first problem with the template, if any.
+func ValidateCronTemplate(template string) error {
+	_, err := RenderCronTemplate(template, nil)
+	return err
+}
+
+// RenderCronTemplate returns the cron expression for template, where each field of the form R or
+// R(min-max) is replaced by a value derived from seed. The value of a field only depends on seed, its
+// position and its range, so that changing other fields of the template keeps it.
+func RenderCronTemplate(template string, seed []byte) (string, error) {
+	values, fields, err := cronTemplateFields(template)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		field := fields[i]
+
+		if match := cronRandomRegex.FindStringSubmatch(strings.ToUpper(value)); match != nil {
+			low, high := field.min, field.max
+			if field.name == cronWeekday.name {
+				high = 6
+			}
+
+			if match[1] != "" {
+				if low, err = field.parseValue(match[1]); err != nil {
+					return "", err
+				}
+				if high, err = field.parseValue(match[2]); err != nil {
+					return "", err
+				}
+				if low > high {
+					return "", fmt.Errorf("%s range %s is reversed", field.name, value)
+				}
+			}
+
+			result[i] = strconv.Itoa(low + int(cronRandomUint64(seed, i)%uint64(high-low+1)))
+			continue
+		}
+
+		if err := field.validate(value); err != nil {
+			return "", err
+		}
+		result[i] = value
+	}
+
+	return strings.Join(result, " "), nil
+}
+
+// cronRandomUint64 derives a number from seed for the field at the given position.
+func cronRandomUint64(seed []byte, position int) uint64 {
+	mac := hmac.New(sha256.New, seed)
+	mac.Write([]byte("field-" + strconv.Itoa(position)))
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+// validate checks a field that is not random: a list of *, values or ranges, each with an optional step.
+func (f cronField) validate(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n < 1 || n > f.max {
+				return fmt.Errorf("invalid %s step %q", f.name, step)
+			}
+		}
+
+		if rangePart == "*" || (rangePart == "?" && (f.name == cronDay.name || f.name == cronWeekday.name)) {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(rangePart, "-")
+		lowValue, err := f.parseValue(low)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+
+		highValue, err := f.parseValue(high)
+		if err != nil {
+			return err
+		}
+		if lowValue > highValue {
+			return fmt.Errorf("%s range %s is reversed", f.name, rangePart)
+		}
+	}
+
+	return nil
+}
+
+// parseValue parses a single number, or for month and day of week a three-letter name.
+func (f cronField) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			if f.name == cronMonth.name {
+				return i + 1, nil
+			}
+			return i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, value)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s %d is out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
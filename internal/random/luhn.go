@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LuhnModNCheckCharacter returns the check character for input according to the Luhn mod N algorithm,
+// where N is the length of alphabet and the code point of a character is its position in alphabet.
+// With alphabet "0123456789" this is the regular Luhn check digit.
+func LuhnModNCheckCharacter(input string, alphabet string) (byte, error) {
+	n := len(alphabet)
+	factor := 2
+	sum := 0
+
+	// Starting from the right, double every other code point, beginning with the rightmost
+	for i := len(input) - 1; i >= 0; i-- {
+		codePoint := strings.IndexByte(alphabet, input[i])
+		if codePoint < 0 {
+			return 0, fmt.Errorf("character %q is not in the alphabet", input[i])
+		}
+
+		addend := factor * codePoint
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+
+		// Sum the digits of the addend, expressed in base n
+		sum += addend/n + addend%n
+	}
+
+	return alphabet[(n-sum%n)%n], nil
+}
+
+// ValidLuhnModN reports whether the last character of input is the Luhn mod N check character for the
+// characters preceding it.
+func ValidLuhnModN(input string, alphabet string) bool {
+	if len(input) < 2 {
+		return false
+	}
+
+	check, err := LuhnModNCheckCharacter(input[:len(input)-1], alphabet)
+	if err != nil {
+		return false
+	}
+
+	return check == input[len(input)-1]
+}
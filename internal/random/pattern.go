@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// patternClasses are the characters of a pattern that stand for a character chosen from a set.
+var patternClasses = map[rune]string{
+	'a': lowerChars,
+	'A': upperChars,
+	'9': numChars,
+	'#': defaultSpecialChars,
+}
+
+// maxPatternRepeat bounds the count of a single repetition, well above the length of any string that can be
+// stored.
+const maxPatternRepeat = 1 << 20
+
+// patternElement is a position of a pattern, repeated count times. A literal character is the only character
+// of chars.
+type patternElement struct {
+	chars string
+	count int64
+}
+
+// StringPattern is a parsed pattern, see ParseStringPattern.
+type StringPattern []patternElement
+
+// ValidateStringPattern returns an error describing the first problem with the pattern, if any.
+func ValidateStringPattern(pattern string) error {
+	_, err := ParseStringPattern(pattern)
+	return err
+}
+
+// ParseStringPattern parses a template of the strings to generate, where a is a lowercase letter, A an
+// uppercase letter, 9 a digit and # a special character, each chosen at random. Other characters are kept as
+// they are, and a backslash keeps the character that follows it, e.g. \9 or \\. {n} repeats the preceding
+// character n times, so that AAA-9{4} generates strings such as XKC-0421.
+func ParseStringPattern(pattern string) (StringPattern, error) {
+	if pattern == "" {
+		return nil, errors.New("the pattern is empty")
+	}
+
+	var result StringPattern
+	repeatable := false
+	for i := 0; i < len(pattern); {
+		character, size := utf8.DecodeRuneInString(pattern[i:])
+		if character == utf8.RuneError && size == 1 {
+			return nil, fmt.Errorf("invalid UTF-8 at position %d", i+1)
+		}
+
+		switch character {
+		case '\\':
+			escaped, escapedSize := utf8.DecodeRuneInString(pattern[i+size:])
+			if escapedSize == 0 {
+				return nil, errors.New("the pattern ends with an unescaped \\")
+			}
+			result = append(result, patternElement{chars: string(escaped), count: 1})
+			size += escapedSize
+			repeatable = true
+		case '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("the { at position %d is not closed", i+1)
+			}
+			if !repeatable {
+				return nil, fmt.Errorf("the repetition at position %d does not follow a character, escape { to "+
+					"use it as a character", i+1)
+			}
+			count, err := strconv.ParseInt(pattern[i+1:i+end], 10, 64)
+			if err != nil || count < 1 || count > maxPatternRepeat {
+				return nil, fmt.Errorf("the repetition %s at position %d must hold a number from 1 to %d",
+					pattern[i:i+end+1], i+1, maxPatternRepeat)
+			}
+			result[len(result)-1].count = count
+			size = end + 1
+			repeatable = false
+		case '}':
+			return nil, fmt.Errorf("the } at position %d is not opened, escape it to use it as a character", i+1)
+		default:
+			chars, ok := patternClasses[character]
+			if !ok {
+				chars = string(character)
+			}
+			result = append(result, patternElement{chars: chars, count: 1})
+			repeatable = true
+		}
+		i += size
+	}
+	return result, nil
+}
+
+// Length returns the number of characters of the strings that the pattern generates.
+func (p StringPattern) Length() int64 {
+	var length int64
+	for _, element := range p {
+		length += element.count
+	}
+	return length
+}
+
+// Size returns the size in bytes of the strings that the pattern generates, which is the same for all of them
+// since only literal characters can take more than one byte.
+func (p StringPattern) Size() int64 {
+	var size int64
+	for _, element := range p {
+		character, _ := utf8.DecodeRuneInString(element.chars)
+		size += int64(utf8.RuneLen(character)) * element.count
+	}
+	return size
+}
+
+// Generate returns a string matching the pattern, with each character chosen independently and uniformly at
+// random from the set of its position.
+func (p StringPattern) Generate() ([]byte, error) {
+	result := make([]byte, 0, p.Size())
+	for _, element := range p {
+		s, err := createFromAlphabet(element.chars, element.count)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s...)
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// StringPresets holds string parameters that satisfy the password policy of common target systems.
+var StringPresets = map[string]StringParams{
+	// Azure SQL requires 8 to 128 characters from at least three of uppercase, lowercase, digits and
+	// non-alphanumeric characters. Characters that need escaping in connection strings (;'"{}=@&) are avoided.
+	"azure_sql": {
+		Length:          32,
+		Upper:           true,
+		MinUpper:        1,
+		Lower:           true,
+		MinLower:        1,
+		Numeric:         true,
+		MinNumeric:      1,
+		Special:         true,
+		MinSpecial:      1,
+		OverrideSpecial: "!#$%*()-_+[]<>?",
+	},
+	// Active Directory Domain Services complexity requires characters from at least three of uppercase,
+	// lowercase, digits and non-alphanumeric characters, and at most 127 characters for compatibility with
+	// older clients. Quotes and backslashes are avoided, as they need escaping in most tooling.
+	"active_directory": {
+		Length:          24,
+		Upper:           true,
+		MinUpper:        1,
+		Lower:           true,
+		MinLower:        1,
+		Numeric:         true,
+		MinNumeric:      1,
+		Special:         true,
+		MinSpecial:      1,
+		OverrideSpecial: "!@#$%^&*_-+=()[]{}<>?~",
+	},
+	// PostgreSQL (and Azure Database for PostgreSQL, which requires 8 to 128 characters from three of four
+	// categories) passwords are often embedded in connection URIs, so only unreserved URI characters are used.
+	"postgres": {
+		Length:          32,
+		Upper:           true,
+		MinUpper:        1,
+		Lower:           true,
+		MinLower:        1,
+		Numeric:         true,
+		MinNumeric:      1,
+		Special:         true,
+		MinSpecial:      1,
+		OverrideSpecial: "-_.~",
+	},
+	"generic_strong": {
+		Length:     32,
+		Upper:      true,
+		MinUpper:   2,
+		Lower:      true,
+		MinLower:   2,
+		Numeric:    true,
+		MinNumeric: 2,
+		Special:    true,
+		MinSpecial: 2,
+	},
+	// Storage account names are 3 to 24 lowercase letters and digits, and are often a fixed prefix followed by
+	// a random suffix.
+	"storage_account": {
+		Length:  24,
+		Lower:   true,
+		Numeric: true,
+	},
+	// Container registry names are 5 to 50 alphanumeric characters. Lowercase is used, as the login server
+	// is lowercase.
+	"container_registry": {
+		Length:  24,
+		Lower:   true,
+		Numeric: true,
+	},
+	// DNS labels, such as the domain name label of a public IP address, are 3 to 63 lowercase letters, digits
+	// and hyphens, starting with a letter and not ending with a hyphen. Hyphens are left out, as they could end
+	// up last.
+	"dns_label": {
+		Length:         16,
+		Lower:          true,
+		Numeric:        true,
+		FirstCharAlpha: true,
+	},
+}
+
+// StringNamingRule describes the names that a target system accepts, which the strings generated with a
+// naming preset satisfy.
+type StringNamingRule struct {
+	MinLength int64
+	MaxLength int64
+	// Allowed holds every character that a name may contain
+	Allowed string
+	// FirstCharAlpha requires names to start with a letter
+	FirstCharAlpha bool
+}
+
+// StringNamingRules holds the naming rules of the presets for naming targets, by preset name.
+var StringNamingRules = map[string]StringNamingRule{
+	"storage_account": {
+		MinLength: 3,
+		MaxLength: 24,
+		Allowed:   lowerChars + numChars,
+	},
+	"container_registry": {
+		MinLength: 5,
+		MaxLength: 50,
+		Allowed:   upperChars + lowerChars + numChars,
+	},
+	"dns_label": {
+		MinLength:      3,
+		MaxLength:      63,
+		Allowed:        lowerChars + numChars,
+		FirstCharAlpha: true,
+	},
+}
+
+// Check returns an error when strings generated with params, including their prefix and suffix, may break rule.
+func (rule StringNamingRule) Check(params StringParams) error {
+	affixes := int64(utf8.RuneCountInString(params.Prefix) + utf8.RuneCountInString(params.Suffix))
+	if affixes >= rule.MaxLength {
+		return fmt.Errorf("the prefix and suffix take up %d characters, leaving none of the at most %d", affixes, rule.MaxLength)
+	}
+	if params.Length+affixes < rule.MinLength || params.Length+affixes > rule.MaxLength {
+		if affixes > 0 {
+			return fmt.Errorf("length must be between %d and %d along with the %d characters of the prefix and "+
+				"suffix, but is %d", max(rule.MinLength-affixes, 1), rule.MaxLength-affixes, affixes, params.Length)
+		}
+		return fmt.Errorf("length must be between %d and %d, but is %d", rule.MinLength, rule.MaxLength, params.Length)
+	}
+
+	for _, r := range params.Prefix + params.Suffix {
+		if !strings.ContainsRune(rule.Allowed, r) {
+			return fmt.Errorf("the prefix or suffix contains %q, which is not allowed", r)
+		}
+	}
+
+	// Characters are chosen from the enabled sets, and from every set with a minimum count
+	chars := removeCharacters(params.Charset, params.ExcludeCharacters)
+	if params.Charset == "" {
+		chars = ""
+		for _, pool := range params.pools() {
+			if pool.enabled || pool.min > 0 {
+				chars += pool.chars
+			}
+		}
+	}
+	for _, r := range chars {
+		if !strings.ContainsRune(rule.Allowed, r) {
+			return fmt.Errorf("the result may contain %q, which is not allowed", r)
+		}
+	}
+
+	if rule.FirstCharAlpha && params.Prefix != "" {
+		if !strings.ContainsRune(upperChars+lowerChars, []rune(params.Prefix)[0]) {
+			return errors.New("the result must start with a letter, but the prefix does not")
+		}
+	} else if rule.FirstCharAlpha && !params.FirstCharAlpha {
+		return errors.New("the result must start with a letter, which requires first_char_alpha")
+	}
+	return nil
+}
+
+// StringPresetNames returns the names of the supported string presets, sorted.
+func StringPresetNames() []string {
+	names := make([]string, 0, len(StringPresets))
+	for name := range StringPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -6,8 +6,11 @@ package random
 import (
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"math/big"
 	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 type StringParams struct {
@@ -21,55 +24,193 @@ type StringParams struct {
 	Special         bool
 	MinSpecial      int64
 	OverrideSpecial string
+	// Charset, when set, replaces the character sets above, which are then ignored
+	Charset string
+	// ExcludeCharacters are removed from all character sets, including Charset
+	ExcludeCharacters string
+	// FirstCharAlpha chooses the first character from the enabled uppercase and lowercase sets. It is ignored
+	// with Charset.
+	FirstCharAlpha bool
+	// Pattern, when set, is a template for the string, see ParseStringPattern. All the other parameters above are
+	// then ignored.
+	Pattern string
+	// Prefix and Suffix are kept as they are before and after the generated characters. They do not count towards
+	// Length or the minimum counts.
+	Prefix string
+	Suffix string
 }
 
-func CreateString(input StringParams) ([]byte, error) {
-	const numChars = "0123456789"
-	const lowerChars = "abcdefghijklmnopqrstuvwxyz"
-	const upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	var specialChars = "!@#$%&*()-_=+[]{}<>:?"
-	var result []byte
+const (
+	numChars            = "0123456789"
+	lowerChars          = "abcdefghijklmnopqrstuvwxyz"
+	upperChars          = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	defaultSpecialChars = "!@#$%&*()-_=+[]{}<>:?"
+)
 
+// stringPool is a character set from which at least min characters are chosen, and, when it is enabled, the
+// remaining characters as well.
+type stringPool struct {
+	name    string
+	chars   string
+	min     int64
+	enabled bool
+	alpha   bool
+}
+
+// pools returns the character sets of input, without the excluded characters. The name of each is the
+// attribute that sets its minimum count.
+func (input StringParams) pools() []stringPool {
+	specialChars := defaultSpecialChars
 	if input.OverrideSpecial != "" {
 		specialChars = input.OverrideSpecial
 	}
 
+	return []stringPool{
+		{name: "min_upper", chars: removeCharacters(upperChars, input.ExcludeCharacters), min: input.MinUpper, enabled: input.Upper, alpha: true},
+		{name: "min_lower", chars: removeCharacters(lowerChars, input.ExcludeCharacters), min: input.MinLower, enabled: input.Lower, alpha: true},
+		{name: "min_numeric", chars: removeCharacters(numChars, input.ExcludeCharacters), min: input.MinNumeric, enabled: input.Numeric},
+		{name: "min_special", chars: removeCharacters(specialChars, input.ExcludeCharacters), min: input.MinSpecial, enabled: input.Special},
+	}
+}
+
+// ValidateExclusions returns an error when ExcludeCharacters removes every character from a character set of
+// input that a minimum count requires characters from, or from all the character sets input draws from.
+func ValidateExclusions(input StringParams) error {
+	if input.ExcludeCharacters == "" {
+		return nil
+	}
+
+	if input.Charset != "" {
+		if removeCharacters(input.Charset, input.ExcludeCharacters) == "" {
+			return errors.New("every character of the charset is excluded")
+		}
+		return nil
+	}
+
+	for _, pool := range input.pools() {
+		if pool.min > 0 && pool.chars == "" {
+			return fmt.Errorf("every character that %s requires is excluded", pool.name)
+		}
+	}
+	if input.chars() == "" {
+		return errors.New("every character of the enabled character sets is excluded")
+	}
+	return nil
+}
+
+// ValidateFirstCharAlpha returns an error when FirstCharAlpha is set, but input has no letters to start the
+// string with, or the letter would leave too few characters for the minimum counts.
+func ValidateFirstCharAlpha(input StringParams) error {
+	if !input.FirstCharAlpha || input.Charset != "" {
+		return nil
+	}
+
+	if firstLetters(input, input.pools()) == "" {
+		return errors.New("no enabled uppercase or lowercase letters can start the string while leaving room " +
+			"for the min_* counts")
+	}
+	return nil
+}
+
+// firstLetters returns the characters that the first character of a string of input can be chosen from. When
+// the minimum counts take up the whole length, only the letters of sets with a minimum count are returned, so
+// that choosing one of them still leaves room for all the counts.
+func firstLetters(input StringParams, pools []stringPool) string {
+	free := input.Length
+	for _, pool := range pools {
+		free -= pool.min
+	}
+
+	var letters string
+	for _, pool := range pools {
+		if pool.alpha && pool.enabled && (free > 0 || pool.min > 0) {
+			letters += pool.chars
+		}
+	}
+	return letters
+}
+
+// chars returns the characters of the enabled character sets of input, without the excluded characters.
+func (input StringParams) chars() string {
 	var chars = ""
-	if input.Upper {
-		chars += upperChars
+	for _, pool := range input.pools() {
+		if pool.enabled {
+			chars += pool.chars
+		}
 	}
-	if input.Lower {
-		chars += lowerChars
+	return chars
+}
+
+// removeCharacters returns chars without the characters of excluded.
+func removeCharacters(chars string, excluded string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
+
+// CreateString returns a string generated with input, between its prefix and suffix.
+func CreateString(input StringParams) ([]byte, error) {
+	result, err := createRandomString(input)
+	if err != nil {
+		return nil, err
 	}
-	if input.Numeric {
-		chars += numChars
+
+	return append(append([]byte(input.Prefix), result...), input.Suffix...), nil
+}
+
+// createRandomString returns the generated characters of a string of input, without its prefix and suffix.
+func createRandomString(input StringParams) ([]byte, error) {
+	if input.Pattern != "" {
+		pattern, err := ParseStringPattern(input.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		return pattern.Generate()
 	}
-	if input.Special {
-		chars += specialChars
+
+	if input.Charset != "" {
+		return createFromAlphabet(removeCharacters(input.Charset, input.ExcludeCharacters), input.Length)
 	}
 
+	var result []byte
+
+	chars := input.chars()
 	if chars == "" {
 		return nil, errors.New("the character set specified is empty")
 	}
 
-	minMapping := map[string]int64{
-		numChars:     input.MinNumeric,
-		lowerChars:   input.MinLower,
-		upperChars:   input.MinUpper,
-		specialChars: input.MinSpecial,
-	}
-
 	result = make([]byte, 0, input.Length)
+	pools := input.pools()
+
+	// The first character is a letter, which counts towards the minimum of its set
+	var first []byte
+	if input.FirstCharAlpha {
+		letters := firstLetters(input, pools)
+		var err error
+		first, err = generateRandomBytes(&letters, 1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to choose a letter to start the string with: %w", err)
+		}
+		for i := range pools {
+			if pools[i].alpha && pools[i].min > 0 && strings.IndexByte(pools[i].chars, first[0]) >= 0 {
+				pools[i].min--
+				break
+			}
+		}
+	}
 
-	for k, v := range minMapping {
-		s, err := generateRandomBytes(&k, v)
+	for _, pool := range pools {
+		s, err := generateRandomBytes(&pool.chars, pool.min)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, s...)
 	}
 
-	s, err := generateRandomBytes(&chars, input.Length-int64(len(result)))
+	s, err := generateRandomBytes(&chars, input.Length-int64(len(first)+len(result)))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +226,7 @@ func CreateString(input StringParams) ([]byte, error) {
 		return order[i] < order[j]
 	})
 
-	return result, nil
+	return append(first, result...), nil
 }
 
 func generateRandomBytes(charSet *string, length int64) ([]byte, error) {
@@ -108,3 +249,87 @@ func generateRandomBytes(charSet *string, length int64) ([]byte, error) {
 	}
 	return bytes, nil
 }
+
+// CreateFromCharset returns length characters chosen uniformly at random from charSet.
+func CreateFromCharset(charSet string, length int64) ([]byte, error) {
+	return generateRandomBytes(&charSet, length)
+}
+
+// createFromAlphabet returns length characters chosen uniformly at random from the distinct characters of
+// alphabet, which may hold any UTF-8 characters. Characters that occur more than once are not more likely.
+func createFromAlphabet(alphabet string, length int64) ([]byte, error) {
+	var characters []rune
+	seen := map[rune]bool{}
+	for _, character := range alphabet {
+		if !seen[character] {
+			seen[character] = true
+			characters = append(characters, character)
+		}
+	}
+
+	if len(characters) == 0 {
+		return nil, errors.New("the character set specified is empty")
+	}
+
+	result := make([]byte, 0, length)
+	setLen := big.NewInt(int64(len(characters)))
+	for i := int64(0); i < length; i++ {
+		idx, err := rand.Int(rand.Reader, setLen)
+		if err != nil {
+			return nil, err
+		}
+		result = utf8.AppendRune(result, characters[idx.Int64()])
+	}
+	return result, nil
+}
+
+// Satisfies returns whether value could have been generated with input: it starts with Prefix and ends with
+// Suffix, and the characters between them are Length characters of Charset or of the character sets above,
+// without the excluded characters, with at least the minimum count of each set and, with FirstCharAlpha, a
+// letter first. A character of a disabled set only satisfies input when the set has a minimum count, like the
+// characters that CreateString draws for it. Strings of a Pattern are never considered to satisfy input.
+func (input StringParams) Satisfies(value string) bool {
+	if input.Pattern != "" || len(value) < len(input.Prefix)+len(input.Suffix) ||
+		!strings.HasPrefix(value, input.Prefix) || !strings.HasSuffix(value, input.Suffix) {
+		return false
+	}
+	value = value[len(input.Prefix) : len(value)-len(input.Suffix)]
+	if int64(utf8.RuneCountInString(value)) != input.Length {
+		return false
+	}
+
+	if input.Charset != "" {
+		charset := removeCharacters(input.Charset, input.ExcludeCharacters)
+		for _, r := range value {
+			if !strings.ContainsRune(charset, r) {
+				return false
+			}
+		}
+		return true
+	}
+
+	pools := input.pools()
+	if input.FirstCharAlpha && (value == "" || !strings.ContainsRune(firstLetters(input, pools), []rune(value)[0])) {
+		return false
+	}
+
+	counts := make([]int64, len(pools))
+	for _, r := range value {
+		allowed := false
+		for i, pool := range pools {
+			if strings.ContainsRune(pool.chars, r) {
+				counts[i]++
+				allowed = allowed || pool.enabled || pool.min > 0
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	for i, pool := range pools {
+		if counts[i] < pool.min {
+			return false
+		}
+	}
+	return true
+}
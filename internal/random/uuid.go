@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// CreateUUIDv7 returns a version 7 UUID as defined by RFC 9562, in its canonical form. The first 48 bits hold
+// the Unix time of now in milliseconds, so that UUIDs created later sort after those created earlier, followed
+// by the version, 74 random bits and the variant.
+func CreateUUIDv7(now time.Time) (string, error) {
+	// The timestamp takes the first 6 bytes, which are random for now
+	b, err := CreateBytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(now.UnixMilli()))
+	copy(b[:6], timestamp[2:])
+
+	return formatUUID(b, 7), nil
+}
+
+// CreateUUIDv5 returns the version 5 UUID of name in namespace, a UUID, as defined by RFC 4122, in its canonical
+// form. It holds the first 16 bytes of the SHA-1 of namespace and name apart from the version and variant, so the
+// same inputs always return the same UUID.
+func CreateUUIDv5(namespace string, name string) (string, error) {
+	namespaceBytes, err := uuid.ParseUUID(namespace)
+	if err != nil {
+		return "", fmt.Errorf("invalid namespace: %w", err)
+	}
+
+	hash := sha1.New()
+	hash.Write(namespaceBytes)
+	hash.Write([]byte(name))
+
+	return formatUUID(hash.Sum(nil)[:16], 5), nil
+}
+
+// formatUUID returns the 16 bytes of b in the canonical form of a UUID, after setting their version and variant
+// bits.
+func formatUUID(b []byte, version byte) string {
+	// The version in the high nibble of byte 6, and variant 10 in the high bits of byte 8
+	b[6] = version<<4 | b[6]&0x0f
+	b[8] = 0x80 | b[8]&0x3f
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package random
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// WeightedChoice returns one of the keys of weights, chosen with a probability proportional to its weight.
+// Options with a weight of 0 are never chosen.
+func WeightedChoice(weights map[string]int64) (string, error) {
+	options := make([]string, 0, len(weights))
+	total := int64(0)
+	for option, weight := range weights {
+		if weight < 0 {
+			return "", errors.New("weights must not be negative")
+		}
+		options = append(options, option)
+		total += weight
+	}
+	if total < 1 {
+		return "", errors.New("at least one option must have a positive weight")
+	}
+
+	// Sort so that the same number picks the same option regardless of map order
+	sort.Strings(options)
+
+	n, err := rand.Int(rand.Reader, big.NewInt(total))
+	if err != nil {
+		return "", err
+	}
+
+	remaining := n.Int64()
+	for _, option := range options {
+		if remaining < weights[option] {
+			return option, nil
+		}
+		remaining -= weights[option]
+	}
+
+	return "", errors.New("no option was chosen")
+}
@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// accessErrorBody returns the JSON body of an error response of the vault.
+func accessErrorBody(code string, message string, innerCode string) string {
+	return `{"error": {"code": "` + code + `", "message": "` + message + `", "innererror": {"code": "` + innerCode + `"}}}`
+}
+
+func TestValidateAccess(t *testing.T) {
+	testCases := map[string]struct {
+		respond     func(req *http.Request) (*http.Response, error)
+		expectError bool
+	}{
+		"empty-vault": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusNotFound, nil, accessErrorBody("SecretNotFound", "not found", "")), nil
+			},
+		},
+		"existing-secret": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("azrandom-access-check", "v1", "value")), nil
+			},
+		},
+		"forbidden": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusForbidden, nil, accessErrorBody("Forbidden", "Caller is not authorized", "ForbiddenByRbac")), nil
+			},
+			expectError: true,
+		},
+		"network-error": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return nil, &net.DNSError{Err: "no such host", Name: req.URL.Host, IsNotFound: true}
+			},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var paths []string
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				return testCase.respond(req)
+			}})
+
+			err := azrandom.ValidateAccess(context.Background(), client)
+			if testCase.expectError && err == nil {
+				t.Error("expected an error")
+			}
+			if !testCase.expectError && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+			if len(paths) != 1 || paths[0] != "/secrets/azrandom-access-check/" {
+				t.Errorf("expected a single request for the access check secret, got %v", paths)
+			}
+		})
+	}
+}
+
+func TestAccessError(t *testing.T) {
+	responseError := func(status int, body string) error {
+		req, _ := http.NewRequest(http.MethodGet, fakeVaultUrl+"secrets/azrandom-access-check/", nil)
+		return runtime.NewResponseError(fakeResponse(req, status, nil, body))
+	}
+
+	testCases := map[string]struct {
+		err     error
+		summary string
+		detail  []string
+	}{
+		"unauthorized": {
+			err:     responseError(http.StatusUnauthorized, accessErrorBody("Unauthorized", "AKV10032: Invalid issuer", "")),
+			summary: "Vault Rejected the Token",
+			detail:  []string{"another tenant"},
+		},
+		"firewall": {
+			err: responseError(http.StatusForbidden, accessErrorBody("Forbidden",
+				"Client address is not authorized and caller is not a trusted service.", "ForbiddenByFirewall")),
+			summary: "Vault Firewall Denied Access",
+			detail:  []string{"networking rules"},
+		},
+		"rbac": {
+			err: responseError(http.StatusForbidden, accessErrorBody("Forbidden",
+				"Caller is not authorized to perform action on resource.", "ForbiddenByRbac")),
+			summary: "Vault Access Denied",
+			detail:  []string{"Key Vault Secrets Officer"},
+		},
+		"unreachable": {
+			err:     &net.DNSError{Err: "no such host", Name: "fake.vault.azure.net", IsNotFound: true},
+			summary: "Vault Unreachable",
+			detail:  []string{"Original Error: lookup fake.vault.azure.net: no such host"},
+		},
+		"timeout": {
+			err:     &azrandom.OperationTimeoutError{Operation: "getting secret", Name: "azrandom-access-check", Err: context.DeadlineExceeded},
+			summary: "Vault Unreachable",
+		},
+		"other": {
+			err:     responseError(http.StatusInternalServerError, accessErrorBody("InternalError", "fake", "")),
+			summary: "Vault Access Check Failed",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := diagnostics.AccessError(fakeVaultUrl, testCase.err)
+			if len(diags) != 1 {
+				t.Fatalf("expected a single diagnostic, got %d", len(diags))
+			}
+			if diags[0].Summary() != testCase.summary {
+				t.Errorf("expected summary %q, got %q", testCase.summary, diags[0].Summary())
+			}
+			for _, expected := range append(testCase.detail, fakeVaultUrl, "validate_access") {
+				if !strings.Contains(diags[0].Detail(), expected) {
+					t.Errorf("expected detail to contain %q, got:\n%s", expected, diags[0].Detail())
+				}
+			}
+		})
+	}
+}
+
+func TestProviderValidateAccess(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError bool
+	}{
+		"default": {},
+		"config": {
+			config:      map[string]any{"validate_access": true},
+			expectError: true,
+		},
+		"env": {
+			env:         map[string]string{"AZRANDOM_VALIDATE_ACCESS": "true"},
+			expectError: true,
+		},
+		"config-overrides-env": {
+			env:    map[string]string{"AZRANDOM_VALIDATE_ACCESS": "true"},
+			config: map[string]any{"validate_access": false},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_VALIDATE_ACCESS", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			// The proxy rejects every request, so that the vault cannot be reached
+			proxy := newTestProxy(t)
+			config := map[string]any{
+				"vault_url":                  fakeVaultUrl,
+				"skip_credential_validation": true,
+				"proxy_url":                  proxy.URL,
+				"retry":                      map[string]any{"max_retries": 0},
+			}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics := configureProvider(t, config)
+			if !testCase.expectError {
+				if len(diagnostics) != 0 {
+					t.Errorf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+				}
+				if hosts := proxy.hosts(); len(hosts) != 0 {
+					t.Errorf("expected no request to the vault, got requests for %v", hosts)
+				}
+				return
+			}
+
+			if len(diagnostics) != 1 || diagnostics[0].Summary != "Vault Unreachable" {
+				t.Fatalf("expected a single vault unreachable error, got %v", diagnostics)
+			}
+			if _, ok := proxy.received("fake.vault.azure.net:443"); !ok {
+				t.Errorf("expected the access check to be sent to the vault, got requests for %v", proxy.hosts())
+			}
+		})
+	}
+}
+
+func TestAccessErrorIsResponseError(t *testing.T) {
+	// The access check returns the response of the vault, which AccessError classifies by its status
+	client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusForbidden, nil, accessErrorBody("Forbidden",
+			"Client address is not authorized and caller is not a trusted service.", "ForbiddenByFirewall")), nil
+	}})
+
+	err := azrandom.ValidateAccess(context.Background(), client)
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		t.Fatalf("expected a response error, got %v", err)
+	}
+	if diags := diagnostics.AccessError(fakeVaultUrl, err); diags[0].Summary() != "Vault Firewall Denied Access" {
+		t.Errorf("expected the firewall to be reported, got %q", diags[0].Summary())
+	}
+}
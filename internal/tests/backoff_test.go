@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	backoff := azrandom.Backoff{
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+	}
+
+	expected := []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+	for i, interval := range expected {
+		if actual := backoff.Interval(i + 1); actual != interval {
+			t.Errorf("expected retry %d to wait %s, got %s", i+1, interval, actual)
+		}
+	}
+}
+
+// recoveringTransport answers the requests of CreateSecret for a soft-deleted secret, failing to set the
+// secret with a conflict until conflicts set requests have been made.
+func recoveringTransport(conflicts int32, sets *atomic.Int32) *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/deletedsecrets/"):
+			return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+		case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/recover"):
+			return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+		case req.Method == http.MethodPut:
+			if sets.Add(1) <= conflicts {
+				return fakeResponse(req, http.StatusConflict, nil, `{"error": {"code": "Conflict", "message": "Secret is currently being recovered"}}`), nil
+			}
+			return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "2", "value")), nil
+		default:
+			return fakeResponse(req, http.StatusNotFound, nil, `{"error": {"code": "NotFound", "message": "fake"}}`), nil
+		}
+	}}
+}
+
+func TestCreateSecretWithBackoffRetries(t *testing.T) {
+	var sets atomic.Int32
+	client := newFakeClient(t, recoveringTransport(2, &sets))
+
+	backoff := azrandom.Backoff{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		Multiplier:      2,
+		MaxElapsedTime:  5 * time.Second,
+	}
+
+	version, _, err := azrandom.CreateSecretWithBackoff(context.Background(), client, "test", "value", azrandom.SecretProperties{}, backoff)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if version != "2" {
+		t.Errorf("expected version 2, got %s", version)
+	}
+	if sets.Load() != 3 {
+		t.Errorf("expected 3 attempts to set the secret, got %d", sets.Load())
+	}
+}
+
+func TestCreateSecretWithBackoffMaxElapsedTime(t *testing.T) {
+	var sets atomic.Int32
+	client := newFakeClient(t, recoveringTransport(1000, &sets))
+
+	backoff := azrandom.Backoff{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		Multiplier:      2,
+		MaxElapsedTime:  100 * time.Millisecond,
+	}
+
+	_, _, err := azrandom.CreateSecretWithBackoff(context.Background(), client, "test", "value", azrandom.SecretProperties{}, backoff)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if sets.Load() < 2 || sets.Load() > 11 {
+		t.Errorf("expected between 2 and 11 attempts to set the secret, got %d", sets.Load())
+	}
+}
+
+func TestCreateSecretWithBackoffCancellation(t *testing.T) {
+	var sets atomic.Int32
+	client := newFakeClient(t, recoveringTransport(1000, &sets))
+
+	backoff := azrandom.Backoff{
+		InitialInterval: 10 * time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		MaxElapsedTime:  time.Minute,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := azrandom.CreateSecretWithBackoff(ctx, client, "test", "value", azrandom.SecretProperties{}, backoff)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected cancellation to abort promptly, took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a context deadline error, got %v", err)
+	}
+}
+
+// Only a deleted secret that is not found is no secret to recover, other errors of the vault are returned.
+func TestRecoverDeletedSecret(t *testing.T) {
+	testCases := map[string]struct {
+		status         int
+		body           string
+		expectedStatus int
+	}{
+		"not-found": {
+			status: http.StatusNotFound,
+			body:   `{"error": {"code": "SecretNotFound", "message": "Deleted Secret not found: test"}}`,
+		},
+		"forbidden": {
+			status:         http.StatusForbidden,
+			body:           `{"error": {"code": "Forbidden", "message": "The user does not have secrets get deleted permission"}}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		"server-error": {
+			status:         http.StatusInternalServerError,
+			body:           `{"error": {"code": "InternalServerError", "message": "fake"}}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/deletedsecrets/") {
+					return fakeResponse(req, testCase.status, nil, testCase.body), nil
+				}
+				t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				return fakeResponse(req, http.StatusBadRequest, nil, `{"error": {"code": "BadRequest", "message": "fake"}}`), nil
+			}})
+
+			recovered, err := azrandom.RecoverDeletedSecret(context.Background(), client, "test")
+			if recovered {
+				t.Error("expected no secret to be recovered")
+			}
+			if testCase.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			responseErr, ok := azrandom.AsResponseError(err)
+			if !ok || responseErr.StatusCode != testCase.expectedStatus {
+				t.Fatalf("expected a response error with status %d, got %v", testCase.expectedStatus, err)
+			}
+		})
+	}
+}
+
+// The recovered secret is read according to the given backoff until it is no longer recovering, and the wait
+// gives up once the backoff has elapsed.
+func TestRecoverDeletedSecretWithBackoff(t *testing.T) {
+	testCases := map[string]struct {
+		recovering  int32
+		expectError bool
+	}{
+		"recovered": {recovering: 2},
+		"timeout":   {recovering: 1000, expectError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var gets atomic.Int32
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				switch {
+				case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/deletedsecrets/"):
+					return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+				case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/recover"):
+					return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+				case req.Method == http.MethodGet && gets.Add(1) <= testCase.recovering:
+					return fakeResponse(req, http.StatusNotFound, nil, `{"error": {"code": "SecretNotFound", "message": "fake"}}`), nil
+				default:
+					return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+				}
+			}})
+
+			backoff := azrandom.Backoff{
+				InitialInterval: 10 * time.Millisecond,
+				MaxInterval:     10 * time.Millisecond,
+				Multiplier:      2,
+				MaxElapsedTime:  100 * time.Millisecond,
+			}
+
+			start := time.Now()
+			recovered, err := azrandom.RecoverDeletedSecretWithBackoff(context.Background(), client, "test", backoff)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("expected the wait to follow the backoff, took %s", elapsed)
+			}
+			if !recovered {
+				t.Error("expected the secret to be recovered")
+			}
+			if testCase.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if gets.Load() != testCase.recovering+1 {
+				t.Errorf("expected %d attempts to read the secret, got %d", testCase.recovering+1, gets.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+// bip39Vectors are the test vectors from the BIP-39 specification, which use the passphrase "TREZOR".
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		entropy:  "808080808080808080808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		seed:     "107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+	{
+		entropy:  "77c2b00716cec7213839159e404db50d",
+		mnemonic: "jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		seed:     "b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+	},
+	{
+		entropy:  "b63a9c59a6e641f288ebc103017f1da9f8290b3da6bdef7b",
+		mnemonic: "renew stay biology evidence goat welcome casual join adapt armor shuffle fault little machine walk stumble urge swap",
+		seed:     "9248d83e06f4cd98debf5b6f010542760df925ce46cf38a1bdb4e4de7d21f5c39366941c69e1bdbf2966e0f6e6dbece898a0e2f0a4c2b3e640953dfe8b7bbdc5",
+	},
+	{
+		entropy:  "3e141609b97933b66a060dcddc71fad1d91677db872031e85f4c015c5e7e8982",
+		mnemonic: "dignity pass list indicate nasty swamp pool script soccer toe leaf photo multiply desk host tomato cradle drill spread actor shine dismiss champion exotic",
+		seed:     "ff7f3184df8696d8bef94b6c03114dbee0ef89ff938712301d27ed8336ca89ef9635da20af07d4175f2bf5f3de130f39c9d9e8dd0472489c19b1a020a940da67",
+	},
+	{
+		entropy:  "15da872c95a13dd738fbf50e427583ad61f18fd99f628c417a61cf8343c90419",
+		mnemonic: "beyond stage sleep clip because twist token leaf atom beauty genius food business side grid unable middle armed observe pair crouch tonight away coconut",
+		seed:     "b15509eaa2d09d3efd3e006ef42151b30367dc6e3aa5e44caba3fe4d3e352e65101fbdb86a96776b91946ff06f8eac594dc6ee1d3e82a42dfe1b40fef6bcc3fd",
+	},
+}
+
+func TestBip39MnemonicFromEntropy(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		entropy, _ := hex.DecodeString(vector.entropy)
+
+		mnemonic, err := random.Bip39MnemonicFromEntropy(entropy)
+		if err != nil {
+			t.Fatalf("unexpected error for entropy %s: %s", vector.entropy, err)
+		}
+		if mnemonic != vector.mnemonic {
+			t.Errorf("expected mnemonic %q for entropy %s, got %q", vector.mnemonic, vector.entropy, mnemonic)
+		}
+	}
+}
+
+func TestBip39EntropyFromMnemonic(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		entropy, err := random.Bip39EntropyFromMnemonic(vector.mnemonic)
+		if err != nil {
+			t.Fatalf("unexpected error for mnemonic %q: %s", vector.mnemonic, err)
+		}
+		if hex.EncodeToString(entropy) != vector.entropy {
+			t.Errorf("expected entropy %s for mnemonic %q, got %x", vector.entropy, vector.mnemonic, entropy)
+		}
+	}
+
+	invalid := []string{
+		// Wrong number of words
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow yellow",
+		// Not in the wordlist
+		"letter advice cage absurd amount doctor acoustic avoid letter advice caged above",
+		// Wrong checksum
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo why",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+	}
+	for _, mnemonic := range invalid {
+		if _, err := random.Bip39EntropyFromMnemonic(mnemonic); err == nil {
+			t.Errorf("expected an error for mnemonic %q", mnemonic)
+		}
+	}
+}
+
+func TestBip39Seed(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		seed := random.Bip39Seed(vector.mnemonic, "TREZOR")
+		if hex.EncodeToString(seed) != vector.seed {
+			t.Errorf("expected seed %s for mnemonic %q, got %x", vector.seed, vector.mnemonic, seed)
+		}
+	}
+}
+
+func TestCreateBip39Mnemonic(t *testing.T) {
+	for _, words := range []int64{12, 15, 18, 21, 24} {
+		mnemonic, err := random.CreateBip39Mnemonic(words)
+		if err != nil {
+			t.Fatalf("unexpected error for %d words: %s", words, err)
+		}
+		if got := len(strings.Fields(mnemonic)); int64(got) != words {
+			t.Errorf("expected %d words, got %d", words, got)
+		}
+		if _, err := random.Bip39EntropyFromMnemonic(mnemonic); err != nil {
+			t.Errorf("expected a valid mnemonic, got %q: %s", mnemonic, err)
+		}
+	}
+}
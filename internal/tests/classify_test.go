@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+)
+
+// Canned error bodies, as returned by the vault.
+const (
+	firewallErrorBody = `{"error": {"code": "Forbidden", "message": "Client address is not authorized and caller is not a ` +
+		`trusted service.\r\nClient address: 203.0.113.7\r\nCaller: appid=00000000-0000-0000-0000-000000000001;` +
+		`oid=00000000-0000-0000-0000-000000000002\r\nVault: classify-test;location=westeurope", ` +
+		`"innererror": {"code": "ForbiddenByFirewall"}}}`
+	rbacErrorBody = `{"error": {"code": "Forbidden", "message": "Caller is not authorized to perform action on resource.` +
+		`\r\nIf role assignments, deny assignments or role definitions were changed recently, please observe ` +
+		`propagation time.\r\nCaller: appid=00000000-0000-0000-0000-000000000001;` +
+		`oid=00000000-0000-0000-0000-000000000002\r\nAction: 'Microsoft.KeyVault/vaults/secrets/getSecret/action'` +
+		`\r\nResource: '/subscriptions/00000000-0000-0000-0000-000000000003/resourcegroups/classify-test/providers/` +
+		`microsoft.keyvault/vaults/classify-test/secrets/classify-test'\r\nAssignment: (not found)\r\nDecisionReason: ` +
+		`'DeniedWithNoValidRBAC'\r\nVault: classify-test;location=westeurope", ` +
+		`"innererror": {"code": "ForbiddenByRbac"}}}`
+	accessPolicyErrorBody = `{"error": {"code": "Forbidden", "message": "The user, group or application ` +
+		`'appid=00000000-0000-0000-0000-000000000001;oid=00000000-0000-0000-0000-000000000002' does not have secrets ` +
+		`get permission on key vault 'classify-test;location=westeurope'.", "innererror": {"code": "AccessDenied"}}}`
+	disabledErrorBody = `{"error": {"code": "Forbidden", "message": "Operation get is not allowed on a disabled ` +
+		`secret.", "innererror": {"code": "SecretDisabled"}}}`
+	beingDeletedErrorBody = `{"error": {"code": "Conflict", "message": "Secret classify-test is currently being ` +
+		`deleted.", "innererror": {"code": "ObjectIsBeingDeleted"}}}`
+	deletedButRecoverableErrorBody = `{"error": {"code": "Conflict", "message": "Secret classify-test is currently ` +
+		`in a deleted but recoverable state, and its name cannot be reused; in this state, the secret can only be ` +
+		`recovered or purged.", "innererror": {"code": "ObjectIsDeletedButRecoverable"}}}`
+	conflictErrorBody = `{"error": {"code": "Conflict", "message": "There was a conflict while recovering secret ` +
+		`classify-test."}}`
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := map[string]struct {
+		err            error
+		classification azrandom.ErrorClassification
+		summary        string
+		hints          []string
+	}{
+		"firewall": {
+			err: vaultResponseError(http.StatusForbidden, firewallErrorBody),
+			classification: azrandom.ErrorClassification{
+				Class:         azrandom.ErrorClassForbiddenByFirewall,
+				ClientAddress: "203.0.113.7",
+			},
+			summary: "Vault Firewall Denied Access",
+			hints:   []string{"the network address 203.0.113.7", "az keyvault network-rule add --ip-address 203.0.113.7"},
+		},
+		"rbac": {
+			err: vaultResponseError(http.StatusForbidden, rbacErrorBody),
+			classification: azrandom.ErrorClassification{
+				Class:  azrandom.ErrorClassForbiddenByRBAC,
+				Action: "Microsoft.KeyVault/vaults/secrets/getSecret/action",
+			},
+			summary: "Vault Access Denied",
+			hints:   []string{"Microsoft.KeyVault/vaults/secrets/getSecret/action", "Key Vault Secrets Officer"},
+		},
+		"access-policy": {
+			err:            vaultResponseError(http.StatusForbidden, accessPolicyErrorBody),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassForbiddenByAccessPolicy},
+			summary:        "Vault Access Denied",
+			hints:          []string{"az keyvault set-policy"},
+		},
+		"forbidden": {
+			err:            vaultResponseError(http.StatusForbidden, disabledErrorBody),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassForbidden},
+			summary:        "Vault Access Denied",
+			hints:          []string{"the secret is enabled"},
+		},
+		"being-deleted": {
+			err:            vaultResponseError(http.StatusConflict, beingDeletedErrorBody),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassBeingDeleted},
+			summary:        "Secret Being Deleted",
+			hints:          []string{"wait_for_deletion", "purge_on_destroy"},
+		},
+		"deleted-but-recoverable": {
+			err:            vaultResponseError(http.StatusConflict, deletedButRecoverableErrorBody),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassDeletedButRecoverable},
+			summary:        "Deleted Secret With the Same Name",
+			hints:          []string{"az keyvault secret purge", "purge_on_destroy"},
+		},
+		"conflict": {
+			err:            vaultResponseError(http.StatusConflict, conflictErrorBody),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassConflict},
+			summary:        "Secret Conflict",
+		},
+		"not-found": {
+			err:            vaultResponseError(http.StatusNotFound, accessErrorBody("SecretNotFound", "not found", "")),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassNotFound},
+			summary:        "Secret Not Found",
+		},
+		"no-body": {
+			err:            vaultResponseError(http.StatusForbidden, ""),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassForbidden},
+			summary:        "Vault Access Denied",
+		},
+		"other-response": {
+			err:            vaultResponseError(http.StatusInternalServerError, accessErrorBody("InternalError", "fake", "")),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassOther},
+			summary:        "Create azrandom_string error",
+		},
+		"no-response": {
+			err:            errors.New("connection refused"),
+			classification: azrandom.ErrorClassification{Class: azrandom.ErrorClassOther},
+			summary:        "Create azrandom_string error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if classification := azrandom.ClassifyError(testCase.err); classification != testCase.classification {
+				t.Errorf("expected classification %+v, got %+v", testCase.classification, classification)
+			}
+
+			diags := diagnostics.CreateError("azrandom_string", "classify-test", fakeVaultUrl, testCase.err)
+			if diags[0].Summary() != testCase.summary {
+				t.Errorf("expected summary %q, got %q", testCase.summary, diags[0].Summary())
+			}
+			for _, hint := range testCase.hints {
+				if !strings.Contains(diags[0].Detail(), hint) {
+					t.Errorf("expected the detail to contain %q, got %q", hint, diags[0].Detail())
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyWrappedError(t *testing.T) {
+	err := &azrandom.OperationTimeoutError{
+		Operation: "setting secret",
+		Name:      "classify-test",
+		Err:       vaultResponseError(http.StatusConflict, beingDeletedErrorBody),
+	}
+	if classification := azrandom.ClassifyError(err); classification.Class != azrandom.ErrorClassBeingDeleted {
+		t.Errorf("expected the wrapped response to be classified as being deleted, got %+v", classification)
+	}
+}
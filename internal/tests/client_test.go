@@ -0,0 +1,691 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// fakeVaultUrl is the vault that clients created by newFakeClient talk to.
+const fakeVaultUrl = "https://fake.vault.azure.net/"
+
+// fakeCredential hands out a static token, without talking to Microsoft Entra ID.
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// fakeTransport answers the authenticated requests of a client with respond. Unauthenticated requests
+// get the challenge that Key Vault clients send first to discover the tenant and scope.
+type fakeTransport struct {
+	respond func(req *http.Request) (*http.Response, error)
+}
+
+func (f *fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		header := http.Header{}
+		header.Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant" resource="https://vault.azure.net"`)
+		return fakeResponse(req, http.StatusUnauthorized, header, ""), nil
+	}
+
+	return f.respond(req)
+}
+
+// fakeResponse returns a response to req with the given status, headers and JSON body.
+func fakeResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// fakeSecretBody returns the JSON body of a secret as returned by GetSecret and SetSecret.
+func fakeSecretBody(name string, version string, value string) string {
+	return `{"id": "` + fakeVaultUrl + `secrets/` + name + `/` + version + `", "value": "` + value + `", "attributes": {"enabled": true}}`
+}
+
+// newFakeClient returns a client of fakeVaultUrl whose requests are answered by transport. Retries are
+// disabled, so that every failure surfaces immediately.
+func newFakeClient(t *testing.T, transport *fakeTransport) *azsecrets.Client {
+	t.Helper()
+
+	client, err := azsecrets.NewClient(fakeVaultUrl, fakeCredential{}, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Transport: transport,
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return client
+}
+
+func TestSecretsClientOptions(t *testing.T) {
+	retry := policy.RetryOptions{
+		MaxRetries:    10,
+		RetryDelay:    2 * time.Second,
+		MaxRetryDelay: 2 * time.Minute,
+		TryTimeout:    30 * time.Second,
+		StatusCodes:   []int{http.StatusServiceUnavailable},
+	}
+
+	options := azrandom.SecretsClientOptions(azrandom.ClientOptions{Cloud: cloud.AzureChina, Retry: retry})
+	if !reflect.DeepEqual(options.Retry, retry) {
+		t.Errorf("expected retry options %+v, got %+v", retry, options.Retry)
+	}
+	if !reflect.DeepEqual(options.Cloud, cloud.AzureChina) {
+		t.Errorf("expected the cloud configuration %+v, got %+v", cloud.AzureChina, options.Cloud)
+	}
+
+	// Unset options keep the defaults of azcore, retrying throttled requests
+	options = azrandom.SecretsClientOptions(azrandom.ClientOptions{})
+	if !slices.Contains(options.Retry.StatusCodes, http.StatusTooManyRequests) {
+		t.Errorf("expected throttled requests to be retried, got status codes %v", options.Retry.StatusCodes)
+	}
+	if options.Retry.MaxRetries != 0 || options.Retry.RetryDelay != 0 || options.Retry.MaxRetryDelay != 0 {
+		t.Errorf("expected default retry options, got %+v", options.Retry)
+	}
+}
+
+func TestSecretExists(t *testing.T) {
+	errorBody := func(code string) string {
+		return `{"error": {"code": "` + code + `", "message": "fake"}}`
+	}
+
+	testCases := map[string]struct {
+		respond      func(req *http.Request) (*http.Response, error)
+		exists       bool
+		expectError  bool
+		expectStatus int
+	}{
+		"exists": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+			},
+			exists: true,
+		},
+		"not-found": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusNotFound, nil, errorBody("SecretNotFound")), nil
+			},
+			exists: false,
+		},
+		"forbidden": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusForbidden, nil, errorBody("Forbidden")), nil
+			},
+			expectError:  true,
+			expectStatus: http.StatusForbidden,
+		},
+		"throttled": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusTooManyRequests, nil, errorBody("Throttled")), nil
+			},
+			expectError:  true,
+			expectStatus: http.StatusTooManyRequests,
+		},
+		"server-error": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, http.StatusInternalServerError, nil, errorBody("InternalError")), nil
+			},
+			expectError:  true,
+			expectStatus: http.StatusInternalServerError,
+		},
+		"network-error": {
+			respond: func(req *http.Request) (*http.Response, error) {
+				return nil, &net.DNSError{Err: "no such host", Name: req.URL.Host, IsNotFound: true}
+			},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient(t, &fakeTransport{respond: testCase.respond})
+
+			exists, err := azrandom.SecretExists(context.Background(), client, "test")
+			if exists != testCase.exists {
+				t.Errorf("expected exists to be %t, got %t", testCase.exists, exists)
+			}
+			if !testCase.expectError {
+				if err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if testCase.expectStatus != 0 {
+				var responseErr *azcore.ResponseError
+				if !errors.As(err, &responseErr) || responseErr.StatusCode != testCase.expectStatus {
+					t.Errorf("expected a response error with status %d, got %s", testCase.expectStatus, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	errorBody := func(code string, message string) string {
+		return `{"error": {"code": "` + code + `", "message": "` + message + `"}}`
+	}
+
+	testCases := map[string]struct {
+		status      int
+		body        string
+		deleted     bool
+		expectError bool
+	}{
+		"deleted": {
+			status:  http.StatusOK,
+			body:    fakeSecretBody("test", "1", ""),
+			deleted: true,
+		},
+		"already-deleted": {
+			status: http.StatusNotFound,
+			body:   errorBody("SecretNotFound", "A secret with (name/id) test was not found in this key vault."),
+		},
+		"being-deleted": {
+			status: http.StatusConflict,
+			body:   errorBody("Conflict", "Secret test is currently being deleted."),
+		},
+		"other-conflict": {
+			status:      http.StatusConflict,
+			body:        errorBody("Conflict", "Secret test is currently being recovered."),
+			expectError: true,
+		},
+		"forbidden": {
+			status:      http.StatusForbidden,
+			body:        errorBody("Forbidden", "fake"),
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				return fakeResponse(req, testCase.status, nil, testCase.body), nil
+			}})
+
+			deleted, err := azrandom.DeleteSecret(context.Background(), client, "test")
+			if deleted != testCase.deleted {
+				t.Errorf("expected deleted to be %t, got %t", testCase.deleted, deleted)
+			}
+			if testCase.expectError != (err != nil) {
+				t.Errorf("expected error to be %t, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestSecretContentType(t *testing.T) {
+	testCases := map[string]struct {
+		contentType string
+		operation   func(ctx context.Context, client *azsecrets.Client, properties azrandom.SecretProperties) (azrandom.SecretProperties, error)
+	}{
+		"update": {
+			contentType: "text/plain",
+			operation: func(ctx context.Context, client *azsecrets.Client, properties azrandom.SecretProperties) (azrandom.SecretProperties, error) {
+				_, stored, err := azrandom.UpdateSecret(ctx, client, "test", "value", properties)
+				return stored, err
+			},
+		},
+		"update-properties": {
+			contentType: "application/x-pem-file",
+			operation: func(ctx context.Context, client *azsecrets.Client, properties azrandom.SecretProperties) (azrandom.SecretProperties, error) {
+				return azrandom.UpdateSecretProperties(ctx, client, "test", "1", properties)
+			},
+		},
+		"unset": {
+			operation: func(ctx context.Context, client *azsecrets.Client, properties azrandom.SecretProperties) (azrandom.SecretProperties, error) {
+				_, stored, err := azrandom.UpdateSecret(ctx, client, "test", "value", properties)
+				return stored, err
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var sent *string
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				var body struct {
+					ContentType *string `json:"contentType"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return nil, err
+				}
+				sent = body.ContentType
+
+				response := map[string]any{
+					"id":         fakeVaultUrl + "secrets/test/1",
+					"attributes": map[string]any{"enabled": true},
+				}
+				if body.ContentType != nil {
+					response["contentType"] = *body.ContentType
+				}
+				encoded, err := json.Marshal(response)
+				if err != nil {
+					return nil, err
+				}
+				return fakeResponse(req, http.StatusOK, nil, string(encoded)), nil
+			}})
+
+			stored, err := testCase.operation(context.Background(), client, azrandom.SecretProperties{ContentType: testCase.contentType})
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if testCase.contentType == "" && sent != nil {
+				t.Errorf("expected no content type to be sent, got %q", *sent)
+			}
+			if testCase.contentType != "" && (sent == nil || *sent != testCase.contentType) {
+				t.Errorf("expected content type %q to be sent, got %v", testCase.contentType, sent)
+			}
+			if stored.ContentType != testCase.contentType {
+				t.Errorf("expected stored content type %q, got %q", testCase.contentType, stored.ContentType)
+			}
+		})
+	}
+}
+
+func TestSecretValidity(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		expires *time.Time
+		// properties updates the properties in place rather than storing a new version
+		properties bool
+		expectSent string
+	}{
+		"update": {
+			expires:    &expires,
+			expectSent: "1893456000",
+		},
+		"update-unbounded": {
+			expectSent: "",
+		},
+		"update-properties": {
+			expires:    &expires,
+			properties: true,
+			expectSent: "1893456000",
+		},
+		"update-properties-unbounded": {
+			properties: true,
+			expectSent: "null",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var sent string
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				var body struct {
+					Attributes map[string]json.RawMessage `json:"attributes"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return nil, err
+				}
+				sent = string(body.Attributes["exp"])
+
+				attributes := `{"enabled": true}`
+				if testCase.expires != nil {
+					attributes = `{"enabled": true, "exp": 1893456000}`
+				}
+				return fakeResponse(req, http.StatusOK, nil, `{"id": "`+fakeVaultUrl+`secrets/test/1", "attributes": `+attributes+`}`), nil
+			}})
+
+			properties := azrandom.SecretProperties{Expires: testCase.expires}
+			var stored azrandom.SecretProperties
+			var err error
+			if testCase.properties {
+				stored, err = azrandom.UpdateSecretProperties(context.Background(), client, "test", "1", properties)
+			} else {
+				_, stored, err = azrandom.UpdateSecret(context.Background(), client, "test", "value", properties)
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if sent != testCase.expectSent {
+				t.Errorf("expected exp %q to be sent, got %q", testCase.expectSent, sent)
+			}
+			if (stored.Expires == nil) != (testCase.expires == nil) || (stored.Expires != nil && !stored.Expires.Equal(expires)) {
+				t.Errorf("expected stored expiry %v, got %v", testCase.expires, stored.Expires)
+			}
+		})
+	}
+}
+
+func TestGetDisabledSecret(t *testing.T) {
+	client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/versions") {
+			return fakeResponse(req, http.StatusOK, nil, `{"value": [
+				{"id": "`+fakeVaultUrl+`secrets/test/1", "attributes": {"enabled": true, "created": 1700000000}},
+				{"id": "`+fakeVaultUrl+`secrets/test/3", "attributes": {"enabled": false, "created": 1700000200}, "contentType": "text/plain"},
+				{"id": "`+fakeVaultUrl+`secrets/test/2", "attributes": {"enabled": true, "created": 1700000100}}
+			]}`), nil
+		}
+		return fakeResponse(req, http.StatusForbidden, nil,
+			`{"error": {"code": "Forbidden", "message": "Operation get is not allowed on a disabled secret."}}`), nil
+	}})
+
+	exists, err := azrandom.SecretExists(context.Background(), client, "test")
+	if err != nil || !exists {
+		t.Errorf("expected the disabled secret to exist, got %t, %v", exists, err)
+	}
+
+	version, properties, err := azrandom.GetSecret(context.Background(), client, "test", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if version != "3" {
+		t.Errorf("expected the latest version 3, got %s", version)
+	}
+	if properties.Enabled == nil || *properties.Enabled {
+		t.Errorf("expected the secret to be disabled, got %v", properties.Enabled)
+	}
+	if properties.ContentType != "text/plain" {
+		t.Errorf("expected the properties of the latest version, got content type %q", properties.ContentType)
+	}
+
+	if _, err := azrandom.GetSecretValue(context.Background(), client, "test", version); !azrandom.IsDisabled(err) {
+		t.Errorf("expected the value of the disabled secret to be refused, got %v", err)
+	}
+
+	// A given version is looked up by its version instead
+	version, properties, err = azrandom.GetSecret(context.Background(), client, "test", "1")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if version != "1" || properties.Enabled == nil || !*properties.Enabled {
+		t.Errorf("expected the enabled version 1, got version %s, enabled %v", version, properties.Enabled)
+	}
+	if _, _, err := azrandom.GetSecret(context.Background(), client, "test", "4"); err == nil {
+		t.Errorf("expected version 4, which is not listed, not to be found")
+	}
+}
+
+func TestGetSecretVersion(t *testing.T) {
+	var path string
+	client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+	}})
+
+	version, _, err := azrandom.GetSecret(context.Background(), client, "test", "1")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if version != "1" || path != "/secrets/test/1" {
+		t.Errorf("expected version 1 to be got from /secrets/test/1, got version %s from %s", version, path)
+	}
+}
+
+// The value is returned along with the properties, from a single request.
+func TestGetSecretWithValue(t *testing.T) {
+	var requests atomic.Int32
+	client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+	}})
+
+	version, _, value, err := azrandom.GetSecretWithValue(context.Background(), client, "test", "1")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if version != "1" || value == nil || *value != "value" {
+		t.Errorf("expected version 1 holding value, got version %s holding %v", version, value)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a single request, got %d", requests.Load())
+	}
+}
+
+func TestHashSecretValue(t *testing.T) {
+	if hash, expected := azrandom.HashSecretValue("value"), "cd42404d52ad55ccfa9aca4adc828aa5800ad9d385a0671fbcbf724118320619"; hash != expected {
+		t.Errorf("expected SHA-256 %s, got %s", expected, hash)
+	}
+}
+
+// fakeSecretVersions is a fake vault holding the versions of the secret test, listed two per page and
+// created one hour apart in order. Disabling a version is recorded in disabled.
+type fakeSecretVersions struct {
+	mu       sync.Mutex
+	versions []string
+	enabled  map[string]bool
+	disabled []string
+}
+
+func (f *fakeSecretVersions) respond(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch req.Method {
+	case http.MethodGet:
+		page := 0
+		if p := req.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+
+		nextLink := "null"
+		versions := f.versions[page*2:]
+		if len(versions) > 2 {
+			versions = versions[:2]
+			nextLink = fmt.Sprintf(`"%ssecrets/test/versions?page=%d"`, fakeVaultUrl, page+1)
+		}
+
+		var values []string
+		for _, version := range versions {
+			created, _ := strconv.Atoi(version)
+			values = append(values, fmt.Sprintf(`{"id": "%ssecrets/test/%s", "attributes": {"enabled": %t, "created": %d}}`,
+				fakeVaultUrl, version, f.enabled[version], 1700000000+created*3600))
+		}
+		return fakeResponse(req, http.StatusOK, nil, `{"value": [`+strings.Join(values, ",")+`], "nextLink": `+nextLink+`}`), nil
+	case http.MethodPatch:
+		var body struct {
+			Attributes struct {
+				Enabled *bool `json:"enabled"`
+			} `json:"attributes"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		version := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		if body.Attributes.Enabled != nil && !*body.Attributes.Enabled {
+			f.enabled[version] = false
+			f.disabled = append(f.disabled, version)
+		}
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", version, "")), nil
+	default:
+		return fakeResponse(req, http.StatusMethodNotAllowed, nil, `{"error": {"code": "BadRequest", "message": "fake"}}`), nil
+	}
+}
+
+func TestDisablePreviousVersions(t *testing.T) {
+	vault := &fakeSecretVersions{
+		versions: []string{"1", "2", "3", "4"},
+		enabled:  map[string]bool{"1": true, "2": true, "3": false, "4": true},
+	}
+
+	disabled, err := azrandom.DisablePreviousVersions(context.Background(), newFakeClient(t, &fakeTransport{respond: vault.respond}), "test", "4")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if strings.Join(disabled, ",") != "1,2" {
+		t.Errorf("expected versions 1 and 2 to be disabled, got %v", disabled)
+	}
+
+	for version, isEnabled := range vault.enabled {
+		if isEnabled != (version == "4") {
+			t.Errorf("expected only the latest version to remain enabled, version %s is enabled: %t", version, isEnabled)
+		}
+	}
+}
+
+func TestPruneSecretVersions(t *testing.T) {
+	testCases := map[string]struct {
+		// versions are listed in this order, the version number being the hour it was created
+		versions       []string
+		disabled       []string
+		version        string
+		keep           int
+		expectDisabled []string
+	}{
+		"oldest-first": {
+			versions:       []string{"3", "1", "5", "2", "4"},
+			version:        "5",
+			keep:           2,
+			expectDisabled: []string{"1", "2", "3"},
+		},
+		"already-disabled": {
+			versions:       []string{"1", "2", "3", "4"},
+			disabled:       []string{"2"},
+			version:        "4",
+			keep:           1,
+			expectDisabled: []string{"1", "3"},
+		},
+		"current-not-recent": {
+			versions:       []string{"1", "2", "3"},
+			version:        "1",
+			keep:           1,
+			expectDisabled: []string{"2"},
+		},
+		"within-limit": {
+			versions: []string{"1", "2"},
+			version:  "2",
+			keep:     2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := &fakeSecretVersions{versions: testCase.versions, enabled: map[string]bool{}}
+			for _, version := range testCase.versions {
+				vault.enabled[version] = !slices.Contains(testCase.disabled, version)
+			}
+
+			disabled, err := azrandom.PruneSecretVersions(context.Background(), newFakeClient(t, &fakeTransport{respond: vault.respond}),
+				"test", testCase.version, testCase.keep)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if !slices.Equal(disabled, testCase.expectDisabled) || !slices.Equal(vault.disabled, testCase.expectDisabled) {
+				t.Errorf("expected versions %v to be disabled, got %v", testCase.expectDisabled, vault.disabled)
+			}
+			if !vault.enabled[testCase.version] {
+				t.Errorf("expected the current version %s to remain enabled", testCase.version)
+			}
+		})
+	}
+}
+
+func TestPruneSecretVersionsListForbidden(t *testing.T) {
+	transport := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusForbidden, nil, `{"error": {"code": "Forbidden", "message": "fake"}}`), nil
+	}}
+
+	_, err := azrandom.PruneSecretVersions(context.Background(), newFakeClient(t, transport), "test", "1", 1)
+	if !errors.Is(err, azrandom.ErrListForbidden) {
+		t.Errorf("expected a list forbidden error, got %v", err)
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	testCases := map[string]struct {
+		// pending is the number of lookups of the deleted secret before its deletion completes
+		pending     int32
+		timeout     time.Duration
+		expectError string
+	}{
+		"completed": {
+			pending: 2,
+		},
+		"not-completed": {
+			pending:     1000,
+			expectError: "did not complete",
+		},
+		"cancelled": {
+			pending:     1000,
+			timeout:     50 * time.Millisecond,
+			expectError: "context deadline exceeded",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var lookups atomic.Int32
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				if lookups.Add(1) <= testCase.pending {
+					return fakeResponse(req, http.StatusNotFound, nil, `{"error": {"code": "SecretNotFound", "message": "fake"}}`), nil
+				}
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+			}})
+
+			backoff := azrandom.Backoff{
+				InitialInterval: 10 * time.Millisecond,
+				MaxInterval:     10 * time.Millisecond,
+				Multiplier:      2,
+				MaxElapsedTime:  200 * time.Millisecond,
+			}
+			ctx := context.Background()
+			if testCase.timeout != 0 {
+				backoff.InitialInterval = 10 * time.Second
+				backoff.MaxInterval = 10 * time.Second
+				backoff.MaxElapsedTime = time.Minute
+
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, testCase.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := azrandom.WaitForDeletionWithBackoff(ctx, client, "test", backoff)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("expected to stop waiting promptly, took %s", elapsed)
+			}
+
+			if testCase.expectError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+				if lookups.Load() != testCase.pending+1 {
+					t.Errorf("expected %d lookups of the deleted secret, got %d", testCase.pending+1, lookups.Load())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectError) {
+				t.Errorf("expected an error containing %q, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// slowTransport answers the authenticated requests of a client after delay, recording how many of them are
+// in flight at once.
+type slowTransport struct {
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowTransport) transport() *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		s.mu.Lock()
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		s.mu.Unlock()
+
+		time.Sleep(s.delay)
+
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("concurrency-test", "v1", "value")), nil
+	}}
+}
+
+// newLimitedClient returns a client of fakeVaultUrl whose requests are sent to transport, limited by limiter.
+func newLimitedClient(t *testing.T, transport policy.Transporter, limiter *azrandom.ConcurrencyLimiter) *azsecrets.Client {
+	t.Helper()
+
+	client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+		Transport: transport,
+		Limiter:   limiter,
+	})
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return client
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	testCases := map[string]struct {
+		clients  int
+		limit    int
+		expected int
+	}{
+		"single-client": {
+			clients:  1,
+			limit:    2,
+			expected: 2,
+		},
+		"shared-between-clients": {
+			clients:  3,
+			limit:    2,
+			expected: 2,
+		},
+		"one": {
+			clients:  2,
+			limit:    1,
+			expected: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			slow := &slowTransport{delay: 20 * time.Millisecond}
+			limiter := azrandom.NewConcurrencyLimiter(testCase.limit)
+
+			var clients []*azsecrets.Client
+			for i := 0; i < testCase.clients; i++ {
+				clients = append(clients, newLimitedClient(t, slow.transport(), limiter))
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 10*testCase.clients)
+			for _, client := range clients {
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func(client *azsecrets.Client) {
+						defer wg.Done()
+						_, err := azrandom.GetSecretValue(context.Background(), client, "concurrency-test", "")
+						errs <- err
+					}(client)
+				}
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("unable to get secret: %s", err)
+				}
+			}
+			if slow.maxInFlight != testCase.expected {
+				t.Errorf("expected at most %d requests in flight, got %d", testCase.expected, slow.maxInFlight)
+			}
+		})
+	}
+}
+
+// blockingTransport answers the authenticated requests of a client once they are released, recording the
+// names of their secrets in the order they arrive.
+type blockingTransport struct {
+	released chan struct{}
+	arrived  chan string
+}
+
+func newBlockingTransport(t *testing.T) *blockingTransport {
+	b := &blockingTransport{released: make(chan struct{}), arrived: make(chan string, 10)}
+	t.Cleanup(func() {
+		select {
+		case <-b.released:
+		default:
+			close(b.released)
+		}
+	})
+	return b
+}
+
+func (b *blockingTransport) transport() *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		name := strings.Split(strings.Trim(req.URL.Path, "/"), "/")[1]
+		b.arrived <- name
+		if name != "warm-up" {
+			<-b.released
+		}
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody(name, "v1", "value")), nil
+	}}
+}
+
+func TestConcurrencyLimiterOrder(t *testing.T) {
+	blocking := newBlockingTransport(t)
+	client := newLimitedClient(t, blocking.transport(), azrandom.NewConcurrencyLimiter(1))
+
+	// The first request authenticates the client, so that the following ones are sent once each
+	if _, err := azrandom.GetSecretValue(context.Background(), client, "warm-up", ""); err != nil {
+		t.Fatalf("unable to get secret: %s", err)
+	}
+	<-blocking.arrived
+
+	var wg sync.WaitGroup
+	names := []string{"first", "second", "third", "fourth"}
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := azrandom.GetSecretValue(context.Background(), client, name, ""); err != nil {
+				t.Errorf("unable to get secret %s: %s", name, err)
+			}
+		}(name)
+		if name == "first" {
+			// The first request holds the only slot until the transport is released
+			<-blocking.arrived
+		} else {
+			// Give the request time to queue for a slot
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	close(blocking.released)
+	wg.Wait()
+	close(blocking.arrived)
+
+	arrived := []string{"first"}
+	for name := range blocking.arrived {
+		arrived = append(arrived, name)
+	}
+	if strings.Join(arrived, ",") != strings.Join(names, ",") {
+		t.Errorf("expected the requests to be sent in the order %v, got %v", names, arrived)
+	}
+}
+
+func TestConcurrencyLimiterCanceled(t *testing.T) {
+	blocking := newBlockingTransport(t)
+	client := newLimitedClient(t, blocking.transport(), azrandom.NewConcurrencyLimiter(1))
+
+	// The only slot is held by a request that does not complete
+	go func() {
+		_, _ = azrandom.GetSecretValue(context.Background(), client, "held", "")
+	}()
+	<-blocking.arrived
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := azrandom.GetSecretValue(ctx, client, "waiting", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request waiting for a slot to be canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the request to be canceled after about 100ms, took %s", elapsed)
+	}
+	select {
+	case name := <-blocking.arrived:
+		t.Errorf("expected the canceled request not to be sent, got a request for %s", name)
+	default:
+	}
+}
+
+func TestProviderConfigureMaxConcurrentRequests(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+		expected    float64
+	}{
+		"default": {},
+		"config": {
+			config:   map[string]any{"max_concurrent_requests": 4},
+			expected: 4,
+		},
+		"env": {
+			env:      map[string]string{"AZRANDOM_MAX_CONCURRENT_REQUESTS": "8"},
+			expected: 8,
+		},
+		"config-overrides-env": {
+			env:      map[string]string{"AZRANDOM_MAX_CONCURRENT_REQUESTS": "8"},
+			config:   map[string]any{"max_concurrent_requests": 2},
+			expected: 2,
+		},
+		"invalid-env": {
+			env:         map[string]string{"AZRANDOM_MAX_CONCURRENT_REQUESTS": "many"},
+			expectError: "Error parsing AZRANDOM_MAX_CONCURRENT_REQUESTS",
+		},
+		"zero-env": {
+			env:         map[string]string{"AZRANDOM_MAX_CONCURRENT_REQUESTS": "0"},
+			expectError: "Error parsing AZRANDOM_MAX_CONCURRENT_REQUESTS",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_MAX_CONCURRENT_REQUESTS", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if testCase.expectError != "" {
+				if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			for _, entry := range entries {
+				if entry["@message"] == "Creating Azrandom client" && entry["max_concurrent_requests"] != testCase.expected {
+					t.Errorf("expected max concurrent requests %v, got %v", testCase.expected, entry["max_concurrent_requests"])
+				}
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigMaxConcurrentRequests(t *testing.T) {
+	errors := validateProviderConfig(t, map[string]any{"vault_url": testVaultUrl, "max_concurrent_requests": 0})
+	if len(errors) != 1 || errors[0] != "Invalid Attribute Value" {
+		t.Errorf("expected an invalid attribute value, got %v", errors)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// callCounter counts the requests made to a vault by the operation of the client that made them.
+type callCounter struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// count records req, and returns the operation that made it.
+func (c *callCounter) count(req *http.Request) string {
+	operation := req.Method + " " + req.URL.Path
+	switch {
+	case req.Method == http.MethodPut && strings.HasPrefix(req.URL.Path, "/secrets/"):
+		operation = "SetSecret"
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/secrets/"):
+		operation = "GetSecret"
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/deletedsecrets/"):
+		operation = "GetDeletedSecret"
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/recover"):
+		operation = "RecoverDeletedSecret"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+	c.calls[operation]++
+	return operation
+}
+
+// counts returns the number of requests made by each operation.
+func (c *callCounter) counts() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := map[string]int{}
+	for operation, n := range c.calls {
+		counts[operation] = n
+	}
+	return counts
+}
+
+// The calls that creating a secret takes:
+//
+//   - 1 (SetSecret) when its name is free, or when the secret exists and is overwritten with a new version
+//   - 4 or more (SetSecret, GetDeletedSecret, RecoverDeletedSecret, SetSecret until recovered) when a
+//     soft-deleted secret holds its name
+func TestCreateSecretCalls(t *testing.T) {
+	testCases := map[string]struct {
+		respond     func(operation string, req *http.Request, sets int) *http.Response
+		calls       map[string]int
+		expectError bool
+	}{
+		"new-secret": {
+			respond: func(operation string, req *http.Request, sets int) *http.Response {
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("create-calls-test", "1", "value"))
+			},
+			calls: map[string]int{"SetSecret": 1},
+		},
+		"soft-deleted-secret": {
+			respond: func(operation string, req *http.Request, sets int) *http.Response {
+				switch {
+				case operation == "SetSecret" && sets == 1:
+					return fakeResponse(req, http.StatusConflict, nil, deletedButRecoverableErrorBody)
+				case operation == "SetSecret":
+					return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("create-calls-test", "2", "value"))
+				}
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("create-calls-test", "1", ""))
+			},
+			calls: map[string]int{"SetSecret": 2, "GetDeletedSecret": 1, "RecoverDeletedSecret": 1},
+		},
+		"conflict-without-deleted-secret": {
+			respond: func(operation string, req *http.Request, sets int) *http.Response {
+				if operation == "SetSecret" {
+					return fakeResponse(req, http.StatusConflict, nil, conflictErrorBody)
+				}
+				return fakeResponse(req, http.StatusNotFound, nil, accessErrorBody("SecretNotFound", "not found", ""))
+			},
+			calls:       map[string]int{"SetSecret": 1, "GetDeletedSecret": 1},
+			expectError: true,
+		},
+		"forbidden": {
+			respond: func(operation string, req *http.Request, sets int) *http.Response {
+				return fakeResponse(req, http.StatusForbidden, nil, rbacErrorBody)
+			},
+			calls:       map[string]int{"SetSecret": 1},
+			expectError: true,
+		},
+	}
+
+	backoff := azrandom.Backoff{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		Multiplier:      2,
+		MaxElapsedTime:  5 * time.Second,
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var counter callCounter
+			client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				operation := counter.count(req)
+				return testCase.respond(operation, req, counter.counts()["SetSecret"]), nil
+			}})
+
+			_, _, err := azrandom.CreateSecretWithBackoff(context.Background(), client, "create-calls-test", "value",
+				azrandom.SecretProperties{}, backoff)
+			if testCase.expectError && err == nil {
+				t.Error("expected an error")
+			}
+			if !testCase.expectError && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+			if calls := counter.counts(); !reflect.DeepEqual(calls, testCase.calls) {
+				t.Errorf("expected calls %v, got %v", testCase.calls, calls)
+			}
+		})
+	}
+}
+
+// The calls that creating an azrandom_uuid takes: 2 (GetSecret, SetSecret) by default, and 1 (SetSecret) with
+// skip_existence_check, which overwrites an existing secret instead of failing.
+func TestCreateResourceCalls(t *testing.T) {
+	testCases := map[string]struct {
+		skipExistenceCheck bool
+		existing           bool
+		calls              map[string]int
+		expectError        string
+	}{
+		"default": {
+			calls: map[string]int{"GetSecret": 1, "SetSecret": 1},
+		},
+		"default-existing": {
+			existing:    true,
+			expectError: "Create azrandom_uuid error",
+		},
+		"skip-existence-check": {
+			skipExistenceCheck: true,
+			calls:              map[string]int{"SetSecret": 1},
+		},
+		"skip-existence-check-existing": {
+			skipExistenceCheck: true,
+			existing:           true,
+			calls:              map[string]int{"SetSecret": 1},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var counter callCounter
+			vault := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" {
+					w.Header().Set("WWW-Authenticate", `Bearer authorization="https://localhost/tenant" resource="https://localhost"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				switch counter.count(r) {
+				case "SetSecret":
+					_, _ = w.Write([]byte(fakeSecretBody("create-calls-test", "2", "")))
+				case "GetSecret":
+					if !testCase.existing {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(accessErrorBody("SecretNotFound", "not found", "")))
+						return
+					}
+					_, _ = w.Write([]byte(fakeSecretBody("create-calls-test", "1", "")))
+				default:
+					_, _ = w.Write([]byte(`{"value": []}`))
+				}
+			}))
+			t.Cleanup(vault.Close)
+
+			server := configuredServer(t, map[string]any{
+				"custom_endpoint":      vault.URL,
+				"allow_insecure":       true,
+				"insecure_skip_verify": true,
+				"use_fake_credential":  true,
+				"retry":                map[string]any{"max_retries": 0},
+				"skip_existence_check": testCase.skipExistenceCheck,
+			})
+
+			resp := applyResourceChange(t, server, "azrandom_uuid", nil, map[string]any{"name": "create-calls-test"})
+			if testCase.expectError != "" {
+				if len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected error %q, got %v", testCase.expectError, resp.Diagnostics)
+				}
+				return
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					t.Fatalf("unexpected error creating resource: %s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+			if calls := counter.counts(); !reflect.DeepEqual(calls, testCase.calls) {
+				t.Errorf("expected calls %v, got %v", testCase.calls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+func TestCredentialError(t *testing.T) {
+	testCases := map[string]struct {
+		errMsg  string
+		summary string
+		detail  []string
+	}{
+		"chain-exhausted": {
+			errMsg: "DefaultAzureCredential: failed to acquire a token.\nAttempted credentials:\n" +
+				"\tEnvironmentCredential: missing environment variable AZURE_TENANT_ID\n" +
+				"\tManagedIdentityCredential: no response from the IMDS endpoint\n" +
+				"\tAzureCLICredential: Azure CLI not found on path",
+			summary: "No Credential in the Chain Could Authenticate",
+			detail: []string{
+				"  - EnvironmentCredential: missing environment variable AZURE_TENANT_ID\n",
+				"  - ManagedIdentityCredential: no response from the IMDS endpoint\n",
+				"  - AzureCLICredential: Azure CLI not found on path\n",
+			},
+		},
+		"app-not-found": {
+			errMsg: "ClientSecretCredential authentication failed\n" +
+				"AADSTS700016: Application with identifier 'abc' was not found in the directory 'contoso'.",
+			summary: "Application Not Found in Tenant",
+			detail:  []string{"tenant ID is wrong"},
+		},
+		"cli-login-expired": {
+			errMsg: "DefaultAzureCredential: failed to acquire a token.\nAttempted credentials:\n" +
+				"\tAzureCLICredential: ERROR: AADSTS700082: The refresh token has expired due to inactivity.\n" +
+				"To re-authenticate, please run:\naz login",
+			summary: "Azure CLI Login Expired",
+			detail: []string{
+				"Run `az login`",
+				"  - AzureCLICredential: ERROR: AADSTS700082: The refresh token has expired due to inactivity. To re-authenticate, please run: az login\n",
+			},
+		},
+		"other": {
+			errMsg:  "context deadline exceeded",
+			summary: "Authentication Failed",
+			detail:  []string{"Original Error: context deadline exceeded"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := diagnostics.CredentialError(testCase.errMsg)
+			if len(diags) != 1 {
+				t.Fatalf("expected a single diagnostic, got %d", len(diags))
+			}
+			if diags[0].Summary() != testCase.summary {
+				t.Errorf("expected summary %q, got %q", testCase.summary, diags[0].Summary())
+			}
+			for _, expected := range testCase.detail {
+				if !strings.Contains(diags[0].Detail(), expected) {
+					t.Errorf("expected detail to contain %q, got:\n%s", expected, diags[0].Detail())
+				}
+			}
+		})
+	}
+}
+
+func TestVaultScope(t *testing.T) {
+	testCases := map[string]string{
+		"https://my-vault.vault.azure.net/":         "https://vault.azure.net/.default",
+		"https://my-vault.vault.usgovcloudapi.net/": "https://vault.usgovcloudapi.net/.default",
+		"https://MY-VAULT.VAULT.AZURE.CN":           "https://vault.azure.cn/.default",
+		"https://vault.example.com/":                "https://vault.azure.net/.default",
+	}
+
+	for vaultUrl, expected := range testCases {
+		t.Run(vaultUrl, func(t *testing.T) {
+			if scope := azrandom.VaultScope(vaultUrl); scope != expected {
+				t.Errorf("expected %q, got %q", expected, scope)
+			}
+		})
+	}
+}
+
+func TestCreateCredential(t *testing.T) {
+	certificates, key, err := azrandom.LoadClientCertificate("testdata/client_certificate.pem", "")
+	if err != nil {
+		t.Fatalf("unable to load client certificate: %s", err)
+	}
+
+	servicePrincipal := azrandom.ServicePrincipal{
+		TenantID:     "00000000-0000-0000-0000-000000000000",
+		ClientID:     "11111111-1111-1111-1111-111111111111",
+		ClientSecret: "secret",
+	}
+
+	testCases := map[string]struct {
+		options     azrandom.CredentialOptions
+		expectType  string
+		expectError error
+	}{
+		"default": {
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"default-with-disabled": {
+			options: azrandom.CredentialOptions{
+				DisabledCredentials: azidentity.DisabledCredentials{AzureCLICredential: true},
+			},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"default-with-tenants": {
+			options: azrandom.CredentialOptions{
+				AdditionallyAllowedTenants: []string{"*"},
+				DisableInstanceDiscovery:   true,
+			},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"client-secret-with-tenants": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal:           servicePrincipal,
+				AdditionallyAllowedTenants: []string{"*"},
+				DisableInstanceDiscovery:   true,
+			},
+			expectType: "*azidentity.ClientSecretCredential",
+		},
+		"auth-method-default-chain": {
+			options:    azrandom.CredentialOptions{AuthMethod: azrandom.AuthMethodDefaultChain},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"auth-method-cli": {
+			options:    azrandom.CredentialOptions{AuthMethod: azrandom.AuthMethodCLI},
+			expectType: "*azidentity.AzureCLICredential",
+		},
+		"auth-method-developer-cli": {
+			options:    azrandom.CredentialOptions{AuthMethod: azrandom.AuthMethodDeveloperCLI},
+			expectType: "*azidentity.AzureDeveloperCLICredential",
+		},
+		"auth-method-managed-identity": {
+			options: azrandom.CredentialOptions{
+				AuthMethod:              azrandom.AuthMethodManagedIdentity,
+				ManagedIdentityClientID: "22222222-2222-2222-2222-222222222222",
+			},
+			expectType: "*azidentity.ManagedIdentityCredential",
+		},
+		"auth-method-with-disabled": {
+			options: azrandom.CredentialOptions{
+				AuthMethod:          azrandom.AuthMethodCLI,
+				DisabledCredentials: azidentity.DisabledCredentials{EnvironmentCredential: true},
+			},
+			expectError: azrandom.ErrConflictingAuthMethod,
+		},
+		"auth-method-with-service-principal": {
+			options: azrandom.CredentialOptions{
+				AuthMethod:       azrandom.AuthMethodDefaultChain,
+				ServicePrincipal: servicePrincipal,
+			},
+			expectError: azrandom.ErrConflictingAuthMethod,
+		},
+		"interactive-browser": {
+			options:    azrandom.CredentialOptions{InteractiveAuth: azrandom.InteractiveAuthBrowser},
+			expectType: "*azidentity.ChainedTokenCredential",
+		},
+		"device-code": {
+			options: azrandom.CredentialOptions{
+				AuthMethod:       azrandom.AuthMethodCLI,
+				InteractiveAuth:  azrandom.InteractiveAuthDeviceCode,
+				DeviceCodePrompt: func(context.Context, string) {},
+			},
+			expectType: "*azidentity.ChainedTokenCredential",
+		},
+		"managed-identity-client-id": {
+			options:    azrandom.CredentialOptions{ManagedIdentityClientID: "22222222-2222-2222-2222-222222222222"},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"managed-identity-resource-id": {
+			options: azrandom.CredentialOptions{
+				ManagedIdentityResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id",
+			},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"managed-identity-client-and-resource-id": {
+			options: azrandom.CredentialOptions{
+				ManagedIdentityClientID:   "22222222-2222-2222-2222-222222222222",
+				ManagedIdentityResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id",
+			},
+			expectError: azrandom.ErrConflictingManagedIdentity,
+		},
+		"client-secret": {
+			options:    azrandom.CredentialOptions{ServicePrincipal: servicePrincipal},
+			expectType: "*azidentity.ClientSecretCredential",
+		},
+		"client-certificate": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal: azrandom.ServicePrincipal{
+					TenantID:     servicePrincipal.TenantID,
+					ClientID:     servicePrincipal.ClientID,
+					Certificates: certificates,
+					PrivateKey:   key,
+				},
+			},
+			expectType: "*azidentity.ClientCertificateCredential",
+		},
+		"client-secret-and-certificate": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal: azrandom.ServicePrincipal{
+					TenantID:     servicePrincipal.TenantID,
+					ClientID:     servicePrincipal.ClientID,
+					ClientSecret: servicePrincipal.ClientSecret,
+					Certificates: certificates,
+					PrivateKey:   key,
+				},
+			},
+			expectError: azrandom.ErrIncompleteServicePrincipal,
+		},
+		"client-secret-incomplete": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal: azrandom.ServicePrincipal{TenantID: servicePrincipal.TenantID, ClientID: servicePrincipal.ClientID},
+			},
+			expectError: azrandom.ErrIncompleteServicePrincipal,
+		},
+		"client-secret-with-token-cache": {
+			options:    azrandom.CredentialOptions{ServicePrincipal: servicePrincipal, TokenCacheName: "azrandom-test"},
+			expectType: "*azidentity.ClientSecretCredential",
+		},
+		"client-certificate-with-token-cache": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal: azrandom.ServicePrincipal{
+					TenantID:     servicePrincipal.TenantID,
+					ClientID:     servicePrincipal.ClientID,
+					Certificates: certificates,
+					PrivateKey:   key,
+				},
+				TokenCacheName: "azrandom-test",
+			},
+			expectType: "*azidentity.ClientCertificateCredential",
+		},
+		"device-code-with-token-cache": {
+			options: azrandom.CredentialOptions{
+				InteractiveAuth:  azrandom.InteractiveAuthDeviceCode,
+				DeviceCodePrompt: func(context.Context, string) {},
+				TokenCacheName:   "azrandom-test",
+			},
+			expectType: "*azidentity.ChainedTokenCredential",
+		},
+		"default-with-token-cache": {
+			options:    azrandom.CredentialOptions{TokenCacheName: "azrandom-test"},
+			expectType: "*azidentity.DefaultAzureCredential",
+		},
+		"client-secret-with-disabled": {
+			options: azrandom.CredentialOptions{
+				ServicePrincipal:    servicePrincipal,
+				DisabledCredentials: azidentity.DisabledCredentials{ManagedIdentityCredential: true},
+			},
+			expectError: azrandom.ErrConflictingCredentials,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			credential, err := azrandom.CreateCredential(testCase.options)
+			if testCase.expectError != nil {
+				if !errors.Is(err, testCase.expectError) {
+					t.Fatalf("expected error %q, got %v", testCase.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if credentialType := fmt.Sprintf("%T", credential); credentialType != testCase.expectType {
+				t.Errorf("expected a %s, got a %s", testCase.expectType, credentialType)
+			}
+		})
+	}
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	testCases := map[string]struct {
+		path        string
+		password    string
+		expectError string
+	}{
+		"pem": {
+			path: "testdata/client_certificate.pem",
+		},
+		"pfx": {
+			path:     "testdata/client_certificate.pfx",
+			password: "test-password",
+		},
+		"pfx-wrong-password": {
+			path:        "testdata/client_certificate.pfx",
+			password:    "wrong-password",
+			expectError: "could not parse client certificate",
+		},
+		"missing": {
+			path:        "testdata/missing.pem",
+			expectError: "could not read client certificate",
+		},
+		"malformed": {
+			path:        "credential_test.go",
+			expectError: "could not parse client certificate",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			certificates, key, err := azrandom.LoadClientCertificate(testCase.path, testCase.password)
+			if testCase.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectError) {
+					t.Fatalf("expected error containing %q, got %v", testCase.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+			if len(certificates) != 1 || certificates[0].Subject.CommonName != "azrandom-test" {
+				t.Errorf("expected the azrandom-test certificate, got %v", certificates)
+			}
+			if key == nil {
+				t.Error("expected a private key")
+			}
+		})
+	}
+}
+
+func TestCheckTokenCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the token cache is stored in the local app data folder on Windows, which does not depend on HOME")
+	}
+
+	// The token cache is stored in the home directory, so it cannot be opened without one
+	t.Setenv("HOME", "")
+
+	err := azrandom.CheckTokenCache("azrandom-test")
+	if !errors.Is(err, azrandom.ErrTokenCacheUnavailable) {
+		t.Fatalf("expected error %q, got %v", azrandom.ErrTokenCacheUnavailable, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+func TestValidateCronTemplate(t *testing.T) {
+	testCases := map[string]struct {
+		template string
+		valid    bool
+	}{
+		"random":               {template: "R R * * *", valid: true},
+		"random-range":         {template: "R R(2-4) * * MON-FRI", valid: true},
+		"seconds":              {template: "R R R(0-20) R(1-28) * 1-5", valid: true},
+		"steps-lists-names":    {template: "*/15 R ? JAN,JUL R", valid: true},
+		"too-few-fields":       {template: "R R * *", valid: false},
+		"too-many-fields":      {template: "R R * * * * *", valid: false},
+		"reversed-range":       {template: "R R(4-2) * * *", valid: false},
+		"random-out-of-range":  {template: "R R(0-24) * * *", valid: false},
+		"value-out-of-range":   {template: "60 R * * *", valid: false},
+		"invalid-name":         {template: "R R * * x", valid: false},
+		"question-mark-minute": {template: "R ? * * *", valid: false},
+		"zero-step":            {template: "R R */0 * *", valid: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := random.ValidateCronTemplate(testCase.template)
+			if testCase.valid && err != nil {
+				t.Errorf("expected %q to be valid, got %s", testCase.template, err)
+			}
+			if !testCase.valid && err == nil {
+				t.Errorf("expected %q to be invalid", testCase.template)
+			}
+		})
+	}
+}
+
+func TestRenderCronTemplate(t *testing.T) {
+	seed := []byte("0123456789abcdef")
+
+	expression, err := random.RenderCronTemplate("R R(2-4) * * R", seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		t.Fatalf("expected 5 fields, got %q", expression)
+	}
+
+	checkRange := func(field string, low, high int) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < low || n > high {
+			t.Errorf("expected a value in %d-%d, got %q", low, high, field)
+		}
+	}
+	checkRange(fields[0], 0, 59)
+	checkRange(fields[1], 2, 4)
+	checkRange(fields[4], 0, 6)
+
+	// Changing fields that are not random keeps the random values
+	other, err := random.RenderCronTemplate("R R(2-4) 1 */2 R", seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherFields := strings.Fields(other)
+	if otherFields[0] != fields[0] || otherFields[1] != fields[1] || otherFields[4] != fields[4] {
+		t.Errorf("expected random fields of %q to match %q", other, expression)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// memoryVault serves the secrets it holds in memory, like a vault whose secrets can be changed or deleted in
+// the portal between the steps of a test. Versions of a secret are numbered from 1.
+type memoryVault struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// versions holds the latest version of each secret
+	versions map[string]int
+	// values holds the value that each secret was last set with
+	values map[string]string
+	// written holds the value that each version of a secret was set with by a PUT
+	written map[string]map[int]string
+	// contentTypes holds the content type that each secret was last set with
+	contentTypes map[string]string
+	// attributes holds the attributes, such as enabled and exp, that each secret was last set with
+	attributes map[string]map[string]any
+}
+
+// newMemoryVault returns an empty memoryVault, which is closed when the test ends.
+func newMemoryVault(t *testing.T) *memoryVault {
+	vault := &memoryVault{
+		versions:     map[string]int{},
+		values:       map[string]string{},
+		written:      map[string]map[int]string{},
+		contentTypes: map[string]string{},
+		attributes:   map[string]map[string]any{},
+	}
+	vault.Server = httptest.NewTLSServer(http.HandlerFunc(vault.serve))
+	t.Cleanup(vault.Close)
+	return vault
+}
+
+func (v *memoryVault) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer authorization="https://localhost/tenant" resource="https://localhost"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "secrets" {
+		_, _ = w.Write([]byte(`{"value": []}`))
+		return
+	}
+	name := segments[1]
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		var body struct {
+			Value       string         `json:"value"`
+			ContentType string         `json:"contentType"`
+			Attributes  map[string]any `json:"attributes"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		v.versions[name]++
+		v.values[name] = body.Value
+		if v.written[name] == nil {
+			v.written[name] = map[int]string{}
+		}
+		v.written[name][v.versions[name]] = body.Value
+		v.contentTypes[name] = body.ContentType
+		v.attributes[name] = body.Attributes
+	case http.MethodPatch:
+		var body struct {
+			Attributes map[string]any `json:"attributes"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		v.attributes[name] = body.Attributes
+	case http.MethodGet:
+		if v.versions[name] == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			message := "A secret with (name/id) " + name + " was not found in this key vault."
+			_, _ = w.Write([]byte(accessErrorBody("SecretNotFound", message, "")))
+			return
+		}
+	}
+	// Earlier versions can be got by their version, and hold the value they were written with, but the
+	// attributes of the latest one
+	version := v.versions[name]
+	value := v.values[name]
+	if len(segments) > 2 && segments[2] != "" {
+		requested, err := strconv.Atoi(segments[2])
+		if err != nil || requested < 1 || requested > version {
+			w.WriteHeader(http.StatusNotFound)
+			message := "A secret with (name/id) " + name + "/" + segments[2] + " was not found in this key vault."
+			_, _ = w.Write([]byte(accessErrorBody("SecretNotFound", message, "")))
+			return
+		}
+		if written, ok := v.written[name][requested]; ok && requested < version {
+			value = written
+		}
+		version = requested
+	}
+	attributes := v.attributes[name]
+	if attributes == nil {
+		attributes = map[string]any{"enabled": true}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"id":          v.URL + "/secrets/" + name + "/" + strconv.Itoa(version),
+		"value":       value,
+		"contentType": v.contentTypes[name],
+		"attributes":  attributes,
+	})
+}
+
+// configuredServer returns a provider server configured for the vault, like the provider that Terraform starts
+// for each command.
+func (v *memoryVault) configuredServer(t *testing.T) tfprotov6.ProviderServer {
+	t.Helper()
+
+	return configuredServer(t, map[string]any{
+		"custom_endpoint":      v.URL,
+		"allow_insecure":       true,
+		"insecure_skip_verify": true,
+		"use_fake_credential":  true,
+		"retry":                map[string]any{"max_retries": 0},
+	})
+}
+
+// write stores a new version of the secret name with another value, as if it was set outside of Terraform.
+func (v *memoryVault) write(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.versions[name]++
+	v.values[name] = "set outside of Terraform " + strconv.Itoa(v.versions[name])
+}
+
+// rewrite stores a new version of the secret name with its current value, as if the value was set again
+// outside of Terraform.
+func (v *memoryVault) rewrite(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.versions[name]++
+}
+
+// setValue replaces the value of the latest version of the secret name, as if it was restored from a backup
+// outside of Terraform.
+func (v *memoryVault) setValue(name string, value string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[name] = value
+}
+
+// setAttributes replaces the attributes of the secret name, as if it was disabled or expired outside of Terraform.
+func (v *memoryVault) setAttributes(name string, attributes map[string]any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.attributes[name] = attributes
+}
+
+// delete deletes the secret name, as if it was deleted outside of Terraform.
+func (v *memoryVault) delete(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.versions, name)
+}
+
+// value returns the value of the latest version of the secret name.
+func (v *memoryVault) value(name string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.values[name]
+}
+
+// exists returns whether the vault holds the secret name.
+func (v *memoryVault) exists(name string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.versions[name] > 0
+}
+
+func TestResourceDeletedOutOfBand(t *testing.T) {
+	testCases := map[string]struct {
+		typeName string
+		config   map[string]any
+		secrets  []string
+	}{
+		"uuid": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "deleted-out-of-band-test"},
+			secrets:  []string{"deleted-out-of-band-test"},
+		},
+		"string": {
+			typeName: "azrandom_string",
+			config:   map[string]any{"name": "deleted-out-of-band-test", "length": 16},
+			secrets:  []string{"deleted-out-of-band-test"},
+		},
+		"password-set": {
+			typeName: "azrandom_password_set",
+			config:   map[string]any{"prefix": "deleted-out-of-band-test", "names": []string{"alpha", "beta"}},
+			secrets:  []string{"deleted-out-of-band-test-alpha", "deleted-out-of-band-test-beta"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), testCase.typeName, nil, testCase.config))
+
+			// The secrets are deleted in the portal, so the resource is removed from state instead of failing
+			for _, secret := range testCase.secrets {
+				vault.delete(secret)
+			}
+			server := vault.configuredServer(t)
+			resp := readResource(t, server, testCase.typeName, created.NewState)
+			if state := stateMap(t, server, testCase.typeName, resp.NewState); state != nil {
+				t.Fatalf("expected the resource to be removed from state, got %v", state)
+			}
+
+			// The next apply creates the secrets again
+			applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), testCase.typeName, nil, testCase.config))
+			for _, secret := range testCase.secrets {
+				if !vault.exists(secret) {
+					t.Errorf("expected the secret %s to be created again", secret)
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordSetEntryDeletedOutOfBand(t *testing.T) {
+	vault := newMemoryVault(t)
+	config := map[string]any{"prefix": "deleted-out-of-band-test", "names": []string{"alpha", "beta"}}
+	created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_password_set", nil, config))
+
+	// Only the entry whose secret was deleted is dropped from state, and planned to be created again
+	vault.delete("deleted-out-of-band-test-beta")
+	server := vault.configuredServer(t)
+	resp := readResource(t, server, "azrandom_password_set", created.NewState)
+	state := stateMap(t, server, "azrandom_password_set", resp.NewState)
+	versions, _ := state["versions"].(map[string]any)
+	if _, ok := versions["beta"]; ok || versions["alpha"] != "1" {
+		t.Fatalf("expected only the version of alpha to remain, got %v", versions)
+	}
+
+	planResp := planResourceChangeWith(t, server, "azrandom_password_set", state, config)
+	for _, diagnostic := range planResp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error planning resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	planned := plannedStateMap(t, server, "azrandom_password_set", planResp)
+	plannedVersions, _ := planned["versions"].(map[string]any)
+	if plannedVersions["alpha"] != "1" || plannedVersions["beta"] != unknownValue {
+		t.Errorf("expected alpha to be kept and beta to be created again, got %v", plannedVersions)
+	}
+}
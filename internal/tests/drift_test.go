@@ -0,0 +1,361 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	azrandom "terraform-provider-azrandom/client"
+)
+
+// planWarnings returns the summaries of the warnings of a plan.
+func planWarnings(resp *tfprotov6.PlanResourceChangeResponse) []string {
+	var warnings []string
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning {
+			warnings = append(warnings, diagnostic.Summary)
+		}
+	}
+	return warnings
+}
+
+func TestDriftRegeneratesValue(t *testing.T) {
+	testCases := map[string]struct {
+		typeName string
+		config   map[string]any
+	}{
+		"uuid": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "drift-test", "keepers": map[string]any{"env": "prod"}},
+		},
+		"string": {
+			typeName: "azrandom_string",
+			config:   map[string]any{"name": "drift-test", "length": 16, "keepers": map[string]any{"env": "prod"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			server := vault.configuredServer(t)
+			created := applySucceeded(t, applyResourceChange(t, server, testCase.typeName, nil, testCase.config))
+
+			// Without drift, refreshing plans no change
+			server = vault.configuredServer(t)
+			read := readResourcePrivate(t, server, testCase.typeName, created.NewState, created.Private)
+			planResp := planResourceChangePrivate(t, server, testCase.typeName,
+				stateMap(t, server, testCase.typeName, read.NewState), read.Private, testCase.config)
+			if planned := plannedStateMap(t, server, testCase.typeName, planResp); planned["version"] != "1" {
+				t.Fatalf("expected version 1 to be kept, got %v", planned["version"])
+			}
+
+			// A new version set in the portal is detected as drift, leaving keepers as configured
+			vault.write("drift-test")
+			server = vault.configuredServer(t)
+			read = readResourcePrivate(t, server, testCase.typeName, created.NewState, created.Private)
+			refreshed := stateMap(t, server, testCase.typeName, read.NewState)
+			if !reflect.DeepEqual(refreshed["keepers"], map[string]any{"env": "prod"}) || refreshed["version"] != "2" {
+				t.Fatalf("expected version 2 with the configured keepers, got keepers %v and version %v",
+					refreshed["keepers"], refreshed["version"])
+			}
+
+			// Planning after the refresh generates a new value
+			planResp = planResourceChangePrivate(t, server, testCase.typeName, refreshed, read.Private, testCase.config)
+			planned := plannedStateMap(t, server, testCase.typeName, planResp)
+			if planned["version"] != unknownValue {
+				t.Fatalf("expected a new value to be planned, got version %v", planned["version"])
+			}
+			if warnings := planWarnings(planResp); !reflect.DeepEqual(warnings, []string{"New value planned"}) {
+				t.Errorf("expected the plan to warn about the new value, got %v", warnings)
+			}
+
+			server = vault.configuredServer(t)
+			applied := applySucceeded(t, applyResourceChangePrivate(t, server, testCase.typeName, refreshed, read.Private, testCase.config))
+			state := stateMap(t, server, testCase.typeName, applied.NewState)
+			if !reflect.DeepEqual(state["keepers"], map[string]any{"env": "prod"}) || state["version"] != "3" {
+				t.Fatalf("expected version 3 with the configured keepers, got keepers %v and version %v",
+					state["keepers"], state["version"])
+			}
+
+			// The new value is not regenerated again
+			server = vault.configuredServer(t)
+			read = readResourcePrivate(t, server, testCase.typeName, applied.NewState, applied.Private)
+			planResp = planResourceChangePrivate(t, server, testCase.typeName,
+				stateMap(t, server, testCase.typeName, read.NewState), read.Private, testCase.config)
+			if planned := plannedStateMap(t, server, testCase.typeName, planResp); planned["version"] != "3" {
+				t.Errorf("expected version 3 to be kept, got %v", planned["version"])
+			}
+		})
+	}
+}
+
+// Earlier releases marked drift with entries in keepers, which are removed on refresh while the value is
+// still regenerated.
+func TestDriftKeeperMarkersMigrated(t *testing.T) {
+	testCases := map[string]struct {
+		keepers  map[string]any
+		expected any
+	}{
+		"configured-keepers": {
+			keepers: map[string]any{
+				"env":                    "prod",
+				"drift-detected-id":      "1e0e4d4c-5d1b-4b8c-9a8c-0d6a2c3c1f5e",
+				"drift-detected-message": "drift was detected",
+			},
+			expected: map[string]any{"env": "prod"},
+		},
+		"only-markers": {
+			keepers: map[string]any{
+				"expired-id":      "1e0e4d4c-5d1b-4b8c-9a8c-0d6a2c3c1f5e",
+				"expired-message": "the value has expired",
+			},
+			expected: nil,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			config := map[string]any{"name": "drift-test"}
+			created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_uuid", nil, config))
+
+			// The state written by an earlier release, whose value was marked for regeneration in keepers
+			prior := stateMap(t, vault.configuredServer(t), "azrandom_uuid", created.NewState)
+			prior["keepers"] = testCase.keepers
+			if testCase.expected != nil {
+				config["keepers"] = testCase.expected
+			}
+
+			server := vault.configuredServer(t)
+			read := readResource(t, server, "azrandom_uuid", resourceDynamicValue(t, server, "azrandom_uuid", prior))
+			refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+			if !reflect.DeepEqual(refreshed["keepers"], testCase.expected) {
+				t.Fatalf("expected keepers %v, got %v", testCase.expected, refreshed["keepers"])
+			}
+
+			planned := plannedStateMap(t, server, "azrandom_uuid",
+				planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+			if planned["version"] != unknownValue {
+				t.Errorf("expected a new value to be planned, got version %v", planned["version"])
+			}
+		})
+	}
+}
+
+// planErrors returns the summaries of the errors of a plan.
+func planErrors(resp *tfprotov6.PlanResourceChangeResponse) []string {
+	var errors []string
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			errors = append(errors, diagnostic.Summary)
+		}
+	}
+	return errors
+}
+
+func TestOnDrift(t *testing.T) {
+	testCases := map[string]struct {
+		typeName string
+		config   map[string]any
+		// The version planned after drift, or nil when the plan fails
+		expected any
+	}{
+		"uuid-regenerate": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "on-drift-test", "on_drift": "regenerate"},
+			expected: unknownValue,
+		},
+		"uuid-ignore": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "on-drift-test", "on_drift": "ignore"},
+			expected: "2",
+		},
+		"uuid-error": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "on-drift-test", "on_drift": "error"},
+		},
+		"string-ignore": {
+			typeName: "azrandom_string",
+			config:   map[string]any{"name": "on-drift-test", "length": 16, "on_drift": "ignore"},
+			expected: "2",
+		},
+		"string-error": {
+			typeName: "azrandom_string",
+			config:   map[string]any{"name": "on-drift-test", "length": 16, "on_drift": "error"},
+		},
+		"cryptographic-key-ignore": {
+			typeName: "azrandom_cryptographic_key",
+			config:   map[string]any{"name": "on-drift-test", "algorithm": "ED25519", "on_drift": "ignore"},
+			expected: "2",
+		},
+		"cryptographic-key-error": {
+			typeName: "azrandom_cryptographic_key",
+			config:   map[string]any{"name": "on-drift-test", "algorithm": "ED25519", "on_drift": "error"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), testCase.typeName, nil, testCase.config))
+
+			vault.write("on-drift-test")
+			server := vault.configuredServer(t)
+			read := readResourcePrivate(t, server, testCase.typeName, created.NewState, created.Private)
+			refreshed := stateMap(t, server, testCase.typeName, read.NewState)
+			if refreshed["version"] != "2" {
+				t.Fatalf("expected version 2 to be read, got %v", refreshed["version"])
+			}
+
+			planResp := planResourceChangePrivate(t, server, testCase.typeName, refreshed, read.Private, testCase.config)
+			if testCase.expected == nil {
+				if errors := planErrors(planResp); !reflect.DeepEqual(errors, []string{"Secret changed outside of Terraform"}) {
+					t.Fatalf("expected the plan to fail on drift, got %v", errors)
+				}
+				return
+			}
+			if planned := plannedStateMap(t, server, testCase.typeName, planResp); planned["version"] != testCase.expected {
+				t.Errorf("expected version %v to be planned, got %v", testCase.expected, planned["version"])
+			}
+		})
+	}
+}
+
+// Changing on_drift from error to ignore after drift was detected adopts the current version.
+func TestOnDriftErrorResolved(t *testing.T) {
+	vault := newMemoryVault(t)
+	config := map[string]any{"name": "on-drift-test", "on_drift": "error"}
+	created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_uuid", nil, config))
+
+	vault.write("on-drift-test")
+	server := vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+	refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+
+	// Read marked the value for regeneration, which the planned on_drift clears, so the version is kept
+	config["on_drift"] = "ignore"
+	server = vault.configuredServer(t)
+	planned := plannedStateMap(t, server, "azrandom_uuid",
+		planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+	if planned["version"] != "2" {
+		t.Fatalf("expected version 2 to be planned, got %v", planned["version"])
+	}
+
+	server = vault.configuredServer(t)
+	applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+	if state := stateMap(t, server, "azrandom_uuid", applied.NewState); state["version"] != "2" {
+		t.Fatalf("expected version 2 to be adopted, got %v", state["version"])
+	}
+
+	// The adopted version is not drift anymore
+	server = vault.configuredServer(t)
+	read = readResourcePrivate(t, server, "azrandom_uuid", applied.NewState, applied.Private)
+	planned = plannedStateMap(t, server, "azrandom_uuid", planResourceChangePrivate(t, server, "azrandom_uuid",
+		stateMap(t, server, "azrandom_uuid", read.NewState), read.Private, config))
+	if planned["version"] != "2" {
+		t.Errorf("expected version 2 to be kept, got %v", planned["version"])
+	}
+}
+
+// A secret whose current version no longer holds the generated value, e.g. after a restore from backup, is
+// handled as drift, although its version is unchanged.
+func TestValueTampered(t *testing.T) {
+	testCases := map[string]struct {
+		typeName string
+		config   map[string]any
+		// The version planned after tampering, or nil when the plan fails
+		expected any
+	}{
+		"uuid-regenerate": {
+			typeName: "azrandom_uuid",
+			config:   map[string]any{"name": "tampered-test"},
+			expected: unknownValue,
+		},
+		"string-error": {
+			typeName: "azrandom_string",
+			config:   map[string]any{"name": "tampered-test", "length": 16, "on_drift": "error"},
+		},
+		"cryptographic-key-ignore": {
+			typeName: "azrandom_cryptographic_key",
+			config:   map[string]any{"name": "tampered-test", "algorithm": "ED25519", "on_drift": "ignore"},
+			expected: "1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), testCase.typeName, nil, testCase.config))
+
+			// Only a keyed checksum of the generated value is kept, rather than the value or its SHA-256
+			value := vault.values["tampered-test"]
+			if value == "" || bytes.Contains(created.Private, []byte(value)) {
+				t.Fatalf("expected private state to hold a checksum of the value only, got %s", created.Private)
+			}
+			if bytes.Contains(created.Private, []byte(azrandom.HashSecretValue(value))) {
+				t.Fatalf("expected private state not to hold the SHA-256 of the value, got %s", created.Private)
+			}
+
+			// Without tampering, the value is compared and kept
+			server := vault.configuredServer(t)
+			read := readResourcePrivate(t, server, testCase.typeName, created.NewState, created.Private)
+			planResp := planResourceChangePrivate(t, server, testCase.typeName,
+				stateMap(t, server, testCase.typeName, read.NewState), read.Private, testCase.config)
+			if planned := plannedStateMap(t, server, testCase.typeName, planResp); planned["version"] != "1" {
+				t.Fatalf("expected version 1 to be kept, got %v", planned["version"])
+			}
+
+			vault.setValue("tampered-test", "restored from backup")
+			server = vault.configuredServer(t)
+			read = readResourcePrivate(t, server, testCase.typeName, created.NewState, created.Private)
+			refreshed := stateMap(t, server, testCase.typeName, read.NewState)
+			if refreshed["version"] != "1" {
+				t.Fatalf("expected version 1 to be read, got %v", refreshed["version"])
+			}
+
+			planResp = planResourceChangePrivate(t, server, testCase.typeName, refreshed, read.Private, testCase.config)
+			if testCase.expected == nil {
+				if errors := planErrors(planResp); !reflect.DeepEqual(errors, []string{"Secret changed outside of Terraform"}) {
+					t.Fatalf("expected the plan to fail on tampering, got %v", errors)
+				}
+				return
+			}
+			if planned := plannedStateMap(t, server, testCase.typeName, planResp); planned["version"] != testCase.expected {
+				t.Errorf("expected version %v to be planned, got %v", testCase.expected, planned["version"])
+			}
+		})
+	}
+}
+
+// A new version of the secret that still holds the generated value, e.g. because the value was set again, is no
+// drift: the new version is kept, and is compared from then on.
+func TestValueRewritten(t *testing.T) {
+	config := map[string]any{"name": "rewritten-test"}
+
+	vault := newMemoryVault(t)
+	created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_uuid", nil, config))
+
+	vault.rewrite("rewritten-test")
+	server := vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+	refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+	planResp := planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config)
+	if planned := plannedStateMap(t, server, "azrandom_uuid", planResp); planned["version"] != "2" {
+		t.Fatalf("expected version 2 to be kept, got %v", planned["version"])
+	}
+
+	// Tampering with the new version is still detected
+	vault.setValue("rewritten-test", "restored from backup")
+	server = vault.configuredServer(t)
+	read = readResourcePrivate(t, server, "azrandom_uuid", read.NewState, read.Private)
+	refreshed = stateMap(t, server, "azrandom_uuid", read.NewState)
+	planResp = planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config)
+	if planned := plannedStateMap(t, server, "azrandom_uuid", planResp); planned["version"] != unknownValue {
+		t.Errorf("expected a new value to be planned, got version %v", planned["version"])
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// newTestEmulator returns a TLS server with a self-signed certificate serving the secret emulator-test, like an
+// emulator of Key Vault. It challenges unauthenticated requests for a resource that is no Key Vault DNS suffix,
+// and records the tokens of the authenticated ones.
+func newTestEmulator(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var tokens []string
+	emulator := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://localhost/tenant" resource="https://localhost"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		tokens = append(tokens, strings.TrimPrefix(authorization, "Bearer "))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		secretPath := strings.Trim(r.URL.Path, "/")
+		if r.Method != http.MethodGet || (secretPath != "secrets/emulator-test" && secretPath != "secrets/emulator-test/v1") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "SecretNotFound", "message": "not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "https://` + r.Host + `/secrets/emulator-test/v1", ` +
+			`"value": "0b1c6f5e-6a4a-4c8e-9d3e-2f1a7b8c9d0e", "attributes": {"enabled": true}}`))
+	}))
+	t.Cleanup(emulator.Close)
+
+	return emulator, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), tokens...)
+	}
+}
+
+func TestProviderConfigureInsecure(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+	}{
+		"custom-endpoint": {
+			config:      map[string]any{"custom_endpoint": "https://localhost:8443"},
+			expectError: "Insecure Azrandom Option Not Allowed",
+		},
+		"insecure-skip-verify": {
+			config:      map[string]any{"vault_url": testVaultUrl, "insecure_skip_verify": true},
+			expectError: "Insecure Azrandom Option Not Allowed",
+		},
+		"use-fake-credential": {
+			config:      map[string]any{"vault_url": testVaultUrl, "use_fake_credential": true},
+			expectError: "Insecure Azrandom Option Not Allowed",
+		},
+		"env": {
+			env:         map[string]string{"AZRANDOM_CUSTOM_ENDPOINT": "https://localhost:8443"},
+			expectError: "Insecure Azrandom Option Not Allowed",
+		},
+		"not-allowed": {
+			config:      map[string]any{"custom_endpoint": "https://localhost:8443", "allow_insecure": false},
+			expectError: "Insecure Azrandom Option Not Allowed",
+		},
+		"allowed": {
+			config: map[string]any{"custom_endpoint": "https://localhost:8443", "allow_insecure": true},
+		},
+		"allowed-env": {
+			env: map[string]string{
+				"AZRANDOM_CUSTOM_ENDPOINT":      "https://localhost:8443",
+				"AZRANDOM_INSECURE_SKIP_VERIFY": "true",
+				"AZRANDOM_USE_FAKE_CREDENTIAL":  "true",
+			},
+			config: map[string]any{"allow_insecure": true},
+		},
+		"custom-endpoint-overrides-env-vault": {
+			env:    map[string]string{"AZRANDOM_VAULT_URL": testVaultUrl},
+			config: map[string]any{"custom_endpoint": "https://localhost:8443", "allow_insecure": true},
+		},
+		"vault-overrides-env-custom-endpoint": {
+			env:    map[string]string{"AZRANDOM_CUSTOM_ENDPOINT": "https://localhost:8443"},
+			config: map[string]any{"vault_url": testVaultUrl, "allow_insecure": true},
+		},
+		"conflicting-env": {
+			env: map[string]string{
+				"AZRANDOM_CUSTOM_ENDPOINT": "https://localhost:8443",
+				"AZRANDOM_VAULT_NAME":      "azrandom-test",
+			},
+			config:      map[string]any{"allow_insecure": true},
+			expectError: "Conflicting Azrandom Custom Endpoint",
+		},
+		"endpoint-with-path": {
+			config:      map[string]any{"custom_endpoint": "https://localhost:8443/vault", "allow_insecure": true},
+			expectError: "Invalid Azrandom Custom Endpoint",
+		},
+		"http-endpoint-from-env": {
+			env:         map[string]string{"AZRANDOM_CUSTOM_ENDPOINT": "http://localhost:8443"},
+			config:      map[string]any{"allow_insecure": true},
+			expectError: "Invalid Azrandom Custom Endpoint",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, envVar := range []string{"AZRANDOM_VAULT_URL", "AZRANDOM_VAULT_NAME", "AZRANDOM_CUSTOM_ENDPOINT",
+				"AZRANDOM_INSECURE_SKIP_VERIFY", "AZRANDOM_USE_FAKE_CREDENTIAL"} {
+				t.Setenv(envVar, "")
+			}
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]any{"skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics := configureProvider(t, config)
+			if testCase.expectError == "" {
+				if len(diagnostics) != 0 {
+					t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+				t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigInsecure(t *testing.T) {
+	testCases := map[string]struct {
+		config   map[string]any
+		expected []string
+	}{
+		"not-allowed": {
+			config: map[string]any{"custom_endpoint": "https://localhost:8443", "insecure_skip_verify": true, "use_fake_credential": true},
+			expected: []string{
+				"Insecure Azrandom Option Not Allowed",
+				"Insecure Azrandom Option Not Allowed",
+				"Insecure Azrandom Option Not Allowed",
+			},
+		},
+		"disabled": {
+			config: map[string]any{"vault_url": testVaultUrl, "insecure_skip_verify": false, "use_fake_credential": false},
+		},
+		"allowed": {
+			config: map[string]any{"custom_endpoint": "https://localhost:8443", "insecure_skip_verify": true, "allow_insecure": true},
+		},
+		"custom-endpoint-and-vault-url": {
+			config:   map[string]any{"custom_endpoint": "https://localhost:8443", "vault_url": testVaultUrl, "allow_insecure": true},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			errors := validateProviderConfig(t, testCase.config)
+			if strings.Join(errors, ", ") != strings.Join(testCase.expected, ", ") {
+				t.Errorf("expected errors %v, got %v", testCase.expected, errors)
+			}
+		})
+	}
+}
+
+func TestProviderEmulator(t *testing.T) {
+	emulator, tokens := newTestEmulator(t)
+
+	testCases := map[string]struct {
+		config      map[string]any
+		expectError string
+	}{
+		"emulator": {
+			config: map[string]any{"insecure_skip_verify": true, "use_fake_credential": true},
+		},
+		"certificate-verified": {
+			config:      map[string]any{"use_fake_credential": true},
+			expectError: "certificate",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]any{
+				"custom_endpoint": emulator.URL,
+				"allow_insecure":  true,
+				"retry":           map[string]any{"max_retries": 0},
+			}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+			server := configuredServer(t, config)
+
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_uuid",
+				ID:       "emulator-test",
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+			if testCase.expectError != "" {
+				if len(resp.Diagnostics) != 1 || !strings.Contains(resp.Diagnostics[0].Detail, testCase.expectError) {
+					t.Fatalf("expected an error containing %q, got %v", testCase.expectError, resp.Diagnostics)
+				}
+				return
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					t.Fatalf("unexpected error importing from the emulator: %s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+			for _, token := range tokens() {
+				if token != "azrandom-fake-token" {
+					t.Errorf("expected the emulator to receive the fake token, got %q", token)
+				}
+			}
+			if len(tokens()) == 0 {
+				t.Error("expected the emulator to receive authenticated requests")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"terraform-provider-azrandom/internal/provider"
+)
+
+func TestAccResourceIdentity(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"azrandom_uuid":   ``,
+		"azrandom_string": `length = 16`,
+	}
+
+	for typeName, attributes := range testCases {
+		t.Run(typeName, func(t *testing.T) {
+			t.Parallel()
+
+			testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+				name := protocol.testName(strings.ReplaceAll(strings.TrimPrefix(typeName, "azrandom_"), "_", "-") + "-identity-test")
+				resourceName := typeName + ".this"
+
+				return resource.TestCase{
+					// Import blocks with an identity
+					TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+						tfversion.SkipBelow(tfversion.Version1_12_0),
+					},
+					Steps: []resource.TestStep{
+						{
+							Config: providerConfig + fmt.Sprintf(`resource %[1]q "this" {
+									name = %[2]q
+									%[3]s
+								}`, typeName, name, attributes),
+							ConfigStateChecks: []statecheck.StateCheck{
+								statecheck.ExpectIdentity(resourceName, map[string]knownvalue.Check{
+									"vault_url": knownvalue.NotNull(),
+									"name":      knownvalue.StringExact(name),
+								}),
+							},
+						},
+						{
+							ResourceName:    resourceName,
+							ImportState:     true,
+							ImportStateKind: resource.ImportBlockWithResourceIdentity,
+						},
+					},
+				}
+			})
+		})
+	}
+}
+
+// identityMap returns identity, the identity of a resource of type typeName returned by an apply, read or
+// import, as a map of attribute values.
+func identityMap(t *testing.T, server tfprotov6.ProviderServer, typeName string, identity *tfprotov6.ResourceIdentityData) map[string]any {
+	t.Helper()
+
+	if identity == nil || identity.IdentityData == nil {
+		return nil
+	}
+	value, err := identity.IdentityData.Unmarshal(identitySchema(t, server, typeName).ValueType())
+	if err != nil {
+		t.Fatalf("unable to unmarshal identity: %s", err)
+	}
+	if value.IsNull() {
+		return nil
+	}
+	return tfValueToMap(t, value)
+}
+
+// identityData returns m, a map of identity attribute values of a resource of type typeName, as identity data.
+func identityData(t *testing.T, server tfprotov6.ProviderServer, typeName string, m map[string]any) *tfprotov6.ResourceIdentityData {
+	t.Helper()
+
+	identityType := identitySchema(t, server, typeName).ValueType()
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unable to marshal identity: %s", err)
+	}
+	value, err := (&tfprotov6.RawState{JSON: raw}).Unmarshal(identityType)
+	if err != nil {
+		t.Fatalf("unable to unmarshal identity: %s", err)
+	}
+	dynamicValue, err := tfprotov6.NewDynamicValue(identityType, value)
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+	return &tfprotov6.ResourceIdentityData{IdentityData: &dynamicValue}
+}
+
+// identitySchema returns the identity schema of the resource typeName.
+func identitySchema(t *testing.T, server tfprotov6.ProviderServer, typeName string) *tfprotov6.ResourceIdentitySchema {
+	t.Helper()
+
+	resp, err := server.GetResourceIdentitySchemas(context.Background(), &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("unable to get resource identity schemas: %s", err)
+	}
+	return resp.IdentitySchemas[typeName]
+}
+
+// Every resource that stores a single secret is identified by its vault and the name of the secret. A password
+// set stores several secrets, named after its configuration, so it has no identity.
+func TestResourceIdentitySchemas(t *testing.T) {
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+
+	for typeName := range schemaResp.ResourceSchemas {
+		schema := identitySchema(t, server, typeName)
+		if typeName == "azrandom_password_set" {
+			if schema != nil {
+				t.Errorf("expected %s to have no identity", typeName)
+			}
+			continue
+		}
+		if schema == nil {
+			t.Errorf("expected %s to have an identity", typeName)
+			continue
+		}
+
+		attributes := map[string]bool{}
+		for _, attribute := range schema.IdentityAttributes {
+			attributes[attribute.Name] = attribute.RequiredForImport
+		}
+		if required, ok := attributes["name"]; !ok || !required || len(attributes) != 2 {
+			t.Errorf("expected the identity of %s to be a vault_url and a required name, got %v", typeName, attributes)
+		}
+		if required, ok := attributes["vault_url"]; !ok || required {
+			t.Errorf("expected the identity of %s to have an optional vault_url, got %v", typeName, attributes)
+		}
+	}
+}
+
+func TestResourceIdentity(t *testing.T) {
+	vault := newMemoryVault(t)
+	expected := map[string]any{
+		"vault_url": strings.ToLower(strings.TrimRight(vault.URL, "/")),
+		"name":      "identity-test",
+	}
+
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_uuid", nil, map[string]any{"name": "identity-test"}))
+	if identity := identityMap(t, server, "azrandom_uuid", created.NewIdentity); fmt.Sprint(identity) != fmt.Sprint(expected) {
+		t.Errorf("expected the created resource to have identity %v, got %v", expected, identity)
+	}
+
+	// Resources created before identities were supported get one on refresh
+	read := readResource(t, server, "azrandom_uuid", created.NewState)
+	if identity := identityMap(t, server, "azrandom_uuid", read.NewIdentity); fmt.Sprint(identity) != fmt.Sprint(expected) {
+		t.Errorf("expected the refreshed resource to have identity %v, got %v", expected, identity)
+	}
+}
+
+func TestResourceImportIdentity(t *testing.T) {
+	vault := newMemoryVault(t)
+	vault.write("import-identity-test")
+	vault.setValue("import-identity-test", "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4")
+
+	testCases := map[string]struct {
+		identity    map[string]any
+		expectError bool
+	}{
+		"name": {
+			identity: map[string]any{"vault_url": nil, "name": "import-identity-test"},
+		},
+		"vault-url": {
+			identity: map[string]any{"vault_url": vault.URL, "name": "import-identity-test"},
+		},
+		"vault-url-trailing-slash": {
+			identity: map[string]any{"vault_url": vault.URL + "/", "name": "import-identity-test"},
+		},
+		"vault-url-not-https": {
+			identity:    map[string]any{"vault_url": strings.TrimPrefix(vault.URL, "https://"), "name": "import-identity-test"},
+			expectError: true,
+		},
+		"empty-name": {
+			identity:    map[string]any{"vault_url": nil, "name": ""},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := vault.configuredServer(t)
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_uuid",
+				Identity: identityData(t, server, "azrandom_uuid", testCase.identity),
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+
+			if testCase.expectError {
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Invalid Import Identity" {
+					t.Fatalf("expected an invalid import identity error, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+				t.Fatalf("expected the secret to be imported, got %v", resp.Diagnostics)
+			}
+
+			// The vault of the identity is the vault of the provider, so vault_url is not set, but the identity
+			// is completed with it
+			imported := stateMap(t, server, "azrandom_uuid", resp.ImportedResources[0].State)
+			if imported["name"] != "import-identity-test" || imported["vault_url"] != nil {
+				t.Errorf("expected import-identity-test to be imported from the vault of the provider, got name %v "+
+					"and vault_url %v", imported["name"], imported["vault_url"])
+			}
+			expected := map[string]any{
+				"vault_url": strings.ToLower(strings.TrimRight(vault.URL, "/")),
+				"name":      "import-identity-test",
+			}
+			identity := identityMap(t, server, "azrandom_uuid", resp.ImportedResources[0].Identity)
+			if fmt.Sprint(identity) != fmt.Sprint(expected) {
+				t.Errorf("expected the imported resource to have identity %v, got %v", expected, identity)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"testing"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func TestLuhnModNCheckCharacter(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		alphabet string
+		expected byte
+	}{
+		// With a decimal alphabet, Luhn mod N is the regular Luhn algorithm
+		"luhn": {
+			input:    "7992739871",
+			alphabet: "0123456789",
+			expected: '3',
+		},
+		"luhn-card-number": {
+			input:    "453914880343646",
+			alphabet: "0123456789",
+			expected: '7',
+		},
+		"luhn-zero": {
+			input:    "0",
+			alphabet: "0123456789",
+			expected: '0',
+		},
+		"hexadecimal-letters": {
+			input:    "abcdef",
+			alphabet: "abcdef",
+			expected: 'e',
+		},
+		"crockford-base32": {
+			input:    "AB7D93KFQ2MT8XP",
+			alphabet: crockfordBase32,
+			expected: 'D',
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := random.LuhnModNCheckCharacter(testCase.input, testCase.alphabet)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.expected {
+				t.Errorf("expected check character %q, got %q", testCase.expected, got)
+			}
+			if !random.ValidLuhnModN(testCase.input+string(got), testCase.alphabet) {
+				t.Errorf("expected %s%c to be valid", testCase.input, got)
+			}
+		})
+	}
+}
+
+func TestValidLuhnModN(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		alphabet string
+		expected bool
+	}{
+		"valid": {
+			input:    "79927398713",
+			alphabet: "0123456789",
+			expected: true,
+		},
+		"wrong-check-digit": {
+			input:    "79927398710",
+			alphabet: "0123456789",
+			expected: false,
+		},
+		// Luhn detects every transposition of adjacent digits except 09 and 90
+		"transposed": {
+			input:    "79927398731",
+			alphabet: "0123456789",
+			expected: false,
+		},
+		"not-in-alphabet": {
+			input:    "AB7D93KFQ2MT8XLX",
+			alphabet: crockfordBase32,
+			expected: false,
+		},
+		"too-short": {
+			input:    "0",
+			alphabet: "0123456789",
+			expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := random.ValidLuhnModN(testCase.input, testCase.alphabet); got != testCase.expected {
+				t.Errorf("expected %t for %s, got %t", testCase.expected, testCase.input, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+var _ plancheck.PlanCheck = expectKnownValue{}
+
+// expectKnownValue is the counterpart of plancheck.ExpectUnknownValue.
+type expectKnownValue struct {
+	resourceAddress string
+	attributePath   tfjsonpath.Path
+}
+
+// CheckPlan implements the plan check logic.
+func (e expectKnownValue) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if e.resourceAddress != rc.Address {
+			continue
+		}
+
+		// Known attributes are left out of after_unknown
+		result, err := tfjsonpath.Traverse(rc.Change.AfterUnknown, e.attributePath)
+		if isUnknown, ok := result.(bool); err == nil && ok && isUnknown {
+			resp.Error = fmt.Errorf("attribute at path is unknown")
+		}
+		return
+	}
+
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
+}
+
+// expectKnownVersion returns a plan check that asserts that the version of the given resource is known.
+func expectKnownVersion(resourceAddress string) plancheck.PlanCheck {
+	return expectKnownValue{resourceAddress: resourceAddress, attributePath: tfjsonpath.New("version")}
+}
+
+func TestPlanVersion(t *testing.T) {
+	// The state of a string with the default generation settings
+	stringState := map[string]any{
+		"name": "plan-version-test", "version": "1", "length": 16,
+		"special": true, "upper": true, "lower": true, "numeric": true,
+		"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0, "first_char_alpha": false,
+		"encoding": "plain",
+	}
+	// The state of an imported string, whose encoding is unknown
+	importedStringState := map[string]any{}
+	for name, value := range stringState {
+		importedStringState[name] = value
+	}
+	delete(importedStringState, "encoding")
+
+	testCases := map[string]struct {
+		typeName   string
+		priorState map[string]any
+		config     map[string]any
+		expected   any
+	}{
+		"uuid-unchanged": {
+			typeName:   "azrandom_uuid",
+			priorState: map[string]any{"name": "plan-version-test", "version": "1", "uuid_version": "v4", "format": "canonical", "uppercase": false, "keepers": map[string]any{"foo": "bar"}},
+			config:     map[string]any{"name": "plan-version-test", "keepers": map[string]any{"foo": "bar"}},
+			expected:   "1",
+		},
+		"uuid-keepers-changed": {
+			typeName:   "azrandom_uuid",
+			priorState: map[string]any{"name": "plan-version-test", "version": "1", "uuid_version": "v4", "format": "canonical", "uppercase": false, "keepers": map[string]any{"foo": "bar"}},
+			config:     map[string]any{"name": "plan-version-test", "keepers": map[string]any{"foo": "baz"}},
+			expected:   unknownValue,
+		},
+		"uuid-description-changed": {
+			typeName:   "azrandom_uuid",
+			priorState: map[string]any{"name": "plan-version-test", "version": "1", "uuid_version": "v4", "format": "canonical", "uppercase": false},
+			config:     map[string]any{"name": "plan-version-test", "description": "a new description"},
+			expected:   "1",
+		},
+		"string-keepers-changed": {
+			typeName:   "azrandom_string",
+			priorState: stringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 16, "keepers": map[string]any{"foo": "bar"}},
+			expected:   unknownValue,
+		},
+		"string-length-changed": {
+			typeName:   "azrandom_string",
+			priorState: stringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 20},
+			expected:   unknownValue,
+		},
+		"string-tags-changed": {
+			typeName:   "azrandom_string",
+			priorState: stringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 16, "tags": map[string]any{"team": "platform"}},
+			expected:   "1",
+		},
+		"string-encoding-changed": {
+			typeName:   "azrandom_string",
+			priorState: stringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 16, "encoding": "base64"},
+			expected:   unknownValue,
+		},
+		"string-prefix-changed": {
+			typeName:   "azrandom_string",
+			priorState: stringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 16, "prefix": "svc-"},
+			expected:   unknownValue,
+		},
+		"string-imported-encoding": {
+			typeName:   "azrandom_string",
+			priorState: importedStringState,
+			config:     map[string]any{"name": "plan-version-test", "length": 16, "encoding": "base64"},
+			expected:   "1",
+		},
+		"cryptographic-key-keepers-changed": {
+			typeName:   "azrandom_cryptographic_key",
+			priorState: map[string]any{"name": "plan-version-test", "version": "1", "algorithm": "ED25519"},
+			config:     map[string]any{"name": "plan-version-test", "algorithm": "ED25519", "keepers": map[string]any{"foo": "bar"}},
+			expected:   unknownValue,
+		},
+		"cryptographic-key-trim-outputs-changed": {
+			typeName:   "azrandom_cryptographic_key",
+			priorState: map[string]any{"name": "plan-version-test", "version": "1", "algorithm": "ED25519", "trim_outputs": false},
+			config:     map[string]any{"name": "plan-version-test", "algorithm": "ED25519", "trim_outputs": true},
+			expected:   "1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, testCase.typeName, testCase.priorState, testCase.config)
+			if planned["version"] != testCase.expected {
+				t.Errorf("expected version %v, got %v", testCase.expected, planned["version"])
+			}
+		})
+	}
+}
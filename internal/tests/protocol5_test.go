@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"terraform-provider-azrandom/internal/provider"
+)
+
+// Resource types with nested attributes cannot be expressed in protocol version 5. They are served over it with
+// a schema that protocol version 5 can express, and fail to validate with an error naming protocol version 6.
+func TestProtocol5Schema(t *testing.T) {
+	server := providerserver.NewProtocol5(provider.NewProtocol5("test")())()
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("unexpected error getting provider schema: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	for _, typeName := range []string{"azrandom_uuid", "azrandom_string", "azrandom_stored_secret"} {
+		if _, ok := resp.ResourceSchemas[typeName]; !ok {
+			t.Errorf("expected %s to be served over protocol version 5", typeName)
+		}
+	}
+	for _, typeName := range []string{"azrandom_cryptographic_key", "azrandom_kdf_params", "azrandom_password_set"} {
+		t.Run(typeName, func(t *testing.T) {
+			resourceSchema, ok := resp.ResourceSchemas[typeName]
+			if !ok {
+				t.Fatalf("expected %s to be served over protocol version 5", typeName)
+			}
+
+			validateResp, err := server.ValidateResourceTypeConfig(context.Background(), &tfprotov5.ValidateResourceTypeConfigRequest{
+				TypeName: typeName,
+				Config:   protocol5NullObject(t, resourceSchema),
+			})
+			if err != nil {
+				t.Fatalf("unable to validate config: %s", err)
+			}
+			expectProtocol6Error(t, typeName, validateResp.Diagnostics)
+		})
+	}
+}
+
+func TestAccProtocol5NestedAttributes(t *testing.T) {
+	t.Parallel()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" {
+							name = %[1]q
+							algorithm = "RSA"
+							rsa = {
+								bits = 2048
+							}
+						}`, testProtocol5.testName("cryptographic-key-protocol5-test")),
+				ExpectError: regexp.MustCompile("Resource Type Requires Protocol Version 6"),
+			},
+		},
+	})
+}
+
+// Resource types that need protocol version 6 fail to read or delete state over protocol version 5, as state of
+// them exists after downgrading Terraform, rather than calling the resource they wrap without provider data.
+func TestProtocol5ExistingState(t *testing.T) {
+	vault := newMemoryVault(t)
+	server := providerserver.NewProtocol5(provider.NewProtocol5("test")())()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	config := protocol5Value(t, schemaResp.Provider, map[string]any{
+		"custom_endpoint":      vault.URL,
+		"allow_insecure":       true,
+		"insecure_skip_verify": true,
+		"use_fake_credential":  true,
+		"retry":                map[string]any{"max_retries": 0},
+	})
+	configureResp, err := server.ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "0.15.5",
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatalf("unable to configure provider: %s", err)
+	}
+	for _, diagnostic := range configureResp.Diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("unexpected error configuring provider: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	for _, typeName := range []string{"azrandom_cryptographic_key", "azrandom_kdf_params", "azrandom_password_set"} {
+		t.Run(typeName, func(t *testing.T) {
+			resourceSchema := schemaResp.ResourceSchemas[typeName]
+			state := protocol5NullObject(t, resourceSchema)
+
+			readResp, err := server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+				TypeName:     typeName,
+				CurrentState: state,
+			})
+			if err != nil {
+				t.Fatalf("unable to read resource: %s", err)
+			}
+			expectProtocol6Error(t, typeName, readResp.Diagnostics)
+
+			plannedState, err := tfprotov5.NewDynamicValue(resourceSchema.ValueType(), tftypes.NewValue(resourceSchema.ValueType(), nil))
+			if err != nil {
+				t.Fatalf("unable to create planned state: %s", err)
+			}
+			applyResp, err := server.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+				TypeName:     typeName,
+				PriorState:   state,
+				PlannedState: &plannedState,
+				Config:       &plannedState,
+			})
+			if err != nil {
+				t.Fatalf("unable to delete resource: %s", err)
+			}
+			expectProtocol6Error(t, typeName, applyResp.Diagnostics)
+		})
+	}
+}
+
+// protocol5NullObject returns a value of the given schema with every attribute null.
+func protocol5NullObject(t *testing.T, s *tfprotov5.Schema) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	objectType := s.ValueType().(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	value, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+	return &value
+}
+
+// protocol5Value returns the given attributes as a value of the given schema.
+func protocol5Value(t *testing.T, s *tfprotov5.Schema, attributes map[string]any) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		t.Fatalf("unable to marshal value: %s", err)
+	}
+	value, err := (&tfprotov5.RawState{JSON: raw}).Unmarshal(s.ValueType())
+	if err != nil {
+		t.Fatalf("unable to unmarshal value: %s", err)
+	}
+	dynamicValue, err := tfprotov5.NewDynamicValue(s.ValueType(), value)
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+	return &dynamicValue
+}
+
+// expectProtocol6Error fails the test unless diagnostics have the error of typeName needing protocol version 6.
+func expectProtocol6Error(t *testing.T, typeName string, diagnostics []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError && strings.Contains(diagnostic.Detail, typeName) &&
+			strings.Contains(diagnostic.Summary, "Protocol Version 6") {
+			return
+		}
+	}
+	t.Errorf("expected an error naming protocol version 6, got: %v", diagnostics)
+}
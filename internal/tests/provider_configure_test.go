@@ -0,0 +1,964 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// servicePrincipalConfig returns a provider configuration authenticating as a service principal with the
+// given attributes, skipping credential validation.
+func servicePrincipalConfig(attributes map[string]any) map[string]any {
+	config := map[string]any{
+		"vault_url":                  testVaultUrl,
+		"tenant_id":                  "00000000-0000-0000-0000-000000000000",
+		"client_id":                  "11111111-1111-1111-1111-111111111111",
+		"skip_credential_validation": true,
+	}
+	for k, v := range attributes {
+		config[k] = v
+	}
+	return config
+}
+
+func TestProviderConfigureClientCertificate(t *testing.T) {
+	testCases := map[string]struct {
+		config       map[string]any
+		expectError  string
+		expectAtPath *tftypes.AttributePath
+	}{
+		"pem": {
+			config: servicePrincipalConfig(map[string]any{"client_certificate_path": "testdata/client_certificate.pem"}),
+		},
+		"pfx": {
+			config: servicePrincipalConfig(map[string]any{
+				"client_certificate_path":     "testdata/client_certificate.pfx",
+				"client_certificate_password": "test-password",
+			}),
+		},
+		"pfx-without-password": {
+			config:       servicePrincipalConfig(map[string]any{"client_certificate_path": "testdata/client_certificate.pfx"}),
+			expectError:  "Invalid Azrandom Client Certificate",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("client_certificate_path"),
+		},
+		"missing": {
+			config:       servicePrincipalConfig(map[string]any{"client_certificate_path": "testdata/missing.pem"}),
+			expectError:  "Invalid Azrandom Client Certificate",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("client_certificate_path"),
+		},
+		"incomplete": {
+			config:      map[string]any{"vault_url": testVaultUrl, "client_certificate_path": "testdata/client_certificate.pem"},
+			expectError: "Incomplete Azrandom Service Principal",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var errors []*tfprotov6.Diagnostic
+			for _, diagnostic := range configureProvider(t, testCase.config) {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					errors = append(errors, diagnostic)
+				}
+			}
+
+			if testCase.expectError == "" {
+				if len(errors) != 0 {
+					t.Fatalf("expected no errors, got %s: %s", errors[0].Summary, errors[0].Detail)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Summary != testCase.expectError {
+				t.Fatalf("expected error %q, got %v", testCase.expectError, errors)
+			}
+			if testCase.expectAtPath != nil && !testCase.expectAtPath.Equal(errors[0].Attribute) {
+				t.Errorf("expected error at %s, got %s", testCase.expectAtPath, errors[0].Attribute)
+			}
+		})
+	}
+}
+
+// The credential is validated while configuring the provider unless skip_credential_validation is set, so that
+// authentication failures are reported before the first secret operation.
+func TestProviderConfigureSkipCredentialValidation(t *testing.T) {
+	for name, skip := range map[string]bool{"default": false, "skipped": true} {
+		t.Run(name, func(t *testing.T) {
+			proxy := newTestProxy(t)
+			config := servicePrincipalConfig(map[string]any{
+				"client_secret":              "secret",
+				"disable_instance_discovery": true,
+				"proxy_url":                  proxy.URL,
+				"retry":                      map[string]any{"max_retries": 0},
+			})
+			if !skip {
+				delete(config, "skip_credential_validation")
+			}
+
+			diagnostics := configureProvider(t, config)
+			if failed := len(diagnostics) != 0; failed == skip {
+				t.Errorf("expected the configuration to fail to be %t, got %v", !skip, diagnostics)
+			}
+			if _, requested := proxy.received("login.microsoftonline.com:443"); requested == skip {
+				t.Errorf("expected a token to be requested to be %t, got requests for %v", !skip, proxy.hosts())
+			}
+		})
+	}
+}
+
+func TestProviderConfigureManagedIdentity(t *testing.T) {
+	const resourceID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"
+
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+	}{
+		"client-id": {
+			config: map[string]any{"managed_identity_client_id": "22222222-2222-2222-2222-222222222222"},
+		},
+		"resource-id-from-env": {
+			env: map[string]string{"AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID": resourceID},
+		},
+		"config-overrides-env": {
+			env:    map[string]string{"AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID": resourceID},
+			config: map[string]any{"managed_identity_client_id": "22222222-2222-2222-2222-222222222222"},
+		},
+		"both-from-env": {
+			env: map[string]string{
+				"AZRANDOM_MANAGED_IDENTITY_CLIENT_ID":   "22222222-2222-2222-2222-222222222222",
+				"AZRANDOM_MANAGED_IDENTITY_RESOURCE_ID": resourceID,
+			},
+			expectError: "Conflicting Azrandom Managed Identity",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors []string
+			for _, diagnostic := range configureProvider(t, config) {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					errors = append(errors, diagnostic.Summary)
+				}
+			}
+
+			if testCase.expectError == "" && len(errors) != 0 {
+				t.Errorf("expected no errors, got %v", errors)
+			}
+			if testCase.expectError != "" && (len(errors) != 1 || errors[0] != testCase.expectError) {
+				t.Errorf("expected error %q, got %v", testCase.expectError, errors)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureEnvironment(t *testing.T) {
+	testCases := map[string]struct {
+		env           map[string]string
+		config        map[string]any
+		expectError   string
+		expectWarning string
+	}{
+		"inferred-from-vault-url": {
+			config: map[string]any{"vault_url": "https://my-vault.vault.azure.cn/"},
+		},
+		"matching": {
+			config: map[string]any{"vault_url": "https://my-vault.vault.usgovcloudapi.net/", "environment": "usgovernment"},
+		},
+		"mismatched": {
+			config:      map[string]any{"vault_url": "https://my-vault.vault.azure.cn/", "environment": "public"},
+			expectError: "Mismatched Azrandom Environment",
+		},
+		"mismatched-dns-suffix": {
+			config:      map[string]any{"vault_name": "my-vault", "dns_suffix": "vault.azure.cn", "environment": "public"},
+			expectError: "Mismatched Azrandom Environment",
+		},
+		"unknown-suffix": {
+			config:      map[string]any{"vault_url": "https://vault.example.com/", "environment": "china"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"vault-name": {
+			config: map[string]any{"vault_name": "my-vault", "environment": "china"},
+		},
+		"vault-name-and-dns-suffix": {
+			config: map[string]any{"vault_name": "my-vault", "dns_suffix": "vault.example.com"},
+		},
+		"dns-suffix-from-env": {
+			env:    map[string]string{"AZRANDOM_DNS_SUFFIX": "vault.example.com"},
+			config: map[string]any{"vault_name": "my-vault"},
+		},
+		"dns-suffix-from-env-with-vault-url": {
+			// The environment variable only completes a vault name
+			env:    map[string]string{"AZRANDOM_DNS_SUFFIX": "vault.example.com"},
+			config: map[string]any{"vault_url": testVaultUrl},
+		},
+		"managed-hsm-dns-suffix": {
+			config:      map[string]any{"vault_name": "my-pool", "dns_suffix": "managedhsm.azure.net"},
+			expectError: "Unsupported Azrandom Vault Type",
+		},
+		"invalid-dns-suffix-from-env": {
+			env:         map[string]string{"AZRANDOM_DNS_SUFFIX": "https://vault.example.com"},
+			config:      map[string]any{"vault_name": "my-vault"},
+			expectError: "Invalid Azrandom DNS Suffix",
+		},
+		"dns-suffix-with-vault-url-from-env": {
+			env:         map[string]string{"AZRANDOM_VAULT_URL": testVaultUrl},
+			config:      map[string]any{"dns_suffix": "vault.example.com"},
+			expectError: "Conflicting Azrandom Vault Url and DNS Suffix",
+		},
+		"custom-authority-host": {
+			config: map[string]any{"vault_url": testVaultUrl, "custom_authority_host": "https://login.example.com/"},
+		},
+		"custom-authority-host-from-env": {
+			env:         map[string]string{"AZRANDOM_CUSTOM_AUTHORITY_HOST": "http://login.example.com/"},
+			config:      map[string]any{"vault_url": testVaultUrl},
+			expectError: "Invalid Azrandom Custom Authority Host",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors, warnings []string
+			for _, diagnostic := range configureProvider(t, config) {
+				switch diagnostic.Severity {
+				case tfprotov6.DiagnosticSeverityError:
+					errors = append(errors, diagnostic.Summary)
+				case tfprotov6.DiagnosticSeverityWarning:
+					warnings = append(warnings, diagnostic.Summary)
+				}
+			}
+
+			if testCase.expectError == "" && len(errors) != 0 {
+				t.Errorf("expected no errors, got %v", errors)
+			}
+			if testCase.expectError != "" && (len(errors) != 1 || errors[0] != testCase.expectError) {
+				t.Errorf("expected error %q, got %v", testCase.expectError, errors)
+			}
+			if testCase.expectWarning == "" && len(warnings) != 0 {
+				t.Errorf("expected no warnings, got %v", warnings)
+			}
+			if testCase.expectWarning != "" && (len(warnings) != 1 || warnings[0] != testCase.expectWarning) {
+				t.Errorf("expected warning %q, got %v", testCase.expectWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureDNSSuffix(t *testing.T) {
+	testCases := map[string]struct {
+		env      map[string]string
+		config   map[string]any
+		expected string
+	}{
+		"config": {
+			config:   map[string]any{"vault_name": "My-Vault", "dns_suffix": "Vault.Example.com"},
+			expected: "https://my-vault.vault.example.com",
+		},
+		"env": {
+			env:      map[string]string{"AZRANDOM_DNS_SUFFIX": "vault.example.com"},
+			config:   map[string]any{"vault_name": "my-vault"},
+			expected: "https://my-vault.vault.example.com",
+		},
+		"config-overrides-env": {
+			env:      map[string]string{"AZRANDOM_DNS_SUFFIX": "vault.example.com"},
+			config:   map[string]any{"vault_name": "my-vault", "dns_suffix": "vault.example.org"},
+			expected: "https://my-vault.vault.example.org",
+		},
+		"env-vault-name": {
+			env:      map[string]string{"AZRANDOM_VAULT_NAME": "my-vault"},
+			config:   map[string]any{"dns_suffix": "vault.example.com"},
+			expected: "https://my-vault.vault.example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_DNS_SUFFIX", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			resolved := false
+			for _, entry := range entries {
+				if entry["@message"] != "Resolved Azure Key Vault URL" {
+					continue
+				}
+				resolved = true
+				if entry["azrandom_vault_url"] != testCase.expected {
+					t.Errorf("expected vault URL %q, got %v", testCase.expected, entry["azrandom_vault_url"])
+				}
+			}
+			if !resolved {
+				t.Error("expected the provider to log the URL of the vault")
+			}
+		})
+	}
+}
+
+func TestProviderConfigureVaultUrl(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+		expected    string
+	}{
+		"trailing-slash": {
+			config:   map[string]any{"vault_url": "https://My-Vault.vault.azure.net/"},
+			expected: "https://my-vault.vault.azure.net",
+		},
+		"no-trailing-slash": {
+			config:   map[string]any{"vault_url": "https://my-vault.vault.azure.net"},
+			expected: "https://my-vault.vault.azure.net",
+		},
+		"vault-name": {
+			config:   map[string]any{"vault_name": "my-vault"},
+			expected: "https://my-vault.vault.azure.net",
+		},
+		"missing-scheme": {
+			env:         map[string]string{"AZRANDOM_VAULT_URL": "my-vault.vault.azure.net"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"http": {
+			env:         map[string]string{"AZRANDOM_VAULT_URL": "http://my-vault.vault.azure.net/"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"secret-path": {
+			config:      map[string]any{"vault_url": "https://my-vault.vault.azure.net/secrets/foo"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"query": {
+			config:      map[string]any{"vault_url": "https://my-vault.vault.azure.net/?api-version=7.4"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"wrong-suffix": {
+			config:      map[string]any{"vault_url": "https://my-vault.vault.azure.com/"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"bare-suffix": {
+			config:      map[string]any{"vault_url": "https://vault.azure.net/"},
+			expectError: "Invalid Azrandom Vault Url",
+		},
+		"managed-hsm": {
+			config:      map[string]any{"vault_url": "https://my-pool.managedhsm.azure.net/"},
+			expectError: "Unsupported Azrandom Vault Type",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if testCase.expectError != "" {
+				if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+				}
+				if diagnostics[0].Attribute == nil || diagnostics[0].Attribute.String() != "AttributeName(\"vault_url\")" {
+					t.Errorf("expected the error to be reported on vault_url, got %v", diagnostics[0].Attribute)
+				}
+				return
+			}
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			for _, entry := range entries {
+				if entry["@message"] == "Resolved Azure Key Vault URL" && entry["azrandom_vault_url"] != testCase.expected {
+					t.Errorf("expected vault URL %q, got %v", testCase.expected, entry["azrandom_vault_url"])
+				}
+			}
+		})
+	}
+}
+
+func TestProviderConfigureTenants(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+	}{
+		"tenants": {
+			config: map[string]any{"additionally_allowed_tenants": []string{"00000000-0000-0000-0000-000000000000", "contoso.onmicrosoft.com"}},
+		},
+		"any-tenant": {
+			config: map[string]any{"additionally_allowed_tenants": []string{"*"}},
+		},
+		"tenants-from-env": {
+			env: map[string]string{"AZRANDOM_ADDITIONALLY_ALLOWED_TENANTS": "00000000-0000-0000-0000-000000000000; contoso.onmicrosoft.com"},
+		},
+		"disable-instance-discovery": {
+			config: map[string]any{"disable_instance_discovery": true},
+		},
+		"disable-instance-discovery-from-env": {
+			env: map[string]string{"AZRANDOM_DISABLE_INSTANCE_DISCOVERY": "true"},
+		},
+		"disable-instance-discovery-invalid-env": {
+			env:         map[string]string{"AZRANDOM_DISABLE_INSTANCE_DISCOVERY": "maybe"},
+			expectError: "Error parsing AZRANDOM_DISABLE_INSTANCE_DISCOVERY",
+		},
+		"config-overrides-invalid-env": {
+			env:    map[string]string{"AZRANDOM_DISABLE_INSTANCE_DISCOVERY": "maybe"},
+			config: map[string]any{"disable_instance_discovery": false},
+			// The environment variable is parsed regardless, like the other boolean environment variables
+			expectError: "Error parsing AZRANDOM_DISABLE_INSTANCE_DISCOVERY",
+		},
+		"service-principal-with-tenants": {
+			config: servicePrincipalConfig(map[string]any{
+				"client_secret":                "secret",
+				"additionally_allowed_tenants": []string{"*"},
+				"disable_instance_discovery":   true,
+			}),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors []string
+			for _, diagnostic := range configureProvider(t, config) {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					errors = append(errors, diagnostic.Summary)
+				}
+			}
+
+			if testCase.expectError == "" && len(errors) != 0 {
+				t.Errorf("expected no errors, got %v", errors)
+			}
+			if testCase.expectError != "" && (len(errors) != 1 || errors[0] != testCase.expectError) {
+				t.Errorf("expected error %q, got %v", testCase.expectError, errors)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureAuthMethod(t *testing.T) {
+	testCases := map[string]struct {
+		env          map[string]string
+		config       map[string]any
+		expectError  string
+		expectAtPath *tftypes.AttributePath
+	}{
+		"cli": {
+			config: map[string]any{"auth_method": "cli"},
+		},
+		"from-env": {
+			env: map[string]string{"AZRANDOM_AUTH_METHOD": "developer_cli"},
+		},
+		"invalid-from-env": {
+			env:          map[string]string{"AZRANDOM_AUTH_METHOD": "browser"},
+			expectError:  "Invalid Azrandom Auth Method",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("auth_method"),
+		},
+		"with-disabled-credential-from-env": {
+			env:          map[string]string{"AZRANDOM_DISABLE_CLI_CREDENTIAL": "true"},
+			config:       map[string]any{"auth_method": "managed_identity"},
+			expectError:  "Conflicting Auth Method",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("auth_method"),
+		},
+		"disabled-credentials-without-auth-method": {
+			config: map[string]any{"disable_azure_cli_credential": true},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors []*tfprotov6.Diagnostic
+			for _, diagnostic := range configureProvider(t, config) {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					errors = append(errors, diagnostic)
+				}
+			}
+
+			if testCase.expectError == "" {
+				if len(errors) != 0 {
+					t.Fatalf("expected no errors, got %s: %s", errors[0].Summary, errors[0].Detail)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Summary != testCase.expectError {
+				t.Fatalf("expected error %q, got %v", testCase.expectError, errors)
+			}
+			if !testCase.expectAtPath.Equal(errors[0].Attribute) {
+				t.Errorf("expected error at %s, got %s", testCase.expectAtPath, errors[0].Attribute)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureInteractiveAuth(t *testing.T) {
+	testCases := map[string]struct {
+		env          map[string]string
+		config       map[string]any
+		expectError  string
+		expectAtPath *tftypes.AttributePath
+	}{
+		"interactive-browser": {
+			config: map[string]any{"use_interactive_browser": true},
+		},
+		"device-code-from-env": {
+			env: map[string]string{"AZRANDOM_USE_DEVICE_CODE": "true"},
+		},
+		"disabled-in-automation": {
+			env:    map[string]string{"TF_IN_AUTOMATION": "1"},
+			config: map[string]any{"use_interactive_browser": false},
+		},
+		"interactive-browser-in-automation": {
+			env:          map[string]string{"TF_IN_AUTOMATION": "1"},
+			config:       map[string]any{"use_interactive_browser": true},
+			expectError:  "Interactive Auth in Automation",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("use_interactive_browser"),
+		},
+		"device-code-in-automation": {
+			env:          map[string]string{"TF_IN_AUTOMATION": "1", "AZRANDOM_USE_DEVICE_CODE": "true"},
+			expectError:  "Interactive Auth in Automation",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("use_device_code"),
+		},
+		"both-from-env": {
+			env:          map[string]string{"AZRANDOM_USE_INTERACTIVE_BROWSER": "true", "AZRANDOM_USE_DEVICE_CODE": "true"},
+			expectError:  "Conflicting Azrandom Interactive Auth",
+			expectAtPath: tftypes.NewAttributePath().WithAttributeName("use_device_code"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TF_IN_AUTOMATION", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors []*tfprotov6.Diagnostic
+			for _, diagnostic := range configureProvider(t, config) {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					errors = append(errors, diagnostic)
+				}
+			}
+
+			if testCase.expectError == "" {
+				if len(errors) != 0 {
+					t.Fatalf("expected no errors, got %s: %s", errors[0].Summary, errors[0].Detail)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Summary != testCase.expectError {
+				t.Fatalf("expected error %q, got %v", testCase.expectError, errors)
+			}
+			if !testCase.expectAtPath.Equal(errors[0].Attribute) {
+				t.Errorf("expected error at %s, got %s", testCase.expectAtPath, errors[0].Attribute)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureTokenCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the token cache is stored in the local app data folder on Windows, which does not depend on HOME")
+	}
+
+	testCases := map[string]struct {
+		env           map[string]string
+		config        map[string]any
+		expectError   string
+		expectWarning string
+	}{
+		"unavailable": {
+			config:        map[string]any{"token_cache_name": "azrandom-test"},
+			expectWarning: "Azrandom Token Cache Unavailable",
+		},
+		"unavailable-from-env": {
+			env:           map[string]string{"AZRANDOM_TOKEN_CACHE_NAME": "azrandom-test"},
+			expectWarning: "Azrandom Token Cache Unavailable",
+		},
+		"unavailable-service-principal": {
+			config:        servicePrincipalConfig(map[string]any{"client_secret": "secret", "token_cache_name": "azrandom-test"}),
+			expectWarning: "Azrandom Token Cache Unavailable",
+		},
+		"invalid-name-from-env": {
+			env:         map[string]string{"AZRANDOM_TOKEN_CACHE_NAME": "../azrandom-test"},
+			expectError: "Invalid Azrandom Token Cache Name",
+		},
+		"config-overrides-env": {
+			env:    map[string]string{"AZRANDOM_TOKEN_CACHE_NAME": "../azrandom-test"},
+			config: map[string]any{"token_cache_name": "azrandom-test"},
+			// The name from the configuration is valid, but the cache is still unavailable
+			expectWarning: "Azrandom Token Cache Unavailable",
+		},
+		"not-set": {},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The token cache is stored in the home directory, so it cannot be opened without one
+			t.Setenv("HOME", "")
+			t.Setenv("AZRANDOM_TOKEN_CACHE_NAME", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			var errors, warnings []*tfprotov6.Diagnostic
+			for _, diagnostic := range configureProvider(t, config) {
+				switch diagnostic.Severity {
+				case tfprotov6.DiagnosticSeverityError:
+					errors = append(errors, diagnostic)
+				case tfprotov6.DiagnosticSeverityWarning:
+					warnings = append(warnings, diagnostic)
+				}
+			}
+
+			expectAtPath := tftypes.NewAttributePath().WithAttributeName("token_cache_name")
+			if testCase.expectError != "" {
+				if len(errors) != 1 || errors[0].Summary != testCase.expectError {
+					t.Fatalf("expected error %q, got %v", testCase.expectError, errors)
+				}
+				if !expectAtPath.Equal(errors[0].Attribute) {
+					t.Errorf("expected error at %s, got %s", expectAtPath, errors[0].Attribute)
+				}
+				return
+			}
+			if len(errors) != 0 {
+				t.Fatalf("expected no errors, got %s: %s", errors[0].Summary, errors[0].Detail)
+			}
+
+			if testCase.expectWarning == "" {
+				if len(warnings) != 0 {
+					t.Fatalf("expected no warnings, got %s: %s", warnings[0].Summary, warnings[0].Detail)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Summary != testCase.expectWarning {
+				t.Fatalf("expected warning %q, got %v", testCase.expectWarning, warnings)
+			}
+			if !expectAtPath.Equal(warnings[0].Attribute) {
+				t.Errorf("expected warning at %s, got %s", expectAtPath, warnings[0].Attribute)
+			}
+		})
+	}
+}
+
+// creatingClientField returns the field name of the entry that the provider logged when creating its client.
+func creatingClientField(t *testing.T, entries []map[string]any, name string) map[string]any {
+	t.Helper()
+
+	for _, entry := range entries {
+		if entry["@message"] != "Creating Azrandom client" {
+			continue
+		}
+		logged, ok := entry[name].(map[string]any)
+		if !ok {
+			t.Fatalf("expected %s to be logged, got %v", name, entry)
+		}
+		return logged
+	}
+	t.Fatal("expected the provider to log the creation of its client")
+	return nil
+}
+
+// disabledCredentials returns the credentials of the DefaultAzureCredential chain that the provider logged as
+// disabled when creating its client.
+func disabledCredentials(t *testing.T, entries []map[string]any) map[string]bool {
+	t.Helper()
+
+	disabled := map[string]bool{}
+	for name, value := range creatingClientField(t, entries, "disabled_credentials") {
+		disabled[name] = value == true
+	}
+	return disabled
+}
+
+func TestProviderConfigureDisabledCredentials(t *testing.T) {
+	flags := map[string]struct {
+		attribute string
+		env       string
+	}{
+		"managed_identity":    {attribute: "disable_managed_identity_credential", env: "AZRANDOM_DISABLE_MANAGED_IDENTITY_CREDENTIAL"},
+		"workload_identity":   {attribute: "disable_workload_identity_credential", env: "AZRANDOM_DISABLE_WORKLOAD_IDENTITY_CREDENTIAL"},
+		"azure_cli":           {attribute: "disable_azure_cli_credential", env: "AZRANDOM_DISABLE_CLI_CREDENTIAL"},
+		"azure_developer_cli": {attribute: "disable_azure_developer_cli_credential", env: "AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL"},
+		"environment":         {attribute: "disable_environment_credential", env: "AZRANDOM_DISABLE_ENVIRONMENT_CREDENTIAL"},
+	}
+
+	// Each case sets the environment variable and the attribute of a single flag, nil leaving them unset
+	precedence := map[string]struct {
+		env           string
+		config        any
+		expectDisable bool
+	}{
+		"unset":                      {},
+		"env":                        {env: "true", expectDisable: true},
+		"config":                     {config: true, expectDisable: true},
+		"config-overrides-env":       {env: "true", config: false},
+		"config-overrides-env-false": {env: "false", config: true, expectDisable: true},
+		"env-false":                  {env: "false"},
+	}
+
+	for credential, flag := range flags {
+		for name, testCase := range precedence {
+			t.Run(credential+"/"+name, func(t *testing.T) {
+				for _, other := range flags {
+					t.Setenv(other.env, "")
+				}
+				t.Setenv("AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL", "")
+				t.Setenv(flag.env, testCase.env)
+
+				config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+				if testCase.config != nil {
+					config[flag.attribute] = testCase.config
+				}
+
+				diagnostics, entries := configureProviderWithLogs(t, config)
+				if len(diagnostics) != 0 {
+					t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+				}
+
+				for other, disabled := range disabledCredentials(t, entries) {
+					expectDisabled := other == credential && testCase.expectDisable
+					if disabled != expectDisabled {
+						t.Errorf("expected the %s credential to be disabled to be %t, got %t", other, expectDisabled, disabled)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestProviderConfigureDeprecatedDeveloperCLIEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env           map[string]string
+		expectDisable bool
+		expectWarning bool
+	}{
+		"misspelled": {
+			env:           map[string]string{"AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL": "true"},
+			expectDisable: true,
+			expectWarning: true,
+		},
+		"correctly-spelled": {
+			env:           map[string]string{"AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL": "true"},
+			expectDisable: true,
+		},
+		"correctly-spelled-wins": {
+			env: map[string]string{
+				"AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL":  "true",
+				"AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL": "false",
+			},
+			expectWarning: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_DISABLE_DEVLOPER_CLI_CREDENTIAL", "")
+			t.Setenv("AZRANDOM_DISABLE_DEVELOPER_CLI_CREDENTIAL", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true})
+
+			var warnings []*tfprotov6.Diagnostic
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Severity != tfprotov6.DiagnosticSeverityWarning {
+					t.Fatalf("expected only warnings, got %s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+				warnings = append(warnings, diagnostic)
+			}
+			if testCase.expectWarning != (len(warnings) == 1 && warnings[0].Summary == "Deprecated Azrandom Environment Variable") {
+				t.Errorf("expected a deprecation warning to be %t, got %v", testCase.expectWarning, warnings)
+			}
+
+			if disabled := disabledCredentials(t, entries)["azure_developer_cli"]; disabled != testCase.expectDisable {
+				t.Errorf("expected the developer CLI credential to be disabled to be %t, got %t", testCase.expectDisable, disabled)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureRetry(t *testing.T) {
+	retryEnv := []string{"AZRANDOM_MAX_RETRIES", "AZRANDOM_RETRY_DELAY", "AZRANDOM_MAX_RETRY_DELAY", "AZRANDOM_TRY_TIMEOUT"}
+
+	testCases := map[string]struct {
+		env         map[string]string
+		retry       map[string]any
+		expected    map[string]any
+		expectError string
+	}{
+		"defaults": {
+			expected: map[string]any{"max_retries": 0.0, "retry_delay": "0s", "max_retry_delay": "0s", "try_timeout": "0s"},
+		},
+		"config": {
+			retry:    map[string]any{"max_retries": 10, "retry_delay": "2s", "max_retry_delay": "2m", "try_timeout": "30s"},
+			expected: map[string]any{"max_retries": 10.0, "retry_delay": "2s", "max_retry_delay": "2m0s", "try_timeout": "30s"},
+		},
+		"env": {
+			env: map[string]string{
+				"AZRANDOM_MAX_RETRIES":     "7",
+				"AZRANDOM_RETRY_DELAY":     "1s",
+				"AZRANDOM_MAX_RETRY_DELAY": "90s",
+				"AZRANDOM_TRY_TIMEOUT":     "1m",
+			},
+			expected: map[string]any{"max_retries": 7.0, "retry_delay": "1s", "max_retry_delay": "1m30s", "try_timeout": "1m0s"},
+		},
+		"config-overrides-env": {
+			env:      map[string]string{"AZRANDOM_MAX_RETRIES": "7", "AZRANDOM_RETRY_DELAY": "1s"},
+			retry:    map[string]any{"max_retries": 2, "retry_delay": "5s"},
+			expected: map[string]any{"max_retries": 2.0, "retry_delay": "5s", "max_retry_delay": "0s", "try_timeout": "0s"},
+		},
+		"no-retries": {
+			// azcore reads zero as its default, so disabling retries is passed on as a negative number
+			retry:    map[string]any{"max_retries": 0},
+			expected: map[string]any{"max_retries": -1.0, "retry_delay": "0s", "max_retry_delay": "0s", "try_timeout": "0s"},
+		},
+		"negative-max-retries-env": {
+			env:         map[string]string{"AZRANDOM_MAX_RETRIES": "-1"},
+			expectError: "Error parsing AZRANDOM_MAX_RETRIES",
+		},
+		"invalid-max-retries-env": {
+			env:         map[string]string{"AZRANDOM_MAX_RETRIES": "many"},
+			expectError: "Error parsing AZRANDOM_MAX_RETRIES",
+		},
+		"negative-delay-env": {
+			env:         map[string]string{"AZRANDOM_RETRY_DELAY": "-5s"},
+			expectError: "Invalid Azrandom Retry Policy",
+		},
+		"invalid-timeout-env": {
+			env:         map[string]string{"AZRANDOM_TRY_TIMEOUT": "soon"},
+			expectError: "Invalid Azrandom Retry Policy",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, env := range retryEnv {
+				t.Setenv(env, "")
+			}
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			if testCase.retry != nil {
+				config["retry"] = testCase.retry
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if testCase.expectError != "" {
+				if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			if retry := creatingClientField(t, entries, "retry"); !reflect.DeepEqual(retry, testCase.expected) {
+				t.Errorf("expected retry policy %v, got %v", testCase.expected, retry)
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigRetry(t *testing.T) {
+	testCases := map[string]map[string]any{
+		"negative-max-retries": {"max_retries": -1},
+		"negative-retry-delay": {"retry_delay": "-1s"},
+		"zero-max-retry-delay": {"max_retry_delay": "0s"},
+		"invalid-try-timeout":  {"try_timeout": "soon"},
+	}
+
+	for name, retry := range testCases {
+		t.Run(name, func(t *testing.T) {
+			errors := validateProviderConfig(t, map[string]any{"vault_url": testVaultUrl, "retry": retry})
+			if !reflect.DeepEqual(errors, []string{"Invalid Attribute Value"}) {
+				t.Errorf("expected an invalid attribute value, got %v", errors)
+			}
+		})
+	}
+}
+
+// TestAccProviderDisableEnvironmentCredential disables only the environment credential, which used to disable
+// the developer CLI credential instead, and authenticates with the Azure CLI.
+func TestAccProviderDisableEnvironmentCredential(t *testing.T) {
+	t.Parallel()
+	testAccSkipEmulator(t)
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`provider "azrandom" {
+							vault_url                      = %[1]q
+							disable_environment_credential = true
+						}
+
+						resource "azrandom_uuid" "this" {
+							name = %[2]q
+						}`, testVaultUrl, protocol.testName("disable-environment-credential-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+			},
+		}
+	})
+}
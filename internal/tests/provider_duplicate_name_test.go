@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccProviderDuplicateSecretName(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}
+						resource "azrandom_string" "this" { 
+							name = %[1]q
+							length = 8
+						}`, protocol.testName("duplicate-name-test")),
+					ExpectError: regexp.MustCompile("Duplicate secret name"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							count = 2
+							name = %[1]q
+						}`, protocol.testName("duplicate-name-test")),
+					ExpectError: regexp.MustCompile("Duplicate secret name"),
+				},
+			},
+		}
+	})
+}
+
+// Replacing a resource plans it twice, once with its prior state and once without, which does not make it a
+// duplicate of itself.
+func TestAccProviderReplaceSecretName(t *testing.T) {
+	t.Parallel()
+
+	config := func(name string) string {
+		return providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, name)
+	}
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config(protocol.testName("replace-name-test")),
+				},
+				{
+					Config: config(protocol.testName("replaced-name-test")),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_uuid.this", plancheck.ResourceActionReplace),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+// The second plan of a replacement carries the private state of the first, so the resource claims the secret
+// name again as its own, while another resource claiming it is still a duplicate.
+func TestProviderDuplicateSecretNameReplace(t *testing.T) {
+	testCases := map[string]struct {
+		typeName   string
+		priorState map[string]any
+		config     map[string]any
+	}{
+		"uuid-renamed": {
+			typeName:   "azrandom_uuid",
+			priorState: map[string]any{"name": "replace-name-test", "version": "1"},
+			config:     map[string]any{"name": "replaced-name-test"},
+		},
+		"uuid-same-name": {
+			typeName:   "azrandom_uuid",
+			priorState: map[string]any{"name": "replace-name-test", "version": "1"},
+			config:     map[string]any{"name": "replace-name-test"},
+		},
+	}
+
+	duplicates := func(resp *tfprotov6.PlanResourceChangeResponse) []string {
+		var summaries []string
+		for _, diagnostic := range resp.Diagnostics {
+			if diagnostic.Summary == "Duplicate secret name" {
+				summaries = append(summaries, diagnostic.Detail)
+			}
+		}
+		return summaries
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := configuredServer(t, vaultUrlProviderConfig)
+
+			planned := planResourceChangeWith(t, server, testCase.typeName, testCase.priorState, testCase.config)
+			if errors := planErrors(planned); len(errors) != 0 {
+				t.Fatalf("unexpected errors planning with the prior state: %v", errors)
+			}
+
+			replaced := planResourceChangePrivate(t, server, testCase.typeName, nil, planned.PlannedPrivate, testCase.config)
+			if errors := planErrors(replaced); len(errors) != 0 {
+				t.Fatalf("unexpected errors planning the replacement: %v", errors)
+			}
+
+			other := planResourceChangeWith(t, server, testCase.typeName, nil, testCase.config)
+			if summaries := duplicates(other); len(summaries) != 1 {
+				t.Errorf("expected another resource to be a duplicate, got %v", summaries)
+			}
+		})
+	}
+}
+
+// The error names the attribute and the type of both the resource being planned and the one that claimed the
+// secret name first.
+func TestProviderDuplicateSecretNameDetail(t *testing.T) {
+	server := configuredServer(t, vaultUrlProviderConfig)
+
+	claimed := planResourceChangeWith(t, server, "azrandom_uuid", nil, map[string]any{"name": "duplicate-name-test"})
+	if errors := planErrors(claimed); len(errors) != 0 {
+		t.Fatalf("unexpected errors planning the first resource: %v", errors)
+	}
+
+	planned := planResourceChangeWith(t, server, "azrandom_string", nil, map[string]any{
+		"name":   "duplicate-name-test",
+		"length": 8,
+	})
+	var details []string
+	for _, diagnostic := range planned.Diagnostics {
+		if diagnostic.Summary == "Duplicate secret name" {
+			details = append(details, diagnostic.Detail)
+		}
+	}
+	if len(details) != 1 {
+		t.Fatalf("expected one duplicate secret name, got %v", details)
+	}
+	for _, expected := range []string{"The name of this azrandom_string", "the name of a azrandom_uuid"} {
+		if !strings.Contains(details[0], expected) {
+			t.Errorf("expected the error to contain %q, got %q", expected, details[0])
+		}
+	}
+}
@@ -4,20 +4,33 @@
 package tests
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
 	provider "terraform-provider-azrandom/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 const (
-	// providerConfig is a shared configuration to combine with the actual
-	// test configuration so the Azrandom client is properly configured.
-	// It is also possible to use the HASHICUPS_ environment variables instead,
-	// such as updating the Makefile and running the testing through that tool.
-	providerConfig = `
+	// testVaultUrl is the vault that the acceptance tests store their secrets in.
+	testVaultUrl = "https://localdev-remote-bxnwi8xn.vault.azure.net/"
+
+	// testVaultProviderConfig configures the provider with testVaultUrl, authenticating with the Azure CLI.
+	testVaultProviderConfig = `
 provider "azrandom" {
-	vault_url 							   = "https://localdev-remote-bxnwi8xn.vault.azure.net/"
+	vault_url 							   = "` + testVaultUrl + `"
 	disable_managed_identity_credential    = true
 	disable_workload_identity_credential   = true
 	disable_azure_cli_credential           = false
@@ -25,8 +38,110 @@ provider "azrandom" {
 	disable_environment_credential         = true
 }
 `
+
+	// testEmulatorEnvVar runs the acceptance tests against the emulator of Key Vault at the given URL, such as
+	// https://localhost:8443 for Lowkey Vault, instead of testVaultUrl.
+	testEmulatorEnvVar = "AZRANDOM_TEST_EMULATOR_URL"
+
+	// testNamePrefixRoot starts the name of every secret created by the acceptance tests, so that the
+	// sweeper can find secrets left behind by any run.
+	testNamePrefixRoot = "acctest-"
+
+	// testNamePrefixEnvVar overrides the prefix of the names of the secrets created by the acceptance tests.
+	testNamePrefixEnvVar = "AZRANDOM_TEST_NAME_PREFIX"
 )
 
+// testNamePrefix is prepended to the name of every secret created by the acceptance tests. It is unique
+// per test run, so that tests may run in parallel, and so that runs sharing a vault do not collide.
+var testNamePrefix = func() string {
+	if prefix := os.Getenv(testNamePrefixEnvVar); prefix != "" {
+		return prefix
+	}
+	return testNamePrefixRoot + acctest.RandString(8) + "-"
+}()
+
+// testEmulatorUrl is the URL of the emulator that the acceptance tests run against, if any.
+var testEmulatorUrl = os.Getenv(testEmulatorEnvVar)
+
+// providerConfig is a shared configuration to combine with the actual test configuration so the Azrandom client
+// is properly configured. It configures the emulator at testEmulatorUrl when it is set, which accepts any token
+// and serves a self-signed certificate.
+var providerConfig = func() string {
+	if testEmulatorUrl == "" {
+		return testVaultProviderConfig
+	}
+	return `
+provider "azrandom" {
+	custom_endpoint      = "` + testEmulatorUrl + `"
+	insecure_skip_verify = true
+	use_fake_credential  = true
+	allow_insecure       = true
+}
+`
+}()
+
+// testAccSkipEmulator skips acceptance tests that configure the provider for a vault in Azure themselves when
+// they run against an emulator.
+func testAccSkipEmulator(t *testing.T) {
+	t.Helper()
+
+	if testEmulatorUrl != "" {
+		t.Skipf("configures the provider for a vault in Azure, but %s is set", testEmulatorEnvVar)
+	}
+}
+
+// testProtocol is a version of the plugin protocol that the acceptance tests serve the provider over.
+type testProtocol int
+
+const (
+	testProtocol6 testProtocol = 6
+	testProtocol5 testProtocol = 5
+)
+
+// testProtocols are the protocol versions that testAccUnitTest runs every test case against.
+var testProtocols = []testProtocol{testProtocol6, testProtocol5}
+
+// String returns the name of the subtests of the protocol version.
+func (p testProtocol) String() string {
+	return fmt.Sprintf("protocol%d", int(p))
+}
+
+// testName returns the name of the secret a test uses for name over protocol version p. It is prefixed with
+// testNamePrefix and the protocol version, as a secret destroyed by the test over one protocol version stays
+// soft-deleted, and would conflict with the test over the other one.
+func (p testProtocol) testName(name string) string {
+	return fmt.Sprintf("%sv%d-%s", testNamePrefix, int(p), name)
+}
+
+// testAccUnitTest runs the test case returned by testCase against the provider served over each of
+// testProtocols in turn, in a subtest named after the protocol version, setting the provider factories of the
+// test case.
+func testAccUnitTest(t *testing.T, testCase func(t *testing.T, protocol testProtocol) resource.TestCase) {
+	t.Helper()
+
+	for _, protocol := range testProtocols {
+		t.Run(protocol.String(), func(t *testing.T) {
+			c := testCase(t, protocol)
+			switch protocol {
+			case testProtocol5:
+				c.ProtoV5ProviderFactories = testAccProtoV5ProviderFactories
+			default:
+				c.ProtoV6ProviderFactories = testAccProtoV6ProviderFactories
+			}
+			resource.UnitTest(t, c)
+		})
+	}
+}
+
+// testAccSkipProtocol5 skips acceptance tests of resources that need protocol version 6 when protocol is 5.
+func testAccSkipProtocol5(t *testing.T, protocol testProtocol) {
+	t.Helper()
+
+	if protocol == testProtocol5 {
+		t.Skip("uses resources that need protocol version 6")
+	}
+}
+
 var (
 	// testAccProtoV6ProviderFactories are used to instantiate a provider during
 	// acceptance testing. The factory function will be invoked for every Terraform
@@ -35,4 +150,436 @@ var (
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 		"azrandom": providerserver.NewProtocol6WithError(provider.New("test")()),
 	}
+
+	// testAccProtoV5ProviderFactories are testAccProtoV6ProviderFactories for the provider served over protocol
+	// version 5.
+	testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+		"azrandom": providerserver.NewProtocol5WithError(provider.NewProtocol5("test")()),
+	}
 )
+
+// upgradeResourceState runs the given prior state through the provider's UpgradeResourceState
+// and returns the upgraded state as a map of attribute values.
+func upgradeResourceState(t *testing.T, typeName string, version int64, priorState map[string]any) map[string]any {
+	t.Helper()
+
+	ctx := context.Background()
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	rawState, err := json.Marshal(priorState)
+	if err != nil {
+		t.Fatalf("unable to marshal prior state: %s", err)
+	}
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	resourceType := schemaResp.ResourceSchemas[typeName].ValueType()
+
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: rawState},
+	})
+	if err != nil {
+		t.Fatalf("unable to upgrade resource state: %s", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error upgrading resource state: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	upgradedValue, err := resp.UpgradedState.Unmarshal(resourceType)
+	if err != nil {
+		t.Fatalf("unable to unmarshal upgraded state: %s", err)
+	}
+
+	return tfValueToMap(t, upgradedValue)
+}
+
+// unknownValue represents values that are unknown in the results of tfValueToAny.
+const unknownValue = "(known after apply)"
+
+// planResourceChange plans the change from priorState, which is nil when the resource is created,
+// to config and returns the planned state as a map of attribute values. Like Terraform, computed
+// attributes that are not configured are proposed to keep their prior value.
+func planResourceChange(t *testing.T, typeName string, priorState map[string]any, config map[string]any) map[string]any {
+	t.Helper()
+
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	resp := planResourceChangeWith(t, server, typeName, priorState, config)
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error planning resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	return plannedStateMap(t, server, typeName, resp)
+}
+
+// planResourceChangeWith plans the change from priorState to config like planResourceChange, using
+// server, which may have been configured, and returns the response as is.
+func planResourceChangeWith(t *testing.T, server tfprotov6.ProviderServer, typeName string, priorState map[string]any, config map[string]any) *tfprotov6.PlanResourceChangeResponse {
+	t.Helper()
+
+	return planResourceChangePrivate(t, server, typeName, priorState, nil, config)
+}
+
+// planResourceChangePrivate is planResourceChangeWith for a resource whose private state, as returned by
+// its last apply or read, is priorPrivate.
+func planResourceChangePrivate(t *testing.T, server tfprotov6.ProviderServer, typeName string, priorState map[string]any, priorPrivate []byte, config map[string]any) *tfprotov6.PlanResourceChangeResponse {
+	t.Helper()
+
+	ctx := context.Background()
+
+	resourceSchema := resourceSchema(t, server, typeName)
+	resourceType := resourceSchema.ValueType()
+
+	fromMap := func(m map[string]any) tftypes.Value {
+		if m == nil {
+			return tftypes.NewValue(resourceType, nil)
+		}
+		raw, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("unable to marshal value: %s", err)
+		}
+		value, err := (&tfprotov6.RawState{JSON: raw}).Unmarshal(resourceType)
+		if err != nil {
+			t.Fatalf("unable to unmarshal value: %s", err)
+		}
+		return value
+	}
+	toDynamicValue := func(value tftypes.Value) *tfprotov6.DynamicValue {
+		dynamicValue, err := tfprotov6.NewDynamicValue(resourceType, value)
+		if err != nil {
+			t.Fatalf("unable to create dynamic value: %s", err)
+		}
+		return &dynamicValue
+	}
+
+	proposed := map[string]any{}
+	for _, attribute := range resourceSchema.Block.Attributes {
+		if value, ok := config[attribute.Name]; ok {
+			proposed[attribute.Name] = value
+		} else if attribute.Computed && priorState != nil {
+			proposed[attribute.Name] = priorState[attribute.Name]
+		}
+	}
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       toDynamicValue(fromMap(priorState)),
+		ProposedNewState: toDynamicValue(fromMap(proposed)),
+		Config:           toDynamicValue(fromMap(config)),
+		PriorPrivate:     priorPrivate,
+	})
+	if err != nil {
+		t.Fatalf("unable to plan resource change: %s", err)
+	}
+	return resp
+}
+
+// plannedStateMap returns the planned state of a response of planResourceChangeWith as a map of attribute values.
+func plannedStateMap(t *testing.T, server tfprotov6.ProviderServer, typeName string, resp *tfprotov6.PlanResourceChangeResponse) map[string]any {
+	t.Helper()
+
+	plannedValue, err := resp.PlannedState.Unmarshal(resourceSchema(t, server, typeName).ValueType())
+	if err != nil {
+		t.Fatalf("unable to unmarshal planned state: %s", err)
+	}
+
+	return tfValueToMap(t, plannedValue)
+}
+
+// applyResourceChange plans the change from priorState, which is nil when the resource is created, to config
+// like planResourceChangeWith, and applies it using server. It returns the response of the apply as is.
+func applyResourceChange(t *testing.T, server tfprotov6.ProviderServer, typeName string, priorState map[string]any, config map[string]any) *tfprotov6.ApplyResourceChangeResponse {
+	t.Helper()
+
+	return applyResourceChangePrivate(t, server, typeName, priorState, nil, config)
+}
+
+// applyResourceChangePrivate is applyResourceChange for a resource whose private state, as returned by its
+// last apply or read, is priorPrivate.
+func applyResourceChangePrivate(t *testing.T, server tfprotov6.ProviderServer, typeName string, priorState map[string]any, priorPrivate []byte, config map[string]any) *tfprotov6.ApplyResourceChangeResponse {
+	t.Helper()
+
+	planResp := planResourceChangePrivate(t, server, typeName, priorState, priorPrivate, config)
+	for _, diagnostic := range planResp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error planning resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     resourceDynamicValue(t, server, typeName, priorState),
+		PlannedState:   planResp.PlannedState,
+		Config:         resourceDynamicValue(t, server, typeName, config),
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		t.Fatalf("unable to apply resource change: %s", err)
+	}
+	return resp
+}
+
+// applySucceeded fails the test when resp has errors, and returns it otherwise.
+func applySucceeded(t *testing.T, resp *tfprotov6.ApplyResourceChangeResponse) *tfprotov6.ApplyResourceChangeResponse {
+	t.Helper()
+
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error applying resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	return resp
+}
+
+// readResource refreshes state, a state of a resource of type typeName, using server.
+func readResource(t *testing.T, server tfprotov6.ProviderServer, typeName string, state *tfprotov6.DynamicValue) *tfprotov6.ReadResourceResponse {
+	t.Helper()
+
+	return readResourcePrivate(t, server, typeName, state, nil)
+}
+
+// readResourcePrivate is readResource for a resource whose private state, as returned by its last apply or
+// read, is private.
+func readResourcePrivate(t *testing.T, server tfprotov6.ProviderServer, typeName string, state *tfprotov6.DynamicValue, private []byte) *tfprotov6.ReadResourceResponse {
+	t.Helper()
+
+	resp, err := server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: state,
+		Private:      private,
+	})
+	if err != nil {
+		t.Fatalf("unable to read resource: %s", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error reading resource: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	return resp
+}
+
+// resourceDynamicValue returns m, a map of attribute values of a resource of type typeName, as a dynamic value,
+// which is null when m is nil.
+func resourceDynamicValue(t *testing.T, server tfprotov6.ProviderServer, typeName string, m map[string]any) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	resourceType := resourceSchema(t, server, typeName).ValueType()
+
+	value := tftypes.NewValue(resourceType, nil)
+	if m != nil {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("unable to marshal value: %s", err)
+		}
+		if value, err = (&tfprotov6.RawState{JSON: raw}).Unmarshal(resourceType); err != nil {
+			t.Fatalf("unable to unmarshal value: %s", err)
+		}
+	}
+	dynamicValue, err := tfprotov6.NewDynamicValue(resourceType, value)
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+	return &dynamicValue
+}
+
+// stateMap returns state, the state of a resource of type typeName returned by an apply or a read, as a
+// map of attribute values, which is nil when the resource was removed.
+func stateMap(t *testing.T, server tfprotov6.ProviderServer, typeName string, state *tfprotov6.DynamicValue) map[string]any {
+	t.Helper()
+
+	value, err := state.Unmarshal(resourceSchema(t, server, typeName).ValueType())
+	if err != nil {
+		t.Fatalf("unable to unmarshal state: %s", err)
+	}
+	if value.IsNull() {
+		return nil
+	}
+	return tfValueToMap(t, value)
+}
+
+// resourceSchema returns the schema of the resource typeName.
+func resourceSchema(t *testing.T, server tfprotov6.ProviderServer, typeName string) *tfprotov6.Schema {
+	t.Helper()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	return schemaResp.ResourceSchemas[typeName]
+}
+
+// configuredServer returns a provider server configured with config, which fails the test when the
+// provider cannot be configured.
+func configuredServer(t *testing.T, config map[string]any) tfprotov6.ProviderServer {
+	t.Helper()
+
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	resp, err := server.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.5.0",
+		Config:           providerConfigValue(t, server, config),
+	})
+	if err != nil {
+		t.Fatalf("unable to configure provider: %s", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error configuring provider: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	return server
+}
+
+// providerConfigValue returns the given provider configuration as a value of the provider schema.
+func providerConfigValue(t *testing.T, server tfprotov6.ProviderServer, config map[string]any) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	providerType := schemaResp.Provider.ValueType()
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("unable to marshal value: %s", err)
+	}
+	value, err := (&tfprotov6.RawState{JSON: raw}).Unmarshal(providerType)
+	if err != nil {
+		t.Fatalf("unable to unmarshal value: %s", err)
+	}
+	dynamicValue, err := tfprotov6.NewDynamicValue(providerType, value)
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+	return &dynamicValue
+}
+
+// validateProviderConfig runs the given provider configuration through the provider's ValidateProviderConfig
+// and returns the summaries of the errors.
+func validateProviderConfig(t *testing.T, config map[string]any) []string {
+	t.Helper()
+
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	resp, err := server.ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+		Config: providerConfigValue(t, server, config),
+	})
+	if err != nil {
+		t.Fatalf("unable to validate provider config: %s", err)
+	}
+
+	var errors []string
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			errors = append(errors, diagnostic.Summary)
+		}
+	}
+	return errors
+}
+
+// configureProvider runs the given provider configuration through the provider's ConfigureProvider and
+// returns its diagnostics. The configuration should set skip_credential_validation unless the test expects a
+// token to be requested.
+func configureProvider(t *testing.T, config map[string]any) []*tfprotov6.Diagnostic {
+	t.Helper()
+
+	diagnostics, _ := configureProviderWithLogs(t, config)
+	return diagnostics
+}
+
+// configureProviderWithLogs configures the provider like configureProvider, also returning the entries
+// the provider logged while doing so.
+func configureProviderWithLogs(t *testing.T, config map[string]any) ([]*tfprotov6.Diagnostic, []map[string]any) {
+	t.Helper()
+
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	var output bytes.Buffer
+	resp, err := server.ConfigureProvider(tflogtest.RootLogger(context.Background(), &output), &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.5.0",
+		Config:           providerConfigValue(t, server, config),
+	})
+	if err != nil {
+		t.Fatalf("unable to configure provider: %s", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode provider logs: %s", err)
+	}
+	return resp.Diagnostics, entries
+}
+
+// tfValueToMap converts an object value into a map of plain Go values, as they would appear in JSON.
+func tfValueToMap(t *testing.T, value tftypes.Value) map[string]any {
+	t.Helper()
+
+	var attributes map[string]tftypes.Value
+	if err := value.As(&attributes); err != nil {
+		t.Fatalf("unable to convert value: %s", err)
+	}
+
+	result := map[string]any{}
+	for name, attribute := range attributes {
+		result[name] = tfValueToAny(t, attribute)
+	}
+	return result
+}
+
+func tfValueToAny(t *testing.T, value tftypes.Value) any {
+	t.Helper()
+
+	if !value.IsKnown() {
+		return unknownValue
+	}
+	if value.IsNull() {
+		return nil
+	}
+
+	switch {
+	case value.Type().Is(tftypes.String):
+		var s string
+		_ = value.As(&s)
+		return s
+	case value.Type().Is(tftypes.Bool):
+		var b bool
+		_ = value.As(&b)
+		return b
+	case value.Type().Is(tftypes.Number):
+		n := new(big.Float)
+		_ = value.As(&n)
+		f, _ := n.Float64()
+		return f
+	case value.Type().Is(tftypes.Object{}):
+		return tfValueToMap(t, value)
+	case value.Type().Is(tftypes.Set{}) || value.Type().Is(tftypes.List{}):
+		var elements []tftypes.Value
+		_ = value.As(&elements)
+		result := []any{}
+		for _, v := range elements {
+			result = append(result, tfValueToAny(t, v))
+		}
+		return result
+	case value.Type().Is(tftypes.Map{}):
+		var elements map[string]tftypes.Value
+		_ = value.As(&elements)
+		result := map[string]any{}
+		for k, v := range elements {
+			result[k] = tfValueToAny(t, v)
+		}
+		return result
+	default:
+		t.Fatalf("unsupported value type %s", value.Type())
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProviderValidateConfig(t *testing.T) {
+	allDisabled := map[string]any{
+		"vault_url":                              testVaultUrl,
+		"disable_environment_credential":         true,
+		"disable_workload_identity_credential":   true,
+		"disable_managed_identity_credential":    true,
+		"disable_azure_cli_credential":           true,
+		"disable_azure_developer_cli_credential": true,
+	}
+	with := func(name string, value any) map[string]any {
+		config := map[string]any{}
+		for k, v := range allDisabled {
+			config[k] = v
+		}
+		config[name] = value
+		return config
+	}
+
+	testCases := map[string]struct {
+		config   map[string]any
+		expected []string
+	}{
+		"all-credentials-disabled": {
+			config:   allDisabled,
+			expected: []string{"No Credential Enabled"},
+		},
+		"only-cli-enabled": {
+			config: with("disable_azure_cli_credential", false),
+		},
+		"only-managed-identity-enabled": {
+			config: with("disable_managed_identity_credential", false),
+		},
+		"one-credential-unset": {
+			// The credential may still be disabled by its environment variable, but may also be enabled
+			config: with("disable_environment_credential", nil),
+		},
+		"no-credential-settings": {
+			config: map[string]any{"vault_url": testVaultUrl},
+		},
+		"service-principal": {
+			config: map[string]any{"vault_url": testVaultUrl, "tenant_id": "tenant", "client_id": "client", "client_secret": "secret"},
+		},
+		"service-principal-with-disabled-credential": {
+			config: map[string]any{
+				"vault_url":                    testVaultUrl,
+				"tenant_id":                    "tenant",
+				"client_id":                    "client",
+				"client_secret":                "secret",
+				"disable_azure_cli_credential": true,
+			},
+			expected: []string{"Conflicting Credential Settings"},
+		},
+		"service-principal-with-all-disabled": {
+			config:   with("client_secret", "secret"),
+			expected: []string{"Conflicting Credential Settings"},
+		},
+		"http-vault-url": {
+			config:   map[string]any{"vault_url": "http://my-vault.vault.azure.net/"},
+			expected: []string{"Invalid Attribute Value"},
+		},
+		"vault-url-and-dns-suffix": {
+			config:   map[string]any{"vault_url": testVaultUrl, "dns_suffix": "vault.example.com"},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+		"invalid-dns-suffix": {
+			config:   map[string]any{"vault_name": "my-vault", "dns_suffix": "https://vault.example.com"},
+			expected: []string{"Invalid Attribute Value"},
+		},
+		"client-secret-and-certificate": {
+			config: map[string]any{
+				"vault_url":               testVaultUrl,
+				"tenant_id":               "tenant",
+				"client_id":               "client",
+				"client_secret":           "secret",
+				"client_certificate_path": "testdata/client_certificate.pem",
+			},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+		"managed-identity-client-and-resource-id": {
+			config: map[string]any{
+				"vault_url":                    testVaultUrl,
+				"managed_identity_client_id":   "client",
+				"managed_identity_resource_id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id",
+			},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+		"empty-additionally-allowed-tenant": {
+			config:   map[string]any{"vault_url": testVaultUrl, "additionally_allowed_tenants": []string{""}},
+			expected: []string{"Invalid Attribute Value Length"},
+		},
+		"auth-method": {
+			config: map[string]any{"vault_url": testVaultUrl, "auth_method": "cli"},
+		},
+		"auth-method-invalid": {
+			config:   map[string]any{"vault_url": testVaultUrl, "auth_method": "browser"},
+			expected: []string{"Invalid Attribute Value Match"},
+		},
+		"auth-method-with-disabled-credential": {
+			config:   map[string]any{"vault_url": testVaultUrl, "auth_method": "cli", "disable_environment_credential": false},
+			expected: []string{"Conflicting Auth Method"},
+		},
+		"auth-method-with-service-principal": {
+			config:   map[string]any{"vault_url": testVaultUrl, "auth_method": "default_chain", "client_id": "client"},
+			expected: []string{"Conflicting Auth Method"},
+		},
+		"interactive-browser-and-device-code": {
+			config:   map[string]any{"vault_url": testVaultUrl, "use_interactive_browser": true, "use_device_code": true},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+		"vault-url-and-name": {
+			config:   map[string]any{"vault_url": testVaultUrl, "vault_name": "my-vault"},
+			expected: []string{"Invalid Attribute Combination"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			errors := validateProviderConfig(t, testCase.config)
+			if !reflect.DeepEqual(errors, testCase.expected) {
+				t.Errorf("expected errors %v, got %v", testCase.expected, errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestPurgeSecret(t *testing.T) {
+	errorBody := func(code string, message string) string {
+		return `{"error": {"code": "` + code + `", "message": "` + message + `"}}`
+	}
+
+	testCases := map[string]struct {
+		// pending is the number of lookups of the deleted secret before its deletion completes
+		pending        int32
+		getDeleted     int
+		purge          int
+		expectPurges   int32
+		expectProtect  bool
+		expectError    bool
+		expectLookups  int32
+		expectNotFound bool
+	}{
+		"purged": {
+			pending:       2,
+			getDeleted:    http.StatusOK,
+			purge:         http.StatusNoContent,
+			expectPurges:  1,
+			expectLookups: 3,
+		},
+		"purge-protected": {
+			getDeleted:    http.StatusOK,
+			purge:         http.StatusForbidden,
+			expectPurges:  1,
+			expectProtect: true,
+			expectError:   true,
+			expectLookups: 1,
+		},
+		"lookup-forbidden": {
+			getDeleted:    http.StatusForbidden,
+			expectError:   true,
+			expectLookups: 1,
+		},
+		"never-deleted": {
+			pending:        1000,
+			expectError:    true,
+			expectNotFound: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var lookups, purges atomic.Int32
+			transport := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				switch req.Method {
+				case http.MethodGet:
+					if lookups.Add(1) <= testCase.pending {
+						return fakeResponse(req, http.StatusNotFound, nil, errorBody("SecretNotFound", "fake")), nil
+					}
+					if testCase.getDeleted != http.StatusOK {
+						return fakeResponse(req, testCase.getDeleted, nil, errorBody("Forbidden", "fake")), nil
+					}
+					return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "")), nil
+				case http.MethodDelete:
+					purges.Add(1)
+					if testCase.purge == http.StatusForbidden {
+						return fakeResponse(req, http.StatusForbidden, nil, errorBody("Forbidden",
+							`Operation \"purge\" is not allowed because purge protection is enabled for this vault.`)), nil
+					}
+					return fakeResponse(req, testCase.purge, nil, ""), nil
+				default:
+					return fakeResponse(req, http.StatusMethodNotAllowed, nil, errorBody("BadRequest", "fake")), nil
+				}
+			}}
+
+			backoff := azrandom.Backoff{
+				InitialInterval: 10 * time.Millisecond,
+				MaxInterval:     10 * time.Millisecond,
+				Multiplier:      2,
+				MaxElapsedTime:  200 * time.Millisecond,
+			}
+
+			err := azrandom.PurgeSecretWithBackoff(context.Background(), newFakeClient(t, transport), "test", backoff)
+			if testCase.expectError != (err != nil) {
+				t.Fatalf("expected error to be %t, got %v", testCase.expectError, err)
+			}
+			if errors.Is(err, azrandom.ErrPurgeProtected) != testCase.expectProtect {
+				t.Errorf("expected purge protection to be %t, got %v", testCase.expectProtect, err)
+			}
+			if azrandom.IsNotFound(err) != testCase.expectNotFound {
+				t.Errorf("expected not found to be %t, got %v", testCase.expectNotFound, err)
+			}
+			if purges.Load() != testCase.expectPurges {
+				t.Errorf("expected %d purges, got %d", testCase.expectPurges, purges.Load())
+			}
+			if testCase.expectLookups != 0 && lookups.Load() != testCase.expectLookups {
+				t.Errorf("expected %d lookups of the deleted secret, got %d", testCase.expectLookups, lookups.Load())
+			}
+		})
+	}
+}
+
+// testAccCheckSecretsPurged checks that the given secrets were neither left behind, nor kept as deleted secrets.
+func testAccCheckSecretsPurged(names ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		client, err := newTestClient()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if _, err := client.GetDeletedSecret(context.Background(), name, nil); !azrandom.IsNotFound(err) {
+				return fmt.Errorf("expected deleted secret %s to be purged, got %v", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckSecretsDeleted checks that the deletion of the given secrets has completed, so that they show
+// up as deleted secrets.
+func testAccCheckSecretsDeleted(names ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		client, err := newTestClient()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if _, err := client.GetDeletedSecret(context.Background(), name, nil); err != nil {
+				return fmt.Errorf("expected secret %s to show up as deleted, got %v", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccRemoveSecret deletes and purges the given secret outside of Terraform, like an operator cleaning
+// up the vault by hand.
+func testAccRemoveSecret(t *testing.T, name string) func() {
+	return func() {
+		client, err := newTestClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		if _, err := azrandom.DeleteSecret(ctx, client, name); err != nil {
+			t.Fatal(err)
+		}
+		if err := azrandom.PurgeSecret(ctx, client, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAccResourceUUIDPurgeOnDestroy(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			CheckDestroy: testAccCheckSecretsPurged(protocol.testName("uuid-purge-on-destroy-test")),
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							purge_on_destroy = true
+						}`, protocol.testName("uuid-purge-on-destroy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "purge_on_destroy", "true"),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDWaitForDeletion(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			CheckDestroy: testAccCheckSecretsDeleted(protocol.testName("uuid-wait-for-deletion-test")),
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							wait_for_deletion = true
+						}`, protocol.testName("uuid-wait-for-deletion-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "wait_for_deletion", "true"),
+					),
+				},
+			},
+		}
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceBip39Mnemonic(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_bip39_mnemonic" "this" { 
+							name = %[1]q
+							words = 12
+						}`, protocol.testName("bip39-mnemonic-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_bip39_mnemonic.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+						resource.TestCheckNoResourceAttr("azrandom_bip39_mnemonic.this", "seed_version"),
+					),
+				},
+				{
+					// Adding the seed keeps the mnemonic
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_bip39_mnemonic" "this" { 
+							name = %[1]q
+							words = 12
+							seed_secret_name = %[2]q
+							passphrase = "TREZOR"
+						}`, protocol.testName("bip39-mnemonic-test"), protocol.testName("bip39-mnemonic-seed-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_bip39_mnemonic.this", "seed_version"),
+						resource.TestCheckResourceAttrWith("azrandom_bip39_mnemonic.this", "version", func(value string) error {
+							if value != version {
+								t.Errorf("expected the mnemonic to keep version %s, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_bip39_mnemonic.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("bip39-mnemonic-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"seed_secret_name", "passphrase", "seed_version"},
+				},
+			},
+		}
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceCron(t *testing.T) {
+	t.Parallel()
+
+	var minute string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cron" "this" { 
+							name = %[1]q
+							template = "R R(2-4) * * *"
+						}`, protocol.testName("cron-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_cron.this", "expression", func(value string) error {
+							minute = strings.Fields(value)[0]
+							return nil
+						}),
+					),
+				},
+				{
+					// Changing the template keeps the random minute
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cron" "this" { 
+							name = %[1]q
+							template = "R R(2-4) * * MON-FRI"
+						}`, protocol.testName("cron-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_cron.this", "expression", func(value string) error {
+							fields := strings.Fields(value)
+							if fields[0] != minute {
+								return fmt.Errorf("expected minute %s to be kept, got %s", minute, fields[0])
+							}
+							if fields[4] != "MON-FRI" {
+								return fmt.Errorf("expected day of week MON-FRI, got %s", fields[4])
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_cron.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("cron-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
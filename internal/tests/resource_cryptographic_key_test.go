@@ -4,58 +4,569 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func TestAccResourceCryptographicKey(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_cryptographic_key" "this" { 
-							name = "cryptographic-key-test"
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
 							algorithm = "RSA"
-							rsa_bits = 2048
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_cryptographic_key.this", "version"),
-				),
-			},
-			// {
-			// 	ResourceName:                         "azrandom_cryptographic_key.this",
-			// 	ImportStateVerifyIdentifierAttribute: "name",
-			// 	ImportStateId:                        "cryptographic-key-test",
-			// 	ImportState:                          true,
-			// 	ImportStateVerify:                    true,
-			// },
-		},
+							rsa = {
+								bits = 2048
+							}
+						}`, protocol.testName("cryptographic-key-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_cryptographic_key.this", "version"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_cryptographic_key.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("cryptographic-key-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
 	})
 }
 
 func TestAccResourceCryptographicKeyHmac(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_cryptographic_key" "this" { 
-							name = "cryptographic-key-test"
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
 							algorithm = "HMAC"
-							hmac_hash_function = "SHA256"
+							hmac = {
+								hash_function = "SHA256"
+								key_length = 64
+							}
+						}`, protocol.testName("cryptographic-key-hmac-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_cryptographic_key.this", "version"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_cryptographic_key.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("cryptographic-key-hmac-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
+
+// cryptographicKeyForgetConfig removes azrandom_cryptographic_key.existing from state without deleting its
+// secret, like a workspace whose state was lost.
+const cryptographicKeyForgetConfig = `
+removed {
+	from = azrandom_cryptographic_key.existing
+	lifecycle {
+		destroy = false
+	}
+}
+`
+
+func TestAccResourceCryptographicKeyConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	var version, publicKeyPem string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "existing" { 
+							name = %[1]q
+							algorithm = "ED25519"
+						}`, protocol.testName("cryptographic-key-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.existing", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.existing", "public_key_pem", func(value string) error {
+							publicKeyPem = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + cryptographicKeyForgetConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
+							algorithm = "ED25519"
+						}`, protocol.testName("cryptographic-key-conflict-policy-test")),
+					ExpectError: regexp.MustCompile(`terraform import ADDRESS ` + protocol.testName("cryptographic-key-conflict-policy-test")),
+				},
+				{
+					// Adopting keeps the existing version, and derives the public key from it
+					Config: providerConfig + cryptographicKeyForgetConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
+							algorithm = "ED25519"
+							conflict_policy = "adopt"
+						}`, protocol.testName("cryptographic-key-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected the existing version %s to be adopted, got %s", version, value)
+							}
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.this", "public_key_pem", func(value string) error {
+							if value != publicKeyPem {
+								return fmt.Errorf("expected the public key of the existing key, got %s", value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					// Forget the adopted resource as well
+					Config: providerConfig + `removed {
+							from = azrandom_cryptographic_key.this
+							lifecycle {
+								destroy = false
+							}
 						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_cryptographic_key.this", "version"),
-				),
-			},
-			// TODO imports do not work at the moment
-			// {
-			// 	ResourceName:                         "azrandom_cryptographic_key.this",
-			// 	ImportStateVerifyIdentifierAttribute: "name",
-			// 	ImportStateId:                        "cryptographic-key-test",
-			// 	ImportState:                          true,
-			// 	ImportStateVerify:                    true,
-			// },
+				},
+				{
+					// Overwriting stores a new key as a new version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "overwritten" { 
+							name = %[1]q
+							algorithm = "ED25519"
+							conflict_policy = "overwrite"
+						}`, protocol.testName("cryptographic-key-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.overwritten", "version", func(value string) error {
+							if value == version {
+								return fmt.Errorf("expected a new version to be stored, got the existing version %s", value)
+							}
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.overwritten", "public_key_pem", func(value string) error {
+							if value == publicKeyPem {
+								return errors.New("expected a new key to be generated, got the existing public key")
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceCryptographicKeyMismatchedSettings(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
+							algorithm = "ECDSA"
+							rsa = {
+								bits = 4096
+							}
+						}`, protocol.testName("cryptographic-key-mismatch-test")),
+					ExpectError: regexp.MustCompile(`may only be set when "algorithm" is "RSA"`),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" { 
+							name = %[1]q
+							algorithm = "RSA"
+							rsa = {
+								bits = 4096
+							}
+							ecdsa = {
+								curve = "P256"
+							}
+						}`, protocol.testName("cryptographic-key-mismatch-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+			},
+		}
+	})
+}
+
+func TestResourceCryptographicKeyUpgradeStateV0(t *testing.T) {
+	testCases := map[string]struct {
+		priorState map[string]any
+		expected   map[string]any
+	}{
+		"rsa": {
+			priorState: map[string]any{
+				"algorithm":          "RSA",
+				"rsa_bits":           4096,
+				"ecdsa_curve":        "P224",
+				"hmac_hash_function": "SHA256",
+			},
+			expected: map[string]any{
+				"algorithm": "RSA",
+				"rsa":       map[string]any{"bits": float64(4096)},
+				"ecdsa":     nil,
+				"hmac":      nil,
+			},
+		},
+		"ecdsa": {
+			priorState: map[string]any{
+				"algorithm":          "ECDSA",
+				"rsa_bits":           2048,
+				"ecdsa_curve":        "P384",
+				"hmac_hash_function": "SHA256",
+			},
+			expected: map[string]any{
+				"algorithm": "ECDSA",
+				"rsa":       nil,
+				"ecdsa":     map[string]any{"curve": "P384"},
+				"hmac":      nil,
+			},
+		},
+		"hmac": {
+			priorState: map[string]any{
+				"algorithm":          "HMAC",
+				"rsa_bits":           2048,
+				"ecdsa_curve":        "P224",
+				"hmac_hash_function": "SHA256",
+			},
+			expected: map[string]any{
+				"algorithm": "HMAC",
+				"rsa":       nil,
+				"ecdsa":     nil,
+				"hmac":      map[string]any{"hash_function": "SHA256", "key_length": float64(32)},
+			},
+		},
+		"ed25519": {
+			priorState: map[string]any{
+				"algorithm":          "ED25519",
+				"rsa_bits":           2048,
+				"ecdsa_curve":        "P224",
+				"hmac_hash_function": "SHA256",
+			},
+			expected: map[string]any{
+				"algorithm": "ED25519",
+				"rsa":       nil,
+				"ecdsa":     nil,
+				"hmac":      nil,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			testCase.priorState["name"] = "cryptographic-key-test"
+			testCase.priorState["version"] = "0123456789abcdef"
+
+			upgraded := upgradeResourceState(t, "azrandom_cryptographic_key", 0, testCase.priorState)
+
+			for attribute, expected := range testCase.expected {
+				actual, _ := json.Marshal(upgraded[attribute])
+				wanted, _ := json.Marshal(expected)
+				if string(actual) != string(wanted) {
+					t.Errorf("expected %s to be %s, got %s", attribute, wanted, actual)
+				}
+			}
+			for _, removed := range []string{"rsa_bits", "ecdsa_curve", "hmac_hash_function"} {
+				if _, ok := upgraded[removed]; ok {
+					t.Errorf("expected %s to be removed from upgraded state", removed)
+				}
+			}
+			if upgraded["version"] != "0123456789abcdef" {
+				t.Errorf("expected version to be kept, got %v", upgraded["version"])
+			}
+			if upgraded["conflict_policy"] != "fail" {
+				t.Errorf("expected conflict_policy to default to fail, got %v", upgraded["conflict_policy"])
+			}
+		})
+	}
+}
+
+func TestResourceCryptographicKeyTrimOutputsPlan(t *testing.T) {
+	priorState := map[string]any{
+		"name":                          "cryptographic-key-test",
+		"version":                       "0123456789abcdef",
+		"algorithm":                     "ED25519",
+		"trim_outputs":                  false,
+		"public_key_pem":                "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEA\n-----END PUBLIC KEY-----\n",
+		"public_key_openssh":            "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5\n",
+		"public_key_fingerprint_md5":    "aa:bb",
+		"public_key_fingerprint_sha256": "SHA256:aabb",
+	}
+
+	testCases := map[string]struct {
+		config          map[string]any
+		expectedPem     any
+		expectedOpenSSH any
+	}{
+		"trimmed": {
+			config: map[string]any{
+				"name":         "cryptographic-key-test",
+				"algorithm":    "ED25519",
+				"trim_outputs": true,
+			},
+			expectedPem:     "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEA\n-----END PUBLIC KEY-----",
+			expectedOpenSSH: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5",
+		},
+		"unchanged": {
+			config: map[string]any{
+				"name":      "cryptographic-key-test",
+				"algorithm": "ED25519",
+			},
+			expectedPem:     "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEA\n-----END PUBLIC KEY-----\n",
+			expectedOpenSSH: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5\n",
 		},
+		"keepers-changed": {
+			config: map[string]any{
+				"name":         "cryptographic-key-test",
+				"algorithm":    "ED25519",
+				"trim_outputs": true,
+				"keepers":      map[string]string{"foo": "bar"},
+			},
+			expectedPem:     unknownValue,
+			expectedOpenSSH: unknownValue,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_cryptographic_key", priorState, testCase.config)
+
+			if planned["public_key_pem"] != testCase.expectedPem {
+				t.Errorf("expected public_key_pem %q, got %q", testCase.expectedPem, planned["public_key_pem"])
+			}
+			if planned["public_key_openssh"] != testCase.expectedOpenSSH {
+				t.Errorf("expected public_key_openssh %q, got %q", testCase.expectedOpenSSH, planned["public_key_openssh"])
+			}
+		})
+	}
+}
+
+// A new key stores the defaults of the settings omitted from configuration, as an import of the key does,
+// while an existing key keeps the settings it was stored with.
+func TestResourceCryptographicKeyDefaultSettingsPlan(t *testing.T) {
+	testCases := map[string]struct {
+		priorState map[string]any
+		algorithm  string
+		expected   map[string]any
+	}{
+		"rsa": {
+			algorithm: "RSA",
+			expected:  map[string]any{"rsa": map[string]any{"bits": 2048}},
+		},
+		"ecdsa": {
+			algorithm: "ECDSA",
+			expected:  map[string]any{"ecdsa": map[string]any{"curve": "P224"}},
+		},
+		"hmac": {
+			algorithm: "HMAC",
+			expected:  map[string]any{"hmac": map[string]any{"hash_function": "SHA256", "key_length": 32}},
+		},
+		"ed25519": {
+			algorithm: "ED25519",
+			expected:  map[string]any{},
+		},
+		"existing-without-settings": {
+			priorState: map[string]any{"name": "cryptographic-key-test", "version": "0123456789abcdef", "algorithm": "RSA"},
+			algorithm:  "RSA",
+			expected:   map[string]any{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_cryptographic_key", testCase.priorState,
+				map[string]any{"name": "cryptographic-key-test", "algorithm": testCase.algorithm})
+
+			for _, attribute := range []string{"rsa", "ecdsa", "hmac"} {
+				if fmt.Sprint(planned[attribute]) != fmt.Sprint(testCase.expected[attribute]) {
+					t.Errorf("expected %s %v, got %v", attribute, testCase.expected[attribute], planned[attribute])
+				}
+			}
+		})
+	}
+}
+
+func TestAccResourceCryptographicKeyKeepersPlan(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		config := func(keepers string, description string) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" {
+							name = %[1]q
+							algorithm = "ED25519"
+							keepers = { "rotation" = %[2]q }
+							description = %[3]q
+						}`, protocol.testName("cryptographic-key-keepers-plan-test"), keepers, description)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config("1", "first"),
+				},
+				{
+					// Changing keepers generates a new value, so resources referencing version see the change
+					Config: config("2", "first"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_cryptographic_key.this", plancheck.ResourceActionUpdate),
+							plancheck.ExpectUnknownValue("azrandom_cryptographic_key.this", tfjsonpath.New("version")),
+							plancheck.ExpectUnknownValue("azrandom_cryptographic_key.this", tfjsonpath.New("public_key_pem")),
+							plancheck.ExpectUnknownValue("azrandom_cryptographic_key.this", tfjsonpath.New("public_key_openssh")),
+							plancheck.ExpectUnknownValue("azrandom_cryptographic_key.this", tfjsonpath.New("public_key_fingerprint_md5")),
+							plancheck.ExpectUnknownValue("azrandom_cryptographic_key.this", tfjsonpath.New("public_key_fingerprint_sha256")),
+						},
+					},
+				},
+				{
+					// Changing the description updates the current version in place
+					Config: config("2", "second"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_cryptographic_key.this", plancheck.ResourceActionUpdate),
+							expectKnownVersion("azrandom_cryptographic_key.this"),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceCryptographicKeyOnDrift(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{"regenerate", "ignore", "error"} {
+		t.Run(mode, func(t *testing.T) {
+			var version string
+
+			testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+				testAccSkipProtocol5(t, protocol)
+
+				name := protocol.testName("cryptographic-key-on-drift-" + mode + "-test")
+				config := providerConfig + fmt.Sprintf(`resource "azrandom_cryptographic_key" "this" {
+							name = %[1]q
+							algorithm = "ED25519"
+							on_drift = %[2]q
+						}`, name, mode)
+
+				return resource.TestCase{
+					Steps: []resource.TestStep{
+						{
+							Config: config,
+							Check: resource.TestCheckResourceAttrWith("azrandom_cryptographic_key.this", "version", func(value string) error {
+								version = value
+								return nil
+							}),
+						},
+						testAccOnDriftStep(t, "azrandom_cryptographic_key.this", name, mode, config, &version),
+					},
+				}
+			})
+		})
+	}
+}
+
+// An imported key takes its algorithm, settings and public key outputs from the stored private key, so that
+// planning the configuration it was created with keeps it.
+func TestResourceCryptographicKeyImportState(t *testing.T) {
+	testCases := map[string]map[string]any{
+		"rsa":     {"algorithm": "RSA", "rsa": map[string]any{"bits": 3072}},
+		"ecdsa":   {"algorithm": "ECDSA", "ecdsa": map[string]any{"curve": "P384"}},
+		"ed25519": {"algorithm": "ED25519"},
+		"hmac":    {"algorithm": "HMAC", "hmac": map[string]any{"hash_function": "SHA256", "key_length": 64}},
+	}
+
+	for name, settings := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]any{"name": "cryptographic-key-import-test"}
+			for attribute, value := range settings {
+				config[attribute] = value
+			}
+
+			vault := newMemoryVault(t)
+			server := vault.configuredServer(t)
+			created := stateMap(t, server, "azrandom_cryptographic_key",
+				applySucceeded(t, applyResourceChange(t, server, "azrandom_cryptographic_key", nil, config)).NewState)
+
+			server = vault.configuredServer(t)
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_cryptographic_key",
+				ID:       "cryptographic-key-import-test",
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+			if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+				t.Fatalf("expected the key to be imported, got %v", resp.Diagnostics)
+			}
+
+			imported := stateMap(t, server, "azrandom_cryptographic_key", resp.ImportedResources[0].State)
+			for _, attribute := range []string{
+				"algorithm", "rsa", "ecdsa", "hmac", "public_key_pem", "public_key_openssh",
+				"public_key_fingerprint_md5", "public_key_fingerprint_sha256",
+			} {
+				if !reflect.DeepEqual(imported[attribute], created[attribute]) {
+					t.Errorf("expected %s %v, got %v", attribute, created[attribute], imported[attribute])
+				}
+			}
+
+			planned := planResourceChangeWith(t, server, "azrandom_cryptographic_key", imported, config)
+			if version := plannedStateMap(t, server, "azrandom_cryptographic_key", planned)["version"]; version != created["version"] {
+				t.Errorf("expected version %v to be kept, got %v", created["version"], version)
+			}
+		})
+	}
+}
+
+func TestResourceCryptographicKeyImportStateNotAKey(t *testing.T) {
+	vault := newMemoryVault(t)
+	vault.write("cryptographic-key-import-test")
+	vault.setValue("cryptographic-key-import-test", "not a key")
+
+	resp, err := vault.configuredServer(t).ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_cryptographic_key",
+		ID:       "cryptographic-key-import-test",
 	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(resp.Diagnostics) != 1 || !strings.Contains(resp.Diagnostics[0].Detail, "PEM format") {
+		t.Errorf("expected the import of a secret without a private key to fail, got %v", resp.Diagnostics)
+	}
 }
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceKDFParams(t *testing.T) {
+	t.Parallel()
+
+	var salt string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_kdf_params" "this" { 
+							name = %[1]q
+							kdf  = "argon2id"
+						}`, protocol.testName("kdf-params-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_kdf_params.this", "version"),
+						resource.TestCheckResourceAttrWith("azrandom_kdf_params.this", "json", func(value string) error {
+							var document struct {
+								KDF    string           `json:"kdf"`
+								Params map[string]int64 `json:"params"`
+								Salt   string           `json:"salt"`
+							}
+							if err := json.Unmarshal([]byte(value), &document); err != nil {
+								return err
+							}
+							if document.KDF != "argon2id" || document.Params["memory"] != 65536 {
+								return fmt.Errorf("unexpected document %s", value)
+							}
+							salt = document.Salt
+							return nil
+						}),
+					),
+				},
+				{
+					// Changing cost parameters keeps the salt
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_kdf_params" "this" { 
+							name = %[1]q
+							kdf  = "argon2id"
+							argon2id = {
+								iterations = 4
+							}
+						}`, protocol.testName("kdf-params-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_kdf_params.this", "json", func(value string) error {
+							expected := fmt.Sprintf(`{"kdf":"argon2id","params":{"iterations":4,"key_length":32,"memory":65536,"parallelism":4},"salt":%q}`, salt)
+							if value != expected {
+								return fmt.Errorf("expected %s, got %s", expected, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_kdf_params.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("kdf-params-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceKDFParamsMismatchedParameters(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_kdf_params" "this" { 
+							name = %[1]q
+							kdf  = "pbkdf2"
+							scrypt = {
+								n = 16384
+							}
+						}`, protocol.testName("kdf-params-mismatch-test")),
+					ExpectError: regexp.MustCompile(`may only be set when "kdf" is "scrypt"`),
+				},
+			},
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceLicenseKey(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_license_key" "this" { 
+							name = %[1]q
+						}`, protocol.testName("license-key-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_license_key.this", "version"),
+						resource.TestMatchResourceAttr("azrandom_license_key.this", "key_id", regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}$`)),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_license_key.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("license-key-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceLicenseKeyDuplicateAlphabetCharacters(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_license_key" "this" { 
+							name = %[1]q
+							alphabet = "ABCA"
+						}`, protocol.testName("license-key-alphabet-test")),
+					ExpectError: regexp.MustCompile(`must not contain any character more than once`),
+				},
+			},
+		}
+	})
+}
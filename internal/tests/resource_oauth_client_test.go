@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceOAuthClient(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_oauth_client" "this" { 
+							name = %[1]q
+							client_id_format = "random"
+							client_id_prefix = "svc-"
+							client_secret_basic_name = %[2]q
+						}`, protocol.testName("oauth-client-test"), protocol.testName("oauth-client-test-basic")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_oauth_client.this", "version"),
+						resource.TestCheckResourceAttrSet("azrandom_oauth_client.this", "client_secret_basic_version"),
+						resource.TestMatchResourceAttr("azrandom_oauth_client.this", "client_id", regexp.MustCompile(`^svc-[a-z0-9]{32}$`)),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_oauth_client.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("oauth-client-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"client_id_format", "client_id_prefix", "client_secret_basic_name", "client_secret_basic_version"},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceOAuthClientRotation(t *testing.T) {
+	t.Parallel()
+
+	var clientID string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_oauth_client" "this" { 
+							name = %[1]q
+							keepers = {"foo": "bar"}
+						}`, protocol.testName("oauth-client-test2")),
+					Check: resource.TestCheckResourceAttrWith("azrandom_oauth_client.this", "client_id", func(value string) error {
+						clientID = value
+						return nil
+					}),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_oauth_client" "this" { 
+							name = %[1]q
+							keepers = {"foo": "baz"}
+						}`, protocol.testName("oauth-client-test2")),
+					Check: resource.TestCheckResourceAttrWith("azrandom_oauth_client.this", "client_id", func(value string) error {
+						if value != clientID {
+							return fmt.Errorf("expected client_id %s to be kept when rotating the secret, got %s", clientID, value)
+						}
+						return nil
+					}),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_oauth_client" "this" { 
+							name = %[1]q
+							keepers = {"foo": "qux"}
+							rotate_client_id = true
+						}`, protocol.testName("oauth-client-test2")),
+					Check: resource.TestCheckResourceAttrWith("azrandom_oauth_client.this", "client_id", func(value string) error {
+						if value == clientID {
+							return fmt.Errorf("expected a new client_id when rotate_client_id is set")
+						}
+						return nil
+					}),
+				},
+			},
+		}
+	})
+}
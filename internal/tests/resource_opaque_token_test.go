@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceOpaqueToken(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "signing_key" { 
+							name = %[1]q
+							length = 64
+							lower = true
+							upper = true
+						}
+						
+						resource "azrandom_opaque_token" "this" { 
+							name = %[2]q
+							ttl = "720h"
+							signing_key_secret_name = azrandom_string.signing_key.name
+						}`, protocol.testName("opaque-token-signing-key-test"), protocol.testName("opaque-token-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_opaque_token.this", "version"),
+						resource.TestCheckResourceAttrWith("azrandom_opaque_token.this", "expires_at", func(value string) error {
+							expiresAt, err := time.Parse(time.RFC3339, value)
+							if err != nil {
+								return err
+							}
+							if expiresAt.Before(time.Now().Add(719 * time.Hour)) {
+								return fmt.Errorf("expected expires_at about 720h from now, got %s", value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_opaque_token.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("opaque-token-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceOpaqueTokenInvalidTTL(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_opaque_token" "this" { 
+							name = %[1]q
+							ttl = "30 days"
+							signing_key_secret_name = %[2]q
+						}`, protocol.testName("opaque-token-invalid-ttl-test"), protocol.testName("opaque-token-signing-key")),
+					ExpectError: regexp.MustCompile(strings.ReplaceAll(`value must be a positive duration`, " ", `\s+`)),
+				},
+			},
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourcePasswordSet(t *testing.T) {
+	t.Parallel()
+
+	var alphaVersion string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		testAccSkipProtocol5(t, protocol)
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_password_set" "this" { 
+							prefix = %[1]q
+							names = ["alpha", "beta"]
+						}`, protocol.testName("password-set-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_password_set.this", "versions.%", "2"),
+						resource.TestCheckResourceAttrWith("azrandom_password_set.this", "versions.alpha", func(value string) error {
+							alphaVersion = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Adding and removing names leaves the other entries untouched
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_password_set" "this" { 
+							prefix = %[1]q
+							entries = {
+								alpha = {}
+								gamma = { length = 64 }
+							}
+						}`, protocol.testName("password-set-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_password_set.this", "versions.%", "2"),
+						resource.TestCheckNoResourceAttr("azrandom_password_set.this", "versions.beta"),
+						resource.TestCheckResourceAttrSet("azrandom_password_set.this", "versions.gamma"),
+						resource.TestCheckResourceAttrWith("azrandom_password_set.this", "versions.alpha", func(value string) error {
+							if value != alphaVersion {
+								t.Errorf("expected alpha to keep version %s, got %s", alphaVersion, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestResourcePasswordSetPlan(t *testing.T) {
+	priorState := map[string]any{
+		"prefix":   "password-set-test",
+		"names":    []string{"alpha", "beta"},
+		"length":   32,
+		"upper":    true,
+		"lower":    true,
+		"numeric":  true,
+		"special":  true,
+		"on_error": "abort",
+		"versions": map[string]string{"alpha": "1", "beta": "2"},
+	}
+
+	testCases := map[string]struct {
+		priorState map[string]any
+		config     map[string]any
+		expected   any
+	}{
+		"unchanged": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix": "password-set-test",
+				"names":  []string{"alpha", "beta"},
+			},
+			expected: map[string]any{"alpha": "1", "beta": "2"},
+		},
+		"name-added": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix": "password-set-test",
+				"names":  []string{"alpha", "beta", "gamma"},
+			},
+			expected: map[string]any{"alpha": "1", "beta": "2", "gamma": unknownValue},
+		},
+		"name-removed": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix": "password-set-test",
+				"names":  []string{"alpha"},
+			},
+			expected: map[string]any{"alpha": "1"},
+		},
+		"entry-overridden": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix":  "password-set-test",
+				"entries": map[string]any{"alpha": map[string]any{}, "beta": map[string]any{"length": 64}},
+			},
+			expected: map[string]any{"alpha": "1", "beta": unknownValue},
+		},
+		"description-changed": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix":      "password-set-test",
+				"names":       []string{"alpha", "beta"},
+				"description": "service accounts",
+			},
+			expected: map[string]any{"alpha": "1", "beta": "2"},
+		},
+		"keepers-changed": {
+			priorState: priorState,
+			config: map[string]any{
+				"prefix":  "password-set-test",
+				"names":   []string{"alpha", "beta"},
+				"keepers": map[string]string{"foo": "bar"},
+			},
+			expected: unknownValue,
+		},
+		// An entry that failed with on_error = "continue" is missing from the versions in state,
+		// and is retried by the next apply
+		"entry-failed": {
+			priorState: map[string]any{
+				"prefix":   "password-set-test",
+				"names":    []string{"alpha", "beta"},
+				"length":   32,
+				"upper":    true,
+				"lower":    true,
+				"numeric":  true,
+				"special":  true,
+				"on_error": "continue",
+				"versions": map[string]string{"alpha": "1"},
+			},
+			config: map[string]any{
+				"prefix":   "password-set-test",
+				"names":    []string{"alpha", "beta"},
+				"on_error": "continue",
+			},
+			expected: map[string]any{"alpha": "1", "beta": unknownValue},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_password_set", testCase.priorState, testCase.config)
+
+			if !reflect.DeepEqual(planned["versions"], testCase.expected) {
+				t.Errorf("expected versions %v, got %v", testCase.expected, planned["versions"])
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceSecretAlias(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "source" { 
+							name = %[1]q
+						}
+						
+						resource "azrandom_secret_alias" "this" { 
+							name = %[2]q
+							source_secret_name = azrandom_uuid.source.name
+						}`, protocol.testName("secret-alias-source-test"), protocol.testName("secret-alias-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_secret_alias.this", "version"),
+						resource.TestCheckResourceAttrPair("azrandom_secret_alias.this", "source_version", "azrandom_uuid.source", "version"),
+					),
+				},
+				{
+					// Rotating the source in the same apply requires its version in keepers
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "source" { 
+							name = %[1]q
+							keepers = {"foo": "bar"}
+						}
+						
+						resource "azrandom_secret_alias" "this" { 
+							name = %[2]q
+							source_secret_name = azrandom_uuid.source.name
+							keepers = {"source_version": azrandom_uuid.source.version}
+						}`, protocol.testName("secret-alias-source-test"), protocol.testName("secret-alias-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrPair("azrandom_secret_alias.this", "source_version", "azrandom_uuid.source", "version"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_secret_alias.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("secret-alias-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"keepers"},
+				},
+			},
+		}
+	})
+}
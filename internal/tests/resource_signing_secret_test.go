@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccResourceSigningSecret(t *testing.T) {
+	t.Parallel()
+
+	// The current secret before the rotation, which becomes the previous one
+	var currentKeyID string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_signing_secret" "this" { 
+							name = %[1]q
+							keepers = {"foo": "bar"}
+						}`, protocol.testName("signing-secret-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_signing_secret.this", "version"),
+						resource.TestCheckResourceAttrSet("azrandom_signing_secret.this", "rotated_at"),
+						resource.TestCheckResourceAttrSet("azrandom_signing_secret.this", "current_key_id"),
+						resource.TestCheckNoResourceAttr("azrandom_signing_secret.this", "previous_key_id"),
+						func(s *terraform.State) error {
+							currentKeyID = s.RootModule().Resources["azrandom_signing_secret.this"].Primary.Attributes["current_key_id"]
+							return nil
+						},
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_signing_secret" "this" { 
+							name = %[1]q
+							keepers = {"foo": "baz"}
+						}`, protocol.testName("signing-secret-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_signing_secret.this", "current_key_id"),
+						resource.TestCheckResourceAttrPtr("azrandom_signing_secret.this", "previous_key_id", &currentKeyID),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_signing_secret.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("signing-secret-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"keepers"},
+				},
+			},
+		}
+	})
+}
+
+// Rotating keeps the current secret as the previous one, unless a version was set that the resource has not
+// seen, whose secret would be lost.
+func TestResourceSigningSecretRotation(t *testing.T) {
+	document := func(t *testing.T, vault *memoryVault) map[string]string {
+		t.Helper()
+
+		var document map[string]string
+		if err := json.Unmarshal([]byte(vault.value("signing-secret-test")), &document); err != nil {
+			t.Fatalf("expected a signing secret document, got %s", err)
+		}
+		return document
+	}
+
+	t.Run("rotated", func(t *testing.T) {
+		vault := newMemoryVault(t)
+		config := map[string]any{"name": "signing-secret-test", "keepers": map[string]any{"foo": "bar"}}
+		server := vault.configuredServer(t)
+		created := applySucceeded(t, applyResourceChange(t, server, "azrandom_signing_secret", nil, config))
+		createdState := stateMap(t, server, "azrandom_signing_secret", created.NewState)
+		before := document(t, vault)
+
+		config["keepers"] = map[string]any{"foo": "baz"}
+		server = vault.configuredServer(t)
+		rotated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_signing_secret", createdState,
+			created.Private, config))
+		rotatedState := stateMap(t, server, "azrandom_signing_secret", rotated.NewState)
+		after := document(t, vault)
+
+		if after["previous"] != before["current"] {
+			t.Errorf("expected the previous secret to be the current secret before the rotation")
+		}
+		if after["current"] == before["current"] {
+			t.Errorf("expected a new current secret")
+		}
+		if rotatedState["previous_key_id"] != createdState["current_key_id"] {
+			t.Errorf("expected previous_key_id %v, got %v", createdState["current_key_id"], rotatedState["previous_key_id"])
+		}
+	})
+
+	t.Run("pinned", func(t *testing.T) {
+		vault := newMemoryVault(t)
+		config := map[string]any{"name": "signing-secret-test", "keepers": map[string]any{"foo": "bar"}}
+		server := vault.configuredServer(t)
+		created := applySucceeded(t, applyResourceChange(t, server, "azrandom_signing_secret", nil, config))
+		createdState := stateMap(t, server, "azrandom_signing_secret", created.NewState)
+		pinned := document(t, vault)
+
+		config["keepers"] = map[string]any{"foo": "baz"}
+		server = vault.configuredServer(t)
+		applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_signing_secret", createdState, created.Private, config))
+
+		// Importing the version from before the rotation pins it, so the newer version is not a concurrent rotation
+		server = vault.configuredServer(t)
+		imported, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+			TypeName: "azrandom_signing_secret",
+			ID:       "signing-secret-test:1",
+		})
+		if err != nil {
+			t.Fatalf("unable to import resource state: %s", err)
+		}
+		if len(imported.Diagnostics) > 0 || len(imported.ImportedResources) != 1 {
+			t.Fatalf("expected the secret to be imported, got %v", imported.Diagnostics)
+		}
+		read := readResourcePrivate(t, server, "azrandom_signing_secret", imported.ImportedResources[0].State,
+			imported.ImportedResources[0].Private)
+		readState := stateMap(t, server, "azrandom_signing_secret", read.NewState)
+
+		config["keepers"] = map[string]any{"foo": "qux"}
+		server = vault.configuredServer(t)
+		rotated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_signing_secret", readState, read.Private, config))
+		if version := stateMap(t, server, "azrandom_signing_secret", rotated.NewState)["version"]; version != "3" {
+			t.Fatalf("expected a new version 3 to be written, got %v", version)
+		}
+		if after := document(t, vault); after["previous"] != pinned["current"] {
+			t.Errorf("expected the previous secret to be the current secret of the pinned version")
+		}
+	})
+
+	t.Run("modified-concurrently", func(t *testing.T) {
+		vault := newMemoryVault(t)
+		config := map[string]any{"name": "signing-secret-test", "keepers": map[string]any{"foo": "bar"}}
+		server := vault.configuredServer(t)
+		created := applySucceeded(t, applyResourceChange(t, server, "azrandom_signing_secret", nil, config))
+		createdState := stateMap(t, server, "azrandom_signing_secret", created.NewState)
+
+		// Another run rotated the secret
+		vault.write("signing-secret-test")
+
+		config["keepers"] = map[string]any{"foo": "baz"}
+		server = vault.configuredServer(t)
+		resp := applyResourceChangePrivate(t, server, "azrandom_signing_secret", createdState, created.Private, config)
+		if len(resp.Diagnostics) == 0 || !strings.Contains(resp.Diagnostics[0].Detail, "was modified concurrently") {
+			t.Fatalf("expected the rotation to fail, got %v", resp.Diagnostics)
+		}
+	})
+}
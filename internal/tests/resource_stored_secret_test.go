@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccResourceStoredSecret(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			// value is write-only
+			TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+				tfversion.SkipBelow(tfversion.Version1_11_0),
+			},
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_stored_secret" "this" {
+							name = %[1]q
+							value = "first"
+							value_version = 1
+						}`, protocol.testName("stored-secret-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckNoResourceAttr("azrandom_stored_secret.this", "value"),
+						resource.TestCheckResourceAttrWith("azrandom_stored_secret.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Changing the value alone does not write it
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_stored_secret" "this" {
+							name = %[1]q
+							value = "second"
+							value_version = 1
+						}`, protocol.testName("stored-secret-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_stored_secret.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when only changing the value, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_stored_secret" "this" {
+							name = %[1]q
+							value = "second"
+							value_version = 2
+						}`, protocol.testName("stored-secret-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_stored_secret.this", "version", func(value string) error {
+							if value == version {
+								return fmt.Errorf("expected a new version when changing value_version, got %s", value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_stored_secret.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("stored-secret-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"value_version"},
+				},
+			},
+		}
+	})
+}
+
+func TestStoredSecretValue(t *testing.T) {
+	vault := newMemoryVault(t)
+	config := map[string]any{"name": "stored-secret-test", "value": "first", "value_version": 1}
+
+	created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_stored_secret", nil, config))
+	server := vault.configuredServer(t)
+	state := stateMap(t, server, "azrandom_stored_secret", created.NewState)
+	if state["value"] != nil {
+		t.Errorf("expected the write-only value not to be stored in state, got %v", state["value"])
+	}
+
+	// Changing the value alone keeps the stored value, until value_version changes
+	config["value"] = "second"
+	server = vault.configuredServer(t)
+	updated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_stored_secret", state, created.Private, config))
+	state = stateMap(t, server, "azrandom_stored_secret", updated.NewState)
+	if state["version"] != "1" {
+		t.Errorf("expected the stored value to be kept while value_version is unchanged, got version %v", state["version"])
+	}
+
+	config["value_version"] = 2
+	server = vault.configuredServer(t)
+	updated = applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_stored_secret", state, updated.Private, config))
+	if state = stateMap(t, server, "azrandom_stored_secret", updated.NewState); state["version"] != "2" {
+		t.Fatalf("expected the new value to be stored when value_version changes, got version %v", state["version"])
+	}
+
+	// A value restored outside of Terraform is written again
+	vault.setValue("stored-secret-test", "restored")
+	server = vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_stored_secret", updated.NewState, updated.Private)
+	refreshed := stateMap(t, server, "azrandom_stored_secret", read.NewState)
+	applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_stored_secret", refreshed, read.Private, config))
+	if state = stateMap(t, server, "azrandom_stored_secret", applied.NewState); state["version"] != "3" {
+		t.Errorf("expected the configured value to be written again after drift, got version %v", state["version"])
+	}
+}
+
+func TestStoredSecretImported(t *testing.T) {
+	testCases := map[string]struct {
+		value         string
+		expectedWrite bool
+	}{
+		"held": {
+			value:         "existing",
+			expectedWrite: false,
+		},
+		"changed": {
+			value:         "configured",
+			expectedWrite: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			vault.write("stored-secret-test")
+			vault.setValue("stored-secret-test", "existing")
+
+			// An imported resource has no value_version, so its first apply compares the configured value
+			server := vault.configuredServer(t)
+			imported := map[string]any{
+				"name":            "stored-secret-test",
+				"version":         "1",
+				"content_type":    "text/plain",
+				"enabled":         true,
+				"conflict_policy": "fail",
+				"on_drift":        "regenerate",
+			}
+			config := map[string]any{"name": "stored-secret-test", "value": testCase.value, "value_version": 1}
+			applied := applySucceeded(t, applyResourceChange(t, server, "azrandom_stored_secret", imported, config))
+
+			state := stateMap(t, server, "azrandom_stored_secret", applied.NewState)
+			if written := state["version"] != "1"; written != testCase.expectedWrite {
+				t.Errorf("expected the value to be written: %t, got version %v", testCase.expectedWrite, state["version"])
+			}
+		})
+	}
+}
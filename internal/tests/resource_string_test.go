@@ -4,45 +4,1080 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func TestAccResourceString(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_string" "this" { 
-							name = "string-test"
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" { 
+							name = %[1]q
 							length = 8
 							lower = true
 							upper = true
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_string.this", "version"),
-				),
-			},
-			// {
-			// 	ResourceName:                         "azrandom_string.this",
-			// 	ImportStateVerifyIdentifierAttribute: "name",
-			// 	ImportStateId:                        "string-test",
-			// 	ImportState:                          true,
-			// 	ImportStatePersist: true,
-			// 	ImportStateCheck: composeImportStateCheck(
-			// 		testCheckNoResourceAttrInstanceState("length"),
-			// 		testCheckNoResourceAttrInstanceState("number"),
-			// 		testCheckNoResourceAttrInstanceState("upper"),
-			// 		testCheckNoResourceAttrInstanceState("lower"),
-			// 		testCheckNoResourceAttrInstanceState("special"),
-			// 		testCheckNoResourceAttrInstanceState("min_numeric"),
-			// 		testCheckNoResourceAttrInstanceState("min_upper"),
-			// 		testCheckNoResourceAttrInstanceState("min_lower"),
-			// 		testCheckNoResourceAttrInstanceState("min_special"),
-			// 		testExtractResourceAttrInstanceState("result", &result1),
-			// 	),
-			// },
+						}`, protocol.testName("string-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_string.this", "version"),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceStringImport(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 12
+						}`, protocol.testName("string-import-test"))
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_string.this", "version"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_string.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("string-import-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStatePersist:                   true,
+				},
+				{
+					Config:   config,
+					PlanOnly: true,
+				},
+			},
+		}
+	})
+}
+
+// Renaming the secret replaces the resource, so the secret under the old name is deleted rather than left behind.
+func TestAccResourceStringRename(t *testing.T) {
+	t.Parallel()
+
+	config := func(name string) string {
+		return providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 12
+						}`, name)
+	}
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config(protocol.testName("string-rename-test")),
+				},
+				{
+					Config: config(protocol.testName("string-renamed-test")),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_string.this", plancheck.ResourceActionReplace),
+						},
+					},
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "name", protocol.testName("string-renamed-test")),
+						testAccCheckSecretsDeleted(protocol.testName("string-rename-test")),
+					),
+				},
+			},
+		}
+	})
+}
+
+// Terraform plans the replacement of a renamed string twice on the same provider, once with its prior state and
+// once without, and neither plan may find the new name claimed by another resource.
+func TestResourceStringRenamePlan(t *testing.T) {
+	server := configuredServer(t, vaultUrlProviderConfig)
+	priorState := map[string]any{"name": "string-rename-test", "version": "1", "length": 12}
+	config := map[string]any{"name": "string-renamed-test", "length": 12}
+
+	planned := planResourceChangeWith(t, server, "azrandom_string", priorState, config)
+	if errors := planErrors(planned); len(errors) != 0 {
+		t.Fatalf("unexpected errors planning the rename: %v", errors)
+	}
+	if len(planned.RequiresReplace) != 1 || !planned.RequiresReplace[0].Equal(tftypes.NewAttributePath().WithAttributeName("name")) {
+		t.Fatalf("expected the rename to require replacement, got %v", planned.RequiresReplace)
+	}
+
+	replaced := planResourceChangePrivate(t, server, "azrandom_string", nil, planned.PlannedPrivate, config)
+	if errors := planErrors(replaced); len(errors) != 0 {
+		t.Fatalf("unexpected errors planning the replacement: %v", errors)
+	}
+}
+
+// An imported string takes the default settings, which differ from those configured here. The first apply
+// after the import records the configured settings without generating a new value, since the stored value
+// satisfies them, after which nothing is planned.
+func TestAccResourceStringImportSettings(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 12
+							min_upper = 3
+							min_lower = 3
+							min_numeric = 3
+							min_special = 3
+						}`, protocol.testName("string-import-settings-test"))
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config,
+				},
+				{
+					ResourceName:                         "azrandom_string.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("string-import-settings-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"min_upper", "min_lower", "min_numeric", "min_special"},
+					ImportStatePersist:                   true,
+				},
+				{
+					Config: config,
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_string.this", plancheck.ResourceActionUpdate),
+							expectKnownVersion("azrandom_string.this"),
+						},
+					},
+				},
+				{
+					Config:   config,
+					PlanOnly: true,
+				},
+			},
+		}
+	})
+}
+
+// An imported string takes its length from the stored value and the defaults of the other settings, so that
+// it verifies against a string created with the defaults. The first plan after the import keeps the value as
+// long as it satisfies the configured settings, so that configuring the settings it was generated with keeps it.
+func TestResourceStringImportState(t *testing.T) {
+	defaults := map[string]any{
+		"upper": true, "lower": true, "numeric": true, "special": true,
+		"min_upper": 0, "min_lower": 0, "min_numeric": 0, "min_special": 0,
+	}
+
+	testCases := map[string]struct {
+		value  string
+		length int
+		config map[string]any
+		kept   bool
+	}{
+		"defaults": {
+			value:  "aB3$cD4%ef",
+			length: 10,
+			config: map[string]any{"length": 10},
+			kept:   true,
+		},
+		"mixed": {
+			value:  "aB3$cD4%ef",
+			length: 10,
+			config: map[string]any{"length": 10, "min_upper": 2, "min_lower": 4, "min_numeric": 2, "min_special": 2},
+			kept:   true,
+		},
+		"lower": {
+			value:  "abcdef",
+			length: 6,
+			config: map[string]any{"length": 6, "upper": false, "numeric": false, "special": false, "min_lower": 6},
+			kept:   true,
+		},
+		"override-special": {
+			value:  "12~~",
+			length: 4,
+			config: map[string]any{
+				"length": 4, "upper": false, "lower": false, "override_special": "~", "min_numeric": 2, "min_special": 2,
+			},
+			kept: true,
+		},
+		"multi-byte-charset": {
+			value:  "äöü€",
+			length: 4,
+			config: map[string]any{"length": 4, "charset": "äöü€"},
+			kept:   true,
+		},
+		// A prefix cannot be told from the value, but the characters after it are checked
+		"prefix": {
+			value:  "sk_live_ab12",
+			length: 12,
+			config: map[string]any{"length": 4, "prefix": "sk_live_", "special": false},
+			kept:   true,
+		},
+		"unsatisfied-minimum": {
+			value:  "abcdef",
+			length: 6,
+			config: map[string]any{"length": 6, "min_numeric": 1},
+		},
+		"disabled-characters": {
+			value:  "aB3$cD4%ef",
+			length: 10,
+			config: map[string]any{"length": 10, "special": false},
+		},
+		"other-length": {
+			value:  "abcdef",
+			length: 6,
+			config: map[string]any{"length": 8},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			vault.write("string-import-test")
+			vault.setValue("string-import-test", testCase.value)
+
+			server := vault.configuredServer(t)
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_string",
+				ID:       "string-import-test",
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+			if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+				t.Fatalf("expected the string to be imported, got %v", resp.Diagnostics)
+			}
+
+			imported := stateMap(t, server, "azrandom_string", resp.ImportedResources[0].State)
+			if fmt.Sprint(imported["length"]) != fmt.Sprint(testCase.length) {
+				t.Errorf("expected length %v, got %v", testCase.length, imported["length"])
+			}
+			for attribute, expected := range defaults {
+				if fmt.Sprint(imported[attribute]) != fmt.Sprint(expected) {
+					t.Errorf("expected %s %v, got %v", attribute, expected, imported[attribute])
+				}
+			}
+			if imported["override_special"] != nil {
+				t.Errorf("expected override_special to be left unset, got %v", imported["override_special"])
+			}
+			if imported["result_hash"] != sha256Hex(testCase.value) {
+				t.Errorf("expected the result_hash of the imported value, got %v", imported["result_hash"])
+			}
+
+			config := map[string]any{"name": "string-import-test"}
+			for attribute, value := range testCase.config {
+				config[attribute] = value
+			}
+			planned := plannedStateMap(t, server, "azrandom_string", planResourceChangePrivate(t, server,
+				"azrandom_string", imported, resp.ImportedResources[0].Private, config))
+			if kept := planned["version"] == imported["version"]; kept != testCase.kept {
+				t.Errorf("expected the version to be kept: %t, planned %v", testCase.kept, planned["version"])
+			}
+		})
+	}
+}
+
+// Applying the first plan after an import that keeps the value records the configured settings, after which
+// nothing is planned, and later changes of the settings generate a new value again.
+func TestResourceStringImportKeptApply(t *testing.T) {
+	vault := newMemoryVault(t)
+	vault.write("string-import-test")
+	vault.setValue("string-import-test", "abcdef")
+
+	server := vault.configuredServer(t)
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_string",
+		ID:       "string-import-test",
+	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+		t.Fatalf("expected the string to be imported, got %v", resp.Diagnostics)
+	}
+	imported := stateMap(t, server, "azrandom_string", resp.ImportedResources[0].State)
+
+	config := map[string]any{
+		"name": "string-import-test", "length": 6, "upper": false, "numeric": false, "special": false, "min_lower": 6,
+	}
+	applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", imported,
+		resp.ImportedResources[0].Private, config))
+	state := stateMap(t, server, "azrandom_string", applied.NewState)
+	if state["version"] != imported["version"] || vault.value("string-import-test") != "abcdef" {
+		t.Fatalf("expected the imported value to be kept, got version %v", state["version"])
+	}
+	if state["upper"] != false || fmt.Sprint(state["min_lower"]) != "6" {
+		t.Errorf("expected the configured settings to be recorded, got upper %v and min_lower %v",
+			state["upper"], state["min_lower"])
+	}
+
+	planned := plannedStateMap(t, server, "azrandom_string",
+		planResourceChangePrivate(t, server, "azrandom_string", state, applied.Private, config))
+	if fmt.Sprint(planned) != fmt.Sprint(state) {
+		t.Errorf("expected an empty plan, got %v", planned)
+	}
+
+	// The value still satisfies fewer lowercase letters, but the resource is no longer just imported
+	config["min_lower"] = 5
+	planned = plannedStateMap(t, server, "azrandom_string",
+		planResourceChangePrivate(t, server, "azrandom_string", state, applied.Private, config))
+	if planned["version"] != unknownValue {
+		t.Errorf("expected a new value to be planned, got version %v", planned["version"])
+	}
+}
+
+// Setting restore_version stores the value of that version again as the newest version, and clearing it keeps
+// the value.
+func TestResourceStringRestoreVersion(t *testing.T) {
+	vault := newMemoryVault(t)
+	config := map[string]any{"name": "string-restore-test", "length": 16, "keepers": map[string]any{"rotation": "1"}}
+
+	created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_string", nil, config))
+	restoredValue := vault.value("string-restore-test")
+
+	server := vault.configuredServer(t)
+	config["keepers"] = map[string]any{"rotation": "2"}
+	rotated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string",
+		stateMap(t, server, "azrandom_string", created.NewState), created.Private, config))
+	if vault.value("string-restore-test") == restoredValue {
+		t.Fatal("expected the rotation to generate a new value")
+	}
+
+	// A version that does not exist is rejected by the plan
+	server = vault.configuredServer(t)
+	prior := stateMap(t, server, "azrandom_string", rotated.NewState)
+	config["restore_version"] = "9"
+	planned := planResourceChangePrivate(t, server, "azrandom_string", prior, rotated.Private, config)
+	if errors := planErrors(planned); len(errors) != 1 || errors[0] != "Invalid restore_version" {
+		t.Fatalf("expected the version to restore to be rejected, got %v", planned.Diagnostics)
+	}
+
+	server = vault.configuredServer(t)
+	config["restore_version"] = "1"
+	planned = planResourceChangePrivate(t, server, "azrandom_string", prior, rotated.Private, config)
+	if version := plannedStateMap(t, server, "azrandom_string", planned)["version"]; version != unknownValue {
+		t.Errorf("expected the restore to plan a new version, got %v", version)
+	}
+	server = vault.configuredServer(t)
+	restored := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", prior, rotated.Private, config))
+	if version := stateMap(t, server, "azrandom_string", restored.NewState)["version"]; version != "3" {
+		t.Errorf("expected the restored value to be stored as version 3, got %v", version)
+	}
+	if vault.value("string-restore-test") != restoredValue {
+		t.Error("expected the value of version 1 to be restored")
+	}
+
+	// Clearing restore_version keeps the restored value
+	server = vault.configuredServer(t)
+	prior = stateMap(t, server, "azrandom_string", restored.NewState)
+	delete(config, "restore_version")
+	planned = planResourceChangePrivate(t, server, "azrandom_string", prior, restored.Private, config)
+	if version := plannedStateMap(t, server, "azrandom_string", planned)["version"]; version != "3" {
+		t.Errorf("expected clearing restore_version to keep version 3, got %v", version)
+	}
+}
+
+func TestAccResourceStringConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "existing" { 
+							name = %[1]q
+							length = 16
+						}`, protocol.testName("string-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_string.existing", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Adopting keeps the existing version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" { 
+							name = %[1]q
+							length = 16
+							conflict_policy = "adopt"
+						}
+						removed {
+							from = azrandom_string.existing
+							lifecycle {
+								destroy = false
+							}
+						}`, protocol.testName("string-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_string.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected the existing version %s to be adopted, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					// Overwriting stores a new version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "overwritten" { 
+							name = %[1]q
+							length = 16
+							conflict_policy = "overwrite"
+						}
+						removed {
+							from = azrandom_string.this
+							lifecycle {
+								destroy = false
+							}
+						}`, protocol.testName("string-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_string.overwritten", "version", func(value string) error {
+							if value == version {
+								return fmt.Errorf("expected a new version to be stored, got the existing version %s", value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestResourceStringPresetPlan(t *testing.T) {
+	testCases := map[string]struct {
+		config   map[string]any
+		expected map[string]any
+	}{
+		"preset": {
+			config: map[string]any{
+				"name":   "string-preset-test",
+				"preset": "postgres",
+			},
+			expected: map[string]any{
+				"length":           float64(32),
+				"min_special":      float64(1),
+				"override_special": "-_.~",
+			},
+		},
+		"preset-overridden": {
+			config: map[string]any{
+				"name":        "string-preset-test",
+				"preset":      "postgres",
+				"length":      64,
+				"min_special": 4,
+			},
+			expected: map[string]any{
+				"length":           float64(64),
+				"min_special":      float64(4),
+				"override_special": "-_.~",
+			},
+		},
+		"naming-preset": {
+			config: map[string]any{
+				"name":   "string-preset-test",
+				"preset": "dns_label",
+				"length": 20,
+			},
+			expected: map[string]any{
+				"length":           float64(20),
+				"upper":            false,
+				"special":          false,
+				"first_char_alpha": true,
+			},
+		},
+		"no-preset": {
+			config: map[string]any{
+				"name":   "string-preset-test",
+				"length": 16,
+			},
+			expected: map[string]any{
+				"length":           float64(16),
+				"min_special":      float64(0),
+				"override_special": nil,
+				"numeric":          true,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_string", nil, testCase.config)
+
+			for attribute, expected := range testCase.expected {
+				if planned[attribute] != expected {
+					t.Errorf("expected %s to be %v, got %v", attribute, expected, planned[attribute])
+				}
+			}
+		})
+	}
+}
+
+// The deprecated number attribute sets numeric, unless numeric is configured as well.
+func TestResourceStringNumberPlan(t *testing.T) {
+	testCases := map[string]struct {
+		config   map[string]any
+		expected bool
+	}{
+		"number": {
+			config:   map[string]any{"name": "string-number-test", "length": 16, "number": false},
+			expected: false,
+		},
+		"numeric": {
+			config:   map[string]any{"name": "string-number-test", "length": 16, "numeric": false},
+			expected: false,
+		},
+		"both": {
+			config:   map[string]any{"name": "string-number-test", "length": 16, "numeric": false, "number": false},
+			expected: false,
+		},
+		"neither": {
+			config:   map[string]any{"name": "string-number-test", "length": 16},
+			expected: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_string", nil, testCase.config)
+
+			if planned["numeric"] != testCase.expected {
+				t.Errorf("expected numeric to be %t, got %v", testCase.expected, planned["numeric"])
+			}
+		})
+	}
+}
+
+func TestAccResourceStringNumberConflict(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 16
+							numeric = true
+							number = false
+						}`, protocol.testName("string-number-conflict-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceStringCharset(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 16
+							charset = "0123456789abcdef"
+							upper = true
+						}`, protocol.testName("string-charset-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 16
+							charset = "0123456789abcdef"
+						}`, protocol.testName("string-charset-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "charset", "0123456789abcdef"),
+						resource.TestCheckResourceAttrSet("azrandom_string.this", "version"),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceStringPattern(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 8
+							pattern = "AAA-9{4}"
+						}`, protocol.testName("string-pattern-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							pattern = "AAA-9{4"
+						}`, protocol.testName("string-pattern-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Value"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							pattern = "AAA-9{4}"
+						}`, protocol.testName("string-pattern-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "pattern", "AAA-9{4}"),
+						resource.TestCheckResourceAttr("azrandom_string.this", "length", "8"),
+						resource.TestCheckResourceAttrSet("azrandom_string.this", "version"),
+					),
+				},
+			},
+		}
+	})
+}
+
+// A naming preset rejects attributes that would generate names its target does not accept.
+func TestAccResourceStringNamingPresetConflict(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							preset = "storage_account"
+							upper = true
+						}`, protocol.testName("string-naming-preset-test")),
+					ExpectError: regexp.MustCompile("break the naming rules"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							preset = "storage_account"
+							length = 30
+						}`, protocol.testName("string-naming-preset-test")),
+					ExpectError: regexp.MustCompile("length must be between 3 and 24"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							preset = "storage_account"
+							prefix = "st"
+						}`, protocol.testName("string-naming-preset-test")),
+					ExpectError: regexp.MustCompile("length must be between 1 and 22 along with"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							preset = "storage_account"
+							length = 8
+						}`, protocol.testName("string-naming-preset-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "length", "8"),
+						resource.TestCheckResourceAttr("azrandom_string.this", "upper", "false"),
+					),
+				},
+			},
+		}
+	})
+}
+
+// testAccCheckOnlyVersionEnabled checks that the version of the secret in the state of resourceName is
+// the only enabled version of that secret.
+func testAccCheckOnlyVersionEnabled(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		name, version := rs.Primary.Attributes["name"], rs.Primary.Attributes["version"]
+
+		client, err := newTestClient()
+		if err != nil {
+			return err
+		}
+
+		pager := client.NewListSecretPropertiesVersionsPager(name, nil)
+		for pager.More() {
+			page, err := pager.NextPage(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, properties := range page.Value {
+				enabled := properties.Attributes != nil && properties.Attributes.Enabled != nil && *properties.Attributes.Enabled
+				if enabled != (properties.ID.Version() == version) {
+					return fmt.Errorf("expected only version %s of secret %s to be enabled, version %s is enabled: %t",
+						version, name, properties.ID.Version(), enabled)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccResourceStringDisablePreviousVersions(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(length int) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = %[2]d
+							disable_previous_versions = true
+						}`, protocol.testName("string-disable-previous-versions-test"), length)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config(8),
+				},
+				{
+					Config: config(12),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "disable_previous_versions", "true"),
+						testAccCheckOnlyVersionEnabled("azrandom_string.this"),
+					),
+				},
+			},
+		}
+	})
+}
+
+// testAccCheckEnabledVersions checks that the secret in the state of resourceName has the expected number
+// of enabled versions.
+func testAccCheckEnabledVersions(resourceName string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		name := rs.Primary.Attributes["name"]
+
+		client, err := newTestClient()
+		if err != nil {
+			return err
+		}
+
+		enabled := 0
+		pager := client.NewListSecretPropertiesVersionsPager(name, nil)
+		for pager.More() {
+			page, err := pager.NextPage(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, properties := range page.Value {
+				if properties.Attributes != nil && properties.Attributes.Enabled != nil && *properties.Attributes.Enabled {
+					enabled++
+				}
+			}
+		}
+		if enabled != expected {
+			return fmt.Errorf("expected secret %s to have %d enabled versions, got %d", name, expected, enabled)
+		}
+		return nil
+	}
+}
+
+func TestAccResourceStringMaxVersionsToKeep(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(length int) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = %[2]d
+							max_versions_to_keep = 2
+						}`, protocol.testName("string-max-versions-to-keep-test"), length)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config(8),
+				},
+				{
+					Config: config(10),
+				},
+				{
+					Config: config(12),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_string.this", "max_versions_to_keep", "2"),
+						testAccCheckEnabledVersions("azrandom_string.this", 2),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceStringKeepersPlan(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(keepers string, description string) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 16
+							keepers = { "rotation" = %[2]q }
+							description = %[3]q
+						}`, protocol.testName("string-keepers-plan-test"), keepers, description)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config("1", "first"),
+				},
+				{
+					// Changing keepers generates a new value, so resources referencing version see the change
+					Config: config("2", "first"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_string.this", plancheck.ResourceActionUpdate),
+							plancheck.ExpectUnknownValue("azrandom_string.this", tfjsonpath.New("version")),
+						},
+					},
+				},
+				{
+					// Changing the description updates the current version in place
+					Config: config("2", "second"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_string.this", plancheck.ResourceActionUpdate),
+							expectKnownVersion("azrandom_string.this"),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceStringOnDrift(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{"regenerate", "ignore", "error"} {
+		t.Run(mode, func(t *testing.T) {
+			var version string
+
+			testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+				name := protocol.testName("string-on-drift-" + mode + "-test")
+				config := providerConfig + fmt.Sprintf(`resource "azrandom_string" "this" {
+							name = %[1]q
+							length = 16
+							on_drift = %[2]q
+						}`, name, mode)
+
+				return resource.TestCase{
+					Steps: []resource.TestStep{
+						{
+							Config: config,
+							Check: resource.TestCheckResourceAttrWith("azrandom_string.this", "version", func(value string) error {
+								version = value
+								return nil
+							}),
+						},
+						testAccOnDriftStep(t, "azrandom_string.this", name, mode, config, &version),
+					},
+				}
+			})
+		})
+	}
+}
+
+func TestResourceStringUpgradeStateV0(t *testing.T) {
+	testCases := map[string]struct {
+		priorState string
+		expected   map[string]any
+	}{
+		"earlier-release": {
+			// Written before conflict_policy and on_drift were stored, with numeric stored as its default of false
+			priorState: `{
+				"name": "string-test", "version": "1", "length": 16,
+				"special": true, "upper": true, "lower": true, "numeric": false,
+				"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0
+			}`,
+			expected: map[string]any{
+				"numeric":          false,
+				"conflict_policy":  "fail",
+				"on_drift":         "regenerate",
+				"first_char_alpha": false,
+			},
+		},
+		"configured": {
+			priorState: `{
+				"name": "string-test", "version": "1", "length": 16,
+				"special": true, "upper": true, "lower": true, "numeric": true,
+				"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0,
+				"conflict_policy": "adopt", "on_drift": "ignore"
+			}`,
+			expected: map[string]any{
+				"numeric":          true,
+				"conflict_policy":  "adopt",
+				"on_drift":         "ignore",
+				"first_char_alpha": false,
+			},
 		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var priorState map[string]any
+			if err := json.Unmarshal([]byte(testCase.priorState), &priorState); err != nil {
+				t.Fatal(err)
+			}
+
+			upgraded := upgradeResourceState(t, "azrandom_string", 0, priorState)
+
+			for attribute, expected := range testCase.expected {
+				if upgraded[attribute] != expected {
+					t.Errorf("expected %s to be %v, got %v", attribute, expected, upgraded[attribute])
+				}
+			}
+			if upgraded["version"] != "1" {
+				t.Errorf("expected version to be kept, got %v", upgraded["version"])
+			}
+
+			// The first Read after the upgrade records the default that the value was generated with, so that
+			// omitting numeric from configuration keeps the value
+			vault := newMemoryVault(t)
+			vault.write("string-test")
+			server := vault.configuredServer(t)
+			read := readResourcePrivate(t, server, "azrandom_string",
+				resourceDynamicValue(t, server, "azrandom_string", upgraded), nil)
+			config := map[string]any{"name": "string-test", "length": 16, "conflict_policy": upgraded["conflict_policy"], "on_drift": upgraded["on_drift"]}
+			planned := plannedStateMap(t, server, "azrandom_string", planResourceChangePrivate(t, server,
+				"azrandom_string", stateMap(t, server, "azrandom_string", read.NewState), read.Private, config))
+			if planned["numeric"] != testCase.expected["numeric"] || planned["version"] != "1" {
+				t.Errorf("expected numeric %v and the version to be kept, got numeric %v and version %v",
+					testCase.expected["numeric"], planned["numeric"], planned["version"])
+			}
+		})
+	}
+}
+
+// numeric keeps its former default of false for a string upgraded from schema version 0 until a new value is
+// generated, while other strings plan its default of true whenever it is not configured.
+func TestResourceStringLegacyNumeric(t *testing.T) {
+	t.Run("upgraded", func(t *testing.T) {
+		priorState := map[string]any{
+			"name": "string-test", "version": "1", "length": 16,
+			"special": true, "upper": true, "lower": true, "numeric": false,
+			"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0,
+		}
+		upgraded := upgradeResourceState(t, "azrandom_string", 0, priorState)
+
+		vault := newMemoryVault(t)
+		vault.write("string-test")
+		server := vault.configuredServer(t)
+		read := readResourcePrivate(t, server, "azrandom_string",
+			resourceDynamicValue(t, server, "azrandom_string", upgraded), nil)
+		readState := stateMap(t, server, "azrandom_string", read.NewState)
+
+		// A new value for another reason is generated with the default
+		config := map[string]any{"name": "string-test", "length": 20}
+		planned := plannedStateMap(t, server, "azrandom_string",
+			planResourceChangePrivate(t, server, "azrandom_string", readState, read.Private, config))
+		if planned["numeric"] != true || planned["version"] != unknownValue {
+			t.Fatalf("expected numeric true and a new value, got numeric %v and version %v", planned["numeric"], planned["version"])
+		}
+
+		server = vault.configuredServer(t)
+		applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", readState, read.Private, config))
+		state := stateMap(t, server, "azrandom_string", applied.NewState)
+		server = vault.configuredServer(t)
+		planned = plannedStateMap(t, server, "azrandom_string",
+			planResourceChangePrivate(t, server, "azrandom_string", state, applied.Private, config))
+		if planned["numeric"] != true || planned["version"] != state["version"] {
+			t.Errorf("expected numeric true and the version to be kept, got numeric %v and version %v",
+				planned["numeric"], planned["version"])
+		}
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		vault := newMemoryVault(t)
+		server := vault.configuredServer(t)
+		config := map[string]any{"name": "string-test", "length": 16, "numeric": false}
+		created := applySucceeded(t, applyResourceChange(t, server, "azrandom_string", nil, config))
+		server = vault.configuredServer(t)
+		read := readResourcePrivate(t, server, "azrandom_string", created.NewState, created.Private)
+		readState := stateMap(t, server, "azrandom_string", read.NewState)
+
+		// Removing numeric from configuration plans its default, which generates a new value
+		delete(config, "numeric")
+		planned := plannedStateMap(t, server, "azrandom_string",
+			planResourceChangePrivate(t, server, "azrandom_string", readState, read.Private, config))
+		if planned["numeric"] != true || planned["version"] != unknownValue {
+			t.Errorf("expected numeric true and a new value, got numeric %v and version %v", planned["numeric"], planned["version"])
+		}
 	})
 }
+
+// State of schema version 1 was written before first_char_alpha existed, and is kept without a new value.
+func TestResourceStringUpgradeStateV1(t *testing.T) {
+	priorState := map[string]any{
+		"name": "string-test", "version": "0123456789abcdef", "length": 16,
+		"special": true, "upper": true, "lower": true, "numeric": true,
+		"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0,
+		"conflict_policy": "fail", "on_drift": "regenerate",
+	}
+
+	upgraded := upgradeResourceState(t, "azrandom_string", 1, priorState)
+	if upgraded["first_char_alpha"] != false || upgraded["encoding"] != "plain" || upgraded["numeric"] != true {
+		t.Errorf("expected first_char_alpha false, encoding plain and numeric to be kept, got %v, %v and %v",
+			upgraded["first_char_alpha"], upgraded["encoding"], upgraded["numeric"])
+	}
+
+	planned := planResourceChange(t, "azrandom_string", upgraded, map[string]any{"name": "string-test", "length": 16})
+	if planned["version"] != "0123456789abcdef" {
+		t.Errorf("expected the version to be kept, got %v", planned["version"])
+	}
+}
+
+// State of schema version 2 holds the settings of version 2, which are kept, but no encoding.
+func TestResourceStringUpgradeStateV2(t *testing.T) {
+	priorState := map[string]any{
+		"name": "string-test", "version": "0123456789abcdef", "length": 16,
+		"special": true, "upper": true, "lower": true, "numeric": true,
+		"min_special": 0, "min_upper": 0, "min_lower": 0, "min_numeric": 0,
+		"conflict_policy": "fail", "on_drift": "regenerate", "first_char_alpha": true,
+		"exclude_characters": "0O", "pattern": nil, "charset": nil,
+	}
+
+	upgraded := upgradeResourceState(t, "azrandom_string", 2, priorState)
+	if upgraded["encoding"] != "plain" {
+		t.Errorf("expected encoding plain, got %v", upgraded["encoding"])
+	}
+	if upgraded["first_char_alpha"] != true || upgraded["exclude_characters"] != "0O" {
+		t.Errorf("expected first_char_alpha and exclude_characters to be kept, got %v and %v",
+			upgraded["first_char_alpha"], upgraded["exclude_characters"])
+	}
+
+	config := map[string]any{"name": "string-test", "length": 16, "first_char_alpha": true, "exclude_characters": "0O"}
+	planned := planResourceChange(t, "azrandom_string", upgraded, config)
+	if planned["version"] != "0123456789abcdef" {
+		t.Errorf("expected the version to be kept, got %v", planned["version"])
+	}
+}
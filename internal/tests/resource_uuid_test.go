@@ -4,104 +4,1118 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func TestAccResourceUUID(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test"
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
-			},
-			{
-				ResourceName:                         "azrandom_uuid.this",
-				ImportStateVerifyIdentifierAttribute: "name",
-				ImportStateId:                        "uuid-test",
-				ImportState:                          true,
-				ImportStateVerify:                    true,
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "created_on"),
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "updated_on"),
+						resource.TestCheckResourceAttrPair("azrandom_uuid.this", "created_date", "azrandom_uuid.this", "created_on"),
+						resource.TestCheckResourceAttrPair("azrandom_uuid.this", "updated_date", "azrandom_uuid.this", "updated_on"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_uuid.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("uuid-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
 			},
-		},
+		}
 	})
 }
 
 func TestAccResourceUUIDUpdate(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test2"
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
-			},
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test3"
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-test2")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-test3")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
 			},
-		},
+		}
 	})
 }
 
 func TestAccResourceUUIDTriggerUpdate(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test4"
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
 							keepers = {"foo": "bar"}
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
-			},
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test4"
+						}`, protocol.testName("uuid-test4")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
 							keepers = {"foo": "barrrr"}
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
+						}`, protocol.testName("uuid-test4")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
 			},
-		},
+		}
 	})
 }
 
 func TestAccResourceUUIDDriftUpdate(t *testing.T) {
-	resource.UnitTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test4"
-						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-test6")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-test6")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDDescription(t *testing.T) {
+	t.Parallel()
+
+	var version, createdOn string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							description = "Used by the uuid acceptance tests"
+						}`, protocol.testName("uuid-test5")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "description", "Used by the uuid acceptance tests"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "created_on", func(value string) error {
+							createdOn = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							description = "Still used by the uuid acceptance tests"
+						}`, protocol.testName("uuid-test5")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "description", "Still used by the uuid acceptance tests"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing the description, got %s", version, value)
+							}
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "created_on", func(value string) error {
+							if value != createdOn {
+								return fmt.Errorf("expected created_on %s to be kept when changing the description, got %s", createdOn, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDVerifyWrite(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							verify_write = true
+						}`, protocol.testName("uuid-verify-write-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Turning verification off does not store a new value
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							verify_write = false
+						}`, protocol.testName("uuid-verify-write-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing verify_write, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
 			},
-			{
-				Config: providerConfig + `resource "azrandom_uuid" "this" { 
-							name = "uuid-test4"
+		}
+	})
+}
+
+// uuidForgetConfig removes azrandom_uuid.existing from state without deleting its secret, like a workspace
+// whose state was lost.
+const uuidForgetConfig = `
+removed {
+	from = azrandom_uuid.existing
+	lifecycle {
+		destroy = false
+	}
+}
+`
+
+func TestAccResourceUUIDConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "existing" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.existing", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + uuidForgetConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							conflict_policy = "fail"
+						}`, protocol.testName("uuid-conflict-policy-test")),
+					ExpectError: regexp.MustCompile(`terraform import ADDRESS ` + protocol.testName("uuid-conflict-policy-test")),
+				},
+				{
+					// Adopting keeps the existing version
+					Config: providerConfig + uuidForgetConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							conflict_policy = "adopt"
+						}`, protocol.testName("uuid-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected the existing version %s to be adopted, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					// Forget the adopted resource as well
+					Config: providerConfig + `removed {
+							from = azrandom_uuid.this
+							lifecycle {
+								destroy = false
+							}
 						}`,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
-				),
+				},
+				{
+					// Overwriting stores a new version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "overwritten" { 
+							name = %[1]q
+							conflict_policy = "overwrite"
+						}`, protocol.testName("uuid-conflict-policy-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.overwritten", "version", func(value string) error {
+							if value == version {
+								return fmt.Errorf("expected a new version to be stored, got the existing version %s", value)
+							}
+							return nil
+						}),
+					),
+				},
 			},
-		},
+		}
 	})
 }
+
+func TestAccResourceUUIDConflictPolicySoftDeleted(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "existing" { 
+							name = %[1]q
+						}`, protocol.testName("uuid-on-existing-deleted-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.existing", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Destroying soft-deletes the secret
+					Config: providerConfig,
+				},
+				{
+					// Adopting recovers the soft-deleted secret and keeps its version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							conflict_policy = "adopt"
+						}`, protocol.testName("uuid-on-existing-deleted-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected the recovered version %s to be adopted, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig,
+				},
+				{
+					// Overwriting recovers the soft-deleted secret and stores a new version
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" { 
+							name = %[1]q
+							conflict_policy = "overwrite"
+						}`, protocol.testName("uuid-on-existing-deleted-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value == version {
+								return fmt.Errorf("expected a new version to be stored, got the recovered version %s", value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDDeletedOutOfBand(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			CheckDestroy: testAccCheckSecretsPurged(protocol.testName("uuid-deleted-out-of-band-test")),
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							purge_on_destroy = true
+						}`, protocol.testName("uuid-deleted-out-of-band-test")),
+				},
+				{
+					// Destroying succeeds although the secret is already gone, and there is nothing left to purge
+					PreConfig: testAccRemoveSecret(t, protocol.testName("uuid-deleted-out-of-band-test")),
+					Config:    providerConfig,
+				},
+			},
+		}
+	})
+}
+
+// testAccSetSecretTag sets a tag on the latest version of the given secret outside of Terraform.
+func testAccSetSecretTag(t *testing.T, name string, key string, value string) func() {
+	return func() {
+		client, err := newTestClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		version, properties, err := azrandom.GetSecret(ctx, client, name, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		properties.Tags[key] = value
+		if _, err := azrandom.UpdateSecretProperties(ctx, client, name, version, properties); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testAccSetSecretValue stores a new version of the given secret outside of Terraform, like a rotation in the
+// portal.
+func testAccSetSecretValue(t *testing.T, name string, value string) func() {
+	return func() {
+		client, err := newTestClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		_, properties, err := azrandom.GetSecret(ctx, client, name, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := azrandom.CreateSecret(ctx, client, name, value, properties); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testAccOnDriftStep returns the step that stores a new version of secretName outside of Terraform and applies
+// config, in which resourceName sets on_drift to mode. version holds the version before the drift.
+func testAccOnDriftStep(t *testing.T, resourceName string, secretName string, mode string, config string, version *string) resource.TestStep {
+	step := resource.TestStep{
+		PreConfig: testAccSetSecretValue(t, secretName, "set outside of terraform"),
+		Config:    config,
+	}
+
+	versionChanged := resource.TestCheckResourceAttrWith(resourceName, "version", func(value string) error {
+		if value == *version {
+			return fmt.Errorf("expected the version to change after drift, got %s", value)
+		}
+		return nil
+	})
+
+	switch mode {
+	case "regenerate":
+		// A new value is generated
+		step.ConfigPlanChecks = resource.ConfigPlanChecks{
+			PreApply: []plancheck.PlanCheck{
+				plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+				plancheck.ExpectUnknownValue(resourceName, tfjsonpath.New("version")),
+			},
+		}
+		step.Check = versionChanged
+	case "ignore":
+		// The version set outside of Terraform is taken into state by the refresh
+		step.ConfigPlanChecks = resource.ConfigPlanChecks{
+			PreApply: []plancheck.PlanCheck{
+				plancheck.ExpectEmptyPlan(),
+			},
+		}
+		step.Check = versionChanged
+	case "error":
+		step.ExpectError = regexp.MustCompile("Secret changed outside of Terraform")
+	}
+	return step
+}
+
+func TestAccResourceUUIDOnDrift(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{"regenerate", "ignore", "error"} {
+		t.Run(mode, func(t *testing.T) {
+			var version string
+
+			testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+				name := protocol.testName("uuid-on-drift-" + mode + "-test")
+				config := providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							on_drift = %[2]q
+						}`, name, mode)
+
+				return resource.TestCase{
+					Steps: []resource.TestStep{
+						{
+							Config: config,
+							Check: resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+								version = value
+								return nil
+							}),
+						},
+						testAccOnDriftStep(t, "azrandom_uuid.this", name, mode, config, &version),
+					},
+				}
+			})
+		})
+	}
+}
+
+func TestAccResourceUUIDTags(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							description = "Used by the uuid acceptance tests"
+							tags = {
+								team = "platform"
+							}
+						}`, protocol.testName("uuid-tags-test"))
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.%", "1"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.team", "platform"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					// A tag added outside of Terraform shows up as drift
+					PreConfig:    testAccSetSecretTag(t, protocol.testName("uuid-tags-test"), "owner", "someone"),
+					RefreshState: true,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.%", "2"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.owner", "someone"),
+					),
+				},
+				{
+					// and is removed again without generating a new value
+					Config: config,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.%", "1"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "description", "Used by the uuid acceptance tests"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing the tags, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_uuid.this",
+					ImportState:                          true,
+					ImportStateId:                        protocol.testName("uuid-tags-test"),
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateCheck: func(states []*terraform.InstanceState) error {
+						if tag := states[0].Attributes["tags.team"]; tag != "platform" {
+							return fmt.Errorf("expected the imported tags to include team = platform, got %q", tag)
+						}
+						return nil
+					},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDTagsReserved(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							tags = {
+								"azrandom:description" = "not allowed"
+							}
+						}`, protocol.testName("uuid-tags-reserved-test")),
+					ExpectError: regexp.MustCompile("must not start with"),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDContentType(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+						}`, protocol.testName("uuid-content-type-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "content_type", "text/plain"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							content_type = "application/vnd.example.id"
+						}`, protocol.testName("uuid-content-type-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "content_type", "application/vnd.example.id"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing the content type, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDExpirationDate(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							expiration_date = "2099-01-01T00:00:00Z"
+							not_before = "2020-01-01T00:00:00Z"
+						}`, protocol.testName("uuid-expiration-date-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "expiration_date", "2099-01-01T00:00:00Z"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "not_before", "2020-01-01T00:00:00Z"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							expiration_date = "2099-06-01T02:00:00+02:00"
+						}`, protocol.testName("uuid-expiration-date-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "expiration_date", "2099-06-01T02:00:00+02:00"),
+						resource.TestCheckNoResourceAttr("azrandom_uuid.this", "not_before"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing the expiration date, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDEnabled(t *testing.T) {
+	t.Parallel()
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(enabled bool) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							enabled = %[2]t
+						}`, protocol.testName("uuid-enabled-test"), enabled)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config(false),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "enabled", "false"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: config(true),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "enabled", "true"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when enabling the secret, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDKeepersPlan(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(keepers string, description string) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							keepers = { "rotation" = %[2]q }
+							description = %[3]q
+						}`, protocol.testName("uuid-keepers-plan-test"), keepers, description)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config("1", "first"),
+				},
+				{
+					// Changing keepers generates a new value, so resources referencing version see the change
+					Config: config("2", "first"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_uuid.this", plancheck.ResourceActionUpdate),
+							plancheck.ExpectUnknownValue("azrandom_uuid.this", tfjsonpath.New("version")),
+						},
+					},
+				},
+				{
+					// Changing the description updates the current version in place
+					Config: config("2", "second"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_uuid.this", plancheck.ResourceActionUpdate),
+							expectKnownVersion("azrandom_uuid.this"),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDVersion7(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(uuidVersion string) string {
+			return providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							uuid_version = %[2]q
+						}`, protocol.testName("uuid-version-7-test"), uuidVersion)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config("v7"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "uuid_version", "v7"),
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+				{
+					// Changing uuid_version generates a new value
+					Config: config("v4"),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("azrandom_uuid.this", plancheck.ResourceActionUpdate),
+							plancheck.ExpectUnknownValue("azrandom_uuid.this", tfjsonpath.New("version")),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+func TestAccResourceUUIDNameBasedInvalid(t *testing.T) {
+	t.Parallel()
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							namespace = "not-a-uuid"
+							name_input = "example.com"
+						}`, protocol.testName("uuid-name-based-invalid-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Value"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							namespace = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+						}`, protocol.testName("uuid-name-based-invalid-test")),
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name = %[1]q
+							namespace = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+							name_input = "example.com"
+							uuid_version = "v7"
+						}`, protocol.testName("uuid-name-based-invalid-test")),
+					ExpectError: regexp.MustCompile(`must be "v5" or omitted when "namespace" is set`),
+				},
+			},
+		}
+	})
+}
+
+// A name-based UUID is derived from namespace and name_input, so regenerating it after drift stores the same value.
+func TestResourceUUIDNameBased(t *testing.T) {
+	const expected = "886313e1-3b8a-5372-9b90-0c9aee199e5d"
+	config := map[string]any{
+		"name":       "uuid-name-based-test",
+		"namespace":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"name_input": "python.org",
+	}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_uuid", nil, config))
+	if uuidVersion := stateMap(t, server, "azrandom_uuid", created.NewState)["uuid_version"]; uuidVersion != "v5" {
+		t.Errorf("expected uuid_version v5 when namespace is set, got %v", uuidVersion)
+	}
+	if value := vault.value("uuid-name-based-test"); value != expected {
+		t.Fatalf("expected the version 5 UUID %s, got %s", expected, value)
+	}
+
+	// A value set in the portal is drift, and the regenerated value is derived again
+	vault.write("uuid-name-based-test")
+	vault.setValue("uuid-name-based-test", "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4")
+	server = vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+	refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+
+	server = vault.configuredServer(t)
+	regenerated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+	if version := stateMap(t, server, "azrandom_uuid", regenerated.NewState)["version"]; version != "3" {
+		t.Errorf("expected the value to be regenerated as version 3, got %v", version)
+	}
+	if value := vault.value("uuid-name-based-test"); value != expected {
+		t.Errorf("expected the regenerated value to be %s again, got %s", expected, value)
+	}
+}
+
+// Changing only the format stores the same UUID in its new shape as a new version.
+func TestResourceUUIDFormat(t *testing.T) {
+	config := map[string]any{
+		"name":   "uuid-format-test",
+		"format": "urn",
+	}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_uuid", nil, config))
+	value := vault.value("uuid-format-test")
+	if !strings.HasPrefix(value, "urn:uuid:") || len(value) != 45 {
+		t.Fatalf("expected a UUID in the urn format, got %s", value)
+	}
+
+	config["format"] = "braced"
+	config["uppercase"] = true
+	server = vault.configuredServer(t)
+	reformatted := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid",
+		stateMap(t, server, "azrandom_uuid", created.NewState), created.Private, config))
+	if version := stateMap(t, server, "azrandom_uuid", reformatted.NewState)["version"]; version != "2" {
+		t.Errorf("expected the reformatted value to be stored as version 2, got %v", version)
+	}
+	if expected := "{" + strings.ToUpper(strings.TrimPrefix(value, "urn:uuid:")) + "}"; vault.value("uuid-format-test") != expected {
+		t.Errorf("expected the same UUID as %s, got %s", expected, vault.value("uuid-format-test"))
+	}
+}
+
+// The prefix is stored in front of the UUID, checked when the value is read back, and kept when a new value is
+// generated after drift.
+func TestResourceUUIDPrefix(t *testing.T) {
+	config := map[string]any{
+		"name":         "uuid-prefix-test",
+		"prefix":       "tenant-",
+		"verify_write": true,
+	}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_uuid", nil, config))
+	value := vault.value("uuid-prefix-test")
+	if !strings.HasPrefix(value, "tenant-") || len(value) != len("tenant-")+36 {
+		t.Fatalf("expected a UUID after the prefix, got %s", value)
+	}
+
+	server = vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+	if warnings := readWarnings(read); len(warnings) != 0 {
+		t.Fatalf("expected no warnings for the prefixed value, got %v", warnings)
+	}
+
+	// A value set in the portal without the prefix is drift, and the regenerated value has the prefix again
+	vault.write("uuid-prefix-test")
+	vault.setValue("uuid-prefix-test", "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4")
+	server = vault.configuredServer(t)
+	read = readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+	if warnings := readWarnings(read); len(warnings) != 1 || warnings[0] != "Secret does not hold a UUID" {
+		t.Errorf("expected a warning that the secret does not hold a UUID, got %v", warnings)
+	}
+
+	server = vault.configuredServer(t)
+	regenerated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid",
+		stateMap(t, server, "azrandom_uuid", read.NewState), read.Private, config))
+	value = vault.value("uuid-prefix-test")
+	if !strings.HasPrefix(value, "tenant-") || value == "tenant-9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4" {
+		t.Fatalf("expected a new UUID after the prefix, got %s", value)
+	}
+
+	// Changing the prefix keeps the UUID
+	config["prefix"] = "customer-"
+	server = vault.configuredServer(t)
+	changed := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid",
+		stateMap(t, server, "azrandom_uuid", regenerated.NewState), regenerated.Private, config))
+	if version := stateMap(t, server, "azrandom_uuid", changed.NewState)["version"]; version != "4" {
+		t.Errorf("expected the value with the new prefix to be stored as version 4, got %v", version)
+	}
+	if expected := "customer-" + strings.TrimPrefix(value, "tenant-"); vault.value("uuid-prefix-test") != expected {
+		t.Errorf("expected %s, got %s", expected, vault.value("uuid-prefix-test"))
+	}
+}
+
+func TestResourceUUIDUpgradeState(t *testing.T) {
+	testCases := map[string]struct {
+		schemaVersion int64
+		priorState    string
+		expected      map[string]any
+	}{
+		"earlier-release": {
+			// Written before conflict_policy and on_drift were stored
+			priorState: `{"name": "uuid-test", "version": "0123456789abcdef", "keepers": {"rotation": "1"}}`,
+			expected: map[string]any{
+				"conflict_policy": "fail",
+				"on_drift":        "regenerate",
+				"uuid_version":    "v4",
+				"format":          "canonical",
+				"uppercase":       false,
+			},
+		},
+		"configured": {
+			priorState: `{"name": "uuid-test", "version": "0123456789abcdef", "conflict_policy": "overwrite", "on_drift": "error"}`,
+			expected: map[string]any{
+				"conflict_policy": "overwrite",
+				"on_drift":        "error",
+				"uuid_version":    "v4",
+				"format":          "canonical",
+				"uppercase":       false,
+			},
+		},
+		"before-uuid-version": {
+			schemaVersion: 1,
+			priorState:    `{"name": "uuid-test", "version": "0123456789abcdef", "conflict_policy": "overwrite", "on_drift": "error"}`,
+			expected: map[string]any{
+				"conflict_policy": "overwrite",
+				"on_drift":        "error",
+				"uuid_version":    "v4",
+				"format":          "canonical",
+				"uppercase":       false,
+			},
+		},
+		"before-format": {
+			schemaVersion: 2,
+			priorState:    `{"name": "uuid-test", "version": "0123456789abcdef", "conflict_policy": "fail", "on_drift": "regenerate", "uuid_version": "v7"}`,
+			expected: map[string]any{
+				"uuid_version": "v7",
+				"format":       "canonical",
+				"uppercase":    false,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var priorState map[string]any
+			if err := json.Unmarshal([]byte(testCase.priorState), &priorState); err != nil {
+				t.Fatal(err)
+			}
+
+			upgraded := upgradeResourceState(t, "azrandom_uuid", testCase.schemaVersion, priorState)
+
+			for attribute, expected := range testCase.expected {
+				if upgraded[attribute] != expected {
+					t.Errorf("expected %s to be %v, got %v", attribute, expected, upgraded[attribute])
+				}
+			}
+			if upgraded["version"] != "0123456789abcdef" {
+				t.Errorf("expected version to be kept, got %v", upgraded["version"])
+			}
+		})
+	}
+}
+
+// A secret that does not hold a UUID imports with a warning, unless strict_import is set on the provider.
+func TestResourceUUIDImportNotAUUID(t *testing.T) {
+	testCases := map[string]struct {
+		strictImport bool
+		value        string
+		severity     tfprotov6.DiagnosticSeverity
+		uuidVersion  string
+		format       string
+		uppercase    bool
+		prefix       any
+	}{
+		"uuid": {
+			value:       "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4",
+			uuidVersion: "v4",
+			format:      "canonical",
+		},
+		"uuid-v7": {
+			value:       "0191e2a3-b4c5-7d2e-9f10-3a4b5c6d7e8f",
+			uuidVersion: "v7",
+			format:      "canonical",
+		},
+		"no-hyphens": {
+			value:       "9f0c4a524e1b4c55a0a15b2c1bd1a6f4",
+			uuidVersion: "v4",
+			format:      "no_hyphens",
+		},
+		"braced-uppercase": {
+			value:       "{0191E2A3-B4C5-7D2E-9F10-3A4B5C6D7E8F}",
+			uuidVersion: "v7",
+			format:      "braced",
+			uppercase:   true,
+		},
+		"urn": {
+			value:       "urn:uuid:886313e1-3b8a-5372-9b90-0c9aee199e5d",
+			uuidVersion: "v5",
+			format:      "urn",
+		},
+		"prefixed": {
+			value:       "tenant-{9F0C4A52-4E1B-4C55-A0A1-5B2C1BD1A6F4}",
+			uuidVersion: "v4",
+			format:      "braced",
+			uppercase:   true,
+			prefix:      "tenant-",
+		},
+		"braced-no-hyphens": {
+			value:    "{9f0c4a524e1b4c55a0a15b2c1bd1a6f4}",
+			severity: tfprotov6.DiagnosticSeverityWarning,
+		},
+		"not-a-uuid": {
+			value:    "not-a-uuid",
+			severity: tfprotov6.DiagnosticSeverityWarning,
+		},
+		"not-a-uuid-strict": {
+			strictImport: true,
+			value:        "not-a-uuid",
+			severity:     tfprotov6.DiagnosticSeverityError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			vault.write("uuid-import-test")
+			vault.setValue("uuid-import-test", testCase.value)
+
+			server := configuredServer(t, map[string]any{
+				"custom_endpoint":      vault.URL,
+				"allow_insecure":       true,
+				"insecure_skip_verify": true,
+				"use_fake_credential":  true,
+				"strict_import":        testCase.strictImport,
+				"retry":                map[string]any{"max_retries": 0},
+			})
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_uuid",
+				ID:       "uuid-import-test",
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+
+			if testCase.severity == tfprotov6.DiagnosticSeverityInvalid {
+				if len(resp.Diagnostics) != 0 || len(resp.ImportedResources) != 1 {
+					t.Fatalf("expected no diagnostics, got %v", resp.Diagnostics)
+				}
+				imported := stateMap(t, server, "azrandom_uuid", resp.ImportedResources[0].State)
+				if imported["uuid_version"] != testCase.uuidVersion {
+					t.Errorf("expected uuid_version %s, got %v", testCase.uuidVersion, imported["uuid_version"])
+				}
+				if imported["format"] != testCase.format || imported["uppercase"] != testCase.uppercase {
+					t.Errorf("expected format %s and uppercase %v, got %v and %v", testCase.format, testCase.uppercase,
+						imported["format"], imported["uppercase"])
+				}
+				if imported["prefix"] != testCase.prefix {
+					t.Errorf("expected prefix %v, got %v", testCase.prefix, imported["prefix"])
+				}
+				return
+			}
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != testCase.severity ||
+				resp.Diagnostics[0].Summary != "Secret does not hold a UUID" {
+				t.Fatalf("expected a single %v that the secret does not hold a UUID, got %v", testCase.severity, resp.Diagnostics)
+			}
+			if imported := len(resp.ImportedResources) > 0; imported == testCase.strictImport {
+				t.Errorf("expected the secret to be imported only without strict_import, got %v imported resources", len(resp.ImportedResources))
+			}
+		})
+	}
+}
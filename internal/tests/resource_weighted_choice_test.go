@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceWeightedChoice(t *testing.T) {
+	t.Parallel()
+
+	var result string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_weighted_choice" "this" { 
+							name = %[1]q
+							options = {
+								v1 = 80
+								v2 = 20
+							}
+						}`, protocol.testName("weighted-choice-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_weighted_choice.this", "result", func(value string) error {
+							if value != "v1" && value != "v2" {
+								return fmt.Errorf("expected v1 or v2, got %s", value)
+							}
+							result = value
+							return nil
+						}),
+					),
+				},
+				{
+					// Changing the weights keeps the choice
+					Config: providerConfig + fmt.Sprintf(`resource "azrandom_weighted_choice" "this" { 
+							name = %[1]q
+							options = {
+								v1 = 50
+								v2 = 50
+							}
+						}`, protocol.testName("weighted-choice-test")),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrWith("azrandom_weighted_choice.this", "result", func(value string) error {
+							if value != result {
+								return fmt.Errorf("expected the choice %s to be kept, got %s", result, value)
+							}
+							return nil
+						}),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_weighted_choice.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        protocol.testName("weighted-choice-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+					ImportStateVerifyIgnore:              []string{"options"},
+				},
+			},
+		}
+	})
+}
+
+func TestResourceWeightedChoicePlan(t *testing.T) {
+	priorState := map[string]any{
+		"name":                    "weighted-choice-test",
+		"version":                 "1",
+		"options":                 map[string]int{"v1": 80, "v2": 20},
+		"repick_on_weight_change": false,
+		"result":                  "v2",
+	}
+
+	testCases := map[string]struct {
+		config   map[string]any
+		expected any
+	}{
+		"unchanged": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 80, "v2": 20},
+			},
+			expected: "v2",
+		},
+		"weights-changed": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 50, "v2": 50},
+			},
+			expected: "v2",
+		},
+		"option-added": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 50, "v2": 30, "v3": 20},
+			},
+			expected: "v2",
+		},
+		"weights-changed-repick": {
+			config: map[string]any{
+				"name":                    "weighted-choice-test",
+				"options":                 map[string]int{"v1": 50, "v2": 50},
+				"repick_on_weight_change": true,
+			},
+			expected: unknownValue,
+		},
+		"chosen-option-removed": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 100},
+			},
+			expected: unknownValue,
+		},
+		"chosen-option-weight-zero": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 100, "v2": 0},
+			},
+			expected: unknownValue,
+		},
+		"keepers-changed": {
+			config: map[string]any{
+				"name":    "weighted-choice-test",
+				"options": map[string]int{"v1": 80, "v2": 20},
+				"keepers": map[string]string{"foo": "bar"},
+			},
+			expected: unknownValue,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned := planResourceChange(t, "azrandom_weighted_choice", priorState, testCase.config)
+
+			if planned["result"] != testCase.expected {
+				t.Errorf("expected result %v, got %v", testCase.expected, planned["result"])
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/diagnostics"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+const testRequestID = "0f6e8b5a-3c1d-4e2f-9a7b-6c5d4e3f2a1b"
+
+// forbiddenTransport answers the authenticated requests of a client with a 403 response carrying
+// testRequestID.
+func forbiddenTransport() *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("x-ms-request-id", testRequestID)
+		return fakeResponse(req, http.StatusForbidden, header, accessErrorBody("Forbidden", "The user does not have secrets get permission", "ForbiddenByPolicy")), nil
+	}}
+}
+
+func TestResponseError(t *testing.T) {
+	operations := map[string]func(t *testing.T) error{
+		"get-secret": func(t *testing.T) error {
+			_, _, err := azrandom.GetSecret(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "")
+			return err
+		},
+		"get-secret-value": func(t *testing.T) error {
+			_, err := azrandom.GetSecretValue(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "")
+			return err
+		},
+		"secret-exists": func(t *testing.T) error {
+			_, err := azrandom.SecretExists(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test")
+			return err
+		},
+		"update-secret": func(t *testing.T) error {
+			_, _, err := azrandom.UpdateSecret(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "value", azrandom.SecretProperties{})
+			return err
+		},
+		"update-secret-properties": func(t *testing.T) error {
+			_, err := azrandom.UpdateSecretProperties(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "v1", azrandom.SecretProperties{})
+			return err
+		},
+		"delete-secret": func(t *testing.T) error {
+			_, err := azrandom.DeleteSecret(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test")
+			return err
+		},
+		"prune-secret-versions": func(t *testing.T) error {
+			_, err := azrandom.PruneSecretVersions(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "v1", 1)
+			return err
+		},
+		"validate-access": func(t *testing.T) error {
+			client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+				Transport: forbiddenTransport(),
+			})
+			if err != nil {
+				t.Fatalf("unable to create client: %s", err)
+			}
+			return azrandom.ValidateAccess(context.Background(), client)
+		},
+	}
+
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			err := operation(t)
+			responseErr, ok := azrandom.AsResponseError(err)
+			if !ok {
+				t.Fatalf("expected a *ResponseError, got %v", err)
+			}
+			if responseErr.RequestID != testRequestID || responseErr.StatusCode != http.StatusForbidden || responseErr.ErrorCode != "Forbidden" {
+				t.Errorf("expected request ID %s, status 403 and error code Forbidden, got %s, %d and %s",
+					testRequestID, responseErr.RequestID, responseErr.StatusCode, responseErr.ErrorCode)
+			}
+
+			// The response of the vault can still be inspected
+			var azcoreErr *azcore.ResponseError
+			if !errors.As(err, &azcoreErr) {
+				t.Errorf("expected the error to wrap an *azcore.ResponseError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestResponseErrorNotWrapped(t *testing.T) {
+	client := newFakeClient(t, &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}})
+
+	_, err := azrandom.GetSecretValue(context.Background(), client, "response-error-test", "")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := azrandom.AsResponseError(err); ok {
+		t.Errorf("expected an error without response not to be a *ResponseError, got %v", err)
+	}
+}
+
+func TestErrorDetail(t *testing.T) {
+	if detail := diagnostics.ErrorDetail(errors.New("connection refused")); detail != "connection refused" {
+		t.Errorf("expected the message of the error, got %q", detail)
+	}
+
+	_, err := azrandom.GetSecretValue(context.Background(), newFakeClient(t, forbiddenTransport()), "response-error-test", "")
+	detail := diagnostics.ErrorDetail(err)
+	if !strings.HasPrefix(detail, err.Error()) {
+		t.Errorf("expected the detail to start with the message of the error, got %q", detail)
+	}
+	expected := "\n\nAzure Response:\n" +
+		"  x-ms-request-id: " + testRequestID + "\n" +
+		"  HTTP status:     403 Forbidden\n" +
+		"  Error code:      Forbidden"
+	if !strings.HasSuffix(detail, expected) {
+		t.Errorf("expected the detail to end with %q, got %q", expected, detail)
+	}
+}
+
+func TestProviderResponseErrorDiagnostics(t *testing.T) {
+	vault := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://localhost/tenant" resource="https://localhost"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ms-request-id", testRequestID)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(accessErrorBody("Forbidden", "The user does not have secrets get permission", "ForbiddenByPolicy")))
+	}))
+	t.Cleanup(vault.Close)
+
+	server := configuredServer(t, map[string]any{
+		"custom_endpoint":      vault.URL,
+		"allow_insecure":       true,
+		"insecure_skip_verify": true,
+		"use_fake_credential":  true,
+		"retry":                map[string]any{"max_retries": 0},
+	})
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	var typeNames []string
+	for typeName := range schemaResp.ResourceSchemas {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	importIDs := map[string]string{"azrandom_password_set": "response-error-test/admin"}
+
+	// Every resource reports the request ID of the response that failed its import
+	for _, typeName := range typeNames {
+		t.Run(typeName, func(t *testing.T) {
+			importID, ok := importIDs[typeName]
+			if !ok {
+				importID = "response-error-test"
+			}
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: typeName,
+				ID:       importID,
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+			if len(resp.Diagnostics) == 0 {
+				t.Fatal("expected the import to fail")
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if !strings.Contains(diagnostic.Detail, "x-ms-request-id: "+testRequestID) ||
+					!strings.Contains(diagnostic.Detail, "HTTP status:     403 Forbidden") {
+					t.Errorf("expected the request ID and status in the diagnostic, got %s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// readWarnings returns the summaries of the warnings of a read.
+func readWarnings(resp *tfprotov6.ReadResourceResponse) []string {
+	var warnings []string
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning {
+			warnings = append(warnings, diagnostic.Summary)
+		}
+	}
+	return warnings
+}
+
+// A secret that was disabled or expired outside of Terraform is reported on refresh, and planned back.
+func TestReadDisabledOrExpiredSecret(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).Unix()
+
+	testCases := map[string]struct {
+		config     map[string]any
+		attributes map[string]any
+		warnings   []string
+		// The attribute whose refreshed value differs from the planned one
+		attribute string
+	}{
+		"disabled": {
+			config:     map[string]any{"name": "secret-status-test"},
+			attributes: map[string]any{"enabled": false},
+			warnings:   []string{"Secret disabled"},
+			attribute:  "enabled",
+		},
+		"expired": {
+			config:     map[string]any{"name": "secret-status-test"},
+			attributes: map[string]any{"enabled": true, "exp": expired},
+			warnings:   []string{"Secret expired"},
+			attribute:  "expiration_date",
+		},
+		"disabled-and-expired": {
+			config:     map[string]any{"name": "secret-status-test"},
+			attributes: map[string]any{"enabled": false, "exp": expired},
+			warnings:   []string{"Secret disabled", "Secret expired"},
+			attribute:  "enabled",
+		},
+		"disabled-in-configuration": {
+			config:     map[string]any{"name": "secret-status-test", "enabled": false},
+			attributes: map[string]any{"enabled": false},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vault := newMemoryVault(t)
+			created := applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_uuid", nil, testCase.config))
+
+			vault.setAttributes("secret-status-test", testCase.attributes)
+			server := vault.configuredServer(t)
+			read := readResourcePrivate(t, server, "azrandom_uuid", created.NewState, created.Private)
+			if warnings := readWarnings(read); !reflect.DeepEqual(warnings, testCase.warnings) {
+				t.Fatalf("expected warnings %v, got %v", testCase.warnings, warnings)
+			}
+			if testCase.attribute == "" {
+				return
+			}
+
+			refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+			planned := plannedStateMap(t, server, "azrandom_uuid",
+				planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, testCase.config))
+			if reflect.DeepEqual(planned[testCase.attribute], refreshed[testCase.attribute]) {
+				t.Errorf("expected %s to be planned back, got %v", testCase.attribute, planned[testCase.attribute])
+			}
+			if planned["version"] != refreshed["version"] {
+				t.Errorf("expected version %v to be kept, got %v", refreshed["version"], planned["version"])
+			}
+		})
+	}
+}
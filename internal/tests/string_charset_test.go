@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+func TestCreateStringCharset(t *testing.T) {
+	testCases := map[string]struct {
+		charset  string
+		length   int64
+		expected string
+	}{
+		"hexadecimal": {
+			charset:  "0123456789abcdef",
+			length:   64,
+			expected: "0123456789abcdef",
+		},
+		"crockford-base32": {
+			charset:  crockfordBase32,
+			length:   26,
+			expected: crockfordBase32,
+		},
+		"duplicates": {
+			charset:  "aab",
+			length:   32,
+			expected: "ab",
+		},
+		"multi-byte": {
+			charset:  "äöü",
+			length:   10,
+			expected: "äöü",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := random.CreateString(random.StringParams{
+				Length: testCase.length,
+				// The character sets are ignored in favor of the charset
+				Upper:      true,
+				MinUpper:   4,
+				Special:    true,
+				MinSpecial: 4,
+				Charset:    testCase.charset,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count := utf8.RuneCount(result); int64(count) != testCase.length {
+				t.Errorf("expected %d characters, got %d in %s", testCase.length, count, result)
+			}
+			for _, character := range string(result) {
+				if !strings.ContainsRune(testCase.expected, character) {
+					t.Errorf("expected only characters of %s, got %q in %s", testCase.expected, character, result)
+				}
+			}
+		})
+	}
+}
+
+// Each distinct character of the charset is equally likely, also when it occurs more than once.
+func TestCreateStringCharsetDistribution(t *testing.T) {
+	testCases := map[string]struct {
+		charset  string
+		distinct string
+	}{
+		"hexadecimal": {
+			charset:  "0123456789abcdef",
+			distinct: "0123456789abcdef",
+		},
+		"duplicates": {
+			charset:  "aaaaaaabbc",
+			distinct: "abc",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			const samples = 100000
+
+			result, err := random.CreateString(random.StringParams{Length: samples, Charset: testCase.charset})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			counts := map[rune]int{}
+			for _, character := range string(result) {
+				counts[character]++
+			}
+
+			// Pearson's chi-squared statistic against the uniform distribution. With at most 15 degrees of
+			// freedom, a uniform generator exceeds 60 with a probability well below one in a million.
+			expected := float64(samples) / float64(len(testCase.distinct))
+			var chiSquared float64
+			for _, character := range testCase.distinct {
+				difference := float64(counts[character]) - expected
+				chiSquared += difference * difference / expected
+			}
+			if chiSquared > 60 {
+				t.Errorf("expected the characters of %s to be uniformly distributed, got %v", testCase.distinct, counts)
+			}
+			if len(counts) != len(testCase.distinct) {
+				t.Errorf("expected only the characters of %s, got %v", testCase.distinct, counts)
+			}
+		})
+	}
+}
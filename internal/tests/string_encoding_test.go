@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+// The generated string is encoded before it is stored, and length is that of the string before encoding.
+func TestResourceStringEncoding(t *testing.T) {
+	testCases := map[string]struct {
+		encoding string
+		length   int
+		decode   func(string) ([]byte, error)
+	}{
+		"plain": {
+			encoding: "plain",
+			length:   16,
+			decode:   func(s string) ([]byte, error) { return []byte(s), nil },
+		},
+		"base64": {
+			encoding: "base64",
+			length:   24,
+			decode:   base64.StdEncoding.DecodeString,
+		},
+		"hex": {
+			encoding: "hex",
+			length:   32,
+			decode:   hex.DecodeString,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]any{"name": "string-encoding-test", "length": 16, "encoding": testCase.encoding}
+
+			vault := newMemoryVault(t)
+			applySucceeded(t, applyResourceChange(t, vault.configuredServer(t), "azrandom_string", nil, config))
+
+			value := vault.value("string-encoding-test")
+			if len(value) != testCase.length {
+				t.Errorf("expected %d characters to be stored, got %s", testCase.length, value)
+			}
+			decoded, err := testCase.decode(value)
+			if err != nil {
+				t.Fatalf("expected a %s value, got %s: %s", testCase.encoding, value, err)
+			}
+			if len(decoded) != 16 {
+				t.Errorf("expected 16 characters before encoding, got %q", decoded)
+			}
+		})
+	}
+}
+
+// Changing the encoding stores a new value, while an imported string takes the configured encoding as it is.
+func TestResourceStringEncodingChanged(t *testing.T) {
+	config := map[string]any{"name": "string-encoding-test", "length": 16}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_string", nil, config))
+	value := vault.value("string-encoding-test")
+
+	config["encoding"] = "hex"
+	server = vault.configuredServer(t)
+	changed := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string",
+		stateMap(t, server, "azrandom_string", created.NewState), created.Private, config))
+	if version := stateMap(t, server, "azrandom_string", changed.NewState)["version"]; version != "2" {
+		t.Errorf("expected the encoded value to be stored as version 2, got %v", version)
+	}
+	if encoded := vault.value("string-encoding-test"); len(encoded) != 32 || encoded == hex.EncodeToString([]byte(value)) {
+		t.Errorf("expected a new hexadecimal value, got %s", encoded)
+	}
+
+	// The encoding of an imported string is null in state
+	imported := stateMap(t, server, "azrandom_string", changed.NewState)
+	delete(imported, "encoding")
+	imported["length"] = 32
+	config["length"] = 32
+	config["encoding"] = "base64"
+	server = vault.configuredServer(t)
+	recorded := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", imported, changed.Private, config))
+	state := stateMap(t, server, "azrandom_string", recorded.NewState)
+	if state["version"] != "2" || state["encoding"] != "base64" {
+		t.Errorf("expected the encoding to be recorded for version 2, got %v for version %v", state["encoding"], state["version"])
+	}
+}
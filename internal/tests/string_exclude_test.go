@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+
+	"terraform-provider-azrandom/internal/provider"
+	"terraform-provider-azrandom/internal/random"
+)
+
+// Excluded characters never appear, also in the characters chosen for the min_* counts.
+func TestCreateStringExcludeCharacters(t *testing.T) {
+	testCases := map[string]random.StringParams{
+		"ambiguous": {
+			Length:            64,
+			Upper:             true,
+			MinUpper:          4,
+			Lower:             true,
+			MinLower:          4,
+			Numeric:           true,
+			MinNumeric:        4,
+			Special:           true,
+			MinSpecial:        4,
+			ExcludeCharacters: "l1IO0",
+		},
+		"override-special": {
+			Length:            32,
+			Special:           true,
+			MinSpecial:        2,
+			Numeric:           true,
+			OverrideSpecial:   "-_.~",
+			ExcludeCharacters: "~.",
+		},
+		"charset": {
+			Length:            32,
+			Charset:           crockfordBase32,
+			ExcludeCharacters: "0O1I",
+		},
+	}
+
+	for name, params := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				result, err := random.CreateString(params)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if int64(len(result)) != params.Length {
+					t.Fatalf("expected %d characters, got %s", params.Length, result)
+				}
+				if strings.ContainsAny(string(result), params.ExcludeCharacters) {
+					t.Fatalf("expected none of %s, got %s", params.ExcludeCharacters, result)
+				}
+				if count := countFunc(string(result), unicode.IsUpper); count < int(params.MinUpper) {
+					t.Fatalf("expected at least %d uppercase characters, got %s", params.MinUpper, result)
+				}
+				if count := countFunc(string(result), unicode.IsDigit); count < int(params.MinNumeric) {
+					t.Fatalf("expected at least %d numeric characters, got %s", params.MinNumeric, result)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateExclusions(t *testing.T) {
+	testCases := map[string]struct {
+		params   random.StringParams
+		expected string
+	}{
+		"nothing-excluded": {
+			params: random.StringParams{Length: 8, Numeric: true, MinNumeric: 1},
+		},
+		"pool-left": {
+			params: random.StringParams{Length: 8, Numeric: true, MinNumeric: 1, ExcludeCharacters: "01"},
+		},
+		"emptied-pool-without-minimum": {
+			params: random.StringParams{Length: 8, Lower: true, Numeric: true, ExcludeCharacters: "0123456789"},
+		},
+		"emptied-pool-with-minimum": {
+			params:   random.StringParams{Length: 8, Lower: true, Numeric: true, MinNumeric: 1, ExcludeCharacters: "0123456789"},
+			expected: "every character that min_numeric requires is excluded",
+		},
+		"emptied-override-special": {
+			params:   random.StringParams{Length: 8, Lower: true, Special: true, MinSpecial: 1, OverrideSpecial: "-_", ExcludeCharacters: "_-"},
+			expected: "every character that min_special requires is excluded",
+		},
+		"all-excluded": {
+			params:   random.StringParams{Length: 8, Numeric: true, ExcludeCharacters: "0123456789"},
+			expected: "every character of the enabled character sets is excluded",
+		},
+		"emptied-charset": {
+			params:   random.StringParams{Length: 8, Charset: "abc", ExcludeCharacters: "cba"},
+			expected: "every character of the charset is excluded",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := random.ValidateExclusions(testCase.params)
+			switch {
+			case testCase.expected == "" && err != nil:
+				t.Errorf("expected no error, got %s", err)
+			case testCase.expected != "" && (err == nil || err.Error() != testCase.expected):
+				t.Errorf("expected error %q, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
+
+// An exclusion that leaves a min_* count without characters fails the plan, including one filled by a preset.
+func TestResourceStringExcludeCharactersPlan(t *testing.T) {
+	testCases := map[string]struct {
+		config      map[string]any
+		expectError bool
+	}{
+		"ambiguous": {
+			config: map[string]any{"name": "string-exclude-test", "length": 16, "min_numeric": 2, "exclude_characters": "l1IO0"},
+		},
+		"emptied-min-numeric": {
+			config:      map[string]any{"name": "string-exclude-test", "length": 16, "min_numeric": 2, "exclude_characters": "0123456789"},
+			expectError: true,
+		},
+		"emptied-preset-min-special": {
+			config:      map[string]any{"name": "string-exclude-test", "preset": "postgres", "exclude_characters": "-_.~"},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := providerserver.NewProtocol6(provider.New("test")())()
+
+			errors := planErrors(planResourceChangeWith(t, server, "azrandom_string", nil, testCase.config))
+			if (len(errors) != 0) != testCase.expectError {
+				t.Fatalf("expected an error to be %t, got %v", testCase.expectError, errors)
+			}
+			if testCase.expectError && errors[0] != "Invalid Attribute Value" {
+				t.Errorf("expected an invalid exclude_characters, got %v", errors)
+			}
+		})
+	}
+}
+
+func countFunc(s string, f func(r rune) bool) int {
+	count := 0
+	for _, r := range s {
+		if f(r) {
+			count++
+		}
+	}
+	return count
+}
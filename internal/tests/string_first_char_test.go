@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+const upperAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// The first character is a letter of an enabled set, while the min_* counts are still met.
+func TestCreateStringFirstCharAlpha(t *testing.T) {
+	testCases := map[string]struct {
+		params  random.StringParams
+		letters string
+	}{
+		"defaults": {
+			params: random.StringParams{
+				Length: 16, Upper: true, Lower: true, Numeric: true, Special: true, FirstCharAlpha: true,
+			},
+			letters: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+		},
+		"lower-only": {
+			params: random.StringParams{
+				Length: 8, Lower: true, Numeric: true, MinNumeric: 4, FirstCharAlpha: true,
+			},
+			letters: "abcdefghijklmnopqrstuvwxyz",
+		},
+		"minimums-take-up-length": {
+			// Only a lowercase letter leaves room for the other counts
+			params: random.StringParams{
+				Length: 4, Upper: true, Lower: true, Numeric: true, MinNumeric: 3, MinLower: 1, FirstCharAlpha: true,
+			},
+			letters: "abcdefghijklmnopqrstuvwxyz",
+		},
+		"excluded": {
+			params: random.StringParams{
+				Length: 12, Upper: true, Numeric: true, MinUpper: 2, FirstCharAlpha: true,
+				ExcludeCharacters: "ABCDEFGHIJKLMNOPQRSTUVWXY",
+			},
+			letters: "Z",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			params := testCase.params
+
+			for i := 0; i < 1000; i++ {
+				result, err := random.CreateString(params)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if int64(len(result)) != params.Length {
+					t.Fatalf("expected %d characters, got %s", params.Length, result)
+				}
+				if !strings.ContainsRune(testCase.letters, rune(result[0])) {
+					t.Fatalf("expected the first character to be one of %s, got %s", testCase.letters, result)
+				}
+				if count := countFunc(string(result), unicode.IsUpper); count < int(params.MinUpper) {
+					t.Fatalf("expected at least %d uppercase characters, got %s", params.MinUpper, result)
+				}
+				if count := countFunc(string(result), unicode.IsLower); count < int(params.MinLower) {
+					t.Fatalf("expected at least %d lowercase characters, got %s", params.MinLower, result)
+				}
+				if count := countFunc(string(result), unicode.IsDigit); count < int(params.MinNumeric) {
+					t.Fatalf("expected at least %d numeric characters, got %s", params.MinNumeric, result)
+				}
+			}
+		})
+	}
+}
+
+// The characters after the first are not constrained to letters.
+func TestCreateStringFirstCharAlphaRest(t *testing.T) {
+	params := random.StringParams{Length: 8, Lower: true, Numeric: true, FirstCharAlpha: true}
+
+	digits := 0
+	for i := 0; i < 1000; i++ {
+		result, err := random.CreateString(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digits += countFunc(string(result[1:]), unicode.IsDigit)
+	}
+	if digits == 0 {
+		t.Errorf("expected digits after the first character")
+	}
+}
+
+func TestValidateFirstCharAlpha(t *testing.T) {
+	testCases := map[string]struct {
+		params      random.StringParams
+		expectError bool
+	}{
+		"disabled": {
+			params: random.StringParams{Length: 8, Numeric: true, FirstCharAlpha: false},
+		},
+		"letters": {
+			params: random.StringParams{Length: 8, Upper: true, Numeric: true, FirstCharAlpha: true},
+		},
+		"no-letters": {
+			params:      random.StringParams{Length: 8, Numeric: true, Special: true, FirstCharAlpha: true},
+			expectError: true,
+		},
+		"letters-excluded": {
+			params:      random.StringParams{Length: 8, Upper: true, Numeric: true, FirstCharAlpha: true, ExcludeCharacters: upperAlphabet},
+			expectError: true,
+		},
+		"minimums-without-letters": {
+			params:      random.StringParams{Length: 4, Upper: true, Numeric: true, MinNumeric: 4, FirstCharAlpha: true},
+			expectError: true,
+		},
+		"minimums-with-letters": {
+			params: random.StringParams{Length: 4, Upper: true, Numeric: true, MinNumeric: 3, MinUpper: 1, FirstCharAlpha: true},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := random.ValidateFirstCharAlpha(testCase.params); (err != nil) != testCase.expectError {
+				t.Errorf("expected an error to be %t, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
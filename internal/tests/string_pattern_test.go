@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+
+	"terraform-provider-azrandom/internal/provider"
+	"terraform-provider-azrandom/internal/random"
+)
+
+func TestParseStringPattern(t *testing.T) {
+	testCases := map[string]struct {
+		pattern  string
+		length   int64
+		size     int64
+		expected string
+	}{
+		"classes":                {pattern: "aA9#", length: 4, size: 4},
+		"literals":               {pattern: "AAA-9999", length: 8, size: 8},
+		"repetition":             {pattern: "A{3}-9{4}", length: 8, size: 8},
+		"escaped-class":          {pattern: `\9a`, length: 2, size: 2},
+		"escaped-repetition":     {pattern: `\{9\}`, length: 3, size: 3},
+		"repeated-escape":        {pattern: `\\{3}`, length: 3, size: 3},
+		"multi-byte-literal":     {pattern: "ä{2}9", length: 3, size: 5},
+		"empty":                  {pattern: "", expected: "the pattern is empty"},
+		"trailing-backslash":     {pattern: `9\`, expected: `the pattern ends with an unescaped \`},
+		"unclosed-repetition":    {pattern: "9{4", expected: "the { at position 2 is not closed"},
+		"unopened-repetition":    {pattern: "9}", expected: "the } at position 2 is not opened, escape it to use it as a character"},
+		"leading-repetition":     {pattern: "{4}9", expected: "the repetition at position 1 does not follow a character, escape { to use it as a character"},
+		"double-repetition":      {pattern: "9{2}{3}", expected: "the repetition at position 5 does not follow a character, escape { to use it as a character"},
+		"zero-repetition":        {pattern: "9{0}", expected: "the repetition {0} at position 2 must hold a number from 1 to 1048576"},
+		"non-numeric-repetition": {pattern: "9{n}", expected: "the repetition {n} at position 2 must hold a number from 1 to 1048576"},
+		"empty-repetition":       {pattern: "9{}", expected: "the repetition {} at position 2 must hold a number from 1 to 1048576"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			pattern, err := random.ParseStringPattern(testCase.pattern)
+			if testCase.expected != "" {
+				if err == nil || err.Error() != testCase.expected {
+					t.Fatalf("expected error %q, got %v", testCase.expected, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected %q to be valid, got %s", testCase.pattern, err)
+			}
+
+			if pattern.Length() != testCase.length {
+				t.Errorf("expected a length of %d, got %d", testCase.length, pattern.Length())
+			}
+			if pattern.Size() != testCase.size {
+				t.Errorf("expected a size of %d, got %d", testCase.size, pattern.Size())
+			}
+		})
+	}
+}
+
+// Each position of a pattern is filled from its own class, and literals are kept as they are.
+func TestCreateStringPattern(t *testing.T) {
+	special := regexp.QuoteMeta("!@#$%&*()-_=+[]{}<>:?")
+
+	testCases := map[string]struct {
+		pattern  string
+		expected *regexp.Regexp
+	}{
+		"license-plate": {
+			pattern:  "AAA-9999",
+			expected: regexp.MustCompile(`^[A-Z]{3}-[0-9]{4}$`),
+		},
+		"repetition": {
+			pattern:  "a{4}A{4}9{4}#{4}",
+			expected: regexp.MustCompile(`^[a-z]{4}[A-Z]{4}[0-9]{4}[` + special + `]{4}$`),
+		},
+		"vendor-key": {
+			pattern:  `sk_live_9{2}\a{2}`,
+			expected: regexp.MustCompile(`^sk_live_[0-9]{2}aa$`),
+		},
+		"multi-byte-literal": {
+			pattern:  "ä-a{3}",
+			expected: regexp.MustCompile(`^ä-[a-z]{3}$`),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				result, err := random.CreateString(random.StringParams{
+					// The character sets are ignored in favor of the pattern
+					Length:     64,
+					Upper:      true,
+					MinUpper:   4,
+					Pattern:    testCase.pattern,
+					Charset:    "x",
+					MinSpecial: 4,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !testCase.expected.Match(result) {
+					t.Fatalf("expected %s to match %s", result, testCase.expected)
+				}
+			}
+		})
+	}
+}
+
+// Every character of a class is chosen for a position of the pattern.
+func TestCreateStringPatternCoverage(t *testing.T) {
+	seen := map[rune]bool{}
+	for i := 0; i < 200; i++ {
+		result, err := random.CreateString(random.StringParams{Pattern: "9{10}"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, character := range string(result) {
+			seen[character] = true
+		}
+	}
+
+	for _, digit := range "0123456789" {
+		if !seen[digit] {
+			t.Errorf("expected %q to be chosen, got %v", digit, seen)
+		}
+	}
+}
+
+// The length of a pattern is planned, and patterns generating more than a secret can hold fail the plan.
+func TestResourceStringPatternPlan(t *testing.T) {
+	testCases := map[string]struct {
+		pattern     string
+		length      float64
+		expectError bool
+	}{
+		"license-plate": {
+			pattern: "AAA-9{4}",
+			length:  8,
+		},
+		"largest": {
+			pattern: "a{25600}",
+			length:  25600,
+		},
+		"too-large": {
+			pattern:     "a{25601}",
+			expectError: true,
+		},
+		"too-large-multi-byte": {
+			pattern:     "ä{12801}",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := providerserver.NewProtocol6(provider.New("test")())()
+			config := map[string]any{"name": "string-pattern-test", "pattern": testCase.pattern}
+
+			resp := planResourceChangeWith(t, server, "azrandom_string", nil, config)
+			errors := planErrors(resp)
+			if (len(errors) != 0) != testCase.expectError {
+				t.Fatalf("expected an error to be %t, got %v", testCase.expectError, errors)
+			}
+			if testCase.expectError {
+				if errors[0] != "Invalid Attribute Value" {
+					t.Errorf("expected an invalid pattern, got %v", errors)
+				}
+				return
+			}
+
+			planned := plannedStateMap(t, server, "azrandom_string", resp)
+			if planned["length"] != testCase.length {
+				t.Errorf("expected length to be %v, got %v", testCase.length, planned["length"])
+			}
+		})
+	}
+}
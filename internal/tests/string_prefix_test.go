@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+
+	"terraform-provider-azrandom/internal/provider"
+	"terraform-provider-azrandom/internal/random"
+)
+
+// The prefix and suffix wrap the generated characters, which alone make up the length and the min_* counts.
+func TestCreateStringPrefixSuffix(t *testing.T) {
+	testCases := map[string]random.StringParams{
+		"min-upper": {
+			Length:   8,
+			Upper:    true,
+			MinUpper: 8,
+			Lower:    true,
+			Prefix:   "svc-",
+			Suffix:   "-ro",
+		},
+		"prefix-only": {
+			Length:  16,
+			Numeric: true,
+			Prefix:  "user",
+		},
+		"charset": {
+			Length:  10,
+			Charset: "äöü",
+			Suffix:  "-ß",
+		},
+		"pattern": {
+			Pattern: "AAA-9{4}",
+			Prefix:  "plate:",
+		},
+	}
+
+	for name, params := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := random.CreateString(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			value := string(result)
+			if !strings.HasPrefix(value, params.Prefix) || !strings.HasSuffix(value, params.Suffix) {
+				t.Fatalf("expected %s between %q and %q", value, params.Prefix, params.Suffix)
+			}
+			generated := strings.TrimSuffix(strings.TrimPrefix(value, params.Prefix), params.Suffix)
+
+			length := params.Length
+			if params.Pattern != "" {
+				length = 8
+			}
+			if count := utf8.RuneCountInString(generated); int64(count) != length {
+				t.Errorf("expected %d generated characters, got %d in %s", length, count, value)
+			}
+			if count := countFunc(generated, unicode.IsUpper); count < int(params.MinUpper) {
+				t.Errorf("expected at least %d uppercase generated characters, got %s", params.MinUpper, value)
+			}
+		})
+	}
+}
+
+// The prefix and suffix are stored with the generated characters, encoded along with them, and changing them
+// generates a new value.
+func TestResourceStringPrefixSuffix(t *testing.T) {
+	config := map[string]any{
+		"name":     "string-prefix-test",
+		"length":   16,
+		"prefix":   "svc-",
+		"suffix":   "-ro",
+		"encoding": "base64",
+	}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_string", nil, config))
+	decoded, err := base64.StdEncoding.DecodeString(vault.value("string-prefix-test"))
+	if err != nil {
+		t.Fatalf("expected a base64 value, got %s", vault.value("string-prefix-test"))
+	}
+	value := string(decoded)
+	if !strings.HasPrefix(value, "svc-") || !strings.HasSuffix(value, "-ro") || len(value) != len("svc-")+16+len("-ro") {
+		t.Fatalf("expected 16 characters between svc- and -ro, got %s", value)
+	}
+
+	config["prefix"] = "app-"
+	server = vault.configuredServer(t)
+	state := stateMap(t, server, "azrandom_string", created.NewState)
+	planned := plannedStateMap(t, server, "azrandom_string",
+		planResourceChangePrivate(t, server, "azrandom_string", state, created.Private, config))
+	if planned["version"] != unknownValue {
+		t.Errorf("expected a new version to be planned, got %v", planned["version"])
+	}
+
+	server = vault.configuredServer(t)
+	applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", state, created.Private, config))
+	decoded, _ = base64.StdEncoding.DecodeString(vault.value("string-prefix-test"))
+	if changed := string(decoded); !strings.HasPrefix(changed, "app-") || strings.TrimPrefix(changed, "app-") == strings.TrimPrefix(value, "svc-") {
+		t.Errorf("expected a new value after app-, got %s", changed)
+	}
+}
+
+// Strings that, with their prefix, suffix and encoding, may not fit in a secret fail the plan.
+func TestResourceStringValueSizePlan(t *testing.T) {
+	testCases := map[string]struct {
+		config      map[string]any
+		expectError bool
+	}{
+		"largest": {
+			config: map[string]any{"length": 25600},
+		},
+		"largest-with-prefix": {
+			config: map[string]any{"length": 25596, "prefix": "svc-"},
+		},
+		"too-large-with-prefix": {
+			config:      map[string]any{"length": 25600, "prefix": "svc-"},
+			expectError: true,
+		},
+		"largest-base64": {
+			config: map[string]any{"length": 19200, "encoding": "base64"},
+		},
+		"too-large-base64": {
+			config:      map[string]any{"length": 19201, "encoding": "base64"},
+			expectError: true,
+		},
+		"too-large-hex": {
+			config:      map[string]any{"length": 12801, "encoding": "hex"},
+			expectError: true,
+		},
+		"too-large-multi-byte-charset": {
+			config:      map[string]any{"length": 12801, "charset": "aä"},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := providerserver.NewProtocol6(provider.New("test")())()
+			testCase.config["name"] = "string-size-test"
+
+			errors := planErrors(planResourceChangeWith(t, server, "azrandom_string", nil, testCase.config))
+			if (len(errors) != 0) != testCase.expectError {
+				t.Fatalf("expected an error to be %t, got %v", testCase.expectError, errors)
+			}
+			if testCase.expectError && errors[0] != "Invalid Attribute Combination" {
+				t.Errorf("expected the string not to fit in a secret, got %v", errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+// stringPolicy describes the documented password policy of a target system.
+type stringPolicy struct {
+	minLength int
+	maxLength int
+	// minCategories is the number of categories out of uppercase, lowercase, digits and other characters
+	// that must be present
+	minCategories int
+	// minPerCategory is the number of characters of each category that must be present
+	minPerCategory int
+	// allowed returns whether a non-alphanumeric character may be used
+	allowed func(r rune) bool
+}
+
+func TestStringPresets(t *testing.T) {
+	policies := map[string]stringPolicy{
+		// https://learn.microsoft.com/sql/relational-databases/security/password-policy
+		"azure_sql": {
+			minLength:     8,
+			maxLength:     128,
+			minCategories: 3,
+			allowed: func(r rune) bool {
+				return !strings.ContainsRune(`;'"{}=@&`, r)
+			},
+		},
+		// https://learn.microsoft.com/windows/security/threat-protection/security-policy-settings/password-must-meet-complexity-requirements
+		"active_directory": {
+			minLength:     7,
+			maxLength:     127,
+			minCategories: 3,
+			allowed: func(r rune) bool {
+				return !strings.ContainsRune(`"'\`, r)
+			},
+		},
+		// https://learn.microsoft.com/azure/postgresql/flexible-server/quickstart-create-server-portal
+		// Only unreserved characters (RFC 3986), so that passwords can be used in connection URIs
+		"postgres": {
+			minLength:     8,
+			maxLength:     128,
+			minCategories: 3,
+			allowed: func(r rune) bool {
+				return strings.ContainsRune("-_.~", r)
+			},
+		},
+		"generic_strong": {
+			minLength:      32,
+			maxLength:      128,
+			minCategories:  4,
+			minPerCategory: 2,
+			allowed: func(r rune) bool {
+				return r < unicode.MaxASCII && unicode.IsPrint(r) && r != ' '
+			},
+		},
+	}
+
+	// The naming presets are checked by TestStringNamingPresets
+	if len(policies)+len(random.StringNamingRules) != len(random.StringPresets) {
+		t.Fatalf("expected a policy for each of the presets %v", random.StringPresetNames())
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			preset, ok := random.StringPresets[name]
+			if !ok {
+				t.Fatalf("expected preset %s to exist", name)
+			}
+
+			for i := 0; i < 200; i++ {
+				result, err := random.CreateString(preset)
+				if err != nil {
+					t.Fatal(err)
+				}
+				value := string(result)
+
+				if len(value) < policy.minLength || len(value) > policy.maxLength {
+					t.Fatalf("expected %q to have a length between %d and %d", value, policy.minLength, policy.maxLength)
+				}
+
+				counts := map[string]int{}
+				for _, r := range value {
+					switch {
+					case unicode.IsUpper(r):
+						counts["upper"]++
+					case unicode.IsLower(r):
+						counts["lower"]++
+					case unicode.IsDigit(r):
+						counts["numeric"]++
+					default:
+						counts["special"]++
+						if !policy.allowed(r) {
+							t.Fatalf("expected %q not to contain %q", value, r)
+						}
+					}
+				}
+
+				if len(counts) < policy.minCategories {
+					t.Fatalf("expected %q to contain at least %d categories of characters", value, policy.minCategories)
+				}
+				for category, count := range counts {
+					if count < policy.minPerCategory {
+						t.Fatalf("expected %q to contain at least %d %s characters", value, policy.minPerCategory, category)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStringNamingPresets(t *testing.T) {
+	patterns := map[string]*regexp.Regexp{
+		// https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules#microsoftstorage
+		"storage_account": regexp.MustCompile(`^[a-z0-9]{3,24}$`),
+		// https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules#microsoftcontainerregistry
+		"container_registry": regexp.MustCompile(`^[a-zA-Z0-9]{5,50}$`),
+		// https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules#microsoftnetwork
+		"dns_label": regexp.MustCompile(`^[a-z][a-z0-9-]{1,61}[a-z0-9]$`),
+	}
+
+	if len(patterns) != len(random.StringNamingRules) {
+		t.Fatalf("expected a pattern for each of the naming presets")
+	}
+
+	for name, pattern := range patterns {
+		t.Run(name, func(t *testing.T) {
+			preset, ok := random.StringPresets[name]
+			if !ok {
+				t.Fatalf("expected preset %s to exist", name)
+			}
+			if err := random.StringNamingRules[name].Check(preset); err != nil {
+				t.Fatalf("expected the preset to satisfy its own naming rule, got %s", err)
+			}
+
+			for i := 0; i < 1000; i++ {
+				result, err := random.CreateString(preset)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !pattern.Match(result) {
+					t.Fatalf("expected %s to match %s", result, pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestStringNamingRuleCheck(t *testing.T) {
+	storageAccount := random.StringPresets["storage_account"]
+	with := func(change func(params *random.StringParams)) random.StringParams {
+		params := storageAccount
+		change(&params)
+		return params
+	}
+
+	testCases := map[string]struct {
+		preset      string
+		params      random.StringParams
+		expectError bool
+	}{
+		"preset": {
+			preset: "storage_account",
+			params: storageAccount,
+		},
+		"shorter": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) { params.Length = 8 }),
+		},
+		"numeric-disabled": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) { params.Numeric = false }),
+		},
+		"too-long": {
+			preset:      "storage_account",
+			params:      with(func(params *random.StringParams) { params.Length = 25 }),
+			expectError: true,
+		},
+		"upper": {
+			preset:      "storage_account",
+			params:      with(func(params *random.StringParams) { params.Upper = true }),
+			expectError: true,
+		},
+		"min-special": {
+			preset:      "storage_account",
+			params:      with(func(params *random.StringParams) { params.MinSpecial = 1 }),
+			expectError: true,
+		},
+		"special-excluded": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) {
+				params.Special = true
+				params.OverrideSpecial = "-"
+				params.ExcludeCharacters = "-"
+			}),
+		},
+		"upper-container-registry": {
+			preset: "container_registry",
+			params: random.StringParams{Length: 24, Upper: true, Lower: true, Numeric: true},
+		},
+		"prefix": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) {
+				params.Length = 22
+				params.Prefix = "st"
+			}),
+		},
+		"prefix-too-long": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) {
+				params.Prefix = "st"
+			}),
+			expectError: true,
+		},
+		"suffix-not-allowed": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) {
+				params.Length = 20
+				params.Suffix = "-dev"
+			}),
+			expectError: true,
+		},
+		"affixes-fill-name": {
+			preset: "storage_account",
+			params: with(func(params *random.StringParams) {
+				params.Prefix = strings.Repeat("a", 12)
+				params.Suffix = strings.Repeat("b", 12)
+			}),
+			expectError: true,
+		},
+		"dns-label-prefix-letter": {
+			preset: "dns_label",
+			params: random.StringParams{Length: 16, Lower: true, Numeric: true, Prefix: "web"},
+		},
+		"dns-label-prefix-digit": {
+			preset:      "dns_label",
+			params:      random.StringParams{Length: 16, Lower: true, Numeric: true, FirstCharAlpha: true, Prefix: "1web"},
+			expectError: true,
+		},
+		"dns-label-without-first-char-alpha": {
+			preset:      "dns_label",
+			params:      random.StringParams{Length: 16, Lower: true, Numeric: true},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := random.StringNamingRules[testCase.preset].Check(testCase.params)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("expected an error to be %t, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
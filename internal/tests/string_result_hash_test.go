@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// result_hash is the SHA-256 of the stored value, kept when only metadata changes and changed with the value.
+func TestResourceStringResultHash(t *testing.T) {
+	config := map[string]any{"name": "string-result-hash-test", "length": 16}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_string", nil, config))
+	state := stateMap(t, server, "azrandom_string", created.NewState)
+	if expected := sha256Hex(vault.value("string-result-hash-test")); state["result_hash"] != expected {
+		t.Fatalf("expected result_hash %s, got %v", expected, state["result_hash"])
+	}
+
+	// Changing the tags keeps the value, and its hash is known in the plan
+	config["tags"] = map[string]any{"team": "platform"}
+	server = vault.configuredServer(t)
+	planned := plannedStateMap(t, server, "azrandom_string",
+		planResourceChangePrivate(t, server, "azrandom_string", state, created.Private, config))
+	if planned["result_hash"] != state["result_hash"] {
+		t.Errorf("expected result_hash %v to be kept, got %v", state["result_hash"], planned["result_hash"])
+	}
+
+	// Changing the length generates a new value with a new hash
+	config["length"] = 20
+	server = vault.configuredServer(t)
+	planned = plannedStateMap(t, server, "azrandom_string",
+		planResourceChangePrivate(t, server, "azrandom_string", state, created.Private, config))
+	if planned["result_hash"] != unknownValue {
+		t.Errorf("expected result_hash to be unknown, got %v", planned["result_hash"])
+	}
+	server = vault.configuredServer(t)
+	updated := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_string", state, created.Private, config))
+	hash := stateMap(t, server, "azrandom_string", updated.NewState)["result_hash"]
+	if hash == state["result_hash"] || hash != sha256Hex(vault.value("string-result-hash-test")) {
+		t.Errorf("expected the result_hash of the new value, got %v", hash)
+	}
+}
+
+// Read updates result_hash when the value is changed outside of Terraform, and fills it for state written
+// before it existed.
+func TestResourceStringResultHashRead(t *testing.T) {
+	config := map[string]any{"name": "string-result-hash-test", "length": 16}
+
+	vault := newMemoryVault(t)
+	server := vault.configuredServer(t)
+	created := applySucceeded(t, applyResourceChange(t, server, "azrandom_string", nil, config))
+	state := stateMap(t, server, "azrandom_string", created.NewState)
+
+	hash := state["result_hash"]
+	delete(state, "result_hash")
+	server = vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_string", resourceDynamicValue(t, server, "azrandom_string", state), created.Private)
+	if filled := stateMap(t, server, "azrandom_string", read.NewState)["result_hash"]; filled != hash {
+		t.Errorf("expected result_hash %v to be filled, got %v", hash, filled)
+	}
+
+	// The value is restored from a backup, so the version holds another value
+	vault.setValue("string-result-hash-test", "restored-value")
+	server = vault.configuredServer(t)
+	read = readResourcePrivate(t, server, "azrandom_string", created.NewState, created.Private)
+	if changed := stateMap(t, server, "azrandom_string", read.NewState)["result_hash"]; changed != sha256Hex("restored-value") {
+		t.Errorf("expected the result_hash of the restored value, got %v", changed)
+	}
+}
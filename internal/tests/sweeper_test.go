@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestMain runs the sweepers instead of the tests when go test is given the -sweep flag, e.g.
+// `go test ./internal/tests -v -sweep=all`.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("azrandom_secrets", &resource.Sweeper{
+		Name: "azrandom_secrets",
+		F:    sweepSecrets,
+	})
+}
+
+// sweepPrefix returns the name prefix of the secrets to sweep. Without an AZRANDOM_TEST_NAME_PREFIX override,
+// the secrets of every test run are swept.
+func sweepPrefix() string {
+	if prefix := os.Getenv(testNamePrefixEnvVar); prefix != "" {
+		return prefix
+	}
+	return testNamePrefixRoot
+}
+
+// newTestClient returns a client of the test vault, authenticating the same way as providerConfig. The
+// vault may be overridden with AZRANDOM_VAULT_URL, unless the tests run against an emulator.
+func newTestClient() (*azsecrets.Client, error) {
+	if testEmulatorUrl != "" {
+		transport, err := azrandom.InsecureTransport(nil)
+		if err != nil {
+			return nil, err
+		}
+		return azrandom.CreateClient(testEmulatorUrl, azrandom.FakeCredential{}, azrandom.ClientOptions{
+			Transport:                            transport,
+			DisableChallengeResourceVerification: true,
+		})
+	}
+
+	vaultUrl := testVaultUrl
+	if value := os.Getenv("AZRANDOM_VAULT_URL"); value != "" {
+		vaultUrl = value
+	}
+
+	credential, err := azrandom.CreateCredential(azrandom.CredentialOptions{
+		DisabledCredentials: azidentity.DisabledCredentials{
+			ManagedIdentityCredential:   true,
+			WorkloadIdentityCredential:  true,
+			AzureDeveloperCLICredential: true,
+			EnvironmentCredential:       true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return azrandom.CreateClient(vaultUrl, credential, azrandom.ClientOptions{})
+}
+
+// sweepSecrets deletes and purges the secrets that acceptance tests left behind in the test vault,
+// which are recognised by their name prefix.
+func sweepSecrets(_ string) error {
+	ctx := context.Background()
+
+	client, err := newTestClient()
+	if err != nil {
+		return err
+	}
+
+	prefix := sweepPrefix()
+	var errs []error
+
+	secrets := client.NewListSecretPropertiesPager(nil)
+	for secrets.More() {
+		page, err := secrets.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, secret := range page.Value {
+			if secret.ID == nil || !strings.HasPrefix(secret.ID.Name(), prefix) {
+				continue
+			}
+			if _, err := azrandom.DeleteSecret(ctx, client, secret.ID.Name()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// Purge, so that the names can be reused right away. Secrets deleted above may take a moment to
+	// show up as deleted, and are purged by the next sweep.
+	deleted := client.NewListDeletedSecretPropertiesPager(nil)
+	for deleted.More() {
+		page, err := deleted.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, secret := range page.Value {
+			if secret.ID == nil || !strings.HasPrefix(secret.ID.Name(), prefix) {
+				continue
+			}
+			if _, err := client.PurgeDeletedSecret(ctx, secret.ID.Name(), nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
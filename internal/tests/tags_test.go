@@ -0,0 +1,329 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testTags returns a map of strings, or a null map when tags is nil.
+func testTags(tags map[string]string) types.Map {
+	if tags == nil {
+		return types.MapNull(types.StringType)
+	}
+	elements := map[string]string{}
+	for k, v := range tags {
+		elements[k] = v
+	}
+	result, _ := types.MapValueFrom(context.Background(), types.StringType, elements)
+	return result
+}
+
+func TestMergeTags(t *testing.T) {
+	testCases := map[string]struct {
+		defaultTags types.Map
+		tags        types.Map
+		expected    types.Map
+	}{
+		"both-null": {
+			defaultTags: testTags(nil),
+			tags:        testTags(nil),
+			expected:    testTags(nil),
+		},
+		"defaults-only": {
+			defaultTags: testTags(map[string]string{"team": "platform"}),
+			tags:        testTags(nil),
+			expected:    testTags(map[string]string{"team": "platform"}),
+		},
+		"tags-only": {
+			defaultTags: testTags(nil),
+			tags:        testTags(map[string]string{"app": "api"}),
+			expected:    testTags(map[string]string{"app": "api"}),
+		},
+		"merged": {
+			defaultTags: testTags(map[string]string{"team": "platform"}),
+			tags:        testTags(map[string]string{"app": "api"}),
+			expected:    testTags(map[string]string{"team": "platform", "app": "api"}),
+		},
+		"override": {
+			defaultTags: testTags(map[string]string{"team": "platform", "env": "prod"}),
+			tags:        testTags(map[string]string{"team": "payments"}),
+			expected:    testTags(map[string]string{"team": "payments", "env": "prod"}),
+		},
+		"empty-tags": {
+			defaultTags: testTags(nil),
+			tags:        testTags(map[string]string{}),
+			expected:    testTags(map[string]string{}),
+		},
+		"unknown-defaults": {
+			defaultTags: types.MapUnknown(types.StringType),
+			tags:        testTags(map[string]string{"app": "api"}),
+			expected:    types.MapUnknown(types.StringType),
+		},
+		"unknown-tags": {
+			defaultTags: testTags(map[string]string{"team": "platform"}),
+			tags:        types.MapUnknown(types.StringType),
+			expected:    types.MapUnknown(types.StringType),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			merged, diags := utils.MergeTags(testCase.defaultTags, testCase.tags)
+			if diags.HasError() {
+				t.Fatalf("expected no error, got %v", diags)
+			}
+			if !merged.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, merged)
+			}
+		})
+	}
+}
+
+func TestRemoveDefaultTags(t *testing.T) {
+	defaultTags := testTags(map[string]string{"team": "platform", "env": "prod"})
+
+	testCases := map[string]struct {
+		tagsAll  types.Map
+		current  types.Map
+		expected types.Map
+	}{
+		"defaults-removed": {
+			tagsAll:  testTags(map[string]string{"team": "platform", "env": "prod", "app": "api"}),
+			current:  testTags(map[string]string{"app": "api"}),
+			expected: testTags(map[string]string{"app": "api"}),
+		},
+		"override-kept": {
+			tagsAll:  testTags(map[string]string{"team": "payments", "env": "prod"}),
+			current:  testTags(map[string]string{"team": "payments"}),
+			expected: testTags(map[string]string{"team": "payments"}),
+		},
+		"same-as-default-kept": {
+			tagsAll:  testTags(map[string]string{"team": "platform", "env": "prod"}),
+			current:  testTags(map[string]string{"env": "prod"}),
+			expected: testTags(map[string]string{"env": "prod"}),
+		},
+		"drift-reported": {
+			tagsAll:  testTags(map[string]string{"team": "platform", "env": "prod", "owner": "someone"}),
+			current:  testTags(map[string]string{}),
+			expected: testTags(map[string]string{"owner": "someone"}),
+		},
+		"unmanaged": {
+			tagsAll:  testTags(map[string]string{"team": "platform", "owner": "someone"}),
+			current:  testTags(nil),
+			expected: testTags(nil),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tags, diags := utils.RemoveDefaultTags(testCase.tagsAll, defaultTags, testCase.current)
+			if diags.HasError() {
+				t.Fatalf("expected no error, got %v", diags)
+			}
+			if !tags.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, tags)
+			}
+		})
+	}
+}
+
+func TestAccResourceUUIDDefaultTags(t *testing.T) {
+	t.Parallel()
+	testAccSkipEmulator(t)
+
+	var version string
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		config := func(team string) string {
+			return fmt.Sprintf(`
+provider "azrandom" {
+	vault_url 							   = %[1]q
+	disable_managed_identity_credential    = true
+	disable_workload_identity_credential   = true
+	disable_azure_cli_credential           = false
+	disable_azure_developer_cli_credential = true
+	disable_environment_credential         = true
+	default_tags = {
+		team = %[3]q
+		env  = "test"
+	}
+}
+
+resource "azrandom_uuid" "this" {
+	name = %[2]q
+	tags = {
+		env = "acceptance"
+	}
+}`, testVaultUrl, protocol.testName("uuid-default-tags-test"), team)
+		}
+
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: config("platform"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags.%", "1"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags_all.%", "2"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags_all.team", "platform"),
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags_all.env", "acceptance"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							version = value
+							return nil
+						}),
+					),
+				},
+				{
+					Config: config("payments"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags_all.team", "payments"),
+						resource.TestCheckResourceAttrWith("azrandom_uuid.this", "version", func(value string) error {
+							if value != version {
+								return fmt.Errorf("expected version %s to be kept when changing the default tags, got %s", version, value)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+// testAccCheckSecretTags checks that the given secret holds each of the given tags.
+func testAccCheckSecretTags(name string, expected map[string]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		client, err := newTestClient()
+		if err != nil {
+			return err
+		}
+
+		_, properties, err := azrandom.GetSecret(context.Background(), client, name, "")
+		if err != nil {
+			return err
+		}
+		for k, v := range expected {
+			if properties.Tags[k] != v {
+				return fmt.Errorf("expected tag %s of secret %s to be %q, got %q", k, name, v, properties.Tags[k])
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccResourceUUIDWriteMetadataTags(t *testing.T) {
+	testAccSkipEmulator(t)
+	t.Setenv("TF_WORKSPACE", "acceptance")
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "azrandom" {
+	vault_url 							   = %[1]q
+	disable_managed_identity_credential    = true
+	disable_workload_identity_credential   = true
+	disable_azure_cli_credential           = false
+	disable_azure_developer_cli_credential = true
+	disable_environment_credential         = true
+	write_metadata_tags                    = true
+}
+
+resource "azrandom_uuid" "this" {
+	name = %[2]q
+	tags = {
+		app = "api"
+	}
+}`, testVaultUrl, protocol.testName("uuid-write-metadata-tags-test")),
+					Check: resource.ComposeTestCheckFunc(
+						// The metadata tags do not show up in the tags of the resource
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "tags_all.%", "1"),
+						testAccCheckSecretTags(protocol.testName("uuid-write-metadata-tags-test"), map[string]string{
+							"app":                       "api",
+							"azrandom:managed-by":       "terraform-provider-azrandom",
+							"azrandom:workspace":        "acceptance",
+							"azrandom:provider-version": "test",
+						}),
+					),
+				},
+			},
+		}
+	})
+}
+
+// Updating the metadata of a resource pinned to an older version keeps the unmanaged tags of that version,
+// rather than those of the latest version.
+func TestUpdateMetadataPinnedVersionTags(t *testing.T) {
+	var patched struct {
+		Tags map[string]string `json:"tags"`
+	}
+	vault := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://localhost/tenant" resource="https://localhost"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		version, owner := "2", "latest"
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/1") {
+			version, owner = "1", "pinned"
+		}
+		if r.Method == http.MethodPatch {
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			owner = patched.Tags["owner"]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":         fakeVaultUrl + "secrets/pinned-tags-test/" + version,
+			"value":      "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4",
+			"attributes": map[string]any{"enabled": true},
+			"tags":       map[string]string{"owner": owner},
+		})
+	}))
+	t.Cleanup(vault.Close)
+
+	server := configuredServer(t, map[string]any{
+		"custom_endpoint":      vault.URL,
+		"allow_insecure":       true,
+		"insecure_skip_verify": true,
+		"use_fake_credential":  true,
+		"retry":                map[string]any{"max_retries": 0},
+	})
+	imported, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_uuid",
+		ID:       "pinned-tags-test:1",
+	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(imported.Diagnostics) > 0 || len(imported.ImportedResources) != 1 {
+		t.Fatalf("expected the secret to be imported, got %v", imported.Diagnostics)
+	}
+	read := readResourcePrivate(t, server, "azrandom_uuid", imported.ImportedResources[0].State, imported.ImportedResources[0].Private)
+	state := stateMap(t, server, "azrandom_uuid", read.NewState)
+
+	config := map[string]any{"name": "pinned-tags-test", "description": "pinned"}
+	applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid", state, read.Private, config))
+	if version := stateMap(t, server, "azrandom_uuid", applied.NewState)["version"]; version != "1" {
+		t.Fatalf("expected the pinned version 1 to be kept, got %v", version)
+	}
+	if patched.Tags["owner"] != "pinned" {
+		t.Errorf("expected the unmanaged tag of the pinned version to be kept, got %v", patched.Tags)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// throttlingTransport throttles the first throttled requests with the given Retry-After headers, and
+// answers the requests after that with respond.
+func throttlingTransport(throttled int32, header http.Header, requests *atomic.Int32, respond func(req *http.Request) (*http.Response, error)) *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		if requests.Add(1) <= throttled {
+			return fakeResponse(req, http.StatusTooManyRequests, header.Clone(), `{"error": {"code": "Throttled", "message": "fake"}}`), nil
+		}
+		return respond(req)
+	}}
+}
+
+// newRetryingFakeClient returns a client of fakeVaultUrl whose requests are answered by transport, created
+// like the provider creates its clients, so that requests are retried by the retry policy of azcore.
+func newRetryingFakeClient(t *testing.T, transport *fakeTransport, retry policy.RetryOptions) *azsecrets.Client {
+	t.Helper()
+
+	client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{Transport: transport, Retry: retry})
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return client
+}
+
+func TestThrottledRetries(t *testing.T) {
+	secret := func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+	}
+	notFound := func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusNotFound, nil, `{"error": {"code": "NotFound", "message": "fake"}}`), nil
+	}
+
+	testCases := map[string]struct {
+		respond   func(req *http.Request) (*http.Response, error)
+		operation func(ctx context.Context, client *azsecrets.Client) error
+	}{
+		"create": {
+			// The deleted secret lookup is not found, so that the throttled requests are the ones setting the secret
+			respond: func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					return notFound(req)
+				}
+				return secret(req)
+			},
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.CreateSecret(ctx, client, "test", "value", azrandom.SecretProperties{})
+				return err
+			},
+		},
+		"update": {
+			respond: secret,
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.UpdateSecret(ctx, client, "test", "value", azrandom.SecretProperties{})
+				return err
+			},
+		},
+		"update-properties": {
+			respond: secret,
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, err := azrandom.UpdateSecretProperties(ctx, client, "test", "1", azrandom.SecretProperties{})
+				return err
+			},
+		},
+		"recover": {
+			// The deleted secret, its recovery and the recovered secret are all found
+			respond: secret,
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				recovered, err := azrandom.RecoverDeletedSecret(ctx, client, "test")
+				if err == nil && !recovered {
+					return errors.New("expected the secret to be recovered")
+				}
+				return err
+			},
+		},
+		"get": {
+			respond: secret,
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.GetSecret(ctx, client, "test", "")
+				return err
+			},
+		},
+		"delete": {
+			respond: secret,
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, err := azrandom.DeleteSecret(ctx, client, "test")
+				return err
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Retry-After", "0")
+
+			var requests atomic.Int32
+			transport := throttlingTransport(2, header, &requests, testCase.respond)
+			client := newRetryingFakeClient(t, transport, policy.RetryOptions{RetryDelay: time.Millisecond})
+
+			if err := testCase.operation(context.Background(), client); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func TestThrottledRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After-Ms", "200")
+
+	var requests atomic.Int32
+	transport := throttlingTransport(2, header, &requests, func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("test", "1", "value")), nil
+	})
+	client := newRetryingFakeClient(t, transport, policy.RetryOptions{})
+
+	start := time.Now()
+	if _, _, err := azrandom.GetSecret(context.Background(), client, "test", ""); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected to wait 200ms before each of the 2 retries, took %s", elapsed)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestThrottledExhausted(t *testing.T) {
+	testCases := map[string]struct {
+		retryAfter       string
+		retry            policy.RetryOptions
+		expectedRequests int32
+	}{
+		"max-retries": {
+			retryAfter:       "0",
+			retry:            policy.RetryOptions{MaxRetries: 2, RetryDelay: time.Millisecond},
+			expectedRequests: 3,
+		},
+		// A Retry-After beyond the maximum delay of the retry policy is not waited for
+		"max-retry-delay": {
+			retryAfter:       "120",
+			retry:            policy.RetryOptions{MaxRetryDelay: time.Second},
+			expectedRequests: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Retry-After", testCase.retryAfter)
+
+			var requests atomic.Int32
+			transport := throttlingTransport(1000, header, &requests, nil)
+			client := newRetryingFakeClient(t, transport, testCase.retry)
+
+			_, _, err := azrandom.GetSecret(context.Background(), client, "test", "")
+
+			var throttledErr *azrandom.ThrottledError
+			if !errors.As(err, &throttledErr) {
+				t.Fatalf("expected a throttled error, got %v", err)
+			}
+			if !azrandom.IsThrottled(err) {
+				t.Errorf("expected the throttled error to wrap the response error, got %v", err)
+			}
+			if responseErr, ok := azrandom.AsResponseError(err); !ok || responseErr.StatusCode != http.StatusTooManyRequests {
+				t.Errorf("expected a response error with status 429, got %v", err)
+			}
+			if requests.Load() != testCase.expectedRequests {
+				t.Errorf("expected %d requests, got %d", testCase.expectedRequests, requests.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// hangingTransport answers the authenticated requests of a client only once they are canceled, like a vault
+// behind a firewall that drops them.
+func hangingTransport() *fakeTransport {
+	return &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}}
+}
+
+// newTimeoutClient returns a client of fakeVaultUrl whose requests are sent to transport, and whose
+// operations time out after timeout.
+func newTimeoutClient(t *testing.T, transport policy.Transporter, timeout time.Duration) *azsecrets.Client {
+	t.Helper()
+
+	client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{
+		Retry:            policy.RetryOptions{MaxRetries: 3, RetryDelay: time.Millisecond},
+		Transport:        transport,
+		OperationTimeout: timeout,
+	})
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return client
+}
+
+func TestOperationTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		operation func(ctx context.Context, client *azsecrets.Client) error
+		expected  string
+	}{
+		"get": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.GetSecret(ctx, client, "timeout-test", "")
+				return err
+			},
+			expected: "getting secret timeout-test timed out after 100ms",
+		},
+		"exists": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, err := azrandom.SecretExists(ctx, client, "timeout-test")
+				return err
+			},
+			expected: "getting secret timeout-test timed out after 100ms",
+		},
+		"set": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.UpdateSecret(ctx, client, "timeout-test", "value", azrandom.SecretProperties{})
+				return err
+			},
+			expected: "setting secret timeout-test timed out after 100ms",
+		},
+		"update-properties": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, err := azrandom.UpdateSecretProperties(ctx, client, "timeout-test", "v1", azrandom.SecretProperties{})
+				return err
+			},
+			expected: "updating the properties of secret timeout-test timed out after 100ms",
+		},
+		"delete": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, err := azrandom.DeleteSecret(ctx, client, "timeout-test")
+				return err
+			},
+			expected: "deleting secret timeout-test timed out after 100ms",
+		},
+		"create": {
+			operation: func(ctx context.Context, client *azsecrets.Client) error {
+				_, _, err := azrandom.CreateSecret(ctx, client, "timeout-test", "value", azrandom.SecretProperties{})
+				return err
+			},
+			expected: "setting secret timeout-test timed out after 100ms",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newTimeoutClient(t, hangingTransport(), 100*time.Millisecond)
+
+			start := time.Now()
+			err := testCase.operation(context.Background(), client)
+			if !azrandom.IsOperationTimeout(err) {
+				t.Fatalf("expected an operation timeout, got %v", err)
+			}
+			if !strings.Contains(err.Error(), testCase.expected) {
+				t.Errorf("expected the error to contain %q, got %q", testCase.expected, err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("expected the operation to time out after about 100ms, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestOperationTimeoutRetries(t *testing.T) {
+	transport := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusServiceUnavailable, nil, ""), nil
+	}}
+	client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{
+		Retry:            policy.RetryOptions{MaxRetries: 5, RetryDelay: time.Minute, MaxRetryDelay: time.Minute},
+		Transport:        transport,
+		OperationTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+
+	// The timeout bounds the wait between the retries of a failing request as well
+	_, _, err = azrandom.GetSecret(context.Background(), client, "timeout-test", "")
+	if !azrandom.IsOperationTimeout(err) {
+		t.Fatalf("expected an operation timeout, got %v", err)
+	}
+}
+
+func TestOperationTimeoutCanceled(t *testing.T) {
+	client := newTimeoutClient(t, hangingTransport(), time.Minute)
+
+	// An operation that Terraform interrupts has not timed out
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err := azrandom.GetSecret(ctx, client, "timeout-test", "")
+	if err == nil {
+		t.Fatal("expected the canceled operation to fail")
+	}
+	if azrandom.IsOperationTimeout(err) {
+		t.Errorf("expected the canceled operation not to be reported as timed out, got %s", err)
+	}
+}
+
+func TestOperationTimeoutNotExceeded(t *testing.T) {
+	transport := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("timeout-test", "v1", "value")), nil
+	}}
+	client := newTimeoutClient(t, transport, time.Minute)
+
+	value, err := azrandom.GetSecretValue(context.Background(), client, "timeout-test", "")
+	if err != nil {
+		t.Fatalf("unable to get secret: %s", err)
+	}
+	if value != "value" {
+		t.Errorf("expected value %q, got %q", "value", value)
+	}
+}
+
+func TestProviderConfigureOperationTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		env         map[string]string
+		config      map[string]any
+		expectError string
+		expected    string
+	}{
+		"default": {
+			expected: "0s",
+		},
+		"config": {
+			config:   map[string]any{"operation_timeout": "30s"},
+			expected: "30s",
+		},
+		"env": {
+			env:      map[string]string{"AZRANDOM_OPERATION_TIMEOUT": "2m"},
+			expected: "2m0s",
+		},
+		"config-overrides-env": {
+			env:      map[string]string{"AZRANDOM_OPERATION_TIMEOUT": "2m"},
+			config:   map[string]any{"operation_timeout": "45s"},
+			expected: "45s",
+		},
+		"invalid-env": {
+			env:         map[string]string{"AZRANDOM_OPERATION_TIMEOUT": "30"},
+			expectError: "Invalid Azrandom Operation Timeout",
+		},
+		"negative-env": {
+			env:         map[string]string{"AZRANDOM_OPERATION_TIMEOUT": "-30s"},
+			expectError: "Invalid Azrandom Operation Timeout",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_OPERATION_TIMEOUT", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if testCase.expectError != "" {
+				if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			for _, entry := range entries {
+				if entry["@message"] == "Creating Azrandom client" && entry["operation_timeout"] != testCase.expected {
+					t.Errorf("expected operation timeout %q, got %v", testCase.expected, entry["operation_timeout"])
+				}
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigOperationTimeout(t *testing.T) {
+	errors := validateProviderConfig(t, map[string]any{"vault_url": testVaultUrl, "operation_timeout": "30"})
+	if len(errors) != 1 || errors[0] != "Invalid Attribute Value" {
+		t.Errorf("expected an invalid attribute value, got %v", errors)
+	}
+}
+
+func TestProviderOperationTimeout(t *testing.T) {
+	t.Parallel()
+
+	// A proxy that never answers stands in for a vault behind a firewall that drops requests. The server does
+	// not notice that the client of a CONNECT request has gone, so its requests are released when it closes.
+	released := make(chan struct{})
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-released:
+		}
+	}))
+	t.Cleanup(proxy.Close)
+	t.Cleanup(func() { close(released) })
+
+	server := configuredServer(t, map[string]any{
+		"vault_url":                  fakeVaultUrl,
+		"skip_credential_validation": true,
+		"proxy_url":                  proxy.URL,
+		"operation_timeout":          "200ms",
+	})
+
+	start := time.Now()
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_uuid",
+		ID:       "timeout-test",
+	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != tfprotov6.DiagnosticSeverityError {
+		t.Fatalf("expected the import to fail, got %v", resp.Diagnostics)
+	}
+	if expected := "getting secret timeout-test timed out after 200ms"; !strings.Contains(resp.Diagnostics[0].Detail, expected) {
+		t.Errorf("expected the diagnostic to contain %q, got %q", expected, resp.Diagnostics[0].Detail)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the import to time out after about 200ms, took %s", elapsed)
+	}
+}
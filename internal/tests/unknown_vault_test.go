@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"terraform-provider-azrandom/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// configureProviderWithUnknown configures the provider like configureProvider, with the given attributes of
+// config unknown, as Terraform does when they depend on resources that are not created yet. deferralAllowed is
+// set when Terraform supports deferred actions.
+func configureProviderWithUnknown(t *testing.T, config map[string]any, deferralAllowed bool, unknown ...string) (tfprotov6.ProviderServer, *tfprotov6.ConfigureProviderResponse) {
+	t.Helper()
+
+	server := providerserver.NewProtocol6(provider.New("test")())()
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unable to get provider schema: %s", err)
+	}
+	providerType := schemaResp.Provider.ValueType()
+
+	value, err := providerConfigValue(t, server, config).Unmarshal(providerType)
+	if err != nil {
+		t.Fatalf("unable to unmarshal value: %s", err)
+	}
+	value, err = tftypes.Transform(value, func(attributePath *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		for _, name := range unknown {
+			if attributePath.Equal(tftypes.NewAttributePath().WithAttributeName(name)) {
+				return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+			}
+		}
+		return v, nil
+	})
+	if err != nil {
+		t.Fatalf("unable to set unknown values: %s", err)
+	}
+	dynamicValue, err := tfprotov6.NewDynamicValue(providerType, value)
+	if err != nil {
+		t.Fatalf("unable to create dynamic value: %s", err)
+	}
+
+	resp, err := server.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.5.0",
+		Config:           &dynamicValue,
+		ClientCapabilities: &tfprotov6.ConfigureProviderClientCapabilities{
+			DeferralAllowed: deferralAllowed,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to configure provider: %s", err)
+	}
+	return server, resp
+}
+
+// An unknown vault defers the resources of the provider when Terraform supports deferred actions, without
+// requests to the vault, and fails at the unknown attribute otherwise.
+func TestProviderConfigureUnknownVault(t *testing.T) {
+	testCases := map[string]struct {
+		config  map[string]any
+		unknown []string
+	}{
+		"vault-url": {
+			unknown: []string{"vault_url"},
+		},
+		"vault-name": {
+			unknown: []string{"vault_name"},
+		},
+		"dns-suffix": {
+			config:  map[string]any{"vault_name": "azrandom-test"},
+			unknown: []string{"dns_suffix"},
+		},
+		"validate-access": {
+			config:  map[string]any{"validate_access": true},
+			unknown: []string{"vault_url"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		for _, deferralAllowed := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s/deferral-allowed=%t", name, deferralAllowed), func(t *testing.T) {
+				t.Setenv("AZRANDOM_VAULT_URL", "")
+				t.Setenv("AZRANDOM_VAULT_NAME", "")
+
+				// No request can reach the vault, which is not known
+				proxy := newTestProxy(t)
+				config := map[string]any{"proxy_url": proxy.URL}
+				for k, v := range testCase.config {
+					config[k] = v
+				}
+
+				server, resp := configureProviderWithUnknown(t, config, deferralAllowed, testCase.unknown...)
+				if hosts := proxy.hosts(); len(hosts) != 0 {
+					t.Errorf("expected no requests, got requests for %v", hosts)
+				}
+
+				// The deferral of the provider shows in the responses of its resources
+				if deferralAllowed {
+					if len(resp.Diagnostics) != 0 {
+						t.Fatalf("expected no diagnostics, got %s: %s", resp.Diagnostics[0].Summary, resp.Diagnostics[0].Detail)
+					}
+					planResp := planResourceChangeWith(t, server, "azrandom_uuid", nil, map[string]any{"name": "unknown-vault-test"})
+					if planResp.Deferred == nil || planResp.Deferred.Reason != tfprotov6.DeferredReasonProviderConfigUnknown {
+						t.Errorf("expected the plan to be deferred as the provider configuration is unknown, got %v", planResp.Deferred)
+					}
+					return
+				}
+
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Unknown Azrandom Vault" {
+					t.Fatalf("expected an unknown vault error, got %v", resp.Diagnostics)
+				}
+				expectedPath := tftypes.NewAttributePath().WithAttributeName(testCase.unknown[0])
+				if !expectedPath.Equal(resp.Diagnostics[0].Attribute) {
+					t.Errorf("expected the error at %s, got %s", expectedPath, resp.Diagnostics[0].Attribute)
+				}
+			})
+		}
+	}
+}
+
+// The resources of a provider whose vault is unknown are deferred, whether or not they set a vault of their own.
+func TestUnknownVaultPlan(t *testing.T) {
+	server, configureResp := configureProviderWithUnknown(t, map[string]any{"skip_credential_validation": true}, true, "vault_url")
+	if len(configureResp.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %s: %s", configureResp.Diagnostics[0].Summary, configureResp.Diagnostics[0].Detail)
+	}
+
+	for _, config := range []map[string]any{
+		{"name": "unknown-vault-test"},
+		{"name": "unknown-vault-test", "vault_url": testVaultUrl},
+	} {
+		resp := planResourceChangeWith(t, server, "azrandom_uuid", nil, config)
+		for _, diagnostic := range resp.Diagnostics {
+			if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+				t.Errorf("unexpected error planning resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+			}
+		}
+		if resp.Deferred == nil || resp.Deferred.Reason != tfprotov6.DeferredReasonProviderConfigUnknown {
+			t.Errorf("expected the plan to be deferred as the provider configuration is unknown, got %v", resp.Deferred)
+		}
+	}
+}
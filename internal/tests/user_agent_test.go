@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	azrandom "terraform-provider-azrandom/client"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// testPartnerID is a partner ID other than the one of Terraform.
+const testPartnerID = "6a1b5e0c-3d2f-4c8e-9b7a-0f1e2d3c4b5a"
+
+func TestUserAgentClient(t *testing.T) {
+	testCases := map[string]struct {
+		userAgent azrandom.UserAgent
+		expected  []string
+		forbidden []string
+	}{
+		"version-and-partner": {
+			userAgent: azrandom.UserAgent{ProviderVersion: "1.2.3", PartnerID: testPartnerID},
+			expected:  []string{"azrandom/1.2.3", "pid-" + testPartnerID, "azsdk-go-azsecrets/"},
+		},
+		"terraform-partner": {
+			userAgent: azrandom.UserAgent{ProviderVersion: "1.2.3", PartnerID: azrandom.TerraformPartnerID},
+			expected:  []string{"azrandom/1.2.3", "pid-" + azrandom.TerraformPartnerID},
+		},
+		"no-partner": {
+			userAgent: azrandom.UserAgent{ProviderVersion: "1.2.3"},
+			expected:  []string{"azrandom/1.2.3", "azsdk-go-azsecrets/"},
+			forbidden: []string{"pid-"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The first authenticated attempt fails, so that the User-Agent of a retried request is checked as well
+			var userAgents []string
+			transport := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+				if len(userAgents) == 2 {
+					return fakeResponse(req, http.StatusServiceUnavailable, nil, ""), nil
+				}
+				return fakeResponse(req, http.StatusOK, nil, fakeSecretBody("user-agent-test", "v1", "value")), nil
+			}}
+			client, err := azrandom.CreateClient(fakeVaultUrl, fakeCredential{}, azrandom.ClientOptions{
+				Retry: policy.RetryOptions{MaxRetries: 1, RetryDelay: time.Millisecond},
+				Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+					userAgents = append(userAgents, req.Header.Get("User-Agent"))
+					return transport.Do(req)
+				}),
+				UserAgent: testCase.userAgent,
+			})
+			if err != nil {
+				t.Fatalf("unable to create client: %s", err)
+			}
+
+			if _, err := azrandom.GetSecretValue(context.Background(), client, "user-agent-test", ""); err != nil {
+				t.Fatalf("unable to get secret: %s", err)
+			}
+
+			if len(userAgents) != 3 {
+				t.Fatalf("expected a challenge, a failed and a retried request to the vault, got %d requests", len(userAgents))
+			}
+			for _, userAgent := range userAgents {
+				for _, expected := range testCase.expected {
+					if !strings.Contains(userAgent, expected) {
+						t.Errorf("expected User-Agent %q to contain %q", userAgent, expected)
+					}
+				}
+				for _, forbidden := range testCase.forbidden {
+					if strings.Contains(userAgent, forbidden) {
+						t.Errorf("expected User-Agent %q not to contain %q", userAgent, forbidden)
+					}
+				}
+				// Retries must not append the partner again
+				if count := strings.Count(userAgent, "pid-"); count > 1 {
+					t.Errorf("expected the partner ID once in User-Agent %q, got it %d times", userAgent, count)
+				}
+			}
+		})
+	}
+}
+
+func TestUserAgentCredential(t *testing.T) {
+	var userAgents []string
+	credential, err := azrandom.CreateCredential(azrandom.CredentialOptions{
+		ServicePrincipal: azrandom.ServicePrincipal{
+			TenantID:     "00000000-0000-0000-0000-000000000000",
+			ClientID:     "00000000-0000-0000-0000-000000000000",
+			ClientSecret: "secret",
+		},
+		DisableInstanceDiscovery: true,
+		Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+			userAgents = append(userAgents, req.Header.Get("User-Agent"))
+			return fakeResponse(req, http.StatusBadRequest, nil, `{"error": "invalid_client"}`), nil
+		}),
+		UserAgent: azrandom.UserAgent{ProviderVersion: "1.2.3", PartnerID: testPartnerID},
+	})
+	if err != nil {
+		t.Fatalf("unable to create credential: %s", err)
+	}
+
+	if err := azrandom.ValidateCredential(context.Background(), credential, fakeVaultUrl); err == nil {
+		t.Fatal("expected the rejected token request to fail")
+	}
+
+	if len(userAgents) == 0 {
+		t.Fatal("expected a token request")
+	}
+	for _, userAgent := range userAgents {
+		for _, expected := range []string{"azrandom/1.2.3", "pid-" + testPartnerID, "azsdk-go-azidentity/"} {
+			if !strings.Contains(userAgent, expected) {
+				t.Errorf("expected User-Agent %q to contain %q", userAgent, expected)
+			}
+		}
+	}
+}
+
+func TestProviderConfigurePartnerID(t *testing.T) {
+	testCases := map[string]struct {
+		env           map[string]string
+		config        map[string]any
+		expectError   string
+		expectPartner string
+	}{
+		"default": {
+			expectPartner: azrandom.TerraformPartnerID,
+		},
+		"config": {
+			config:        map[string]any{"partner_id": testPartnerID},
+			expectPartner: testPartnerID,
+		},
+		"env": {
+			env:           map[string]string{"AZRANDOM_PARTNER_ID": testPartnerID},
+			expectPartner: testPartnerID,
+		},
+		"disable-terraform": {
+			config: map[string]any{"disable_terraform_partner_id": true},
+		},
+		"disable-terraform-env": {
+			env: map[string]string{"AZRANDOM_DISABLE_TERRAFORM_PARTNER_ID": "true"},
+		},
+		"disable-terraform-with-partner": {
+			config:        map[string]any{"partner_id": testPartnerID, "disable_terraform_partner_id": true},
+			expectPartner: testPartnerID,
+		},
+		"invalid-env": {
+			env:         map[string]string{"AZRANDOM_PARTNER_ID": "not-a-guid"},
+			expectError: "Invalid Azrandom Partner ID",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AZRANDOM_PARTNER_ID", "")
+			t.Setenv("AZRANDOM_DISABLE_TERRAFORM_PARTNER_ID", "")
+			for k, v := range testCase.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+			for k, v := range testCase.config {
+				config[k] = v
+			}
+
+			diagnostics, entries := configureProviderWithLogs(t, config)
+			if testCase.expectError != "" {
+				if len(diagnostics) != 1 || diagnostics[0].Summary != testCase.expectError {
+					t.Fatalf("expected a single %q error, got %v", testCase.expectError, diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 0 {
+				t.Fatalf("expected no diagnostics, got %s: %s", diagnostics[0].Summary, diagnostics[0].Detail)
+			}
+
+			for _, entry := range entries {
+				if entry["@message"] == "Creating Azrandom client" && entry["partner_id"] != testCase.expectPartner {
+					t.Errorf("expected partner ID %q, got %v", testCase.expectPartner, entry["partner_id"])
+				}
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigPartnerID(t *testing.T) {
+	errors := validateProviderConfig(t, map[string]any{"vault_url": testVaultUrl, "partner_id": "not-a-guid"})
+	if len(errors) != 1 || errors[0] != "Invalid Attribute Value" {
+		t.Errorf("expected an invalid attribute value, got %v", errors)
+	}
+}
+
+// transportFunc adapts a function to policy.Transporter.
+type transportFunc func(req *http.Request) (*http.Response, error)
+
+func (f transportFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+
+	"terraform-provider-azrandom/internal/random"
+)
+
+func TestCreateUUIDv7(t *testing.T) {
+	now := time.UnixMilli(0x0191e2a3b4c5)
+
+	generated := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		value, err := random.CreateUUIDv7(now)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := uuid.ParseUUID(value); err != nil {
+			t.Fatalf("expected %q to be a UUID in canonical form: %s", value, err)
+		}
+
+		bytes, err := hex.DecodeString(strings.ReplaceAll(value, "-", ""))
+		if err != nil {
+			t.Fatalf("unable to decode %q: %s", value, err)
+		}
+		// The first 48 bits hold the Unix time in milliseconds, big-endian
+		if timestamp := hex.EncodeToString(bytes[:6]); timestamp != "0191e2a3b4c5" {
+			t.Errorf("expected timestamp 0191e2a3b4c5 in %q, got %s", value, timestamp)
+		}
+		if version := bytes[6] >> 4; version != 7 {
+			t.Errorf("expected version 7 in %q, got %d", value, version)
+		}
+		if variant := bytes[8] >> 6; variant != 0b10 {
+			t.Errorf("expected variant 0b10 in %q, got %b", value, variant)
+		}
+		generated[value] = true
+	}
+	// The remaining 74 bits are random
+	if len(generated) != 100 {
+		t.Errorf("expected 100 distinct UUIDs for the same millisecond, got %d", len(generated))
+	}
+}
+
+func TestCreateUUIDv7Ordered(t *testing.T) {
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	var values []string
+	for i := 0; i < 10; i++ {
+		value, err := random.CreateUUIDv7(start.Add(time.Duration(i) * time.Millisecond))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		values = append(values, value)
+	}
+
+	if !sort.StringsAreSorted(values) {
+		t.Errorf("expected UUIDs generated in later milliseconds to sort after earlier ones, got %v", values)
+	}
+}
+
+func TestCreateUUIDv5(t *testing.T) {
+	testCases := map[string]struct {
+		namespace string
+		name      string
+		expected  string
+	}{
+		"dns": {
+			namespace: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			name:      "python.org",
+			expected:  "886313e1-3b8a-5372-9b90-0c9aee199e5d",
+		},
+		"url": {
+			namespace: "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+			name:      "https://example.com/",
+			expected:  "dd2c1780-811a-5296-81c5-178a0ef488bc",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, err := random.CreateUUIDv5(testCase.namespace, testCase.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if value != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestCreateUUIDv5InvalidNamespace(t *testing.T) {
+	if _, err := random.CreateUUIDv5("not-a-uuid", "python.org"); err == nil {
+		t.Error("expected an error for a namespace that is not a UUID")
+	}
+}
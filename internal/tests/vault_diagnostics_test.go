@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"terraform-provider-azrandom/internal/diagnostics"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// vaultResponseError returns the error of a response of the vault with the given status and body.
+func vaultResponseError(status int, body string) error {
+	req, _ := http.NewRequest(http.MethodGet, fakeVaultUrl+"secrets/vault-diagnostics-test/", nil)
+	return runtime.NewResponseError(fakeResponse(req, status, nil, body))
+}
+
+func TestVaultError(t *testing.T) {
+	testCases := map[string]struct {
+		err     error
+		summary string
+		hint    string
+	}{
+		"not-found": {
+			err:     vaultResponseError(http.StatusNotFound, accessErrorBody("SecretNotFound", "not found", "")),
+			summary: "Secret Not Found",
+			hint:    "deleted outside of Terraform",
+		},
+		"forbidden": {
+			err:     vaultResponseError(http.StatusForbidden, accessErrorBody("Forbidden", "not allowed", "ForbiddenByRbac")),
+			summary: "Vault Access Denied",
+			hint:    "Key Vault Secrets Officer",
+		},
+		"being-deleted": {
+			err:     vaultResponseError(http.StatusConflict, accessErrorBody("Conflict", "being deleted", "ObjectIsBeingDeleted")),
+			summary: "Secret Being Deleted",
+			hint:    "purge_on_destroy",
+		},
+		"conflict": {
+			err:     vaultResponseError(http.StatusConflict, accessErrorBody("Conflict", "being recovered", "")),
+			summary: "Secret Conflict",
+			hint:    "conflicts with the current state",
+		},
+		"other-response": {
+			err:     vaultResponseError(http.StatusInternalServerError, accessErrorBody("InternalError", "fake", "")),
+			summary: "Update azrandom_uuid error",
+		},
+		"no-response": {
+			err:     errors.New("connection refused"),
+			summary: "Update azrandom_uuid error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := diagnostics.VaultError(diagnostics.OperationUpdate, "update the properties of the secret",
+				"azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, testCase.err)
+			if len(diags) != 1 || diags[0].Severity() != diag.SeverityError {
+				t.Fatalf("expected a single error, got %v", diags)
+			}
+			if diags[0].Summary() != testCase.summary {
+				t.Errorf("expected summary %q, got %q", testCase.summary, diags[0].Summary())
+			}
+
+			detail := diags[0].Detail()
+			header := "Could not update the properties of the secret for azrandom_uuid.\n\n" +
+				"Secret: vault-diagnostics-test\n" +
+				"Vault:  " + fakeVaultUrl + "\n\n"
+			if !strings.HasPrefix(detail, header) {
+				t.Errorf("expected the detail to start with %q, got %q", header, detail)
+			}
+			if !strings.Contains(detail, testCase.hint) {
+				t.Errorf("expected the detail to contain %q, got %q", testCase.hint, detail)
+			}
+			if !strings.HasSuffix(detail, "Original Error: "+diagnostics.ErrorDetail(testCase.err)) {
+				t.Errorf("expected the detail to end with the original error, got %q", detail)
+			}
+		})
+	}
+}
+
+func TestVaultErrorWithoutVaultUrl(t *testing.T) {
+	diags := diagnostics.ReadError("azrandom_string", "vault-diagnostics-test", "", errors.New("connection refused"))
+	expected := "Could not read the secret for azrandom_string.\n\n" +
+		"Secret: vault-diagnostics-test\n\n" +
+		"Original Error: connection refused"
+	if diags[0].Detail() != expected {
+		t.Errorf("expected detail %q, got %q", expected, diags[0].Detail())
+	}
+}
+
+func TestVaultErrorHelpers(t *testing.T) {
+	err := errors.New("connection refused")
+
+	testCases := map[string]struct {
+		diags   diag.Diagnostics
+		summary string
+		action  string
+	}{
+		"create": {
+			diags:   diagnostics.CreateError("azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, err),
+			summary: "Create azrandom_uuid error",
+			action:  "Could not create the secret for azrandom_uuid.",
+		},
+		"read": {
+			diags:   diagnostics.ReadError("azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, err),
+			summary: "Read azrandom_uuid error",
+			action:  "Could not read the secret for azrandom_uuid.",
+		},
+		"update": {
+			diags:   diagnostics.UpdateError("azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, err),
+			summary: "Update azrandom_uuid error",
+			action:  "Could not update the secret for azrandom_uuid.",
+		},
+		"delete": {
+			diags:   diagnostics.DeleteError("azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, err),
+			summary: "Delete azrandom_uuid error",
+			action:  "Could not delete the secret for azrandom_uuid.",
+		},
+		"import": {
+			diags:   diagnostics.ImportError("azrandom_uuid", "vault-diagnostics-test", fakeVaultUrl, err),
+			summary: "Import azrandom_uuid error",
+			action:  "Could not read the secret for azrandom_uuid.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if len(testCase.diags) != 1 {
+				t.Fatalf("expected a single diagnostic, got %d", len(testCase.diags))
+			}
+			if testCase.diags[0].Summary() != testCase.summary {
+				t.Errorf("expected summary %q, got %q", testCase.summary, testCase.diags[0].Summary())
+			}
+			if !strings.HasPrefix(testCase.diags[0].Detail(), testCase.action) {
+				t.Errorf("expected the detail to start with %q, got %q", testCase.action, testCase.diags[0].Detail())
+			}
+		})
+	}
+}
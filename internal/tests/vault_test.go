@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	azrandom "terraform-provider-azrandom/client"
+)
+
+func TestVaultURL(t *testing.T) {
+	testCases := map[string]struct {
+		vaultName   string
+		environment azrandom.Environment
+		expected    string
+	}{
+		"public":       {vaultName: "my-vault", environment: azrandom.EnvironmentPublic, expected: "https://my-vault.vault.azure.net/"},
+		"usgovernment": {vaultName: "my-vault", environment: azrandom.EnvironmentUSGovernment, expected: "https://my-vault.vault.usgovcloudapi.net/"},
+		"china":        {vaultName: "my-vault", environment: azrandom.EnvironmentChina, expected: "https://my-vault.vault.azure.cn/"},
+		"mixed-case":   {vaultName: "My-Vault1", environment: azrandom.EnvironmentPublic, expected: "https://my-vault1.vault.azure.net/"},
+		"resource-id": {
+			vaultName:   "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			environment: azrandom.EnvironmentPublic,
+			expected:    "https://my-vault.vault.azure.net/",
+		},
+		"resource-id-lowercase": {
+			vaultName:   "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.keyvault/vaults/my-vault",
+			environment: azrandom.EnvironmentChina,
+			expected:    "https://my-vault.vault.azure.cn/",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			url, err := azrandom.VaultURL(testCase.vaultName, testCase.environment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if url != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, url)
+			}
+		})
+	}
+}
+
+func TestVaultURLInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		vaultName   string
+		environment azrandom.Environment
+	}{
+		"too-short":             {vaultName: "kv", environment: azrandom.EnvironmentPublic},
+		"too-long":              {vaultName: "a123456789012345678901234", environment: azrandom.EnvironmentPublic},
+		"leading-digit":         {vaultName: "1vault", environment: azrandom.EnvironmentPublic},
+		"trailing-hyphen":       {vaultName: "vault-", environment: azrandom.EnvironmentPublic},
+		"consecutive-hyphens":   {vaultName: "my--vault", environment: azrandom.EnvironmentPublic},
+		"url":                   {vaultName: "https://my-vault.vault.azure.net/", environment: azrandom.EnvironmentPublic},
+		"other-resource-id":     {vaultName: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account", environment: azrandom.EnvironmentPublic},
+		"malformed-resource-id": {vaultName: "/subscriptions", environment: azrandom.EnvironmentPublic},
+		"unknown-environment":   {vaultName: "my-vault", environment: azrandom.Environment("germany")},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if url, err := azrandom.VaultURL(testCase.vaultName, testCase.environment); err == nil {
+				t.Errorf("expected an error, got %q", url)
+			}
+		})
+	}
+}
+
+func TestVaultURLWithDNSSuffix(t *testing.T) {
+	testCases := map[string]struct {
+		vaultName string
+		dnsSuffix string
+		expected  string
+	}{
+		"public":     {vaultName: "my-vault", dnsSuffix: "vault.azure.net", expected: "https://my-vault.vault.azure.net/"},
+		"private":    {vaultName: "my-vault", dnsSuffix: "vault.contoso-cloud.example", expected: "https://my-vault.vault.contoso-cloud.example/"},
+		"mixed-case": {vaultName: "My-Vault", dnsSuffix: "Vault.Example.COM", expected: "https://my-vault.vault.example.com/"},
+		"resource-id": {
+			vaultName: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/my-vault",
+			dnsSuffix: "vault.example.com",
+			expected:  "https://my-vault.vault.example.com/",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			url, err := azrandom.VaultURLWithDNSSuffix(testCase.vaultName, testCase.dnsSuffix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if url != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, url)
+			}
+		})
+	}
+}
+
+func TestVaultURLWithDNSSuffixInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		vaultName string
+		dnsSuffix string
+	}{
+		"single-label":  {vaultName: "my-vault", dnsSuffix: "vault"},
+		"leading-dot":   {vaultName: "my-vault", dnsSuffix: ".vault.azure.net"},
+		"trailing-dot":  {vaultName: "my-vault", dnsSuffix: "vault.azure.net."},
+		"scheme":        {vaultName: "my-vault", dnsSuffix: "https://vault.azure.net"},
+		"path":          {vaultName: "my-vault", dnsSuffix: "vault.azure.net/"},
+		"port":          {vaultName: "my-vault", dnsSuffix: "vault.azure.net:443"},
+		"hyphen-label":  {vaultName: "my-vault", dnsSuffix: "vault.-azure.net"},
+		"empty":         {vaultName: "my-vault", dnsSuffix: ""},
+		"invalid-vault": {vaultName: "kv", dnsSuffix: "vault.azure.net"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if url, err := azrandom.VaultURLWithDNSSuffix(testCase.vaultName, testCase.dnsSuffix); err == nil {
+				t.Errorf("expected an error, got %q", url)
+			}
+		})
+	}
+}
+
+func TestVaultEnvironment(t *testing.T) {
+	testCases := map[string]struct {
+		expected azrandom.Environment
+		known    bool
+	}{
+		"https://my-vault.vault.azure.net/":         {expected: azrandom.EnvironmentPublic, known: true},
+		"https://my-vault.vault.usgovcloudapi.net/": {expected: azrandom.EnvironmentUSGovernment, known: true},
+		"https://MY-VAULT.VAULT.AZURE.CN":           {expected: azrandom.EnvironmentChina, known: true},
+		"https://vault.example.com/":                {},
+	}
+
+	for vaultUrl, testCase := range testCases {
+		t.Run(vaultUrl, func(t *testing.T) {
+			environment, known := azrandom.VaultEnvironment(vaultUrl)
+			if environment != testCase.expected || known != testCase.known {
+				t.Errorf("expected %q (%t), got %q (%t)", testCase.expected, testCase.known, environment, known)
+			}
+		})
+	}
+}
+
+func TestValidateVaultURL(t *testing.T) {
+	testCases := map[string]struct {
+		vaultUrl    string
+		expectError bool
+	}{
+		"public":         {vaultUrl: "https://my-vault.vault.azure.net/"},
+		"no-slash":       {vaultUrl: "https://my-vault.vault.azure.net"},
+		"china":          {vaultUrl: "https://MY-VAULT.VAULT.AZURE.CN/"},
+		"no-host":        {vaultUrl: "my-vault.vault.azure.net", expectError: true},
+		"secret-path":    {vaultUrl: "https://my-vault.vault.azure.net/secrets/foo", expectError: true},
+		"query":          {vaultUrl: "https://my-vault.vault.azure.net/?a=b", expectError: true},
+		"fragment":       {vaultUrl: "https://my-vault.vault.azure.net/#a", expectError: true},
+		"user-info":      {vaultUrl: "https://user@my-vault.vault.azure.net/", expectError: true},
+		"unknown-suffix": {vaultUrl: "https://vault.example.com/", expectError: true},
+		"bare-suffix":    {vaultUrl: "https://vault.azure.net/", expectError: true},
+		"managed-hsm":    {vaultUrl: "https://my-pool.managedhsm.azure.net/", expectError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := azrandom.ValidateVaultURL(testCase.vaultUrl)
+			if testCase.expectError && err == nil {
+				t.Error("expected an error")
+			}
+			if !testCase.expectError && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func TestManagedHSM(t *testing.T) {
+	testCases := map[string]bool{
+		"https://my-pool.managedhsm.azure.net/":         true,
+		"https://MY-POOL.MANAGEDHSM.USGOVCLOUDAPI.NET":  true,
+		"https://my-pool.managedhsm.azure.cn/":          true,
+		"https://my-vault.vault.azure.net/":             false,
+		"https://managedhsm.azure.net/":                 false,
+		"https://my-pool.managedhsm.example.com/":       false,
+		"https://my-pool.managedhsm.azure.net.example/": false,
+	}
+
+	for vaultUrl, expected := range testCases {
+		t.Run(vaultUrl, func(t *testing.T) {
+			if managedHSM := azrandom.IsManagedHSM(vaultUrl); managedHSM != expected {
+				t.Errorf("expected %t, got %t", expected, managedHSM)
+			}
+			if err := azrandom.ValidateVaultURL(vaultUrl); errors.Is(err, azrandom.ErrManagedHSM) != expected {
+				t.Errorf("expected an error wrapping ErrManagedHSM to be %t, got %v", expected, err)
+			}
+			if _, err := azrandom.CreateClient(vaultUrl, fakeCredential{}, azrandom.ClientOptions{}); errors.Is(err, azrandom.ErrManagedHSM) != expected {
+				t.Errorf("expected CreateClient to fail with ErrManagedHSM to be %t, got %v", expected, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeVaultURL(t *testing.T) {
+	for _, vaultUrl := range []string{
+		"https://my-vault.vault.azure.net",
+		"https://my-vault.vault.azure.net/",
+		"https://My-Vault.vault.azure.net//",
+	} {
+		if normalized := azrandom.NormalizeVaultURL(vaultUrl); normalized != "https://my-vault.vault.azure.net" {
+			t.Errorf("expected %q to be normalized to https://my-vault.vault.azure.net, got %q", vaultUrl, normalized)
+		}
+	}
+}
+
+func TestEnvironmentCloud(t *testing.T) {
+	testCases := map[string]struct {
+		environment   azrandom.Environment
+		authorityHost string
+		expected      string
+	}{
+		"public":       {environment: azrandom.EnvironmentPublic, expected: "https://login.microsoftonline.com/"},
+		"usgovernment": {environment: azrandom.EnvironmentUSGovernment, expected: "https://login.microsoftonline.us/"},
+		"china":        {environment: azrandom.EnvironmentChina, expected: "https://login.chinacloudapi.cn/"},
+		"custom":       {environment: azrandom.EnvironmentChina, authorityHost: "https://login.example.com/", expected: "https://login.example.com/"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cloud := testCase.environment.Cloud(testCase.authorityHost)
+			if cloud.ActiveDirectoryAuthorityHost != testCase.expected {
+				t.Errorf("expected authority host %q, got %q", testCase.expected, cloud.ActiveDirectoryAuthorityHost)
+			}
+		})
+	}
+
+	// A custom authority host must not leak into the configuration of the environment
+	if host := azrandom.EnvironmentChina.Cloud("").ActiveDirectoryAuthorityHost; host != "https://login.chinacloudapi.cn/" {
+		t.Errorf("expected the authority host of china to be unchanged, got %q", host)
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const (
+	// otherVaultUrl is a vault other than the vault of the provider, which the unit tests never reach.
+	otherVaultUrl = "https://azrandom-other.vault.azure.net/"
+)
+
+// vaultUrlProviderConfig configures the provider without validating its credential, so that resources can
+// be planned without reaching Azure.
+var vaultUrlProviderConfig = map[string]any{"vault_url": testVaultUrl, "skip_credential_validation": true}
+
+func TestResourceVaultUrlPlan(t *testing.T) {
+	testCases := map[string]struct {
+		stateVaultUrl  any
+		configVaultUrl any
+		expectReplace  bool
+	}{
+		"unchanged": {},
+		"provider-vault": {
+			configVaultUrl: testVaultUrl,
+		},
+		"provider-vault-without-slash": {
+			configVaultUrl: "https://LOCALDEV-REMOTE-BXNWI8XN.vault.azure.net",
+		},
+		"other-vault": {
+			configVaultUrl: otherVaultUrl,
+			expectReplace:  true,
+		},
+		"same-other-vault": {
+			stateVaultUrl:  "https://azrandom-other.vault.azure.net",
+			configVaultUrl: otherVaultUrl,
+		},
+		"back-to-provider-vault": {
+			stateVaultUrl: otherVaultUrl,
+			expectReplace: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := configuredServer(t, vaultUrlProviderConfig)
+
+			priorState := map[string]any{
+				"name":       "vault-url-test",
+				"vault_url":  testCase.stateVaultUrl,
+				"version":    "1",
+				"created_on": "2024-01-01T00:00:00Z",
+				"updated_on": "2024-01-01T00:00:00Z",
+			}
+			config := map[string]any{"name": "vault-url-test", "vault_url": testCase.configVaultUrl}
+
+			resp := planResourceChangeWith(t, server, "azrandom_uuid", priorState, config)
+			for _, diagnostic := range resp.Diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					t.Fatalf("unexpected error planning resource change: %s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+
+			vaultUrlPath := tftypes.NewAttributePath().WithAttributeName("vault_url")
+			replaced := false
+			for _, replacePath := range resp.RequiresReplace {
+				replaced = replaced || replacePath.Equal(vaultUrlPath)
+			}
+			if replaced != testCase.expectReplace {
+				t.Errorf("expected replacement to be %t, got %t", testCase.expectReplace, replaced)
+			}
+		})
+	}
+}
+
+func TestResourceVaultUrlDuplicateSecretName(t *testing.T) {
+	testCases := map[string]struct {
+		firstVaultUrl  any
+		secondVaultUrl any
+		expectError    bool
+	}{
+		"provider-vault": {
+			expectError: true,
+		},
+		"provider-vault-explicit": {
+			secondVaultUrl: testVaultUrl,
+			expectError:    true,
+		},
+		"other-vault": {
+			secondVaultUrl: otherVaultUrl,
+		},
+		"same-other-vault": {
+			firstVaultUrl:  otherVaultUrl,
+			secondVaultUrl: "https://AZRANDOM-OTHER.vault.azure.net",
+			expectError:    true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := configuredServer(t, vaultUrlProviderConfig)
+
+			for i, vaultUrl := range []any{testCase.firstVaultUrl, testCase.secondVaultUrl} {
+				resp := planResourceChangeWith(t, server, "azrandom_uuid", nil, map[string]any{
+					"name":      "vault-url-duplicate-test",
+					"vault_url": vaultUrl,
+				})
+
+				duplicate := false
+				for _, diagnostic := range resp.Diagnostics {
+					duplicate = duplicate || diagnostic.Summary == "Duplicate secret name"
+				}
+				if expectDuplicate := i == 1 && testCase.expectError; duplicate != expectDuplicate {
+					t.Errorf("expected a duplicate secret name in resource %d to be %t, got %t", i, expectDuplicate, duplicate)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceVaultUrlInvalidImportID(t *testing.T) {
+	for _, importID := range []string{
+		"https://azrandom-other.vault.azure.net/",
+		"https://azrandom-other.vault.azure.net/secrets/",
+		"https://azrandom-other.vault.azure.net/keys/vault-url-test",
+		"https:///secrets/vault-url-test",
+		"https://azrandom-other.vault.azure.net/secrets/vault-url-test/1/2",
+		"https://azrandom-other.vault.azure.net/secrets//1",
+	} {
+		t.Run(importID, func(t *testing.T) {
+			server := configuredServer(t, vaultUrlProviderConfig)
+
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_uuid",
+				ID:       importID,
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Invalid Import ID" {
+				t.Fatalf("expected an invalid import ID error, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// Resources are imported by the name of their secret, or by a secret identifier as shown in the portal, either
+// of which may pin a version.
+func TestResourceImportSecretIdentifier(t *testing.T) {
+	vault := newMemoryVault(t)
+	vault.write("import-id-test")
+	vault.write("import-id-test")
+	vault.setValue("import-id-test", "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4")
+
+	testCases := map[string]struct {
+		importID    string
+		version     string
+		expectError bool
+	}{
+		"name":                      {importID: "import-id-test", version: "2"},
+		"name-trailing-slash":       {importID: "import-id-test/", version: "2"},
+		"name-version":              {importID: "import-id-test:1", version: "1"},
+		"name-slash-version":        {importID: "import-id-test/1", version: "1"},
+		"name-empty-version":        {importID: "import-id-test:", expectError: true},
+		"name-two-versions":         {importID: "import-id-test:1:2", expectError: true},
+		"version-only":              {importID: ":2", expectError: true},
+		"identifier":                {importID: vault.URL + "/secrets/import-id-test", version: "2"},
+		"identifier-trailing-slash": {importID: vault.URL + "/secrets/import-id-test/", version: "2"},
+		"identifier-version":        {importID: vault.URL + "/secrets/import-id-test/1", version: "1"},
+		"identifier-version-slash":  {importID: vault.URL + "/secrets/import-id-test/1/", version: "1"},
+		"identifier-upper-case":     {importID: strings.ToUpper(vault.URL) + "/secrets/import-id-test/2", version: "2"},
+		"identifier-extra-segment":  {importID: vault.URL + "/secrets/import-id-test/2/extra", expectError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := vault.configuredServer(t)
+			resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "azrandom_uuid",
+				ID:       testCase.importID,
+			})
+			if err != nil {
+				t.Fatalf("unable to import resource state: %s", err)
+			}
+
+			if testCase.expectError {
+				if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Invalid Import ID" {
+					t.Fatalf("expected an invalid import ID error, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+				t.Fatalf("expected the secret to be imported, got %v", resp.Diagnostics)
+			}
+
+			// The vault of the identifier is the vault of the provider, so vault_url is not set
+			imported := stateMap(t, server, "azrandom_uuid", resp.ImportedResources[0].State)
+			if imported["name"] != "import-id-test" || imported["version"] != testCase.version || imported["vault_url"] != nil {
+				t.Errorf("expected version %s of import-id-test to be imported from the vault of the provider, got "+
+					"name %v, version %v and vault_url %v", testCase.version, imported["name"], imported["version"],
+					imported["vault_url"])
+			}
+		})
+	}
+}
+
+// A resource imported with a version keeps that version when the secret has newer versions, until it generates
+// a new value.
+func TestResourceImportPinnedVersion(t *testing.T) {
+	vault := newMemoryVault(t)
+	vault.write("import-pinned-test")
+	vault.write("import-pinned-test")
+	vault.setValue("import-pinned-test", "9f0c4a52-4e1b-4c55-a0a1-5b2c1bd1a6f4")
+
+	server := vault.configuredServer(t)
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_uuid",
+		ID:       "import-pinned-test:1",
+	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(resp.Diagnostics) > 0 || len(resp.ImportedResources) != 1 {
+		t.Fatalf("expected the secret to be imported, got %v", resp.Diagnostics)
+	}
+
+	// Reading the pinned version is no drift, also when yet another version is set
+	vault.write("import-pinned-test")
+	server = vault.configuredServer(t)
+	read := readResourcePrivate(t, server, "azrandom_uuid", resp.ImportedResources[0].State, resp.ImportedResources[0].Private)
+	refreshed := stateMap(t, server, "azrandom_uuid", read.NewState)
+	if refreshed["version"] != "1" {
+		t.Fatalf("expected the pinned version 1 to be read, got %v", refreshed["version"])
+	}
+
+	config := map[string]any{"name": "import-pinned-test"}
+	planned := plannedStateMap(t, server, "azrandom_uuid", planResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+	if planned["version"] != "1" {
+		t.Fatalf("expected the pinned version 1 to be kept, got %v", planned["version"])
+	}
+
+	// Generating a new value ends the pin, after which newer versions are drift again
+	config["keepers"] = map[string]any{"rotation": "1"}
+	server = vault.configuredServer(t)
+	applied := applySucceeded(t, applyResourceChangePrivate(t, server, "azrandom_uuid", refreshed, read.Private, config))
+	created := stateMap(t, server, "azrandom_uuid", applied.NewState)
+	if created["version"] != "4" {
+		t.Fatalf("expected a new version 4 to be written, got %v", created["version"])
+	}
+
+	vault.write("import-pinned-test")
+	server = vault.configuredServer(t)
+	read = readResourcePrivate(t, server, "azrandom_uuid", applied.NewState, applied.Private)
+	if version := stateMap(t, server, "azrandom_uuid", read.NewState)["version"]; version != "5" {
+		t.Errorf("expected the latest version 5 to be read after the pin ended, got %v", version)
+	}
+}
+
+// testAccUnusedVaultProviderConfig configures the provider with a vault that does not exist, so that only
+// resources that set their own vault_url can store secrets.
+const testAccUnusedVaultProviderConfig = `
+provider "azrandom" {
+	vault_url                              = "https://azrandom-unused.vault.azure.net/"
+	disable_managed_identity_credential    = true
+	disable_workload_identity_credential   = true
+	disable_azure_developer_cli_credential = true
+	disable_environment_credential         = true
+}
+`
+
+func TestResourceVaultUrlManagedHSM(t *testing.T) {
+	server := configuredServer(t, vaultUrlProviderConfig)
+
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "azrandom_uuid",
+		ID:       "https://azrandom-hsm.managedhsm.azure.net/secrets/vault-url-test",
+	})
+	if err != nil {
+		t.Fatalf("unable to import resource state: %s", err)
+	}
+	if len(resp.Diagnostics) != 1 || !strings.Contains(resp.Diagnostics[0].Detail, "Managed HSM") {
+		t.Errorf("expected the import from a Managed HSM pool to fail, got %v", resp.Diagnostics)
+	}
+}
+
+func TestAccResourceUUIDVaultUrl(t *testing.T) {
+	t.Parallel()
+	testAccSkipEmulator(t)
+
+	testAccUnitTest(t, func(t *testing.T, protocol testProtocol) resource.TestCase {
+		return resource.TestCase{
+			Steps: []resource.TestStep{
+				{
+					Config: testAccUnusedVaultProviderConfig + fmt.Sprintf(`resource "azrandom_uuid" "this" {
+							name      = %[1]q
+							vault_url = %[2]q
+						}`, protocol.testName("uuid-vault-url-test"), testVaultUrl),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("azrandom_uuid.this", "vault_url", testVaultUrl),
+						resource.TestCheckResourceAttrSet("azrandom_uuid.this", "version"),
+					),
+				},
+				{
+					ResourceName:                         "azrandom_uuid.this",
+					ImportStateVerifyIdentifierAttribute: "name",
+					ImportStateId:                        testVaultUrl + "secrets/" + protocol.testName("uuid-vault-url-test"),
+					ImportState:                          true,
+					ImportStateVerify:                    true,
+				},
+			},
+		}
+	})
+}
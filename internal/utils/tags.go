@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// MergeTags merges the tags of a resource into defaultTags, the tags of a resource winning when both set
+// the same key. The result is unknown when either is unknown, and null when both are null.
+func MergeTags(defaultTags types.Map, tags types.Map) (types.Map, diag.Diagnostics) {
+	if defaultTags.IsUnknown() || tags.IsUnknown() {
+		return types.MapUnknown(types.StringType), nil
+	}
+	if defaultTags.IsNull() && tags.IsNull() {
+		return types.MapNull(types.StringType), nil
+	}
+
+	elements := map[string]attr.Value{}
+	for k, v := range defaultTags.Elements() {
+		elements[k] = v
+	}
+	for k, v := range tags.Elements() {
+		elements[k] = v
+	}
+	return types.MapValue(types.StringType, elements)
+}
+
+// RemoveDefaultTags returns the tags of a resource given all tags of a secret, leaving out the tags that
+// only hold their value from defaultTags. Keys in current are kept, so that a resource may set a tag to the
+// same value as defaultTags. current is returned when it or tagsAll is null.
+func RemoveDefaultTags(tagsAll types.Map, defaultTags types.Map, current types.Map) (types.Map, diag.Diagnostics) {
+	if tagsAll.IsNull() || current.IsNull() || tagsAll.IsUnknown() {
+		return current, nil
+	}
+
+	defaults := defaultTags.Elements()
+	configured := current.Elements()
+
+	elements := map[string]attr.Value{}
+	for k, v := range tagsAll.Elements() {
+		_, isConfigured := configured[k]
+		if defaultValue, isDefault := defaults[k]; isDefault && defaultValue.Equal(v) && !isConfigured {
+			continue
+		}
+		elements[k] = v
+	}
+	return types.MapValue(types.StringType, elements)
+}
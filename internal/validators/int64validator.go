@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// PowerOfTwoValidator is the underlying struct implementing PowerOfTwo.
+type PowerOfTwoValidator struct{}
+
+func (v PowerOfTwoValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v PowerOfTwoValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a power of two"
+}
+
+func (v PowerOfTwoValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueInt64()
+	if value > 0 && value&(value-1) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+		req.Path,
+		v.Description(ctx),
+		req.ConfigValue.String(),
+	))
+}
+
+// PowerOfTwo returns a validator which ensures that a configured integer is a power of two.
+func PowerOfTwo() validator.Int64 {
+	return PowerOfTwoValidator{}
+}
@@ -0,0 +1,402 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	azrandom "terraform-provider-azrandom/client"
+	"terraform-provider-azrandom/internal/random"
+)
+
+// PositiveDurationValidator is the underlying struct implementing PositiveDuration.
+type PositiveDurationValidator struct{}
+
+func (v PositiveDurationValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v PositiveDurationValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a positive duration such as \"30m\" or \"720h\""
+}
+
+func (v PositiveDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err == nil && duration > 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+		req.Path,
+		v.Description(ctx),
+		req.ConfigValue.String(),
+	))
+}
+
+// PositiveDuration returns a validator which ensures that a configured string parses as a positive
+// time.Duration.
+func PositiveDuration() validator.String {
+	return PositiveDurationValidator{}
+}
+
+// UniqueCharactersValidator is the underlying struct implementing UniqueCharacters.
+type UniqueCharactersValidator struct{}
+
+func (v UniqueCharactersValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v UniqueCharactersValidator) MarkdownDescription(_ context.Context) string {
+	return "value must not contain any character more than once"
+}
+
+func (v UniqueCharactersValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	seen := map[rune]bool{}
+	for _, c := range req.ConfigValue.ValueString() {
+		if seen[c] {
+			resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+				req.Path,
+				v.Description(ctx),
+				req.ConfigValue.String(),
+			))
+			return
+		}
+		seen[c] = true
+	}
+}
+
+// UniqueCharacters returns a validator which ensures that a configured string contains every character
+// at most once.
+func UniqueCharacters() validator.String {
+	return UniqueCharactersValidator{}
+}
+
+// CronTemplateValidator is the underlying struct implementing CronTemplate.
+type CronTemplateValidator struct{}
+
+func (v CronTemplateValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v CronTemplateValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a cron expression of 5 or 6 fields, where fields may be R or R(min-max)"
+}
+
+func (v CronTemplateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := random.ValidateCronTemplate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+" ("+err.Error()+")",
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// CronTemplate returns a validator which ensures that a configured string is a valid template for
+// random.RenderCronTemplate.
+func CronTemplate() validator.String {
+	return CronTemplateValidator{}
+}
+
+// StringPatternValidator is the underlying struct implementing StringPattern.
+type StringPatternValidator struct{}
+
+func (v StringPatternValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v StringPatternValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a pattern of a, A, 9, # and literal characters, optionally repeated with {n}"
+}
+
+func (v StringPatternValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := random.ValidateStringPattern(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+" ("+err.Error()+")",
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// StringPattern returns a validator which ensures that a configured string is a valid pattern for
+// random.ParseStringPattern.
+func StringPattern() validator.String {
+	return StringPatternValidator{}
+}
+
+// VaultNameValidator is the underlying struct implementing VaultName.
+type VaultNameValidator struct{}
+
+func (v VaultNameValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v VaultNameValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be the name or the ARM resource ID of an Azure Key Vault"
+}
+
+func (v VaultNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := azrandom.ParseVaultName(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+" ("+err.Error()+")",
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// VaultName returns a validator which ensures that a configured string is a valid vault name or ARM
+// resource ID of a vault.
+func VaultName() validator.String {
+	return VaultNameValidator{}
+}
+
+// DNSSuffixValidator is the underlying struct implementing DNSSuffix.
+type DNSSuffixValidator struct{}
+
+func (v DNSSuffixValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v DNSSuffixValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a DNS suffix such as \"vault.azure.net\""
+}
+
+func (v DNSSuffixValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := azrandom.ValidateDNSSuffix(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// DNSSuffix returns a validator which ensures that a configured string is a DNS suffix.
+func DNSSuffix() validator.String {
+	return DNSSuffixValidator{}
+}
+
+// NoPrefixValidator is the underlying struct implementing NoPrefix.
+type NoPrefixValidator struct {
+	prefix string
+}
+
+func (v NoPrefixValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v NoPrefixValidator) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("value must not start with %q", v.prefix)
+}
+
+func (v NoPrefixValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if strings.HasPrefix(strings.ToLower(req.ConfigValue.ValueString()), strings.ToLower(v.prefix)) {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// NoPrefix returns a validator which ensures that a configured string does not start with prefix,
+// ignoring case.
+func NoPrefix(prefix string) validator.String {
+	return NoPrefixValidator{prefix: prefix}
+}
+
+// RFC3339Validator is the underlying struct implementing RFC3339.
+type RFC3339Validator struct{}
+
+func (v RFC3339Validator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v RFC3339Validator) MarkdownDescription(_ context.Context) string {
+	return "value must be an RFC3339 timestamp such as \"2030-01-01T00:00:00Z\""
+}
+
+func (v RFC3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// RFC3339 returns a validator which ensures that a configured string parses as an RFC3339 timestamp.
+func RFC3339() validator.String {
+	return RFC3339Validator{}
+}
+
+// HTTPSURLValidator is the underlying struct implementing HTTPSURL.
+type HTTPSURLValidator struct{}
+
+func (v HTTPSURLValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v HTTPSURLValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be an https URL such as \"https://login.example.com/\""
+}
+
+func (v HTTPSURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := ValidateHTTPSURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+": "+err.Error(),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// HTTPSURL returns a validator which ensures that a configured string is an absolute https URL.
+func HTTPSURL() validator.String {
+	return HTTPSURLValidator{}
+}
+
+// ValidateHTTPSURL returns an error when value is not an absolute https URL, so that values given by
+// environment variables can be checked like configured ones.
+func ValidateHTTPSURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%q does not use the https scheme", value)
+	}
+	if u.Host == "" {
+		return errors.New("the URL has no host")
+	}
+	return nil
+}
+
+// URLSchemeValidator is the underlying struct implementing URLScheme.
+type URLSchemeValidator struct {
+	schemes []string
+}
+
+func (v URLSchemeValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v URLSchemeValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a URL with one of the schemes " + strings.Join(v.schemes, ", ")
+}
+
+func (v URLSchemeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := ValidateURLScheme(req.ConfigValue.ValueString(), v.schemes); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+": "+err.Error(),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// URLScheme returns a validator which ensures that a configured string is an absolute URL with one of
+// the given schemes.
+func URLScheme(schemes ...string) validator.String {
+	return URLSchemeValidator{schemes: schemes}
+}
+
+// ValidateURLScheme returns an error when value is not an absolute URL with one of the given schemes, so
+// that values given by environment variables can be checked like configured ones.
+func ValidateURLScheme(value string, schemes []string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("%q does not use one of the schemes %s", value, strings.Join(schemes, ", "))
+	}
+	if u.Host == "" {
+		return errors.New("the URL has no host")
+	}
+	return nil
+}
+
+// UUIDValidator is the underlying struct implementing UUID.
+type UUIDValidator struct{}
+
+func (v UUIDValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v UUIDValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a GUID such as \"00000000-0000-0000-0000-000000000000\""
+}
+
+func (v UUIDValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := uuid.ParseUUID(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.String(),
+		))
+	}
+}
+
+// UUID returns a validator which ensures that a configured string is a GUID.
+func UUID() validator.String {
+	return UUIDValidator{}
+}
@@ -7,12 +7,24 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
-	"terraform-provider-azrandom/internal/provider"
+	azrandom "terraform-provider-azrandom/internal/provider"
 )
 
+const (
+	// address is the address of the provider in the registry.
+	address = "registry.terraform.io/schrieksoft/azrandom"
+
+	// protocolVersionsEnvVar lists the plugin protocol versions that Terraform supports, as set by go-plugin
+	// when Terraform starts the provider.
+	protocolVersionsEnvVar = "PLUGIN_PROTOCOL_VERSIONS"
+)
 
 var (
 	// these will be set by the goreleaser configuration
@@ -25,18 +37,44 @@ var (
 
 func main() {
 	var debug bool
+	var debugProtocolVersion int
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.IntVar(&debugProtocolVersion, "protocol-version", 6, "the plugin protocol version, 5 or 6, to serve the provider over with -debug")
 	flag.Parse()
 
+	protocolVersion := servedProtocolVersion(os.Getenv(protocolVersionsEnvVar))
+	// Debuggers reattach with TF_REATTACH_PROVIDERS, which takes the protocol version of the provider
+	if debug {
+		protocolVersion = debugProtocolVersion
+	}
+
 	opts := providerserver.ServeOpts{
-		Address: "registry.terraform.io/schrieksoft/azrandom",
-		Debug:   debug,
+		Address:         address,
+		Debug:           debug,
+		ProtocolVersion: protocolVersion,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	err := providerserver.Serve(context.Background(), newProvider(protocolVersion), opts)
 
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 }
+
+// servedProtocolVersion returns the protocol version to serve the provider over for the protocol versions that
+// Terraform lists in protocolVersionsEnvVar: version 6 from Terraform 1.0 on, and version 5 before.
+func servedProtocolVersion(protocolVersions string) int {
+	if protocolVersions != "" && !slices.Contains(strings.Split(protocolVersions, ","), "6") {
+		return 5
+	}
+	return 6
+}
+
+// newProvider returns the provider for the given protocol version.
+func newProvider(protocolVersion int) func() provider.Provider {
+	if protocolVersion == 5 {
+		return azrandom.NewProtocol5(version)
+	}
+	return azrandom.New(version)
+}